@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"time"
 )
 
@@ -11,6 +12,9 @@ type HTTP struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	// TrustedProxies — адреса/CIDR прокси (ingress), чьему X-Forwarded-For верим; пусто — никому,
+	// IP клиента — адрес TCP-пира.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 func (h *HTTP) Validate() error {
@@ -20,6 +24,16 @@ func (h *HTTP) Validate() error {
 	if h.Addr == "" {
 		return errors.New("http.addr is required")
 	}
+	return validateProxies("http.trusted_proxies", h.TrustedProxies)
+}
+
+// validateProxies — CIDR или одиночные адреса.
+func validateProxies(field string, ps []string) error {
+	for _, p := range ps {
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			return fmt.Errorf("%s: invalid address %q", field, p)
+		}
+	}
 	return nil
 }
 
@@ -118,6 +132,22 @@ type JWT struct {
 	RefreshTTL      time.Duration `mapstructure:"refresh_ttl"`
 	AccessAudience  string        `mapstructure:"access_audience"`
 	RefreshAudience string        `mapstructure:"refresh_audience"`
+
+	// издатель (users): асимметричные ключи; подписывает active_kid (по умолчанию первый)
+	Keys      []JWTKey `mapstructure:"keys"`
+	ActiveKid string   `mapstructure:"active_kid"`
+
+	// проверяющие сервисы: публичные ключи из JWKS издателя вместо secret
+	JWKSURL     string        `mapstructure:"jwks_url"`
+	JWKSRefresh time.Duration `mapstructure:"jwks_refresh"`
+}
+
+// JWTKey — PEM-файл приватного ключа (RSA/Ed25519) или только публичного — для ключа,
+// выведенного из оборота, но ещё нужного для проверки выданных им токенов.
+type JWTKey struct {
+	Kid            string `mapstructure:"kid"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
 }
 
 type Logger struct {
@@ -134,3 +164,35 @@ type Telemetry struct {
 func (t *Telemetry) Validate() error {
 	return nil
 }
+
+type RateLimitRule struct {
+	Method    string        `mapstructure:"method"`    // gRPC full method | "POST /v1/orders" | "*"
+	Principal string        `mapstructure:"principal"` // user|ip|"" (любой)
+	Rate      int           `mapstructure:"rate"`
+	Period    time.Duration `mapstructure:"period"`
+	Burst     int           `mapstructure:"burst"`
+}
+
+type RateLimit struct {
+	Enabled bool            `mapstructure:"enabled"`
+	Default RateLimitRule   `mapstructure:"default"`
+	Rules   []RateLimitRule `mapstructure:"rules"`
+	// TrustedProxies — для gRPC: пиры (ingress), чьему x-forwarded-for верим, кроме loopback
+	// (REST-фасад gateway). HTTP берёт IP клиента по http.trusted_proxies.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+func (r *RateLimit) Validate() error {
+	if r == nil || !r.Enabled {
+		return nil
+	}
+	for i, rule := range r.Rules {
+		if rule.Method == "" {
+			return fmt.Errorf("rate_limit.rules[%d].method is required", i)
+		}
+		if rule.Rate <= 0 || rule.Period <= 0 {
+			return fmt.Errorf("rate_limit.rules[%d]: rate and period must be > 0", i)
+		}
+	}
+	return validateProxies("rate_limit.trusted_proxies", r.TrustedProxies)
+}
@@ -0,0 +1,158 @@
+package grpcauth
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/jwtauth"
+)
+
+// Verifier — jwtauth.Manager или jwtauth.Verifier (JWKS users).
+type Verifier interface {
+	ParseAndVerify(token string) (*jwtauth.Claims, error)
+}
+
+// RevocationChecker — отзыв access-токенов по sid (pkg/revocation).
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, sid string) (bool, error)
+}
+
+// Rules — полное имя метода → роли, любая из которых открывает метод; пустой список —
+// любой пользователь с валидным access-токеном. Методы вне Rules интерцептор не трогает.
+type Rules map[string][]string
+
+type Options struct {
+	Logger   *slog.Logger
+	Verifier Verifier
+	Revoked  RevocationChecker // nil — без списка отозванных
+	Audience string            // "" — aud не проверяется
+	// Fallback — проверка до JWT (например, статический x-admin-token для скриптов);
+	// true — вызов разрешён без токена.
+	Fallback func(ctx context.Context) bool
+}
+
+type ctxKey struct{}
+
+// ClaimsFromContext — claims вызова, прошедшего RequireRole.
+func ClaimsFromContext(ctx context.Context) (*jwtauth.Claims, bool) {
+	c, ok := ctx.Value(ctxKey{}).(*jwtauth.Claims)
+	return c, ok
+}
+
+// NewContext — ctx с claims, как после RequireRole (тесты, внутренние вызовы).
+func NewContext(ctx context.Context, claims *jwtauth.Claims) context.Context {
+	return context.WithValue(ctx, ctxKey{}, claims)
+}
+
+// RequireRole — gRPC-аналог httpx.AuthJWT + httpx.RequireRole для методов из rules:
+// access-токен из metadata "authorization: Bearer ...", подпись, exp, aud, отзыв sid и роль.
+// Недоступный список отозванных вызов не блокирует (fail-open), как и в HTTP.
+func RequireRole(opt Options, rules Rules) grpc.UnaryServerInterceptor {
+	log := opt.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		roles, ok := rules[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		if opt.Fallback != nil && opt.Fallback(ctx) {
+			return handler(ctx, req)
+		}
+		claims, err := authorize(ctx, log, opt, info.FullMethod, roles)
+		if err != nil {
+			return nil, err
+		}
+		return handler(NewContext(ctx, claims), req)
+	}
+}
+
+// RequireRoleStream — RequireRole для стриминговых методов: claims доступны через
+// ClaimsFromContext(stream.Context()).
+func RequireRoleStream(opt Options, rules Rules) grpc.StreamServerInterceptor {
+	log := opt.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		roles, ok := rules[info.FullMethod]
+		if !ok {
+			return handler(srv, ss)
+		}
+		ctx := ss.Context()
+		if opt.Fallback != nil && opt.Fallback(ctx) {
+			return handler(srv, ss)
+		}
+		claims, err := authorize(ctx, log, opt, info.FullMethod, roles)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: NewContext(ctx, claims)})
+	}
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context { return s.ctx }
+
+func authorize(ctx context.Context, log *slog.Logger, opt Options, method string, roles []string) (*jwtauth.Claims, error) {
+	if opt.Verifier == nil {
+		return nil, status.Error(codes.PermissionDenied, "method is disabled")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	vals := md.Get("authorization")
+	if len(vals) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization")
+	}
+	parts := strings.SplitN(vals[0], " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || parts[1] == "" {
+		return nil, status.Error(codes.Unauthenticated, "invalid authorization")
+	}
+
+	claims, err := opt.Verifier.ParseAndVerify(parts[1])
+	if err != nil {
+		log.Warn("grpcauth: token verify failed", slog.String("method", method), slog.String("err", err.Error()))
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if opt.Audience != "" && !hasAudience(claims, opt.Audience) {
+		log.Warn("grpcauth: wrong audience", slog.String("method", method), slog.Any("aud", claims.Audience))
+		return nil, status.Error(codes.PermissionDenied, "forbidden")
+	}
+	if opt.Revoked != nil {
+		revoked, err := opt.Revoked.IsRevoked(ctx, claims.SessionID)
+		if err != nil {
+			log.Warn("grpcauth: revocation check failed", slog.String("err", err.Error()))
+		} else if revoked {
+			return nil, status.Error(codes.Unauthenticated, "token revoked")
+		}
+	}
+	if len(roles) > 0 && !claims.HasRole(roles...) {
+		log.Warn("grpcauth: missing role",
+			slog.String("method", method),
+			slog.String("uid", claims.UserID),
+			slog.Any("have", claims.Roles),
+			slog.Any("need", roles),
+		)
+		return nil, status.Error(codes.PermissionDenied, "forbidden")
+	}
+	return claims, nil
+}
+
+func hasAudience(claims *jwtauth.Claims, aud string) bool {
+	for _, a := range claims.Audience {
+		if a == aud {
+			return true
+		}
+	}
+	return false
+}
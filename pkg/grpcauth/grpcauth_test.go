@@ -0,0 +1,158 @@
+package grpcauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/jwtauth"
+)
+
+func TestRequireRole(t *testing.T) {
+	m := jwtauth.New(jwtauth.Config{Secret: "test-secret", Issuer: "goshop-auth", AccessTTL: time.Minute, RefreshTTL: time.Hour, AccessAudience: "api"})
+	admin, refresh, _, err := m.GeneratePair("u1", "a@example.com", "", jwtauth.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, _, _, err := m.GeneratePair("u2", "b@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	interceptor := RequireRole(Options{
+		Verifier: m,
+		Audience: "api",
+		Fallback: func(ctx context.Context) bool {
+			md, _ := metadata.FromIncomingContext(ctx)
+			return len(md.Get("x-admin-token")) > 0
+		},
+	}, Rules{"/svc/Admin": {jwtauth.RoleAdmin}, "/svc/Mine": {}})
+
+	call := func(method string, md metadata.MD) (string, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		out, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ any) (any, error) {
+			if c, ok := ClaimsFromContext(ctx); ok {
+				return c.UserID, nil
+			}
+			return "", nil
+		})
+		s, _ := out.(string)
+		return s, err
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		md     metadata.MD
+		want   codes.Code
+		uid    string
+	}{
+		{"open method", "/svc/Public", nil, codes.OK, ""},
+		{"no token", "/svc/Admin", nil, codes.Unauthenticated, ""},
+		{"garbage token", "/svc/Admin", metadata.Pairs("authorization", "Bearer x.y.z"), codes.Unauthenticated, ""},
+		{"no role", "/svc/Admin", metadata.Pairs("authorization", "Bearer "+user), codes.PermissionDenied, ""},
+		{"refresh token", "/svc/Admin", metadata.Pairs("authorization", "Bearer "+refresh), codes.PermissionDenied, ""},
+		{"admin", "/svc/Admin", metadata.Pairs("authorization", "Bearer "+admin), codes.OK, "u1"},
+		{"fallback", "/svc/Admin", metadata.Pairs("x-admin-token", "t"), codes.OK, ""},
+		{"any user", "/svc/Mine", metadata.Pairs("authorization", "Bearer "+user), codes.OK, "u2"},
+		{"any user without token", "/svc/Mine", nil, codes.Unauthenticated, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uid, err := call(tc.method, tc.md)
+			if status.Code(err) != tc.want {
+				t.Fatalf("code = %v, want %v (%v)", status.Code(err), tc.want, err)
+			}
+			if uid != tc.uid {
+				t.Fatalf("claims uid = %q, want %q", uid, tc.uid)
+			}
+		})
+	}
+}
+
+type stubRevoked struct {
+	revoked bool
+	err     error
+}
+
+func (s stubRevoked) IsRevoked(context.Context, string) (bool, error) { return s.revoked, s.err }
+
+// Отозванная сессия отклоняется; недоступный список отозванных вызов не блокирует (fail-open).
+func TestRequireRole_Revoked(t *testing.T) {
+	m := jwtauth.New(jwtauth.Config{Secret: "test-secret", Issuer: "goshop-auth", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	user, _, _, err := m.GeneratePair("u1", "a@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+user))
+	ok := func(context.Context, any) (any, error) { return nil, nil }
+
+	cases := []struct {
+		name string
+		rev  RevocationChecker
+		want codes.Code
+	}{
+		{"revoked", stubRevoked{revoked: true}, codes.Unauthenticated},
+		{"not revoked", stubRevoked{}, codes.OK},
+		{"list unavailable", stubRevoked{err: errors.New("redis: connection refused")}, codes.OK},
+	}
+	for _, tc := range cases {
+		interceptor := RequireRole(Options{Verifier: m, Revoked: tc.rev}, Rules{"/svc/Mine": {}})
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Mine"}, ok)
+		if status.Code(err) != tc.want {
+			t.Fatalf("%s: code = %v, want %v", tc.name, status.Code(err), tc.want)
+		}
+	}
+}
+
+type stubStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s stubStream) Context() context.Context { return s.ctx }
+
+func TestRequireRoleStream(t *testing.T) {
+	m := jwtauth.New(jwtauth.Config{Secret: "test-secret", Issuer: "goshop-auth", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	user, _, _, err := m.GeneratePair("u2", "b@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	interceptor := RequireRoleStream(Options{Verifier: m}, Rules{"/svc/Watch": {}})
+
+	cases := []struct {
+		name   string
+		method string
+		md     metadata.MD
+		want   codes.Code
+		uid    string
+	}{
+		{"open method", "/svc/Other", nil, codes.OK, ""},
+		{"no token", "/svc/Watch", nil, codes.Unauthenticated, ""},
+		{"user", "/svc/Watch", metadata.Pairs("authorization", "Bearer "+user), codes.OK, "u2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ss := stubStream{ctx: metadata.NewIncomingContext(context.Background(), tc.md)}
+			uid := ""
+			err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: tc.method}, func(_ any, ss grpc.ServerStream) error {
+				if c, ok := ClaimsFromContext(ss.Context()); ok {
+					uid = c.UserID
+				}
+				return nil
+			})
+			if status.Code(err) != tc.want {
+				t.Fatalf("code = %v, want %v (%v)", status.Code(err), tc.want, err)
+			}
+			if uid != tc.uid {
+				t.Fatalf("claims uid = %q, want %q", uid, tc.uid)
+			}
+		})
+	}
+}
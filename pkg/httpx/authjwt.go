@@ -1,6 +1,7 @@
 package httpx
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -25,15 +26,26 @@ type verifier interface {
 	ParseAndVerify(token string) (*jwtauth.Claims, error)
 }
 
+// RevocationChecker — отзыв access-токенов по sid до exp (logout, logout_all); см. pkg/revocation.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, sid string) (bool, error)
+}
+
 func AuthJWTExpectAudience(rootLog *slog.Logger, v verifier, expectAudience string) gin.HandlerFunc {
-	return authJWTInternal(rootLog, v, expectAudience)
+	return authJWTInternal(rootLog, v, nil, expectAudience)
 }
 
 func AuthJWTWith(rootLog *slog.Logger, v verifier) gin.HandlerFunc {
-	return authJWTInternal(rootLog, v, "")
+	return authJWTInternal(rootLog, v, nil, "")
+}
+
+// AuthJWTRevocable — как AuthJWTExpectAudience, плюс проверка списка отозванных сессий.
+// Недоступность списка не блокирует запросы (fail-open): подпись и exp всё равно проверены.
+func AuthJWTRevocable(rootLog *slog.Logger, v verifier, rc RevocationChecker, expectAudience string) gin.HandlerFunc {
+	return authJWTInternal(rootLog, v, rc, expectAudience)
 }
 
-func authJWTInternal(rootLog *slog.Logger, v verifier, expectAudience string) gin.HandlerFunc {
+func authJWTInternal(rootLog *slog.Logger, v verifier, rc RevocationChecker, expectAudience string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Cache-Control", "no-store")
 
@@ -68,6 +80,18 @@ func authJWTInternal(rootLog *slog.Logger, v verifier, expectAudience string) gi
 			return
 		}
 
+		if rc != nil {
+			revoked, err := rc.IsRevoked(c.Request.Context(), claims.SessionID)
+			if err != nil {
+				l.Warn("auth: revocation check failed", slog.String("err", err.Error()))
+			} else if revoked {
+				l.Warn("auth: token revoked", slog.String("sid", claims.SessionID))
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+				c.Abort()
+				return
+			}
+		}
+
 		if expectAudience != "" {
 			okAud := false
 			for _, a := range claims.Audience {
@@ -99,3 +123,35 @@ func GetJWTClaims(c *gin.Context) (*jwtauth.Claims, bool) {
 	claims, ok := v.(*jwtauth.Claims)
 	return claims, ok
 }
+
+// RequireRole — после AuthJWT*: пропускает, если у токена есть хотя бы одна из ролей, иначе 403.
+// Роли берутся из access-токена, поэтому отзыв роли действует после его перевыпуска (refresh).
+func RequireRole(rootLog *slog.Logger, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := rootLog
+		if rl, ok := c.Get(CtxKeyLogger); ok {
+			if reqLog, ok := rl.(*slog.Logger); ok && reqLog != nil {
+				l = reqLog
+			}
+		}
+
+		claims, ok := GetJWTClaims(c)
+		if !ok {
+			l.Warn("auth: require role without jwt claims")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+		if !claims.HasRole(roles...) {
+			l.Warn("auth: missing role",
+				slog.String("uid", claims.UserID),
+				slog.Any("have", claims.Roles),
+				slog.Any("need", roles),
+			)
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
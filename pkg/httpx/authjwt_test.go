@@ -0,0 +1,60 @@
+package httpx
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"goshop/pkg/jwtauth"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := jwtauth.New(jwtauth.Config{Secret: "test-secret", Issuer: "goshop-auth", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	support, _, _, err := m.GeneratePair("u1", "s@example.com", "", jwtauth.RoleSupport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, _, _, err := m.GeneratePair("u2", "u@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log := slog.New(slog.NewTextHandler(httptest.NewRecorder(), nil))
+	r := gin.New()
+	r.GET("/orders", AuthJWT(log, m), RequireRole(log, jwtauth.RoleAdmin, jwtauth.RoleSupport), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/unguarded", RequireRole(log, jwtauth.RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	cases := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{"support", "/orders", support, http.StatusNoContent},
+		{"no role", "/orders", user, http.StatusForbidden},
+		{"no token", "/orders", "", http.StatusUnauthorized},
+		{"without AuthJWT", "/unguarded", support, http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package httpx
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag — версия ресурса по updated_at (микросекунды, как хранит Postgres).
+func ETag(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UTC().UnixMicro(), 36) + `"`
+}
+
+// NotModified — true, если If-None-Match совпал с etag (или "*"): ответ 304 уже записан.
+// Для GET/HEAD; сравнение слабое (W/ игнорируем), RFC 9110 §13.1.2.
+func NotModified(c *gin.Context, etag string) bool {
+	h := c.GetHeader("If-None-Match")
+	if h == "" || !etagListMatch(h, etag, true) {
+		return false
+	}
+	c.Header("ETag", etag)
+	c.Status(http.StatusNotModified)
+	c.Abort()
+	return true
+}
+
+// IfMatch — проверка If-Match для изменяющих запросов (optimistic concurrency).
+// Без заголовка — пропускаем; при несовпадении пишет 412 и возвращает false.
+// Сравнение строгое, RFC 9110 §13.1.1.
+func IfMatch(c *gin.Context, etag string) bool {
+	h := c.GetHeader("If-Match")
+	if h == "" || etagListMatch(h, etag, false) {
+		return true
+	}
+	c.Header("ETag", etag)
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "precondition failed: resource was modified"})
+	c.Abort()
+	return false
+}
+
+func etagListMatch(header, etag string, weak bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if strings.HasPrefix(t, "W/") {
+			if !weak {
+				continue
+			}
+			t = t[2:]
+		}
+		if t == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestETagConditionals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	etag := ETag(time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC))
+
+	r := gin.New()
+	r.GET("/x", func(c *gin.Context) {
+		if NotModified(c, etag) {
+			return
+		}
+		c.Header("ETag", etag)
+		c.String(http.StatusOK, "body")
+	})
+	r.PATCH("/x", func(c *gin.Context) {
+		if !IfMatch(c, etag) {
+			return
+		}
+		c.Header("ETag", etag)
+		c.Status(http.StatusNoContent)
+	})
+
+	cases := []struct {
+		name   string
+		method string
+		header string
+		value  string
+		want   int
+	}{
+		{"get without validator", http.MethodGet, "", "", http.StatusOK},
+		{"if-none-match hit", http.MethodGet, "If-None-Match", etag, http.StatusNotModified},
+		{"if-none-match weak hit", http.MethodGet, "If-None-Match", `"zzz", W/` + etag, http.StatusNotModified},
+		{"if-none-match star", http.MethodGet, "If-None-Match", "*", http.StatusNotModified},
+		{"if-none-match miss", http.MethodGet, "If-None-Match", `"stale"`, http.StatusOK},
+		{"patch without if-match", http.MethodPatch, "", "", http.StatusNoContent},
+		{"if-match hit", http.MethodPatch, "If-Match", etag, http.StatusNoContent},
+		{"if-match weak is not strong", http.MethodPatch, "If-Match", "W/" + etag, http.StatusPreconditionFailed},
+		{"if-match stale", http.MethodPatch, "If-Match", `"stale"`, http.StatusPreconditionFailed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, "/x", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			r.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d", w.Code, tc.want)
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Fatalf("ETag = %q, want %q", got, etag)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package httpx
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"goshop/pkg/ratelimit"
+)
+
+// RateLimit — gin-версия лимитера из pkg/ratelimit.
+// Ключ метода — "<METHOD> <route>" (например, "POST /v1/orders").
+// Принципал — uid из JWT (claims из AuthJWT или Bearer-токен, проверенный v), иначе IP клиента.
+// v может быть nil — тогда без AuthJWT лимитируем только по IP.
+func RateLimit(rootLog *slog.Logger, l *ratelimit.Limiter, v verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := ReqLog(c, rootLog)
+
+		method := c.Request.Method + " " + c.FullPath()
+		p := httpPrincipal(c, v)
+
+		d, err := l.Allow(c.Request.Context(), method, p)
+		if err != nil {
+			log.Warn("ratelimit: check failed, allowing",
+				slog.String("route", method),
+				slog.String("principal", p.String()),
+				slog.Any("err", err),
+			)
+			c.Next()
+			return
+		}
+
+		if d.Limit > 0 {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(d.Limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		}
+		if !d.Allowed {
+			log.Warn("ratelimit: limited",
+				slog.String("route", method),
+				slog.String("principal", p.String()),
+				slog.Int64("retry_after_ms", d.RetryAfter.Milliseconds()),
+			)
+			c.Header("Retry-After", strconv.Itoa(d.RetryAfterSeconds()))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func httpPrincipal(c *gin.Context, v verifier) ratelimit.Principal {
+	if claims, ok := GetJWTClaims(c); ok && claims.UserID != "" {
+		return ratelimit.UserPrincipal(claims.UserID)
+	}
+	if v != nil {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") && parts[1] != "" {
+			if claims, err := v.ParseAndVerify(parts[1]); err == nil && claims.UserID != "" {
+				return ratelimit.UserPrincipal(claims.UserID)
+			}
+		}
+	}
+	return ratelimit.IPPrincipal(c.ClientIP())
+}
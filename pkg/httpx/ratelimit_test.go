@@ -0,0 +1,101 @@
+package httpx
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	pcfg "goshop/pkg/config"
+	"goshop/pkg/jwtauth"
+	"goshop/pkg/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	m := jwtauth.New(jwtauth.Config{Secret: "test-secret", Issuer: "goshop-auth", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	access, _, _, err := m.GeneratePair("u1", "u@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := ratelimit.New(rdb, []ratelimit.Rule{
+		{Method: "POST /v1/orders/:id", Principal: ratelimit.PrincipalUser, Limit: ratelimit.Limit{Rate: 1, Period: time.Minute}},
+		{Method: "POST /v1/orders/:id", Principal: ratelimit.PrincipalIP, Limit: ratelimit.Limit{Rate: 2, Period: time.Minute}},
+	})
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := gin.New()
+	r.POST("/v1/orders/:id", RateLimit(log, l, m), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	r.GET("/v1/orders", RateLimit(log, l, m), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// ключ — маршрут, а не путь: /1 и /2 делят бакет
+	if w := do(http.MethodPost, "/v1/orders/1", access); w.Code != http.StatusNoContent || w.Header().Get("X-RateLimit-Limit") != "1" {
+		t.Fatalf("first user call: %d %v", w.Code, w.Header())
+	}
+	w := do(http.MethodPost, "/v1/orders/2", access)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("second user call: %d %v", w.Code, w.Header())
+	}
+
+	// без токена — бакет по IP
+	for i := 0; i < 2; i++ {
+		if w := do(http.MethodPost, "/v1/orders/1", ""); w.Code != http.StatusNoContent {
+			t.Fatalf("ip call %d: %d", i, w.Code)
+		}
+	}
+	if w := do(http.MethodPost, "/v1/orders/1", ""); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("third ip call: %d", w.Code)
+	}
+
+	// маршрут без правила — без заголовков лимита
+	if w := do(http.MethodGet, "/v1/orders", ""); w.Code != http.StatusNoContent || w.Header().Get("X-RateLimit-Limit") != "" {
+		t.Fatalf("unlimited route: %d %v", w.Code, w.Header())
+	}
+
+	// Redis недоступен — запрос проходит
+	mr.Close()
+	if w := do(http.MethodPost, "/v1/orders/1", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("fail-open: %d", w.Code)
+	}
+}
+
+// Анонимный вызывающий не уходит от лимита по IP, подставляя новый X-Forwarded-For.
+func TestRateLimit_ForgedXFF(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	l := ratelimit.New(rdb, []ratelimit.Rule{
+		{Method: "GET /ip", Principal: ratelimit.PrincipalIP, Limit: ratelimit.Limit{Rate: 1, Period: time.Minute}},
+	})
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := NewServer(pcfg.HTTP{Addr: ":0"}, log, WithMiddleware(RateLimit(log, l, nil)), WithModules(ipModule{}))
+
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = "203.0.113.5:4000"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i+1))
+		w := httptest.NewRecorder()
+		s.http.Handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Fatalf("request %d: status %d, want %d", i+1, w.Code, want)
+		}
+	}
+}
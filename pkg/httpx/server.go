@@ -49,6 +49,11 @@ func NewServer(cfg pcfg.HTTP, log *slog.Logger, opts ...Option) *Server {
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
+	// по умолчанию gin верит X-Forwarded-For от любого пира — тогда ClientIP (лимиты по IP,
+	// защита логина, IP сессий) подделывается заголовком
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		panic(err)
+	}
 	r.Use(RequestID())
 	r.Use(GinLogger(log))
 	r.Use(GinRecovery(log))
@@ -0,0 +1,48 @@
+package httpx
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	pcfg "goshop/pkg/config"
+)
+
+type ipModule struct{}
+
+func (ipModule) Name() string { return "ip" }
+func (ipModule) Mount(r *gin.Engine) error {
+	r.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+	return nil
+}
+
+// X-Forwarded-For принимается только от доверенного прокси: иначе каждый запрос мог бы
+// назваться новым IP и обойти лимиты и блокировки по IP.
+func TestNewServer_TrustedProxies(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cases := []struct {
+		name    string
+		trusted []string
+		remote  string
+		want    string
+	}{
+		{"no proxies: forged xff ignored", nil, "203.0.113.5:4000", "203.0.113.5"},
+		{"untrusted peer", []string{"10.244.0.0/16"}, "203.0.113.5:4000", "203.0.113.5"},
+		{"trusted ingress", []string{"10.244.0.0/16"}, "10.244.1.7:4000", "198.51.100.9"},
+	}
+	for _, c := range cases {
+		s := NewServer(pcfg.HTTP{Addr: ":0", TrustedProxies: c.trusted}, log, WithModules(ipModule{}))
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = c.remote
+		req.Header.Set("X-Forwarded-For", "198.51.100.9")
+		w := httptest.NewRecorder()
+		s.http.Handler.ServeHTTP(w, req)
+		if got := w.Body.String(); got != c.want {
+			t.Errorf("%s: ClientIP = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package jwtauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type VerifierConfig struct {
+	JWKSURL string // https://users/.well-known/jwks.json
+	Issuer  string
+	// Secret — опционально: принимать HS256-токены, выпущенные до перехода на асимметричные ключи
+	Secret string
+	// Refresh — плановое обновление набора ключей; MinRefresh — не чаще этого
+	// перечитывать JWKS из-за незнакомого kid (иначе мусорные токены превращаются в DoS на users)
+	Refresh    time.Duration
+	MinRefresh time.Duration
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+// Verifier проверяет токены по публичным ключам из JWKS издателя. Ключи кэшируются; при
+// ротации новый kid появляется в JWKS раньше, чем им начинают подписывать, а старый
+// остаётся там, пока живут его токены — поэтому незнакомый kid означает лишь устаревший кэш.
+type Verifier struct {
+	cfg    VerifierConfig
+	client *http.Client
+	secret []byte
+
+	mu        sync.RWMutex
+	keys      map[string]verifyKey
+	fetchedAt time.Time
+	fetchMu   sync.Mutex // один запрос JWKS за раз
+}
+
+func NewVerifier(cfg VerifierConfig) *Verifier {
+	if cfg.Refresh <= 0 {
+		cfg.Refresh = 10 * time.Minute
+	}
+	if cfg.MinRefresh <= 0 {
+		cfg.MinRefresh = 30 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 3 * time.Second
+	}
+	v := &Verifier{cfg: cfg, client: cfg.HTTPClient, keys: map[string]verifyKey{}}
+	if v.client == nil {
+		v.client = &http.Client{Timeout: cfg.Timeout}
+	}
+	if cfg.Secret != "" {
+		v.secret = []byte(cfg.Secret)
+	}
+	return v
+}
+
+// Refresh — загрузить JWKS сейчас (на старте сервиса, чтобы первый запрос не ждал users).
+func (v *Verifier) Refresh(ctx context.Context) error {
+	v.fetchMu.Lock()
+	defer v.fetchMu.Unlock()
+	return v.fetch(ctx)
+}
+
+func (v *Verifier) ParseAndVerify(tokenStr string) (*Claims, error) {
+	return parse(tokenStr, v.cfg.Issuer, func(t *jwt.Token) (any, error) {
+		if t.Method == jwt.SigningMethodHS256 {
+			if v.secret == nil {
+				return nil, errors.New("HS256 tokens are not accepted")
+			}
+			return v.secret, nil
+		}
+		v.maybeRefresh(t)
+		v.mu.RLock()
+		defer v.mu.RUnlock()
+		return lookup(v.keys, t)
+	})
+}
+
+// maybeRefresh — перечитать JWKS, если кэш протух или kid незнаком (не чаще MinRefresh).
+// Ошибка загрузки не фатальна: остаётся прежний набор ключей.
+func (v *Verifier) maybeRefresh(t *jwt.Token) {
+	kid, _ := t.Header["kid"].(string)
+
+	v.mu.RLock()
+	_, known := v.keys[kid]
+	age := time.Since(v.fetchedAt)
+	v.mu.RUnlock()
+
+	if (known || kid == "") && age < v.cfg.Refresh {
+		return
+	}
+	if age < v.cfg.MinRefresh {
+		return
+	}
+
+	v.fetchMu.Lock()
+	defer v.fetchMu.Unlock()
+	// пока ждали, ключи мог обновить соседний запрос
+	v.mu.RLock()
+	age = time.Since(v.fetchedAt)
+	v.mu.RUnlock()
+	if age < v.cfg.MinRefresh {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.cfg.Timeout)
+	defer cancel()
+	_ = v.fetch(ctx)
+}
+
+func (v *Verifier) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("jwks request: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		v.touch()
+		return fmt.Errorf("jwks fetch: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		v.touch()
+		return fmt.Errorf("jwks fetch: status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set); err != nil {
+		v.touch()
+		return fmt.Errorf("jwks decode: %w", err)
+	}
+	keys := make(map[string]verifyKey, len(set.Keys))
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		k, err := j.Key()
+		if err != nil {
+			continue // незнакомый тип ключа не должен ломать остальные
+		}
+		method, err := methodFor(k.Public)
+		if err != nil {
+			continue
+		}
+		keys[k.ID] = verifyKey{method: method, key: k.Public}
+	}
+	if len(keys) == 0 {
+		v.touch()
+		return errors.New("jwks: no usable keys")
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+// touch — неудачная попытка тоже сдвигает время, чтобы не долбить недоступный users.
+func (v *Verifier) touch() {
+	v.mu.Lock()
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+}
@@ -1,7 +1,9 @@
 package jwtauth
 
 import (
+	"crypto"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -9,7 +11,11 @@ import (
 )
 
 type Config struct {
+	// Secret — HS256. Если заданы Keys, подпись асимметричная (RS256/EdDSA по типу ключа),
+	// а Secret остаётся только для проверки старых HS256-токенов на время миграции.
 	Secret          string
+	Keys            []Key // все публикуемые в JWKS ключи; подписывает ActiveKid (по умолчанию первый с приватной частью)
+	ActiveKid       string
 	Issuer          string
 	AccessTTL       time.Duration
 	RefreshTTL      time.Duration
@@ -19,6 +25,9 @@ type Config struct {
 
 type Manager struct {
 	secret          []byte
+	signer          *signer
+	keys            map[string]verifyKey
+	jwks            JWKS
 	issuer          string
 	accessTTL       time.Duration
 	refreshTTL      time.Duration
@@ -26,50 +35,138 @@ type Manager struct {
 	refreshAudience string
 }
 
+// TokenVerifier — Manager (свои ключи/секрет) или Verifier (JWKS издателя).
+type TokenVerifier interface {
+	ParseAndVerify(token string) (*Claims, error)
+}
+
 type Claims struct {
-	UserID string `json:"uid"`
-	Email  string `json:"email"`
+	UserID    string `json:"uid"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid,omitempty"` // семейство сессий (устройство); общее у access и refresh
+	// Roles — только у access-токена; при refresh перечитываются из БД users
+	Roles []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Роли, которые проверяют сервисы (httpx.RequireRole, grpcauth.RequireRole).
+const (
+	RoleAdmin   = "admin"   // всё, включая назначение ролей
+	RoleSupport = "support" // чтение чужих заказов
+)
+
+// HasRole — есть ли у токена хотя бы одна из ролей.
+func (c *Claims) HasRole(roles ...string) bool {
+	for _, have := range c.Roles {
+		for _, want := range roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type signer struct {
+	kid    string
+	method jwt.SigningMethod
+	key    crypto.Signer
+}
+
+type verifyKey struct {
+	method jwt.SigningMethod
+	key    crypto.PublicKey
+}
+
+// New паникует на ключах неподдерживаемого типа и на ActiveKid без приватного ключа —
+// это ошибка конфигурации, ключи уже прошли LoadKeys.
 func New(cfg Config) *Manager {
-	return &Manager{
-		secret:          []byte(cfg.Secret),
+	m := &Manager{
 		issuer:          cfg.Issuer,
 		accessTTL:       cfg.AccessTTL,
 		refreshTTL:      cfg.RefreshTTL,
 		accessAudience:  cfg.AccessAudience,
 		refreshAudience: cfg.RefreshAudience,
+		keys:            make(map[string]verifyKey, len(cfg.Keys)),
+		jwks:            JWKS{Keys: []JWK{}},
+	}
+	if cfg.Secret != "" {
+		m.secret = []byte(cfg.Secret)
+	}
+	for _, k := range cfg.Keys {
+		method, err := methodFor(k.Public)
+		if err != nil {
+			panic(fmt.Sprintf("jwtauth: key %s: %v", k.ID, err))
+		}
+		jwk, err := toJWK(k)
+		if err != nil {
+			panic(fmt.Sprintf("jwtauth: key %s: %v", k.ID, err))
+		}
+		m.keys[k.ID] = verifyKey{method: method, key: k.Public}
+		m.jwks.Keys = append(m.jwks.Keys, jwk)
+
+		active := cfg.ActiveKid == k.ID || (cfg.ActiveKid == "" && m.signer == nil)
+		if active && k.Private != nil {
+			m.signer = &signer{kid: k.ID, method: method, key: k.Private}
+		}
+	}
+	if len(cfg.Keys) > 0 && m.signer == nil {
+		panic(fmt.Sprintf("jwtauth: no private key for active kid %q", cfg.ActiveKid))
 	}
+	return m
 }
 
-func (m *Manager) GeneratePair(userID, email string) (access string, refresh string, refreshJTI uuid.UUID, err error) {
-	jti := uuid.New()
-	refresh, err = m.generateRefreshWithJTI(userID, email, jti)
-	if err != nil {
-		return "", "", uuid.Nil, err
+// JWKS — публичные ключи для /.well-known/jwks.json.
+func (m *Manager) JWKS() JWKS { return m.jwks }
+
+// ActiveKid — kid ключа, которым подписываются новые токены ("" — HS256).
+func (m *Manager) ActiveKid() string {
+	if m.signer == nil {
+		return ""
 	}
-	access, err = m.generateAccess(userID, email)
+	return m.signer.kid
+}
+
+func (m *Manager) sign(cl *Claims) (string, error) {
+	if m.signer == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, cl).SignedString(m.secret)
+	}
+	tok := jwt.NewWithClaims(m.signer.method, cl)
+	tok.Header["kid"] = m.signer.kid
+	return tok.SignedString(m.signer.key)
+}
+
+// GeneratePair — access + refresh одной сессии. sid — идентификатор семейства сессий
+// (устройства); пустой — новое семейство, sid = jti refresh-токена (логин). roles попадают
+// только в access: refresh живёт долго, роли при ротации берутся заново.
+func (m *Manager) GeneratePair(userID, email, sid string, roles ...string) (access string, refresh string, refreshJTI uuid.UUID, err error) {
+	jti := uuid.New()
+	access, refresh, err = m.GeneratePairWithJTI(userID, email, sid, jti, roles...)
 	if err != nil {
 		return "", "", uuid.Nil, err
 	}
 	return access, refresh, jti, nil
 }
 
-func (m *Manager) GeneratePairWithJTI(userID, email string, refreshJTI uuid.UUID) (access string, refresh string, err error) {
-	refresh, err = m.generateRefreshWithJTI(userID, email, refreshJTI)
+func (m *Manager) GeneratePairWithJTI(userID, email, sid string, refreshJTI uuid.UUID, roles ...string) (access string, refresh string, err error) {
+	if sid == "" {
+		sid = refreshJTI.String()
+	}
+	refresh, err = m.generateRefreshWithJTI(userID, email, sid, refreshJTI)
 	if err != nil {
 		return "", "", err
 	}
-	access, err = m.generateAccess(userID, email)
+	access, err = m.generateAccess(userID, email, sid, roles)
 	return access, refresh, err
 }
 
-func (m *Manager) generateAccess(userID, email string) (string, error) {
+// generateAccess — у access свой jti и sid сессии: по sid его можно отозвать раньше exp.
+func (m *Manager) generateAccess(userID, email, sid string, roles []string) (string, error) {
 	now := time.Now().UTC()
 	rc := jwt.RegisteredClaims{
 		Issuer:    m.issuer,
 		Subject:   userID,
+		ID:        uuid.NewString(),
 		IssuedAt:  jwt.NewNumericDate(now),
 		ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTTL)),
 	}
@@ -79,12 +176,14 @@ func (m *Manager) generateAccess(userID, email string) (string, error) {
 	ac := &Claims{
 		UserID:           userID,
 		Email:            email,
+		SessionID:        sid,
+		Roles:            roles,
 		RegisteredClaims: rc,
 	}
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, ac).SignedString(m.secret)
+	return m.sign(ac)
 }
 
-func (m *Manager) generateRefreshWithJTI(userID, email string, jti uuid.UUID) (string, error) {
+func (m *Manager) generateRefreshWithJTI(userID, email, sid string, jti uuid.UUID) (string, error) {
 	now := time.Now().UTC()
 	rc := jwt.RegisteredClaims{
 		Issuer:    m.issuer,
@@ -99,16 +198,34 @@ func (m *Manager) generateRefreshWithJTI(userID, email string, jti uuid.UUID) (s
 	cl := &Claims{
 		UserID:           userID,
 		Email:            email,
+		SessionID:        sid,
 		RegisteredClaims: rc,
 	}
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, cl).SignedString(m.secret)
+	return m.sign(cl)
 }
 
 func (m *Manager) ParseAndVerify(tokenStr string) (*Claims, error) {
-	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
-	tok, err := parser.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (any, error) {
-		return m.secret, nil
+	return parse(tokenStr, m.issuer, func(t *jwt.Token) (any, error) {
+		if t.Method == jwt.SigningMethodHS256 {
+			if m.secret == nil {
+				return nil, errors.New("HS256 tokens are not accepted")
+			}
+			return m.secret, nil
+		}
+		return lookup(m.keys, t)
 	})
+}
+
+// методы, которые принимаем: HS256 — только если есть секрет (keyfunc это проверит)
+var validMethods = []string{
+	jwt.SigningMethodHS256.Alg(),
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+func parse(tokenStr, issuer string, keyfunc jwt.Keyfunc) (*Claims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(validMethods))
+	tok, err := parser.ParseWithClaims(tokenStr, &Claims{}, keyfunc)
 	if err != nil {
 		return nil, err
 	}
@@ -116,12 +233,33 @@ func (m *Manager) ParseAndVerify(tokenStr string) (*Claims, error) {
 	if !ok || !tok.Valid {
 		return nil, errors.New("invalid token")
 	}
-	if m.issuer != "" && claims.Issuer != "" && claims.Issuer != m.issuer {
+	if issuer != "" && claims.Issuer != "" && claims.Issuer != issuer {
 		return nil, errors.New("invalid issuer")
 	}
 	return claims, nil
 }
 
+var errUnknownKid = errors.New("unknown kid")
+
+// lookup — ключ по kid; алгоритм токена должен совпадать с типом ключа (иначе alg confusion).
+func lookup(keys map[string]verifyKey, t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("missing kid")
+	}
+	k, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errUnknownKid, kid)
+	}
+	if k.method.Alg() != t.Method.Alg() {
+		return nil, fmt.Errorf("kid %q: alg %s does not match key", kid, t.Method.Alg())
+	}
+	return k.key, nil
+}
+
+// AccessTTL — сколько живёт access-токен: столько же держим его sid в списке отозванных.
+func (m *Manager) AccessTTL() time.Duration { return m.accessTTL }
+
 func (m *Manager) ExpiresIn() int64 {
 	return int64(m.accessTTL / time.Second)
 }
@@ -0,0 +1,142 @@
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func edKey(t *testing.T, kid string) Key {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Key{ID: kid, Private: priv, Public: pub}
+}
+
+func rsaKey(t *testing.T, kid string) Key {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Key{ID: kid, Private: priv, Public: &priv.PublicKey}
+}
+
+func jwksServer(t *testing.T, m *Manager, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_ = json.NewEncoder(w).Encode(m.JWKS())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVerifierRotation(t *testing.T) {
+	old, next := edKey(t, "k1"), rsaKey(t, "k2")
+	cfg := Config{Issuer: "goshop-auth", AccessTTL: time.Minute, RefreshTTL: time.Hour}
+
+	// до ротации: подписывает k1, k2 ещё не опубликован
+	cfg.Keys = []Key{old}
+	m := New(cfg)
+	var hits atomic.Int32
+	srv := jwksServer(t, m, &hits)
+	v := NewVerifier(VerifierConfig{JWKSURL: srv.URL, Issuer: "goshop-auth", MinRefresh: time.Nanosecond})
+
+	tokOld, _, _, err := m.GeneratePair("u1", "u1@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, err := v.ParseAndVerify(tokOld); err != nil || c.UserID != "u1" {
+		t.Fatalf("old token: %v", err)
+	}
+
+	// ротация: подписывает k2, k1 остаётся в JWKS для проверки
+	cfg.Keys = []Key{next, {ID: old.ID, Public: old.Public}}
+	*m = *New(cfg)
+	tokNew, _, _, err := m.GeneratePair("u2", "u2@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := hits.Load()
+	if c, err := v.ParseAndVerify(tokNew); err != nil || c.UserID != "u2" {
+		t.Fatalf("new token (unknown kid must trigger refresh): %v", err)
+	}
+	if hits.Load() != before+1 {
+		t.Fatalf("jwks fetched %d times, want 1", hits.Load()-before)
+	}
+	if _, err := v.ParseAndVerify(tokOld); err != nil {
+		t.Fatalf("old token after rotation: %v", err)
+	}
+}
+
+func TestVerifierRejects(t *testing.T) {
+	m := New(Config{Keys: []Key{edKey(t, "k1")}, AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	var hits atomic.Int32
+	v := NewVerifier(VerifierConfig{JWKSURL: jwksServer(t, m, &hits).URL})
+
+	other := New(Config{Keys: []Key{edKey(t, "k1")}, AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	forged, _, _, _ := other.GeneratePair("u1", "", "")
+	if _, err := v.ParseAndVerify(forged); err == nil {
+		t.Error("token signed by foreign key with known kid accepted")
+	}
+
+	hs := New(Config{Secret: "s", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	legacy, _, _, _ := hs.GeneratePair("u1", "", "")
+	if _, err := v.ParseAndVerify(legacy); err == nil {
+		t.Error("HS256 token accepted without secret")
+	}
+	withSecret := NewVerifier(VerifierConfig{JWKSURL: v.cfg.JWKSURL, Secret: "s"})
+	if _, err := withSecret.ParseAndVerify(legacy); err != nil {
+		t.Errorf("HS256 token with secret: %v", err)
+	}
+}
+
+func TestJWKRoundTrip(t *testing.T) {
+	for _, k := range []Key{edKey(t, "ed"), rsaKey(t, "rsa")} {
+		j, err := toJWK(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := j.Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		type equaler interface{ Equal(x crypto.PublicKey) bool }
+		if !k.Public.(equaler).Equal(back.Public) || back.ID != k.ID {
+			t.Errorf("%s: round trip mismatch", k.ID)
+		}
+	}
+}
+
+func TestRolesOnlyInAccess(t *testing.T) {
+	m := New(Config{Keys: []Key{edKey(t, "k1")}, Issuer: "goshop-auth", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+
+	access, refresh, _, err := m.GeneratePair("u1", "u1@example.com", "", RoleSupport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac, err := m.ParseAndVerify(access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ac.HasRole(RoleAdmin, RoleSupport) || ac.HasRole(RoleAdmin) {
+		t.Fatalf("access roles: %v", ac.Roles)
+	}
+	rc, err := m.ParseAndVerify(refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rc.Roles) != 0 {
+		t.Fatalf("refresh carries roles: %v", rc.Roles)
+	}
+}
@@ -0,0 +1,201 @@
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key — ключ подписи с идентификатором kid. Private == nil — ключ только для проверки
+// (выведенный из оборота, но ещё публикуемый в JWKS, пока живут подписанные им токены).
+type Key struct {
+	ID      string
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// KeySpec — ключ в конфиге: PEM-файл приватного (PKCS#8, PKCS#1 для RSA) или публичного (PKIX) ключа.
+type KeySpec struct {
+	Kid            string
+	PrivateKeyFile string
+	PublicKeyFile  string
+}
+
+// LoadKeys читает ключи из файлов; порядок сохраняется.
+func LoadKeys(specs []KeySpec) ([]Key, error) {
+	out := make([]Key, 0, len(specs))
+	seen := make(map[string]bool, len(specs))
+	for _, s := range specs {
+		if s.Kid == "" {
+			return nil, errors.New("jwt key: kid is required")
+		}
+		if seen[s.Kid] {
+			return nil, fmt.Errorf("jwt key %s: duplicate kid", s.Kid)
+		}
+		seen[s.Kid] = true
+
+		var (
+			k   Key
+			err error
+		)
+		switch {
+		case s.PrivateKeyFile != "":
+			k, err = loadPEM(s.PrivateKeyFile, ParsePrivateKeyPEM)
+		case s.PublicKeyFile != "":
+			k, err = loadPEM(s.PublicKeyFile, ParsePublicKeyPEM)
+		default:
+			err = errors.New("private_key_file or public_key_file is required")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %w", s.Kid, err)
+		}
+		k.ID = s.Kid
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+func loadPEM(path string, parse func([]byte) (Key, error)) (Key, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+	return parse(b)
+}
+
+// ParsePrivateKeyPEM — RSA (PKCS#1/PKCS#8) или Ed25519 (PKCS#8).
+func ParsePrivateKeyPEM(b []byte) (Key, error) {
+	blk, _ := pem.Decode(b)
+	if blk == nil {
+		return Key{}, errors.New("no PEM block")
+	}
+	var (
+		priv any
+		err  error
+	)
+	switch blk.Type {
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(blk.Bytes)
+	default:
+		priv, err = x509.ParsePKCS8PrivateKey(blk.Bytes)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("parse private key: %w", err)
+	}
+	switch p := priv.(type) {
+	case *rsa.PrivateKey:
+		return Key{Private: p, Public: &p.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return Key{Private: p, Public: p.Public()}, nil
+	default:
+		return Key{}, fmt.Errorf("unsupported private key type %T", priv)
+	}
+}
+
+// ParsePublicKeyPEM — RSA или Ed25519 в PKIX ("PUBLIC KEY").
+func ParsePublicKeyPEM(b []byte) (Key, error) {
+	blk, _ := pem.Decode(b)
+	if blk == nil {
+		return Key{}, errors.New("no PEM block")
+	}
+	pub, err := x509.ParsePKIXPublicKey(blk.Bytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("parse public key: %w", err)
+	}
+	if _, err := methodFor(pub); err != nil {
+		return Key{}, err
+	}
+	return Key{Public: pub}, nil
+}
+
+// methodFor — алгоритм определяется типом ключа: RSA → RS256, Ed25519 → EdDSA.
+func methodFor(pub crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// JWK — публичный ключ в формате RFC 7517 (только поля, нужные для RS256/EdDSA).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// OKP (Ed25519)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var b64 = base64.RawURLEncoding
+
+func toJWK(k Key) (JWK, error) {
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA", Kid: k.ID, Use: "sig", Alg: jwt.SigningMethodRS256.Alg(),
+			N: b64.EncodeToString(pub.N.Bytes()),
+			E: b64.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP", Kid: k.ID, Use: "sig", Alg: jwt.SigningMethodEdDSA.Alg(),
+			Crv: "Ed25519", X: b64.EncodeToString(pub),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", k.Public)
+	}
+}
+
+// Key восстанавливает публичный ключ из JWK.
+func (j JWK) Key() (Key, error) {
+	k := Key{ID: j.Kid}
+	switch j.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(j.N)
+		if err != nil {
+			return k, fmt.Errorf("jwk %s: n: %w", j.Kid, err)
+		}
+		e, err := b64.DecodeString(j.E)
+		if err != nil {
+			return k, fmt.Errorf("jwk %s: e: %w", j.Kid, err)
+		}
+		eb := new(big.Int).SetBytes(e)
+		if !eb.IsInt64() || eb.Int64() < 3 || eb.Int64() > 1<<31-1 {
+			return k, fmt.Errorf("jwk %s: bad exponent", j.Kid)
+		}
+		k.Public = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(eb.Int64())}
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return k, fmt.Errorf("jwk %s: unsupported curve %q", j.Kid, j.Crv)
+		}
+		x, err := b64.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return k, fmt.Errorf("jwk %s: bad x", j.Kid)
+		}
+		k.Public = ed25519.PublicKey(x)
+	default:
+		return k, fmt.Errorf("jwk %s: unsupported kty %q", j.Kid, j.Kty)
+	}
+	return k, nil
+}
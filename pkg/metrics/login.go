@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LoginMetrics — исходы логина и блокировки за подбор пароля; реализует loginguard.Observer.
+type LoginMetrics struct {
+	attempts *prometheus.CounterVec
+	lockouts *prometheus.CounterVec
+}
+
+func NewLoginMetrics(reg *prometheus.Registry, namespace, service string) *LoginMetrics {
+	m := &LoginMetrics{
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: service,
+			Name:      "login_attempts_total",
+			Help:      "Login attempts by result (success|failure|blocked).",
+		}, []string{"result"}),
+		lockouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: service,
+			Name:      "login_lockouts_total",
+			Help:      "Temporary lockouts after repeated failed logins by scope (account|ip).",
+		}, []string{"scope"}),
+	}
+	reg.MustRegister(m.attempts, m.lockouts)
+	return m
+}
+
+func (m *LoginMetrics) ObserveLogin(result string)  { m.attempts.WithLabelValues(result).Inc() }
+func (m *LoginMetrics) ObserveLockout(scope string) { m.lockouts.WithLabelValues(scope).Inc() }
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RateLimitMetrics — решения лимитера; реализует ratelimit.Observer.
+type RateLimitMetrics struct {
+	decisions *prometheus.CounterVec
+}
+
+func NewRateLimitMetrics(reg *prometheus.Registry, namespace, service string) *RateLimitMetrics {
+	m := &RateLimitMetrics{
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: service,
+			Name:      "ratelimit_decisions_total",
+			Help:      "Rate limiter decisions by rule, principal kind and outcome (allowed|limited|error).",
+		}, []string{"rule", "principal", "decision"}),
+	}
+	reg.MustRegister(m.decisions)
+	return m
+}
+
+func (m *RateLimitMetrics) Observe(rule, principalKind, decision string) {
+	m.decisions.WithLabelValues(rule, principalKind, decision).Inc()
+}
@@ -0,0 +1,90 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+var ErrInvalidRate = errors.New("money: invalid rate")
+
+// Money — сумма в минорных единицах валюты (копейки, центы; для JPY — иены).
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// New — сумма в минорных единицах + код валюты.
+func New(amountMinor int64, code string) (Money, error) {
+	c, err := ParseCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amountMinor, Currency: c}, nil
+}
+
+// Decimal — сумма в основных единицах строкой, без потери точности: 19901 RUB -> "199.01".
+func (m Money) Decimal() string {
+	if m.Currency.Minor == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+	return big.NewRat(m.Amount, m.Currency.Factor()).FloatString(m.Currency.Minor)
+}
+
+// Major — сумма в основных единицах как float64 (для legacy-полей double).
+func (m Money) Major() float64 {
+	return float64(m.Amount) / float64(m.Currency.Factor())
+}
+
+func (m Money) String() string { return m.Decimal() + " " + m.Currency.Code }
+
+// ParseRate — курс из десятичной строки ("92.5", "0.0108"); только > 0.
+func ParseRate(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return r, nil
+}
+
+// Convert переводит m в валюту to по курсу rate (сколько единиц to за одну единицу m.Currency).
+// Учитывает разную разрядность валют, округление — половина от нуля.
+func Convert(m Money, to Currency, rate *big.Rat) (Money, error) {
+	if rate == nil || rate.Sign() <= 0 {
+		return Money{}, ErrInvalidRate
+	}
+	if m.Currency.Code == to.Code {
+		return m, nil
+	}
+
+	v := new(big.Rat).SetInt64(m.Amount)
+	v.Mul(v, rate)
+	v.Mul(v, big.NewRat(to.Factor(), m.Currency.Factor()))
+
+	n, err := round(v)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: n, Currency: to}, nil
+}
+
+func round(v *big.Rat) (int64, error) {
+	num := new(big.Int).Set(v.Num())
+	den := v.Denom()
+
+	neg := num.Sign() < 0
+	num.Abs(num)
+
+	// (2*num + den) / (2*den) — округление половины вверх по модулю
+	num.Mul(num, big.NewInt(2))
+	num.Add(num, den)
+	q := num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+	if neg {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return 0, errors.New("money: amount overflows int64")
+	}
+	return q.Int64(), nil
+}
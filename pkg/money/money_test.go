@@ -0,0 +1,85 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCurrency(t *testing.T) {
+	c, err := ParseCurrency(" rub ")
+	if err != nil || c.Code != "RUB" || c.Minor != 2 {
+		t.Fatalf("ParseCurrency(rub) = %+v, %v", c, err)
+	}
+	if c, _ := ParseCurrency("JPY"); c.Minor != 0 {
+		t.Fatalf("JPY minor = %d, want 0", c.Minor)
+	}
+	if c, _ := ParseCurrency("KWD"); c.Minor != 3 {
+		t.Fatalf("KWD minor = %d, want 3", c.Minor)
+	}
+	for _, bad := range []string{"", "RU", "RUR", "XAU", "rubles"} {
+		if _, err := ParseCurrency(bad); !errors.Is(err, ErrUnknownCurrency) {
+			t.Errorf("ParseCurrency(%q) err = %v, want ErrUnknownCurrency", bad, err)
+		}
+	}
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	cases := []struct {
+		amount int64
+		code   string
+		want   string
+	}{
+		{19901, "RUB", "199.01"},
+		{5, "USD", "0.05"},
+		{1500, "JPY", "1500"},
+		{12345, "KWD", "12.345"},
+		{-250, "EUR", "-2.50"},
+	}
+	for _, tc := range cases {
+		m, err := New(tc.amount, tc.code)
+		if err != nil {
+			t.Fatalf("New(%d, %s): %v", tc.amount, tc.code, err)
+		}
+		if got := m.Decimal(); got != tc.want {
+			t.Errorf("Decimal(%d %s) = %q, want %q", tc.amount, tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		amount   int64
+		from, to string
+		rate     string
+		want     int64
+	}{
+		{1000, "USD", "RUB", "92.5", 92500},    // 10.00 USD -> 925.00 RUB
+		{1000, "USD", "JPY", "150.255", 1503},  // 10.00 USD -> 1502.55 -> 1503 JPY
+		{1000, "JPY", "USD", "0.0066555", 666}, // 1000 JPY -> 6.6555 -> 6.66 USD
+		{1000, "USD", "KWD", "0.30712", 3071},  // 10.00 USD -> 3.0712 -> 3.071 KWD
+		{-1000, "USD", "RUB", "92.505", -92505},
+		{777, "RUB", "RUB", "1", 777},
+	}
+	for _, tc := range cases {
+		m, _ := New(tc.amount, tc.from)
+		rate, err := ParseRate(tc.rate)
+		if err != nil {
+			t.Fatalf("ParseRate(%s): %v", tc.rate, err)
+		}
+		got, err := Convert(m, MustCurrency(tc.to), rate)
+		if err != nil {
+			t.Fatalf("Convert: %v", err)
+		}
+		if got.Amount != tc.want || got.Currency.Code != tc.to {
+			t.Errorf("Convert(%s -> %s @%s) = %s (%d), want %d", m, tc.to, tc.rate, got, got.Amount, tc.want)
+		}
+	}
+}
+
+func TestParseRate_Invalid(t *testing.T) {
+	for _, bad := range []string{"", "abc", "0", "-1.5"} {
+		if _, err := ParseRate(bad); !errors.Is(err, ErrInvalidRate) {
+			t.Errorf("ParseRate(%q) err = %v, want ErrInvalidRate", bad, err)
+		}
+	}
+}
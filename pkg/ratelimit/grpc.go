@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/jwtauth"
+)
+
+// Verifier — проверка access-токена (jwtauth.Manager); nil — принципал только по IP.
+type Verifier interface {
+	ParseAndVerify(token string) (*jwtauth.Claims, error)
+}
+
+// loopback — REST-фасад самого gateway: ходит в свой gRPC через 127.0.0.1 и кладёт IP клиента
+// в x-forwarded-for.
+var loopback = []string{"127.0.0.0/8", "::1/128"}
+
+func parseProxies(ps []string) []*net.IPNet {
+	out := make([]*net.IPNet, 0, len(ps))
+	for _, p := range ps {
+		if _, n, err := net.ParseCIDR(p); err == nil {
+			out = append(out, n)
+			continue
+		}
+		if ip := net.ParseIP(p); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			out = append(out, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return out
+}
+
+func isTrusted(ip string, trusted []*net.IPNet) bool {
+	pip := net.ParseIP(ip)
+	if pip == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(pip) {
+			return true
+		}
+	}
+	return false
+}
+
+// GRPCPrincipal определяет принципала вызова:
+// uid из "authorization: Bearer ..." (если задан verifier и токен валиден), иначе IP пира.
+// x-forwarded-for учитываем только от доверенного пира (trusted: loopback-фасад, ingress) и идём
+// по нему справа налево до первого недоверенного адреса — левые значения присылает сам клиент.
+func GRPCPrincipal(ctx context.Context, v Verifier, trusted []*net.IPNet) Principal {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if v != nil {
+		if vals := md.Get("authorization"); len(vals) > 0 {
+			parts := strings.SplitN(vals[0], " ", 2)
+			if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") && parts[1] != "" {
+				if claims, err := v.ParseAndVerify(parts[1]); err == nil && claims.UserID != "" {
+					return UserPrincipal(claims.UserID)
+				}
+			}
+		}
+	}
+
+	ip := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	if isTrusted(ip, trusted) {
+		var hops []string
+		for _, v := range md.Get("x-forwarded-for") {
+			hops = append(hops, strings.Split(v, ",")...)
+		}
+		for i := len(hops) - 1; i >= 0; i-- {
+			h := strings.TrimSpace(hops[i])
+			if net.ParseIP(h) == nil {
+				break
+			}
+			ip = h
+			if !isTrusted(h, trusted) {
+				break
+			}
+		}
+	}
+	if ip == "" {
+		ip = "unknown"
+	}
+	return IPPrincipal(ip)
+}
+
+// UnaryServerInterceptor отвечает ResourceExhausted, если бакет (метод, принципал) пуст.
+// В header metadata кладём retry-after (секунды) и x-ratelimit-*.
+func UnaryServerInterceptor(l *Limiter, log *slog.Logger, v Verifier) grpc.UnaryServerInterceptor {
+	if log == nil {
+		log = slog.Default()
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		d, ok := check(ctx, l, log, v, info.FullMethod)
+		if d.Limit > 0 {
+			_ = grpc.SetHeader(ctx, decisionMD(d))
+		}
+		if !ok {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInterceptor(l *Limiter, log *slog.Logger, v Verifier) grpc.StreamServerInterceptor {
+	if log == nil {
+		log = slog.Default()
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		d, ok := check(ss.Context(), l, log, v, info.FullMethod)
+		if d.Limit > 0 {
+			_ = ss.SetHeader(decisionMD(d))
+		}
+		if !ok {
+			return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return handler(srv, ss)
+	}
+}
+
+func check(ctx context.Context, l *Limiter, log *slog.Logger, v Verifier, method string) (Decision, bool) {
+	p := GRPCPrincipal(ctx, v, l.trusted)
+	d, err := l.Allow(ctx, method, p)
+	if err != nil {
+		log.Warn("ratelimit: check failed, allowing",
+			slog.String("method", method),
+			slog.String("principal", p.String()),
+			slog.Any("err", err),
+		)
+		return d, true
+	}
+	if !d.Allowed {
+		log.Warn("ratelimit: limited",
+			slog.String("method", method),
+			slog.String("principal", p.String()),
+			slog.Int64("retry_after_ms", d.RetryAfter.Milliseconds()),
+		)
+	}
+	return d, d.Allowed
+}
+
+func decisionMD(d Decision) metadata.MD {
+	md := metadata.Pairs(
+		"x-ratelimit-limit", strconv.Itoa(d.Limit),
+		"x-ratelimit-remaining", strconv.Itoa(d.Remaining),
+	)
+	if !d.Allowed {
+		md.Set("retry-after", strconv.Itoa(d.RetryAfterSeconds()))
+	}
+	return md
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"goshop/pkg/jwtauth"
+)
+
+type stubVerifier map[string]string // token -> uid
+
+func (v stubVerifier) ParseAndVerify(token string) (*jwtauth.Claims, error) {
+	uid, ok := v[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return &jwtauth.Claims{UserID: uid}, nil
+}
+
+func TestGRPCPrincipal(t *testing.T) {
+	v := stubVerifier{"good": "u1"}
+	remote := &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 5555}
+	lo := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 5555}
+	ingress := &net.TCPAddr{IP: net.ParseIP("10.244.1.7"), Port: 5555}
+	trusted := parseProxies(append([]string{"10.244.0.0/16"}, "127.0.0.0/8"))
+
+	cases := []struct {
+		name string
+		addr net.Addr
+		md   metadata.MD
+		v    Verifier
+		want Principal
+	}{
+		{"valid token", remote, metadata.Pairs("authorization", "Bearer good"), v, UserPrincipal("u1")},
+		{"invalid token falls back to ip", remote, metadata.Pairs("authorization", "Bearer bad"), v, IPPrincipal("203.0.113.7")},
+		{"no verifier ignores token", remote, metadata.Pairs("authorization", "Bearer good"), nil, IPPrincipal("203.0.113.7")},
+		{"xff ignored from remote peer", remote, metadata.Pairs("x-forwarded-for", "198.51.100.1"), v, IPPrincipal("203.0.113.7")},
+		{"xff from loopback", lo, metadata.Pairs("x-forwarded-for", "198.51.100.1"), v, IPPrincipal("198.51.100.1")},
+		{"forged xff through ingress", ingress, metadata.Pairs("x-forwarded-for", "1.2.3.4, 198.51.100.1"), v, IPPrincipal("198.51.100.1")},
+		{"trusted hops skipped", ingress, metadata.Pairs("x-forwarded-for", "198.51.100.1, 10.244.3.3"), v, IPPrincipal("198.51.100.1")},
+		{"garbage xff keeps peer", ingress, metadata.Pairs("x-forwarded-for", "not-an-ip"), v, IPPrincipal("10.244.1.7")},
+		{"loopback without xff", lo, nil, v, IPPrincipal("127.0.0.1")},
+		{"no peer", nil, nil, v, IPPrincipal("unknown")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.addr != nil {
+				ctx = peer.NewContext(ctx, &peer.Peer{Addr: tc.addr})
+			}
+			if tc.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tc.md)
+			}
+			if got := GRPCPrincipal(ctx, tc.v, trusted); got != tc.want {
+				t.Fatalf("GRPCPrincipal = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
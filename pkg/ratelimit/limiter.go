@@ -0,0 +1,220 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	pcfg "goshop/pkg/config"
+)
+
+// Limit — token bucket: Rate токенов за Period, ёмкость Burst (по умолчанию = Rate).
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+func (l Limit) valid() bool { return l.Rate > 0 && l.Period > 0 }
+
+// Rule — лимит для метода и типа принципала.
+// Method: полное имя gRPC-метода ("/checkout.v1.Checkout/CreateOrder"),
+// HTTP-маршрут ("POST /v1/orders") или "*".
+// Principal: "user", "ip" или "" (любой).
+type Rule struct {
+	Method    string
+	Principal string
+	Limit     Limit
+}
+
+const (
+	PrincipalUser = "user"
+	PrincipalIP   = "ip"
+)
+
+// Principal — кого лимитируем: пользователя из JWT или IP клиента.
+type Principal struct {
+	Kind string
+	ID   string
+}
+
+func (p Principal) String() string { return p.Kind + ":" + p.ID }
+
+func UserPrincipal(uid string) Principal { return Principal{Kind: PrincipalUser, ID: uid} }
+func IPPrincipal(ip string) Principal    { return Principal{Kind: PrincipalIP, ID: ip} }
+
+type Decision struct {
+	Allowed    bool
+	Limit      int // ёмкость бакета; 0 — метод не лимитируется
+	Remaining  int
+	RetryAfter time.Duration
+	Rule       string // метка для метрик/логов
+}
+
+// Observer — куда отдавать решения лимитера (см. metrics.RateLimitMetrics).
+type Observer interface {
+	Observe(rule, principalKind, decision string)
+}
+
+const (
+	DecisionAllowed = "allowed"
+	DecisionLimited = "limited"
+	DecisionError   = "error"
+)
+
+type Limiter struct {
+	rdb     *redis.Client
+	rules   []Rule
+	prefix  string
+	obs     Observer
+	trusted []*net.IPNet
+}
+
+type Option func(*Limiter)
+
+func WithPrefix(p string) Option     { return func(l *Limiter) { l.prefix = p } }
+func WithObserver(o Observer) Option { return func(l *Limiter) { l.obs = o } }
+
+// WithTrustedProxies — CIDR или адреса прокси, чьему x-forwarded-for верит gRPC-принципал
+// (в дополнение к loopback); некорректные пропускаются — их отсекает валидация конфига.
+func WithTrustedProxies(ps ...string) Option {
+	return func(l *Limiter) { l.trusted = append(l.trusted, parseProxies(ps)...) }
+}
+
+func New(rdb *redis.Client, rules []Rule, opts ...Option) *Limiter {
+	l := &Limiter{rdb: rdb, rules: rules, prefix: "rl:", trusted: parseProxies(loopback)}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Match — первое подходящее правило в порядке объявления.
+func (l *Limiter) Match(method string, p Principal) (Rule, bool) {
+	for _, r := range l.rules {
+		if r.Method != "*" && r.Method != method {
+			continue
+		}
+		if r.Principal != "" && r.Principal != p.Kind {
+			continue
+		}
+		if !r.Limit.valid() {
+			continue
+		}
+		return r, true
+	}
+	return Rule{}, false
+}
+
+// Allow списывает один токен из бакета (method, principal).
+// При ошибке Redis лимитер пропускает запрос (fail-open) и возвращает ошибку для логов.
+func (l *Limiter) Allow(ctx context.Context, method string, p Principal) (Decision, error) {
+	rule, ok := l.Match(method, p)
+	if !ok {
+		return Decision{Allowed: true}, nil
+	}
+
+	burst := rule.Limit.Burst
+	if burst <= 0 {
+		burst = rule.Limit.Rate
+	}
+	perMS := float64(rule.Limit.Rate) / float64(rule.Limit.Period.Milliseconds())
+	key := l.prefix + rule.Method + ":" + p.String()
+
+	d := Decision{Allowed: true, Limit: burst, Rule: rule.Method}
+
+	if l.rdb == nil {
+		return d, errors.New("ratelimit: redis client is nil")
+	}
+	res, err := tokenBucket.Run(ctx, l.rdb, []string{key}, perMS, burst, 1).Int64Slice()
+	if err != nil {
+		l.observe(rule.Method, p.Kind, DecisionError)
+		return d, fmt.Errorf("ratelimit: eval: %w", err)
+	}
+
+	d.Allowed = res[0] == 1
+	d.Remaining = int(res[1])
+	d.RetryAfter = time.Duration(res[2]) * time.Millisecond
+	if d.Allowed {
+		l.observe(rule.Method, p.Kind, DecisionAllowed)
+	} else {
+		l.observe(rule.Method, p.Kind, DecisionLimited)
+	}
+	return d, nil
+}
+
+func (l *Limiter) observe(rule, kind, decision string) {
+	if l.obs != nil {
+		l.obs.Observe(rule, kind, decision)
+	}
+}
+
+// RetryAfterSeconds — значение для заголовка Retry-After (минимум 1).
+func (d Decision) RetryAfterSeconds() int {
+	s := int((d.RetryAfter + time.Second - 1) / time.Second)
+	if s < 1 {
+		s = 1
+	}
+	return s
+}
+
+// RulesFromConfig переводит конфиг в правила; default (если задан) идёт последним как "*".
+func RulesFromConfig(c pcfg.RateLimit) []Rule {
+	out := make([]Rule, 0, len(c.Rules)+1)
+	for _, r := range c.Rules {
+		out = append(out, Rule{
+			Method:    strings.TrimSpace(r.Method),
+			Principal: strings.ToLower(strings.TrimSpace(r.Principal)),
+			Limit:     Limit{Rate: r.Rate, Period: r.Period, Burst: r.Burst},
+		})
+	}
+	if c.Default.Rate > 0 {
+		out = append(out, Rule{
+			Method:    "*",
+			Principal: strings.ToLower(strings.TrimSpace(c.Default.Principal)),
+			Limit:     Limit{Rate: c.Default.Rate, Period: c.Default.Period, Burst: c.Default.Burst},
+		})
+	}
+	return out
+}
+
+// tokenBucket: KEYS[1] — бакет; ARGV: токенов в мс, ёмкость, стоимость.
+// Время берём из Redis (TIME), чтобы реплики сервиса не зависели от своих часов.
+// Возвращает {allowed, remaining, retry_after_ms}.
+var tokenBucket = redis.NewScript(`
+local rate  = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost  = tonumber(ARGV[3])
+
+local t   = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local b      = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(b[1])
+local ts     = tonumber(b[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry   = 0
+if tokens >= cost then
+  tokens  = tokens - cost
+  allowed = 1
+else
+  retry = math.ceil((cost - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + 1000)
+
+return {allowed, math.floor(tokens), retry}
+`)
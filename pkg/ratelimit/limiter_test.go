@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	pcfg "goshop/pkg/config"
+)
+
+func TestMatch(t *testing.T) {
+	l := New(nil, []Rule{
+		{Method: "/checkout.v1.Checkout/CreateOrder", Principal: PrincipalUser, Limit: Limit{Rate: 5, Period: time.Minute}},
+		{Method: "/checkout.v1.Checkout/CreateOrder", Principal: PrincipalIP, Limit: Limit{Rate: 0, Period: time.Minute}}, // невалидный — пропускается
+		{Method: "POST /v1/orders", Limit: Limit{Rate: 3, Period: time.Second}},
+		{Method: "*", Principal: PrincipalIP, Limit: Limit{Rate: 100, Period: time.Minute}},
+	})
+
+	cases := []struct {
+		name     string
+		method   string
+		p        Principal
+		wantOK   bool
+		wantRate int
+	}{
+		{"user rule", "/checkout.v1.Checkout/CreateOrder", UserPrincipal("u1"), true, 5},
+		{"invalid rule falls through to default", "/checkout.v1.Checkout/CreateOrder", IPPrincipal("10.0.0.1"), true, 100},
+		{"any principal", "POST /v1/orders", UserPrincipal("u1"), true, 3},
+		{"default by ip", "GET /v1/orders", IPPrincipal("10.0.0.1"), true, 100},
+		{"no rule for user", "GET /v1/orders", UserPrincipal("u1"), false, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, ok := l.Match(tc.method, tc.p)
+			if ok != tc.wantOK || r.Limit.Rate != tc.wantRate {
+				t.Fatalf("Match = (%+v, %v), want rate %d ok %v", r, ok, tc.wantRate, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestRulesFromConfig(t *testing.T) {
+	rules := RulesFromConfig(pcfg.RateLimit{
+		Enabled: true,
+		Default: pcfg.RateLimitRule{Principal: " IP ", Rate: 50, Period: time.Minute},
+		Rules: []pcfg.RateLimitRule{
+			{Method: " POST /v1/orders ", Principal: "User", Rate: 5, Period: time.Minute, Burst: 10},
+		},
+	})
+	if len(rules) != 2 {
+		t.Fatalf("rules = %+v", rules)
+	}
+	want := Rule{Method: "POST /v1/orders", Principal: PrincipalUser, Limit: Limit{Rate: 5, Period: time.Minute, Burst: 10}}
+	if rules[0] != want {
+		t.Fatalf("rules[0] = %+v, want %+v", rules[0], want)
+	}
+	if rules[1].Method != "*" || rules[1].Principal != PrincipalIP || rules[1].Limit.Rate != 50 {
+		t.Fatalf("default rule = %+v", rules[1])
+	}
+
+	if got := RulesFromConfig(pcfg.RateLimit{Enabled: true}); len(got) != 0 {
+		t.Fatalf("without default: %+v", got)
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	cases := []struct {
+		in   time.Duration
+		want int
+	}{
+		{0, 1},
+		{300 * time.Millisecond, 1},
+		{time.Second, 1},
+		{1001 * time.Millisecond, 2},
+		{59500 * time.Millisecond, 60},
+	}
+	for _, tc := range cases {
+		if got := (Decision{RetryAfter: tc.in}).RetryAfterSeconds(); got != tc.want {
+			t.Fatalf("RetryAfterSeconds(%v) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
+
+type countingObserver map[string]int
+
+func (o countingObserver) Observe(rule, kind, decision string) { o[rule+"|"+kind+"|"+decision]++ }
+
+func TestAllow_TokenBucket(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	obs := countingObserver{}
+	l := New(rdb, []Rule{{Method: "POST /v1/orders", Limit: Limit{Rate: 2, Period: time.Minute}}}, WithObserver(obs))
+	ctx := context.Background()
+	p := IPPrincipal("10.0.0.1")
+
+	for i := 0; i < 2; i++ {
+		d, err := l.Allow(ctx, "POST /v1/orders", p)
+		if err != nil || !d.Allowed || d.Limit != 2 || d.Remaining != 1-i {
+			t.Fatalf("call %d: %+v, %v", i, d, err)
+		}
+	}
+	d, err := l.Allow(ctx, "POST /v1/orders", p)
+	if err != nil || d.Allowed {
+		t.Fatalf("third call: %+v, %v", d, err)
+	}
+	// 2 токена в минуту — следующий через ~30s
+	if d.RetryAfter <= 0 || d.RetryAfter > 30*time.Second {
+		t.Fatalf("retry after = %v", d.RetryAfter)
+	}
+
+	// бакеты раздельные по принципалу
+	if d, _ := l.Allow(ctx, "POST /v1/orders", IPPrincipal("10.0.0.2")); !d.Allowed {
+		t.Fatalf("other principal limited: %+v", d)
+	}
+	// метод без правила не лимитируется и не ходит в Redis
+	if d, err := l.Allow(ctx, "GET /v1/orders", p); err != nil || !d.Allowed || d.Limit != 0 {
+		t.Fatalf("unmatched method: %+v, %v", d, err)
+	}
+
+	if obs["POST /v1/orders|ip|allowed"] != 3 || obs["POST /v1/orders|ip|limited"] != 1 {
+		t.Fatalf("observed: %v", obs)
+	}
+}
+
+func TestAllow_FailOpen(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	obs := countingObserver{}
+	l := New(rdb, []Rule{{Method: "*", Limit: Limit{Rate: 1, Period: time.Minute}}}, WithObserver(obs))
+	mr.Close()
+
+	d, err := l.Allow(context.Background(), "POST /v1/orders", IPPrincipal("10.0.0.1"))
+	if err == nil || !d.Allowed {
+		t.Fatalf("redis down: %+v, %v", d, err)
+	}
+	if obs["*|ip|error"] != 1 {
+		t.Fatalf("observed: %v", obs)
+	}
+}
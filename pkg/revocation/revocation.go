@@ -0,0 +1,111 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix — общий для издателя (users) и проверяющих сервисов: ключи пишет один, читают все.
+const DefaultPrefix = "jwt:revoked:sid:"
+
+// List — отозванные сессии (sid из access-токена) в Redis. Запись живёт access TTL: позже
+// токены сессии истекут сами. Проверка кэшируется локально: отзыв необратим и кэшируется
+// до истечения записи, «не отозван» — на cacheTTL (столько токен может прожить после logout).
+type List struct {
+	rdb      *redis.Client
+	prefix   string
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]entry
+}
+
+type entry struct {
+	revoked bool
+	until   time.Time
+}
+
+type Option func(*List)
+
+func WithPrefix(p string) Option          { return func(l *List) { l.prefix = p } }
+func WithCacheTTL(d time.Duration) Option { return func(l *List) { l.cacheTTL = d } }
+
+func New(rdb *redis.Client, opts ...Option) *List {
+	l := &List{rdb: rdb, prefix: DefaultPrefix, cacheTTL: 5 * time.Second, cache: map[string]entry{}}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Revoke — отозвать сессии на ttl (= access TTL издателя).
+func (l *List) Revoke(ctx context.Context, ttl time.Duration, sids ...string) error {
+	if len(sids) == 0 {
+		return nil
+	}
+	if ttl <= 0 {
+		return errors.New("revocation: ttl must be positive")
+	}
+	pipe := l.rdb.Pipeline()
+	for _, sid := range sids {
+		pipe.Set(ctx, l.prefix+sid, 1, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("revocation: set: %w", err)
+	}
+
+	until := time.Now().Add(ttl)
+	l.mu.Lock()
+	for _, sid := range sids {
+		l.cache[sid] = entry{revoked: true, until: until}
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// IsRevoked — отозвана ли сессия sid. Пустой sid (токены, выданные до появления sid) — нет.
+func (l *List) IsRevoked(ctx context.Context, sid string) (bool, error) {
+	if sid == "" {
+		return false, nil
+	}
+	now := time.Now()
+
+	l.mu.Lock()
+	e, ok := l.cache[sid]
+	l.mu.Unlock()
+	if ok && now.Before(e.until) {
+		return e.revoked, nil
+	}
+
+	ttl, err := l.rdb.PTTL(ctx, l.prefix+sid).Result()
+	if err != nil {
+		return false, fmt.Errorf("revocation: pttl: %w", err)
+	}
+	// -2: ключа нет; -1: без срока (не пишем так, но считаем отзывом)
+	e = entry{revoked: ttl != -2, until: now.Add(l.cacheTTL)}
+	if ttl > 0 {
+		e.until = now.Add(ttl)
+	}
+
+	l.mu.Lock()
+	if len(l.cache) > 100_000 {
+		l.sweep(now)
+	}
+	l.cache[sid] = e
+	l.mu.Unlock()
+	return e.revoked, nil
+}
+
+// sweep — выбросить протухшие записи; вызывается под mu.
+func (l *List) sweep(now time.Time) {
+	for k, e := range l.cache {
+		if !now.Before(e.until) {
+			delete(l.cache, k)
+		}
+	}
+}
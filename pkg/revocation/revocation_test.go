@@ -0,0 +1,102 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestList(t *testing.T, opts ...Option) (*List, *miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return New(rdb, opts...), mr, rdb
+}
+
+func TestList_Revoke(t *testing.T) {
+	ctx := context.Background()
+	l, mr, rdb := newTestList(t)
+
+	if err := l.Revoke(ctx, time.Minute, "s1", "s2"); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(DefaultPrefix + "s1"); ttl != time.Minute {
+		t.Fatalf("key ttl = %v, want 1m", ttl)
+	}
+	if err := l.Revoke(ctx, 0, "s3"); err == nil {
+		t.Fatal("Revoke with zero ttl: nil error")
+	}
+
+	// другая реплика без локального кэша видит отзыв через Redis
+	other := New(rdb)
+	for sid, want := range map[string]bool{"s1": true, "s2": true, "s3": false, "": false} {
+		got, err := other.IsRevoked(ctx, sid)
+		if err != nil || got != want {
+			t.Fatalf("IsRevoked(%q) = %v, %v; want %v", sid, got, err, want)
+		}
+	}
+}
+
+// «Не отозван» кэшируется на cacheTTL: отзыв с другой реплики виден не сразу, а после истечения кэша.
+func TestList_CacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	l, _, rdb := newTestList(t, WithCacheTTL(50*time.Millisecond))
+
+	if revoked, err := l.IsRevoked(ctx, "s1"); err != nil || revoked {
+		t.Fatalf("before revoke: %v, %v", revoked, err)
+	}
+	if err := New(rdb).Revoke(ctx, time.Minute, "s1"); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, _ := l.IsRevoked(ctx, "s1"); revoked {
+		t.Fatal("cached 'not revoked' ignored")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if revoked, err := l.IsRevoked(ctx, "s1"); err != nil || !revoked {
+		t.Fatalf("after cache expiry: %v, %v; want revoked", revoked, err)
+	}
+}
+
+// Отзыв кэшируется до истечения записи в Redis, после — сессия снова не отозвана (токены уже истекли).
+func TestList_RevokedEntryExpires(t *testing.T) {
+	ctx := context.Background()
+	l, mr, _ := newTestList(t)
+
+	if err := l.Revoke(ctx, 50*time.Millisecond, "s1"); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, _ := l.IsRevoked(ctx, "s1"); !revoked {
+		t.Fatal("revoked session not revoked")
+	}
+	time.Sleep(60 * time.Millisecond)
+	mr.FastForward(60 * time.Millisecond)
+	if revoked, err := l.IsRevoked(ctx, "s1"); err != nil || revoked {
+		t.Fatalf("after entry expiry: %v, %v; want not revoked", revoked, err)
+	}
+}
+
+// Redis недоступен: ошибка (вызывающий пропускает запрос — fail-open), но уже известный отзыв
+// из локального кэша действует.
+func TestList_RedisDown(t *testing.T) {
+	ctx := context.Background()
+	l, mr, _ := newTestList(t)
+
+	if err := l.Revoke(ctx, time.Minute, "s1"); err != nil {
+		t.Fatal(err)
+	}
+	mr.Close()
+
+	if revoked, err := l.IsRevoked(ctx, "s1"); err != nil || !revoked {
+		t.Fatalf("cached revoke with redis down: %v, %v; want revoked", revoked, err)
+	}
+	if revoked, err := l.IsRevoked(ctx, "s2"); err == nil || revoked {
+		t.Fatalf("unknown session with redis down: %v, %v; want error", revoked, err)
+	}
+	if err := l.Revoke(ctx, time.Minute, "s3"); err == nil {
+		t.Fatal("Revoke with redis down: nil error")
+	}
+}
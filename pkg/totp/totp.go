@@ -0,0 +1,85 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Параметры, которые понимают все приложения-аутентификаторы (Google Authenticator и др.):
+// RFC 6238, HMAC-SHA1, 6 цифр, шаг 30s.
+const (
+	Digits = 6
+	Period = 30 * time.Second
+)
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret — 160 бит (длина ключа HMAC-SHA1, рекомендация RFC 4226).
+func NewSecret() ([]byte, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// EncodeSecret — base32 без паддинга, как его вводят в приложение вручную.
+func EncodeSecret(secret []byte) string { return b32.EncodeToString(secret) }
+
+// URI — otpauth://totp/<issuer>:<account>?secret=...&issuer=... для QR-кода.
+func URI(issuer, account string, secret []byte) string {
+	q := url.Values{}
+	q.Set("secret", EncodeSecret(secret))
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(Digits))
+	q.Set("period", fmt.Sprint(int(Period/time.Second)))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// Step — номер 30-секундного интервала для t.
+func Step(t time.Time) int64 { return t.Unix() / int64(Period/time.Second) }
+
+// Code — код для интервала step (RFC 4226, dynamic truncation).
+func Code(secret []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	off := sum[len(sum)-1] & 0x0f
+	v := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, v%1_000_000)
+}
+
+// Validate ищет code в окне ±skew интервалов вокруг t и возвращает совпавший step.
+// Повторное использование кода вызывающий отсекает сам: принимает только step больше прошлого.
+func Validate(secret []byte, code string, t time.Time, skew int) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+	now := Step(t)
+	for d := -skew; d <= skew; d++ {
+		s := now + int64(d)
+		if subtle.ConstantTimeCompare([]byte(Code(secret, s)), []byte(code)) == 1 {
+			return s, true
+		}
+	}
+	return 0, false
+}
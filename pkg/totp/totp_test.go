@@ -0,0 +1,57 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// RFC 6238, Appendix B (SHA1): последние 6 цифр восьмизначных кодов.
+func TestCodeRFC6238(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, c := range cases {
+		if got := Code(secret, Step(time.Unix(c.unix, 0))); got != c.want {
+			t.Errorf("T=%d: got %s, want %s", c.unix, got, c.want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	prev := Code(secret, Step(now)-1)
+
+	if s, ok := Validate(secret, prev, now, 1); !ok || s != Step(now)-1 {
+		t.Fatalf("previous step code rejected: %v %d", ok, s)
+	}
+	if _, ok := Validate(secret, prev, now, 0); ok {
+		t.Fatal("previous step code accepted without skew")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Fatal("short code accepted")
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("goshop", "a@example.com", []byte("12345678901234567890")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/goshop:a@example.com" {
+		t.Errorf("uri = %s", u)
+	}
+	if got := u.Query().Get("secret"); got != "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" {
+		t.Errorf("secret = %s", got)
+	}
+}
@@ -24,10 +24,13 @@ const (
 type OrderStatus int32
 
 const (
-	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
-	OrderStatus_ORDER_STATUS_NEW         OrderStatus = 1
-	OrderStatus_ORDER_STATUS_PAID        OrderStatus = 2
-	OrderStatus_ORDER_STATUS_CANCELLED   OrderStatus = 3
+	OrderStatus_ORDER_STATUS_UNSPECIFIED    OrderStatus = 0
+	OrderStatus_ORDER_STATUS_NEW            OrderStatus = 1
+	OrderStatus_ORDER_STATUS_PAID           OrderStatus = 2
+	OrderStatus_ORDER_STATUS_CANCELLED      OrderStatus = 3
+	OrderStatus_ORDER_STATUS_SHIPPED        OrderStatus = 4
+	OrderStatus_ORDER_STATUS_DELIVERED      OrderStatus = 5
+	OrderStatus_ORDER_STATUS_PAYMENT_FAILED OrderStatus = 6 // оплата не прошла, можно повторить (RetryPayment)
 )
 
 // Enum value maps for OrderStatus.
@@ -37,12 +40,18 @@ var (
 		1: "ORDER_STATUS_NEW",
 		2: "ORDER_STATUS_PAID",
 		3: "ORDER_STATUS_CANCELLED",
+		4: "ORDER_STATUS_SHIPPED",
+		5: "ORDER_STATUS_DELIVERED",
+		6: "ORDER_STATUS_PAYMENT_FAILED",
 	}
 	OrderStatus_value = map[string]int32{
-		"ORDER_STATUS_UNSPECIFIED": 0,
-		"ORDER_STATUS_NEW":         1,
-		"ORDER_STATUS_PAID":        2,
-		"ORDER_STATUS_CANCELLED":   3,
+		"ORDER_STATUS_UNSPECIFIED":    0,
+		"ORDER_STATUS_NEW":            1,
+		"ORDER_STATUS_PAID":           2,
+		"ORDER_STATUS_CANCELLED":      3,
+		"ORDER_STATUS_SHIPPED":        4,
+		"ORDER_STATUS_DELIVERED":      5,
+		"ORDER_STATUS_PAYMENT_FAILED": 6,
 	}
 )
 
@@ -73,30 +82,887 @@ func (OrderStatus) EnumDescriptor() ([]byte, []int) {
 	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{0}
 }
 
+// Money — точная сумма: units_minor в минорных единицах currency (ISO-4217).
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UnitsMinor    int64                  `protobuf:"varint,1,opt,name=units_minor,json=unitsMinor,proto3" json:"units_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetUnitsMinor() int64 {
+	if x != nil {
+		return x.UnitsMinor
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Address — адрес доставки; country — ISO 3166-1 alpha-2.
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Recipient     string                 `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Phone         string                 `protobuf:"bytes,2,opt,name=phone,proto3" json:"phone,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	City          string                 `protobuf:"bytes,5,opt,name=city,proto3" json:"city,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Line1         string                 `protobuf:"bytes,7,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,8,opt,name=line2,proto3" json:"line2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Address) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Address) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+// Shipment — доставка: pending (после оплаты) -> shipped -> delivered.
+type Shipment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Status         string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Carrier        string                 `protobuf:"bytes,2,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,3,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	ShippedAt      string                 `protobuf:"bytes,4,opt,name=shipped_at,json=shippedAt,proto3" json:"shipped_at,omitempty"`       // RFC3339, пусто — ещё не отправлен
+	DeliveredAt    string                 `protobuf:"bytes,5,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"` // RFC3339, пусто — ещё не доставлен
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Shipment) Reset() {
+	*x = Shipment{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shipment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shipment) ProtoMessage() {}
+
+func (x *Shipment) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shipment.ProtoReflect.Descriptor instead.
+func (*Shipment) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Shipment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Shipment) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *Shipment) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *Shipment) GetShippedAt() string {
+	if x != nil {
+		return x.ShippedAt
+	}
+	return ""
+}
+
+func (x *Shipment) GetDeliveredAt() string {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return ""
+}
+
+// Payment — последняя попытка оплаты заказа (из payments).
+type Payment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // confirmed|failed
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // причина отказа (например, insufficient_funds); пусто для confirmed
+	Amount        *Money                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339
+	Attempt       int32                  `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`                     // номер попытки, с 1
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Payment) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Payment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Payment) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Payment) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Payment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Payment) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
 type CreateOrderRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // опционально: заказ создаётся на владельца токена, чужой user_id — PermissionDenied
+	AmountCents     int64                  `protobuf:"varint,2,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	Currency        string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	ShippingAddress *Address               `protobuf:"bytes,4,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"` // опционально
+	PromoCode       string                 `protobuf:"bytes,5,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`                   // опционально; amount_cents — сумма до скидки
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateOrderRequest) Reset() {
+	*x = CreateOrderRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderRequest) ProtoMessage() {}
+
+func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *CreateOrderRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+type CreateOrderResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	OrderId  string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status   OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=checkout.v1.OrderStatus" json:"status,omitempty"`
+	Currency string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Deprecated: Marked as deprecated in services/gateway/api/checkoutpb/checkout.proto.
+	TotalAmount   float64 `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"` // используйте total; заполняется до v2
+	CreatedAt     string  `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Total         *Money  `protobuf:"bytes,6,opt,name=total,proto3" json:"total,omitempty"`       // к оплате, после скидки
+	Discount      *Money  `protobuf:"bytes,7,opt,name=discount,proto3" json:"discount,omitempty"` // пусто — без промо-кода
+	PromoCode     string  `protobuf:"bytes,8,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderResponse) Reset() {
+	*x = CreateOrderResponse{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderResponse) ProtoMessage() {}
+
+func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateOrderResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CreateOrderResponse) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *CreateOrderResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in services/gateway/api/checkoutpb/checkout.proto.
+func (x *CreateOrderResponse) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *CreateOrderResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *CreateOrderResponse) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *CreateOrderResponse) GetDiscount() *Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *CreateOrderResponse) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	OrderId  string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId   string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status   OrderStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=checkout.v1.OrderStatus" json:"status,omitempty"`
+	Currency string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Deprecated: Marked as deprecated in services/gateway/api/checkoutpb/checkout.proto.
+	TotalAmount     float64   `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"` // используйте total; заполняется до v2
+	CreatedAt       string    `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       string    `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Total           *Money    `protobuf:"bytes,8,opt,name=total,proto3" json:"total,omitempty"`
+	ShippingAddress *Address  `protobuf:"bytes,9,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	Shipment        *Shipment `protobuf:"bytes,10,opt,name=shipment,proto3" json:"shipment,omitempty"`
+	Discount        *Money    `protobuf:"bytes,11,opt,name=discount,proto3" json:"discount,omitempty"` // пусто — без промо-кода
+	PromoCode       string    `protobuf:"bytes,12,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	Payment         *Payment  `protobuf:"bytes,13,opt,name=payment,proto3" json:"payment,omitempty"` // пусто — оплаты ещё не было или payments недоступен
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetOrderResponse) Reset() {
+	*x = GetOrderResponse{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderResponse) ProtoMessage() {}
+
+func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderResponse) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetOrderResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *GetOrderResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetOrderResponse) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *GetOrderResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in services/gateway/api/checkoutpb/checkout.proto.
+func (x *GetOrderResponse) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *GetOrderResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *GetOrderResponse) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *GetOrderResponse) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetShipment() *Shipment {
+	if x != nil {
+		return x.Shipment
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetDiscount() *Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *GetOrderResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+// RetryPaymentRequest — повтор оплаты заказа в статусе PAYMENT_FAILED.
+type RetryPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryPaymentRequest) Reset() {
+	*x = RetryPaymentRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPaymentRequest) ProtoMessage() {}
+
+func (x *RetryPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPaymentRequest.ProtoReflect.Descriptor instead.
+func (*RetryPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RetryPaymentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderStatusRequest) Reset() {
+	*x = GetOrderStatusRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderStatusRequest) ProtoMessage() {}
+
+func (x *GetOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetOrderStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        OrderStatus            `protobuf:"varint,1,opt,name=status,proto3,enum=checkout.v1.OrderStatus" json:"status,omitempty"` // UNKNOWN если ключа нет в кэше
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderStatusResponse) Reset() {
+	*x = GetOrderStatusResponse{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderStatusResponse) ProtoMessage() {}
+
+func (x *GetOrderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOrderStatusResponse) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+type WatchOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchOrderStatusRequest) Reset() {
+	*x = WatchOrderStatusRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOrderStatusRequest) ProtoMessage() {}
+
+func (x *WatchOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchOrderStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type WatchOrderStatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	AmountCents   int64                  `protobuf:"varint,2,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
-	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,1,opt,name=status,proto3,enum=checkout.v1.OrderStatus" json:"status,omitempty"`
+	Heartbeat     bool                   `protobuf:"varint,2,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"` // true — keepalive, статус не менялся
+	At            string                 `protobuf:"bytes,3,opt,name=at,proto3" json:"at,omitempty"`                // RFC3339, время отправки сообщения
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrderRequest) Reset() {
-	*x = CreateOrderRequest{}
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[0]
+func (x *WatchOrderStatusResponse) Reset() {
+	*x = WatchOrderStatusResponse{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrderRequest) String() string {
+func (x *WatchOrderStatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrderRequest) ProtoMessage() {}
+func (*WatchOrderStatusResponse) ProtoMessage() {}
 
-func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[0]
+func (x *WatchOrderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -107,58 +973,59 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use WatchOrderStatusResponse.ProtoReflect.Descriptor instead.
+func (*WatchOrderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *CreateOrderRequest) GetUserId() string {
+func (x *WatchOrderStatusResponse) GetStatus() OrderStatus {
 	if x != nil {
-		return x.UserId
+		return x.Status
 	}
-	return ""
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
 }
 
-func (x *CreateOrderRequest) GetAmountCents() int64 {
+func (x *WatchOrderStatusResponse) GetHeartbeat() bool {
 	if x != nil {
-		return x.AmountCents
+		return x.Heartbeat
 	}
-	return 0
+	return false
 }
 
-func (x *CreateOrderRequest) GetCurrency() string {
+func (x *WatchOrderStatusResponse) GetAt() string {
 	if x != nil {
-		return x.Currency
+		return x.At
 	}
 	return ""
 }
 
-type CreateOrderResponse struct {
+type CartItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=checkout.v1.OrderStatus" json:"status,omitempty"`
-	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
-	TotalAmount   float64                `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Sku           string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice     *Money                 `protobuf:"bytes,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	LineTotal     *Money                 `protobuf:"bytes,5,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
+	Available     bool                   `protobuf:"varint,6,opt,name=available,proto3" json:"available,omitempty"` // false — SKU пропал из каталога или в другой валюте; в total не входит
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrderResponse) Reset() {
-	*x = CreateOrderResponse{}
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[1]
+func (x *CartItem) Reset() {
+	*x = CartItem{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrderResponse) String() string {
+func (x *CartItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrderResponse) ProtoMessage() {}
+func (*CartItem) ProtoMessage() {}
 
-func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[1]
+func (x *CartItem) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -169,68 +1036,78 @@ func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
-func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
-	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
+func (*CartItem) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *CreateOrderResponse) GetOrderId() string {
+func (x *CartItem) GetSku() string {
 	if x != nil {
-		return x.OrderId
+		return x.Sku
 	}
 	return ""
 }
 
-func (x *CreateOrderResponse) GetStatus() OrderStatus {
+func (x *CartItem) GetName() string {
 	if x != nil {
-		return x.Status
+		return x.Name
 	}
-	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+	return ""
 }
 
-func (x *CreateOrderResponse) GetCurrency() string {
+func (x *CartItem) GetQuantity() int32 {
 	if x != nil {
-		return x.Currency
+		return x.Quantity
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateOrderResponse) GetTotalAmount() float64 {
+func (x *CartItem) GetUnitPrice() *Money {
 	if x != nil {
-		return x.TotalAmount
+		return x.UnitPrice
 	}
-	return 0
+	return nil
 }
 
-func (x *CreateOrderResponse) GetCreatedAt() string {
+func (x *CartItem) GetLineTotal() *Money {
 	if x != nil {
-		return x.CreatedAt
+		return x.LineTotal
 	}
-	return ""
+	return nil
 }
 
-type GetOrderRequest struct {
+func (x *CartItem) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+type Cart struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items         []*CartItem            `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Total         *Money                 `protobuf:"bytes,3,opt,name=total,proto3" json:"total,omitempty"`                          // пусто для пустой корзины
+	ExpiresAt     string                 `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // RFC3339; TTL продлевается при каждом изменении
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderRequest) Reset() {
-	*x = GetOrderRequest{}
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[2]
+func (x *Cart) Reset() {
+	*x = Cart{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderRequest) String() string {
+func (x *Cart) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderRequest) ProtoMessage() {}
+func (*Cart) ProtoMessage() {}
 
-func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[2]
+func (x *Cart) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -241,46 +1118,61 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use Cart.ProtoReflect.Descriptor instead.
+func (*Cart) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GetOrderRequest) GetOrderId() string {
+func (x *Cart) GetUserId() string {
 	if x != nil {
-		return x.OrderId
+		return x.UserId
 	}
 	return ""
 }
 
-type GetOrderResponse struct {
+func (x *Cart) GetItems() []*CartItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Cart) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Cart) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type GetCartRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=checkout.v1.OrderStatus" json:"status,omitempty"`
-	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
-	TotalAmount   float64                `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderResponse) Reset() {
-	*x = GetOrderResponse{}
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[3]
+func (x *GetCartRequest) Reset() {
+	*x = GetCartRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderResponse) String() string {
+func (x *GetCartRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderResponse) ProtoMessage() {}
+func (*GetCartRequest) ProtoMessage() {}
 
-func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[3]
+func (x *GetCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -291,82 +1183,161 @@ func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
-func (*GetOrderResponse) Descriptor() ([]byte, []int) {
-	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use GetCartRequest.ProtoReflect.Descriptor instead.
+func (*GetCartRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *GetOrderResponse) GetOrderId() string {
+func (x *GetCartRequest) GetUserId() string {
 	if x != nil {
-		return x.OrderId
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *GetOrderResponse) GetUserId() string {
+type AddCartItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Sku           string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"` // прибавляется к текущему
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCartItemRequest) Reset() {
+	*x = AddCartItemRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCartItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCartItemRequest) ProtoMessage() {}
+
+func (x *AddCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[16]
 	if x != nil {
-		return x.UserId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *GetOrderResponse) GetStatus() OrderStatus {
+// Deprecated: Use AddCartItemRequest.ProtoReflect.Descriptor instead.
+func (*AddCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AddCartItemRequest) GetUserId() string {
 	if x != nil {
-		return x.Status
+		return x.UserId
 	}
-	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+	return ""
 }
 
-func (x *GetOrderResponse) GetCurrency() string {
+func (x *AddCartItemRequest) GetSku() string {
 	if x != nil {
-		return x.Currency
+		return x.Sku
 	}
 	return ""
 }
 
-func (x *GetOrderResponse) GetTotalAmount() float64 {
+func (x *AddCartItemRequest) GetQuantity() int32 {
 	if x != nil {
-		return x.TotalAmount
+		return x.Quantity
 	}
 	return 0
 }
 
-func (x *GetOrderResponse) GetCreatedAt() string {
+type UpdateCartItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Sku           string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"` // новое значение; 0 — удалить позицию
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCartItemRequest) Reset() {
+	*x = UpdateCartItemRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCartItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCartItemRequest) ProtoMessage() {}
+
+func (x *UpdateCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[17]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCartItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateCartItemRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *GetOrderResponse) GetUpdatedAt() string {
+func (x *UpdateCartItemRequest) GetSku() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Sku
 	}
 	return ""
 }
 
-type GetOrderStatusRequest struct {
+func (x *UpdateCartItemRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type RemoveCartItemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // UUID
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Sku           string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderStatusRequest) Reset() {
-	*x = GetOrderStatusRequest{}
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[4]
+func (x *RemoveCartItemRequest) Reset() {
+	*x = RemoveCartItemRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderStatusRequest) String() string {
+func (x *RemoveCartItemRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderStatusRequest) ProtoMessage() {}
+func (*RemoveCartItemRequest) ProtoMessage() {}
 
-func (x *GetOrderStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[4]
+func (x *RemoveCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -377,40 +1348,49 @@ func (x *GetOrderStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderStatusRequest) Descriptor() ([]byte, []int) {
-	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use RemoveCartItemRequest.ProtoReflect.Descriptor instead.
+func (*RemoveCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *GetOrderStatusRequest) GetOrderId() string {
+func (x *RemoveCartItemRequest) GetUserId() string {
 	if x != nil {
-		return x.OrderId
+		return x.UserId
 	}
 	return ""
 }
 
-type GetOrderStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        OrderStatus            `protobuf:"varint,1,opt,name=status,proto3,enum=checkout.v1.OrderStatus" json:"status,omitempty"` // UNKNOWN если ключа нет в кэше
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *RemoveCartItemRequest) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
 }
 
-func (x *GetOrderStatusResponse) Reset() {
-	*x = GetOrderStatusResponse{}
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[5]
+type CheckoutCartRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ShippingAddress *Address               `protobuf:"bytes,2,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"` // опционально
+	PromoCode       string                 `protobuf:"bytes,3,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`                   // опционально; скидка считается от total корзины
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CheckoutCartRequest) Reset() {
+	*x = CheckoutCartRequest{}
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderStatusResponse) String() string {
+func (x *CheckoutCartRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderStatusResponse) ProtoMessage() {}
+func (*CheckoutCartRequest) ProtoMessage() {}
 
-func (x *GetOrderStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[5]
+func (x *CheckoutCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_gateway_api_checkoutpb_checkout_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -421,59 +1401,171 @@ func (x *GetOrderStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetOrderStatusResponse) Descriptor() ([]byte, []int) {
-	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use CheckoutCartRequest.ProtoReflect.Descriptor instead.
+func (*CheckoutCartRequest) Descriptor() ([]byte, []int) {
+	return file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *GetOrderStatusResponse) GetStatus() OrderStatus {
+func (x *CheckoutCartRequest) GetUserId() string {
 	if x != nil {
-		return x.Status
+		return x.UserId
 	}
-	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+	return ""
+}
+
+func (x *CheckoutCartRequest) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *CheckoutCartRequest) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
 }
 
 var File_services_gateway_api_checkoutpb_checkout_proto protoreflect.FileDescriptor
 
 const file_services_gateway_api_checkoutpb_checkout_proto_rawDesc = "" +
 	"\n" +
-	".services/gateway/api/checkoutpb/checkout.proto\x12\vcheckout.v1\"l\n" +
+	".services/gateway/api/checkoutpb/checkout.proto\x12\vcheckout.v1\"D\n" +
+	"\x05Money\x12\x1f\n" +
+	"\vunits_minor\x18\x01 \x01(\x03R\n" +
+	"unitsMinor\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xd0\x01\n" +
+	"\aAddress\x12\x1c\n" +
+	"\trecipient\x18\x01 \x01(\tR\trecipient\x12\x14\n" +
+	"\x05phone\x18\x02 \x01(\tR\x05phone\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x04 \x01(\tR\x06region\x12\x12\n" +
+	"\x04city\x18\x05 \x01(\tR\x04city\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12\x14\n" +
+	"\x05line1\x18\a \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\b \x01(\tR\x05line2\"\xa7\x01\n" +
+	"\bShipment\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\acarrier\x18\x02 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x03 \x01(\tR\x0etrackingNumber\x12\x1d\n" +
+	"\n" +
+	"shipped_at\x18\x04 \x01(\tR\tshippedAt\x12!\n" +
+	"\fdelivered_at\x18\x05 \x01(\tR\vdeliveredAt\"\xbd\x01\n" +
+	"\aPayment\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12*\n" +
+	"\x06amount\x18\x04 \x01(\v2\x12.checkout.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x18\n" +
+	"\aattempt\x18\x06 \x01(\x05R\aattempt\"\xcc\x01\n" +
 	"\x12CreateOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
 	"\famount_cents\x18\x02 \x01(\x03R\vamountCents\x12\x1a\n" +
-	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\xc0\x01\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12?\n" +
+	"\x10shipping_address\x18\x04 \x01(\v2\x14.checkout.v1.AddressR\x0fshippingAddress\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\x05 \x01(\tR\tpromoCode\"\xbd\x02\n" +
 	"\x13CreateOrderResponse\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x120\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x18.checkout.v1.OrderStatusR\x06status\x12\x1a\n" +
-	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12!\n" +
-	"\ftotal_amount\x18\x04 \x01(\x01R\vtotalAmount\x12\x1d\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12%\n" +
+	"\ftotal_amount\x18\x04 \x01(\x01B\x02\x18\x01R\vtotalAmount\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12(\n" +
+	"\x05total\x18\x06 \x01(\v2\x12.checkout.v1.MoneyR\x05total\x12.\n" +
+	"\bdiscount\x18\a \x01(\v2\x12.checkout.v1.MoneyR\bdiscount\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\tR\tcreatedAt\",\n" +
+	"promo_code\x18\b \x01(\tR\tpromoCode\",\n" +
 	"\x0fGetOrderRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\tR\aorderId\"\xf5\x01\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\x96\x04\n" +
 	"\x10GetOrderResponse\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x120\n" +
 	"\x06status\x18\x03 \x01(\x0e2\x18.checkout.v1.OrderStatusR\x06status\x12\x1a\n" +
-	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12!\n" +
-	"\ftotal_amount\x18\x05 \x01(\x01R\vtotalAmount\x12\x1d\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12%\n" +
+	"\ftotal_amount\x18\x05 \x01(\x01B\x02\x18\x01R\vtotalAmount\x12\x1d\n" +
 	"\n" +
 	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\tR\tupdatedAt\"2\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x12(\n" +
+	"\x05total\x18\b \x01(\v2\x12.checkout.v1.MoneyR\x05total\x12?\n" +
+	"\x10shipping_address\x18\t \x01(\v2\x14.checkout.v1.AddressR\x0fshippingAddress\x121\n" +
+	"\bshipment\x18\n" +
+	" \x01(\v2\x15.checkout.v1.ShipmentR\bshipment\x12.\n" +
+	"\bdiscount\x18\v \x01(\v2\x12.checkout.v1.MoneyR\bdiscount\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\f \x01(\tR\tpromoCode\x12.\n" +
+	"\apayment\x18\r \x01(\v2\x14.checkout.v1.PaymentR\apayment\"0\n" +
+	"\x13RetryPaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"2\n" +
 	"\x15GetOrderStatusRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\"J\n" +
 	"\x16GetOrderStatusResponse\x120\n" +
-	"\x06status\x18\x01 \x01(\x0e2\x18.checkout.v1.OrderStatusR\x06status*t\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.checkout.v1.OrderStatusR\x06status\"4\n" +
+	"\x17WatchOrderStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"z\n" +
+	"\x18WatchOrderStatusResponse\x120\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.checkout.v1.OrderStatusR\x06status\x12\x1c\n" +
+	"\theartbeat\x18\x02 \x01(\bR\theartbeat\x12\x0e\n" +
+	"\x02at\x18\x03 \x01(\tR\x02at\"\xd0\x01\n" +
+	"\bCartItem\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x121\n" +
+	"\n" +
+	"unit_price\x18\x04 \x01(\v2\x12.checkout.v1.MoneyR\tunitPrice\x121\n" +
+	"\n" +
+	"line_total\x18\x05 \x01(\v2\x12.checkout.v1.MoneyR\tlineTotal\x12\x1c\n" +
+	"\tavailable\x18\x06 \x01(\bR\tavailable\"\x95\x01\n" +
+	"\x04Cart\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12+\n" +
+	"\x05items\x18\x02 \x03(\v2\x15.checkout.v1.CartItemR\x05items\x12(\n" +
+	"\x05total\x18\x03 \x01(\v2\x12.checkout.v1.MoneyR\x05total\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\")\n" +
+	"\x0eGetCartRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"[\n" +
+	"\x12AddCartItemRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"^\n" +
+	"\x15UpdateCartItemRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"B\n" +
+	"\x15RemoveCartItemRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\"\x8e\x01\n" +
+	"\x13CheckoutCartRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12?\n" +
+	"\x10shipping_address\x18\x02 \x01(\v2\x14.checkout.v1.AddressR\x0fshippingAddress\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\x03 \x01(\tR\tpromoCode*\xcb\x01\n" +
 	"\vOrderStatus\x12\x1c\n" +
 	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x14\n" +
 	"\x10ORDER_STATUS_NEW\x10\x01\x12\x15\n" +
 	"\x11ORDER_STATUS_PAID\x10\x02\x12\x1a\n" +
-	"\x16ORDER_STATUS_CANCELLED\x10\x032\x80\x02\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x03\x12\x18\n" +
+	"\x14ORDER_STATUS_SHIPPED\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_DELIVERED\x10\x05\x12\x1f\n" +
+	"\x1bORDER_STATUS_PAYMENT_FAILED\x10\x062\xab\x03\n" +
 	"\bCheckout\x12P\n" +
 	"\vCreateOrder\x12\x1f.checkout.v1.CreateOrderRequest\x1a .checkout.v1.CreateOrderResponse\x12G\n" +
 	"\bGetOrder\x12\x1c.checkout.v1.GetOrderRequest\x1a\x1d.checkout.v1.GetOrderResponse\x12Y\n" +
-	"\x0eGetOrderStatus\x12\".checkout.v1.GetOrderStatusRequest\x1a#.checkout.v1.GetOrderStatusResponseB.Z,./services/gateway/api/checkoutpb;checkoutpbb\x06proto3"
+	"\x0eGetOrderStatus\x12\".checkout.v1.GetOrderStatusRequest\x1a#.checkout.v1.GetOrderStatusResponse\x12a\n" +
+	"\x10WatchOrderStatus\x12$.checkout.v1.WatchOrderStatusRequest\x1a%.checkout.v1.WatchOrderStatusResponse0\x01\x12F\n" +
+	"\fRetryPayment\x12 .checkout.v1.RetryPaymentRequest\x1a\x14.checkout.v1.Payment2\xed\x02\n" +
+	"\vCartService\x129\n" +
+	"\aGetCart\x12\x1b.checkout.v1.GetCartRequest\x1a\x11.checkout.v1.Cart\x12=\n" +
+	"\aAddItem\x12\x1f.checkout.v1.AddCartItemRequest\x1a\x11.checkout.v1.Cart\x12K\n" +
+	"\x12UpdateItemQuantity\x12\".checkout.v1.UpdateCartItemRequest\x1a\x11.checkout.v1.Cart\x12C\n" +
+	"\n" +
+	"RemoveItem\x12\".checkout.v1.RemoveCartItemRequest\x1a\x11.checkout.v1.Cart\x12R\n" +
+	"\fCheckoutCart\x12 .checkout.v1.CheckoutCartRequest\x1a .checkout.v1.CreateOrderResponseB.Z,./services/gateway/api/checkoutpb;checkoutpbb\x06proto3"
 
 var (
 	file_services_gateway_api_checkoutpb_checkout_proto_rawDescOnce sync.Once
@@ -488,31 +1580,74 @@ func file_services_gateway_api_checkoutpb_checkout_proto_rawDescGZIP() []byte {
 }
 
 var file_services_gateway_api_checkoutpb_checkout_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_services_gateway_api_checkoutpb_checkout_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_services_gateway_api_checkoutpb_checkout_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_services_gateway_api_checkoutpb_checkout_proto_goTypes = []any{
-	(OrderStatus)(0),               // 0: checkout.v1.OrderStatus
-	(*CreateOrderRequest)(nil),     // 1: checkout.v1.CreateOrderRequest
-	(*CreateOrderResponse)(nil),    // 2: checkout.v1.CreateOrderResponse
-	(*GetOrderRequest)(nil),        // 3: checkout.v1.GetOrderRequest
-	(*GetOrderResponse)(nil),       // 4: checkout.v1.GetOrderResponse
-	(*GetOrderStatusRequest)(nil),  // 5: checkout.v1.GetOrderStatusRequest
-	(*GetOrderStatusResponse)(nil), // 6: checkout.v1.GetOrderStatusResponse
+	(OrderStatus)(0),                 // 0: checkout.v1.OrderStatus
+	(*Money)(nil),                    // 1: checkout.v1.Money
+	(*Address)(nil),                  // 2: checkout.v1.Address
+	(*Shipment)(nil),                 // 3: checkout.v1.Shipment
+	(*Payment)(nil),                  // 4: checkout.v1.Payment
+	(*CreateOrderRequest)(nil),       // 5: checkout.v1.CreateOrderRequest
+	(*CreateOrderResponse)(nil),      // 6: checkout.v1.CreateOrderResponse
+	(*GetOrderRequest)(nil),          // 7: checkout.v1.GetOrderRequest
+	(*GetOrderResponse)(nil),         // 8: checkout.v1.GetOrderResponse
+	(*RetryPaymentRequest)(nil),      // 9: checkout.v1.RetryPaymentRequest
+	(*GetOrderStatusRequest)(nil),    // 10: checkout.v1.GetOrderStatusRequest
+	(*GetOrderStatusResponse)(nil),   // 11: checkout.v1.GetOrderStatusResponse
+	(*WatchOrderStatusRequest)(nil),  // 12: checkout.v1.WatchOrderStatusRequest
+	(*WatchOrderStatusResponse)(nil), // 13: checkout.v1.WatchOrderStatusResponse
+	(*CartItem)(nil),                 // 14: checkout.v1.CartItem
+	(*Cart)(nil),                     // 15: checkout.v1.Cart
+	(*GetCartRequest)(nil),           // 16: checkout.v1.GetCartRequest
+	(*AddCartItemRequest)(nil),       // 17: checkout.v1.AddCartItemRequest
+	(*UpdateCartItemRequest)(nil),    // 18: checkout.v1.UpdateCartItemRequest
+	(*RemoveCartItemRequest)(nil),    // 19: checkout.v1.RemoveCartItemRequest
+	(*CheckoutCartRequest)(nil),      // 20: checkout.v1.CheckoutCartRequest
 }
 var file_services_gateway_api_checkoutpb_checkout_proto_depIdxs = []int32{
-	0, // 0: checkout.v1.CreateOrderResponse.status:type_name -> checkout.v1.OrderStatus
-	0, // 1: checkout.v1.GetOrderResponse.status:type_name -> checkout.v1.OrderStatus
-	0, // 2: checkout.v1.GetOrderStatusResponse.status:type_name -> checkout.v1.OrderStatus
-	1, // 3: checkout.v1.Checkout.CreateOrder:input_type -> checkout.v1.CreateOrderRequest
-	3, // 4: checkout.v1.Checkout.GetOrder:input_type -> checkout.v1.GetOrderRequest
-	5, // 5: checkout.v1.Checkout.GetOrderStatus:input_type -> checkout.v1.GetOrderStatusRequest
-	2, // 6: checkout.v1.Checkout.CreateOrder:output_type -> checkout.v1.CreateOrderResponse
-	4, // 7: checkout.v1.Checkout.GetOrder:output_type -> checkout.v1.GetOrderResponse
-	6, // 8: checkout.v1.Checkout.GetOrderStatus:output_type -> checkout.v1.GetOrderStatusResponse
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	1,  // 0: checkout.v1.Payment.amount:type_name -> checkout.v1.Money
+	2,  // 1: checkout.v1.CreateOrderRequest.shipping_address:type_name -> checkout.v1.Address
+	0,  // 2: checkout.v1.CreateOrderResponse.status:type_name -> checkout.v1.OrderStatus
+	1,  // 3: checkout.v1.CreateOrderResponse.total:type_name -> checkout.v1.Money
+	1,  // 4: checkout.v1.CreateOrderResponse.discount:type_name -> checkout.v1.Money
+	0,  // 5: checkout.v1.GetOrderResponse.status:type_name -> checkout.v1.OrderStatus
+	1,  // 6: checkout.v1.GetOrderResponse.total:type_name -> checkout.v1.Money
+	2,  // 7: checkout.v1.GetOrderResponse.shipping_address:type_name -> checkout.v1.Address
+	3,  // 8: checkout.v1.GetOrderResponse.shipment:type_name -> checkout.v1.Shipment
+	1,  // 9: checkout.v1.GetOrderResponse.discount:type_name -> checkout.v1.Money
+	4,  // 10: checkout.v1.GetOrderResponse.payment:type_name -> checkout.v1.Payment
+	0,  // 11: checkout.v1.GetOrderStatusResponse.status:type_name -> checkout.v1.OrderStatus
+	0,  // 12: checkout.v1.WatchOrderStatusResponse.status:type_name -> checkout.v1.OrderStatus
+	1,  // 13: checkout.v1.CartItem.unit_price:type_name -> checkout.v1.Money
+	1,  // 14: checkout.v1.CartItem.line_total:type_name -> checkout.v1.Money
+	14, // 15: checkout.v1.Cart.items:type_name -> checkout.v1.CartItem
+	1,  // 16: checkout.v1.Cart.total:type_name -> checkout.v1.Money
+	2,  // 17: checkout.v1.CheckoutCartRequest.shipping_address:type_name -> checkout.v1.Address
+	5,  // 18: checkout.v1.Checkout.CreateOrder:input_type -> checkout.v1.CreateOrderRequest
+	7,  // 19: checkout.v1.Checkout.GetOrder:input_type -> checkout.v1.GetOrderRequest
+	10, // 20: checkout.v1.Checkout.GetOrderStatus:input_type -> checkout.v1.GetOrderStatusRequest
+	12, // 21: checkout.v1.Checkout.WatchOrderStatus:input_type -> checkout.v1.WatchOrderStatusRequest
+	9,  // 22: checkout.v1.Checkout.RetryPayment:input_type -> checkout.v1.RetryPaymentRequest
+	16, // 23: checkout.v1.CartService.GetCart:input_type -> checkout.v1.GetCartRequest
+	17, // 24: checkout.v1.CartService.AddItem:input_type -> checkout.v1.AddCartItemRequest
+	18, // 25: checkout.v1.CartService.UpdateItemQuantity:input_type -> checkout.v1.UpdateCartItemRequest
+	19, // 26: checkout.v1.CartService.RemoveItem:input_type -> checkout.v1.RemoveCartItemRequest
+	20, // 27: checkout.v1.CartService.CheckoutCart:input_type -> checkout.v1.CheckoutCartRequest
+	6,  // 28: checkout.v1.Checkout.CreateOrder:output_type -> checkout.v1.CreateOrderResponse
+	8,  // 29: checkout.v1.Checkout.GetOrder:output_type -> checkout.v1.GetOrderResponse
+	11, // 30: checkout.v1.Checkout.GetOrderStatus:output_type -> checkout.v1.GetOrderStatusResponse
+	13, // 31: checkout.v1.Checkout.WatchOrderStatus:output_type -> checkout.v1.WatchOrderStatusResponse
+	4,  // 32: checkout.v1.Checkout.RetryPayment:output_type -> checkout.v1.Payment
+	15, // 33: checkout.v1.CartService.GetCart:output_type -> checkout.v1.Cart
+	15, // 34: checkout.v1.CartService.AddItem:output_type -> checkout.v1.Cart
+	15, // 35: checkout.v1.CartService.UpdateItemQuantity:output_type -> checkout.v1.Cart
+	15, // 36: checkout.v1.CartService.RemoveItem:output_type -> checkout.v1.Cart
+	6,  // 37: checkout.v1.CartService.CheckoutCart:output_type -> checkout.v1.CreateOrderResponse
+	28, // [28:38] is the sub-list for method output_type
+	18, // [18:28] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_services_gateway_api_checkoutpb_checkout_proto_init() }
@@ -526,9 +1661,9 @@ func file_services_gateway_api_checkoutpb_checkout_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_services_gateway_api_checkoutpb_checkout_proto_rawDesc), len(file_services_gateway_api_checkoutpb_checkout_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   6,
+			NumMessages:   20,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_services_gateway_api_checkoutpb_checkout_proto_goTypes,
 		DependencyIndexes: file_services_gateway_api_checkoutpb_checkout_proto_depIdxs,
@@ -2,20 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"goshop/pkg/metrics"
 	"log/slog"
+	"maps"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/httpx"
+	"goshop/pkg/jwtauth"
 	"goshop/pkg/logger"
+	"goshop/pkg/ratelimit"
+	"goshop/pkg/revocation"
+	"goshop/services/gateway/api/checkoutpb"
 	"goshop/services/gateway/config"
+	httpadp "goshop/services/gateway/internal/adapters/http"
 	"goshop/services/gateway/internal/server"
+	"goshop/services/gateway/internal/service"
 )
 
+const shutdownHTTP = 10 * time.Second
+
 func main() {
 	start := time.Now()
 
@@ -30,6 +46,7 @@ func main() {
 
 	log.Info("gateway: starting",
 		slog.String("grpc.addr", cfg.GRPC.Addr),
+		slog.String("http.addr", cfg.HTTP.Addr),
 		slog.String("orders.grpc.addr", cfg.OrdersGRPC.Addr),
 		slog.String("redis.addr", cfg.Redis.Addr),
 	)
@@ -83,17 +100,113 @@ func main() {
 	)
 	defer func() { _ = rdb.Close() }()
 
+	// JWT: принципал для rate limit, владелец корзины и заказа; без jwt методы корзины и заказа закрыты
+	var v grpcauth.Verifier
+	switch {
+	case cfg.JWT.JWKSURL != "":
+		v = jwtauth.NewVerifier(jwtauth.VerifierConfig{
+			JWKSURL: cfg.JWT.JWKSURL,
+			Issuer:  cfg.JWT.Issuer,
+			Secret:  cfg.JWT.Secret,
+			Refresh: cfg.JWT.JWKSRefresh,
+		})
+	case cfg.JWT.Secret != "":
+		v = jwtauth.New(jwtauth.Config{Secret: cfg.JWT.Secret, Issuer: cfg.JWT.Issuer})
+	default:
+		log.Warn("gateway: jwt not configured, cart and order methods are disabled")
+	}
+
+	// Rate limiting (Redis token bucket) — после метрик, чтобы отказы тоже считались
+	unary := []server.UnaryInt{grpcm.UnaryServerInterceptor()}
+	stream := []server.StreamInt{grpcm.StreamServerInterceptor()}
+	if cfg.RateLimit.Enabled {
+		rules := ratelimit.RulesFromConfig(cfg.RateLimit)
+		lim := ratelimit.New(rdb, rules,
+			ratelimit.WithPrefix("rl:gateway:"),
+			ratelimit.WithObserver(metrics.NewRateLimitMetrics(met.Registry(), "goshop", "gateway")),
+			ratelimit.WithTrustedProxies(cfg.RateLimit.TrustedProxies...),
+		)
+		unary = append(unary, ratelimit.UnaryServerInterceptor(lim, log, v))
+		stream = append(stream, ratelimit.StreamServerInterceptor(lim, log, v))
+		log.Info("gateway: rate limiting enabled",
+			slog.Int("rules", len(rules)),
+			slog.Bool("jwt_principal", v != nil),
+		)
+	}
+	// Авторизация — после лимитера: перебор токенов тоже упирается в лимит
+	authRules := grpcauth.Rules{}
+	maps.Copy(authRules, service.CartMethods)
+	maps.Copy(authRules, service.CheckoutMethods)
+	authOpts := grpcauth.Options{
+		Logger:   log,
+		Verifier: v,
+		Revoked:  revocation.New(rdb),
+		Audience: cfg.JWT.AccessAudience,
+	}
+	unary = append(unary, grpcauth.RequireRole(authOpts, authRules))
+	stream = append(stream, grpcauth.RequireRoleStream(authOpts, authRules))
+
+	// Каталог для корзины (цены на момент чтения)
+	items := make([]service.CatalogItem, 0, len(cfg.Catalog))
+	for _, it := range cfg.Catalog {
+		items = append(items, service.CatalogItem{SKU: it.SKU, Name: it.Name, PriceMinor: it.PriceMinor, Currency: it.Currency})
+	}
+	catalog, err := service.NewStaticCatalog(items)
+	if err != nil {
+		log.Error("gateway: catalog init failed", slog.Any("err", err))
+		return
+	}
+	log.Info("gateway: cart catalog loaded",
+		slog.Int("items", len(items)),
+		slog.String("currency", cfg.Cart.Currency),
+	)
+
 	// Server
 	opts := server.Options{
 		Addr:           cfg.GRPC.Addr,
 		OrdersGRPCAddr: cfg.OrdersGRPC.Addr,
 		OrdersTimeout:  cfg.OrdersGRPC.Timeout,
+		PaymentsAddr:   cfg.PaymentsGRPC.Addr,
+		PaymentsTO:     cfg.PaymentsGRPC.Timeout,
 		Logger:         log,
 		EnableReflect:  true,
 		Redis:          rdb,
+		WatchHeartbeat: cfg.Watch.Heartbeat,
+		Cart: service.CartOptions{
+			Catalog:     catalog,
+			TTL:         cfg.Cart.TTL,
+			MaxItems:    cfg.Cart.MaxItems,
+			MaxQuantity: cfg.Cart.MaxQuantity,
+			Currency:    cfg.Cart.Currency,
+		},
 
-		Unary:  []server.UnaryInt{grpcm.UnaryServerInterceptor()},
-		Stream: []server.StreamInt{grpcm.StreamServerInterceptor()},
+		Unary:  unary,
+		Stream: stream,
+	}
+
+	// REST/JSON фасад (опционально): ходит в наш же gRPC через loopback
+	var srv *httpx.Server
+	if cfg.HTTP.Addr != "" {
+		cc, err := grpc.DialContext(ctx, loopbackAddr(cfg.GRPC.Addr),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+		)
+		if err != nil {
+			log.Error("gateway.http: loopback dial failed", slog.Any("err", err))
+			return
+		}
+		defer func() { _ = cc.Close() }()
+
+		httpm := metrics.NewHTTPMetrics(met.Registry(), "goshop", "gateway", metrics.WithBuckets(metrics.WebFastBuckets))
+		gwHTTP := httpadp.NewModule(log, rdb, checkoutpb.NewCheckoutClient(cc), checkoutpb.NewCartServiceClient(cc))
+		srv = httpx.NewServer(cfg.HTTP, log, httpx.WithMiddleware(metrics.GinMiddleware(httpm)), httpx.WithModules(gwHTTP))
+
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("http: listen failed", slog.Any("err", err))
+				stop()
+			}
+		}()
 	}
 
 	if err := server.Start(ctx, opts); err != nil {
@@ -101,7 +214,29 @@ func main() {
 		return
 	}
 
+	if srv != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownHTTP)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error("http: graceful shutdown failed", slog.Any("err", err))
+		} else {
+			log.Info("http: server stopped cleanly")
+		}
+	}
+
 	log.Info("gateway: stopped",
 		slog.Int64("uptime_ms", time.Since(start).Milliseconds()),
 	)
 }
+
+// loopbackAddr превращает адрес listen (":7070", "0.0.0.0:7070") в адрес для локального dial.
+func loopbackAddr(listen string) string {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return listen
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
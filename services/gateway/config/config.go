@@ -6,6 +6,7 @@ import (
 	"time"
 
 	cfg "goshop/pkg/config"
+	"goshop/pkg/money"
 )
 
 type Gateway struct {
@@ -16,11 +17,20 @@ type Gateway struct {
 		Addr string `mapstructure:"addr"`
 	} `mapstructure:"grpc"`
 
+	// HTTP — REST/JSON фасад над Checkout; пустой addr — фасад выключен
+	HTTP cfg.HTTP `mapstructure:"http"`
+
 	OrdersGRPC struct {
 		Addr    string        `mapstructure:"addr"`
 		Timeout time.Duration `mapstructure:"timeout"`
 	} `mapstructure:"orders_grpc"`
 
+	// PaymentsGRPC — последняя попытка оплаты в GetOrder; пустой addr — без неё
+	PaymentsGRPC struct {
+		Addr    string        `mapstructure:"addr"`
+		Timeout time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"payments_grpc"`
+
 	Redis struct {
 		Addr         string        `mapstructure:"addr"`
 		Password     string        `mapstructure:"password"`
@@ -29,6 +39,32 @@ type Gateway struct {
 		ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 		WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	} `mapstructure:"redis"`
+
+	Watch struct {
+		Heartbeat time.Duration `mapstructure:"heartbeat"`
+	} `mapstructure:"watch"`
+
+	// JWT — только проверка access-токенов (принципал для rate limit): jwks_url или secret, оба опциональны
+	JWT cfg.JWT `mapstructure:"jwt"`
+
+	RateLimit cfg.RateLimit `mapstructure:"rate_limit"`
+
+	// Cart — корзины в Redis (cart:<user_id>); цены не хранятся, берутся из Catalog при чтении
+	Cart struct {
+		TTL         time.Duration `mapstructure:"ttl"`          // продлевается при каждом изменении
+		MaxItems    int           `mapstructure:"max_items"`    // разных SKU в корзине
+		MaxQuantity int           `mapstructure:"max_quantity"` // штук одного SKU
+		Currency    string        `mapstructure:"currency"`     // валюта корзины; позиции в другой — недоступны
+	} `mapstructure:"cart"`
+
+	Catalog []CatalogItem `mapstructure:"catalog"`
+}
+
+type CatalogItem struct {
+	SKU        string `mapstructure:"sku"`
+	Name       string `mapstructure:"name"`
+	PriceMinor int64  `mapstructure:"price_minor"`
+	Currency   string `mapstructure:"currency"`
 }
 
 func (g *Gateway) Validate() error {
@@ -44,6 +80,30 @@ func (g *Gateway) Validate() error {
 	if g.Redis.Addr == "" {
 		return errors.New("redis.addr is required")
 	}
+	if g.HTTP.Addr != "" {
+		if err := g.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := g.RateLimit.Validate(); err != nil {
+		return err
+	}
+	if _, err := money.ParseCurrency(g.Cart.Currency); err != nil {
+		return fmt.Errorf("cart.currency: %w", err)
+	}
+	seen := make(map[string]struct{}, len(g.Catalog))
+	for i, it := range g.Catalog {
+		if it.SKU == "" || it.PriceMinor <= 0 {
+			return fmt.Errorf("catalog[%d]: sku and positive price_minor are required", i)
+		}
+		if _, dup := seen[it.SKU]; dup {
+			return fmt.Errorf("catalog[%d]: duplicate sku %q", i, it.SKU)
+		}
+		seen[it.SKU] = struct{}{}
+		if _, err := money.ParseCurrency(it.Currency); err != nil {
+			return fmt.Errorf("catalog[%d].currency: %w", i, err)
+		}
+	}
 	return nil
 }
 
@@ -59,6 +119,24 @@ func New() *Gateway {
 	if c.OrdersGRPC.Timeout <= 0 {
 		c.OrdersGRPC.Timeout = 3 * time.Second
 	}
+	if c.PaymentsGRPC.Timeout <= 0 {
+		c.PaymentsGRPC.Timeout = 2 * time.Second
+	}
+	if c.Watch.Heartbeat <= 0 {
+		c.Watch.Heartbeat = 15 * time.Second
+	}
+	if c.Cart.TTL <= 0 {
+		c.Cart.TTL = 7 * 24 * time.Hour
+	}
+	if c.Cart.MaxItems <= 0 {
+		c.Cart.MaxItems = 50
+	}
+	if c.Cart.MaxQuantity <= 0 {
+		c.Cart.MaxQuantity = 99
+	}
+	if c.Cart.Currency == "" {
+		c.Cart.Currency = "RUB"
+	}
 
 	if err := c.Validate(); err != nil {
 		panic(fmt.Errorf("invalid gateway config: %w", err))
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"goshop/services/gateway/api/checkoutpb"
+)
+
+type CartHandlers struct {
+	log *slog.Logger
+	cli checkoutpb.CartServiceClient
+}
+
+func NewCartHandlers(log *slog.Logger, cli checkoutpb.CartServiceClient) *CartHandlers {
+	return &CartHandlers{log: log, cli: cli}
+}
+
+// GET /v1/cart
+func (h *CartHandlers) Get(c *gin.Context) {
+	noCache(c)
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.GetCart(ctx, &checkoutpb.GetCartRequest{}, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.cart.get", hdr, err)
+		return
+	}
+	writeProto(c, http.StatusOK, out)
+}
+
+// POST /v1/cart/items  {"sku": "...", "quantity": 2}
+func (h *CartHandlers) AddItem(c *gin.Context) {
+	noCache(c)
+
+	var in checkoutpb.AddCartItemRequest
+	if !bindProto(c, &in) {
+		return
+	}
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.AddItem(ctx, &in, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.cart.add", hdr, err)
+		return
+	}
+	writeProto(c, http.StatusOK, out)
+}
+
+// PATCH /v1/cart/items/:sku  {"quantity": 3}; 0 — удалить позицию
+func (h *CartHandlers) UpdateItem(c *gin.Context) {
+	noCache(c)
+
+	var in checkoutpb.UpdateCartItemRequest
+	if !bindProto(c, &in) {
+		return
+	}
+	in.Sku = c.Param("sku")
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.UpdateItemQuantity(ctx, &in, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.cart.update", hdr, err)
+		return
+	}
+	writeProto(c, http.StatusOK, out)
+}
+
+// DELETE /v1/cart/items/:sku
+func (h *CartHandlers) RemoveItem(c *gin.Context) {
+	noCache(c)
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.RemoveItem(ctx, &checkoutpb.RemoveCartItemRequest{Sku: c.Param("sku")}, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.cart.remove", hdr, err)
+		return
+	}
+	writeProto(c, http.StatusOK, out)
+}
+
+// POST /v1/cart/checkout  {"shipping_address": {...}} (тело опционально)
+func (h *CartHandlers) Checkout(c *gin.Context) {
+	noCache(c)
+
+	var in checkoutpb.CheckoutCartRequest
+	if !bindProto(c, &in) {
+		return
+	}
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.CheckoutCart(ctx, &in, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.cart.checkout", hdr, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/v1/checkout/orders/%s", out.GetOrderId()))
+	writeProto(c, http.StatusCreated, out)
+}
+
+// bindProto — protojson из тела; пустое тело допустимо (поля из URL).
+func bindProto(c *gin.Context, m proto.Message) bool {
+	body, ok := readBody(c)
+	if !ok {
+		return false
+	}
+	if len(body) == 0 {
+		return true
+	}
+	if err := jsonIn.Unmarshal(body, m); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"goshop/pkg/httpx"
+	"goshop/services/gateway/api/checkoutpb"
+)
+
+const (
+	callTimeout = 5 * time.Second
+	// maxBodyBytes — потолок тела запроса: заказ на сотни позиций укладывается с запасом
+	maxBodyBytes = 1 << 20
+)
+
+var (
+	jsonIn  = protojson.UnmarshalOptions{DiscardUnknown: true}
+	jsonOut = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+)
+
+type CheckoutHandlers struct {
+	log *slog.Logger
+	cli checkoutpb.CheckoutClient
+}
+
+func NewCheckoutHandlers(log *slog.Logger, cli checkoutpb.CheckoutClient) *CheckoutHandlers {
+	return &CheckoutHandlers{log: log, cli: cli}
+}
+
+// POST /v1/checkout/orders
+func (h *CheckoutHandlers) CreateOrder(c *gin.Context) {
+	noCache(c)
+
+	body, ok := readBody(c)
+	if !ok {
+		return
+	}
+	var in checkoutpb.CreateOrderRequest
+	if err := jsonIn.Unmarshal(body, &in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		return
+	}
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.CreateOrder(ctx, &in, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.create", hdr, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/v1/checkout/orders/%s", out.GetOrderId()))
+	writeProto(c, http.StatusCreated, out)
+}
+
+// GET /v1/checkout/orders/:id
+func (h *CheckoutHandlers) GetOrder(c *gin.Context) {
+	noCache(c)
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.GetOrder(ctx, &checkoutpb.GetOrderRequest{OrderId: c.Param("id")}, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.get", hdr, err)
+		return
+	}
+	writeProto(c, http.StatusOK, out)
+}
+
+// GET /v1/checkout/orders/:id/status
+func (h *CheckoutHandlers) GetOrderStatus(c *gin.Context) {
+	noCache(c)
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.GetOrderStatus(ctx, &checkoutpb.GetOrderStatusRequest{OrderId: c.Param("id")}, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.status", hdr, err)
+		return
+	}
+	writeProto(c, http.StatusOK, out)
+}
+
+// POST /v1/checkout/orders/:id/payments — повтор оплаты заказа в PAYMENT_FAILED
+func (h *CheckoutHandlers) RetryPayment(c *gin.Context) {
+	noCache(c)
+
+	ctx, cancel := outgoing(c)
+	defer cancel()
+
+	var hdr metadata.MD
+	out, err := h.cli.RetryPayment(ctx, &checkoutpb.RetryPaymentRequest{OrderId: c.Param("id")}, grpc.Header(&hdr))
+	if err != nil {
+		writeError(c, h.log, "gateway.http.retry_payment", hdr, err)
+		return
+	}
+	writeProto(c, http.StatusCreated, out)
+}
+
+// readBody — тело не больше maxBodyBytes; при ошибке ответ уже отправлен.
+func readBody(c *gin.Context) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		return nil, false
+	}
+	return body, true
+}
+
+// outgoing переносит HTTP-заголовки в исходящую gRPC metadata.
+func outgoing(c *gin.Context) (context.Context, context.CancelFunc) {
+	md := metadata.MD{}
+
+	// idempotency: CheckoutService.CreateOrder читает оба варианта, отдаём канонический
+	for _, k := range []string{"Idempotency-Key", "X-Idempotency-Key"} {
+		if v := strings.TrimSpace(c.GetHeader(k)); v != "" {
+			md.Set("idempotency-key", v)
+			break
+		}
+	}
+	if v := c.GetHeader("Authorization"); v != "" {
+		md.Set("authorization", v)
+	}
+	if v, ok := c.Get(httpx.CtxKeyReqID); ok {
+		if rid, _ := v.(string); rid != "" {
+			md.Set("x-request-id", rid)
+		}
+	}
+	// ClientIP учитывает X-Forwarded-For только от http.trusted_proxies: подделанный клиентом
+	// заголовок в лимиты gRPC не попадает; Set, а не Append — входящие значения не пробрасываются
+	md.Set("x-forwarded-for", c.ClientIP())
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), callTimeout)
+	return metadata.NewOutgoingContext(ctx, md), cancel
+}
+
+func writeError(c *gin.Context, log *slog.Logger, op string, hdr metadata.MD, err error) {
+	l := reqLog(c, log)
+
+	// rate limiter gateway отдаёт retry-after в header metadata
+	if v := hdr.Get("retry-after"); len(v) > 0 {
+		c.Header("Retry-After", v[0])
+	}
+
+	st := status.Convert(err)
+	code := HTTPStatusFromCode(st.Code())
+	if code >= http.StatusInternalServerError {
+		l.Error(op+": grpc call failed", slog.String("grpc_code", st.Code().String()), slog.Any("err", err))
+	} else {
+		l.Warn(op+": grpc call rejected", slog.String("grpc_code", st.Code().String()), slog.String("msg", st.Message()))
+	}
+
+	c.JSON(code, gin.H{
+		"error": st.Message(),
+		"code":  st.Code().String(),
+	})
+}
+
+func writeProto(c *gin.Context, code int, m proto.Message) {
+	b, err := jsonOut.Marshal(m)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.Data(code, "application/json", b)
+}
+
+// HTTPStatusFromCode — соответствие gRPC-кодов HTTP-статусам (как в google.api / grpc-gateway).
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // client closed request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// local helpers
+func noCache(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+}
+
+func reqLog(c *gin.Context, fallback *slog.Logger) *slog.Logger {
+	return httpx.ReqLog(c, fallback)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"goshop/services/gateway/api/checkoutpb"
+)
+
+type stubCheckoutClient struct {
+	checkoutpb.CheckoutClient
+
+	createFn func(ctx context.Context, in *checkoutpb.CreateOrderRequest) (*checkoutpb.CreateOrderResponse, error)
+	getFn    func(ctx context.Context, in *checkoutpb.GetOrderRequest) (*checkoutpb.GetOrderResponse, error)
+}
+
+func (s *stubCheckoutClient) CreateOrder(ctx context.Context, in *checkoutpb.CreateOrderRequest, _ ...grpc.CallOption) (*checkoutpb.CreateOrderResponse, error) {
+	return s.createFn(ctx, in)
+}
+
+func (s *stubCheckoutClient) GetOrder(ctx context.Context, in *checkoutpb.GetOrderRequest, _ ...grpc.CallOption) (*checkoutpb.GetOrderResponse, error) {
+	return s.getFn(ctx, in)
+}
+
+func newTestRouter(cli checkoutpb.CheckoutClient) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewCheckoutHandlers(slog.New(slog.NewTextHandler(io.Discard, nil)), cli)
+	r := gin.New()
+	r.POST("/v1/checkout/orders", h.CreateOrder)
+	r.GET("/v1/checkout/orders/:id", h.GetOrder)
+	return r
+}
+
+func TestCheckoutHandlers_CreateOrder_Success(t *testing.T) {
+	var gotKey []string
+	var gotReq *checkoutpb.CreateOrderRequest
+	cli := &stubCheckoutClient{
+		createFn: func(ctx context.Context, in *checkoutpb.CreateOrderRequest) (*checkoutpb.CreateOrderResponse, error) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			gotKey = md.Get("idempotency-key")
+			gotReq = in
+			return &checkoutpb.CreateOrderResponse{
+				OrderId:  "o-1",
+				Status:   checkoutpb.OrderStatus_ORDER_STATUS_NEW,
+				Currency: "RUB",
+				Total:    &checkoutpb.Money{UnitsMinor: 1999, Currency: "RUB"},
+			}, nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/checkout/orders",
+		strings.NewReader(`{"user_id":"u-1","amount_cents":"1999","currency":"RUB"}`))
+	req.Header.Set("Idempotency-Key", "k-123")
+	newTestRouter(cli).ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d (body=%s)", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if loc := w.Header().Get("Location"); loc != "/v1/checkout/orders/o-1" {
+		t.Fatalf("Location = %q", loc)
+	}
+	if len(gotKey) != 1 || gotKey[0] != "k-123" {
+		t.Fatalf("idempotency-key metadata = %v, want [k-123]", gotKey)
+	}
+	if gotReq.GetUserId() != "u-1" || gotReq.GetAmountCents() != 1999 {
+		t.Fatalf("unexpected request: %v", gotReq)
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp["order_id"] != "o-1" || resp["status"] != "ORDER_STATUS_NEW" {
+		t.Fatalf("unexpected body: %v", resp)
+	}
+	// int64 в protojson — строкой
+	total, _ := resp["total"].(map[string]any)
+	if total["units_minor"] != "1999" || total["currency"] != "RUB" {
+		t.Fatalf("unexpected total: %v", resp["total"])
+	}
+}
+
+func TestCheckoutHandlers_CreateOrder_InvalidJSON(t *testing.T) {
+	cli := &stubCheckoutClient{
+		createFn: func(context.Context, *checkoutpb.CreateOrderRequest) (*checkoutpb.CreateOrderResponse, error) {
+			t.Fatalf("CreateOrder should not be called")
+			return nil, nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/checkout/orders", strings.NewReader(`{"user_id":`))
+	newTestRouter(cli).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestCheckoutHandlers_CreateOrder_BodyTooLarge(t *testing.T) {
+	cli := &stubCheckoutClient{
+		createFn: func(context.Context, *checkoutpb.CreateOrderRequest) (*checkoutpb.CreateOrderResponse, error) {
+			t.Fatalf("CreateOrder should not be called")
+			return nil, nil
+		},
+	}
+
+	body := `{"user_id":"` + strings.Repeat("x", maxBodyBytes) + `"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/checkout/orders", strings.NewReader(body))
+	newTestRouter(cli).ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestCheckoutHandlers_GetOrder_MapsGRPCCode(t *testing.T) {
+	cli := &stubCheckoutClient{
+		getFn: func(_ context.Context, in *checkoutpb.GetOrderRequest) (*checkoutpb.GetOrderResponse, error) {
+			if in.GetOrderId() != "missing" {
+				t.Fatalf("order_id = %q, want %q", in.GetOrderId(), "missing")
+			}
+			return nil, status.Error(codes.NotFound, "order not found")
+		},
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/checkout/orders/missing", nil)
+	newTestRouter(cli).ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	var resp map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp["code"] != "NotFound" || resp["error"] != "order not found" {
+		t.Fatalf("unexpected body: %v", resp)
+	}
+}
+
+func TestHTTPStatusFromCode(t *testing.T) {
+	cases := map[codes.Code]int{
+		codes.OK:                 http.StatusOK,
+		codes.InvalidArgument:    http.StatusBadRequest,
+		codes.FailedPrecondition: http.StatusBadRequest,
+		codes.NotFound:           http.StatusNotFound,
+		codes.AlreadyExists:      http.StatusConflict,
+		codes.Aborted:            http.StatusConflict,
+		codes.Unauthenticated:    http.StatusUnauthorized,
+		codes.PermissionDenied:   http.StatusForbidden,
+		codes.ResourceExhausted:  http.StatusTooManyRequests,
+		codes.Unavailable:        http.StatusServiceUnavailable,
+		codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+		codes.Internal:           http.StatusInternalServerError,
+	}
+	for c, want := range cases {
+		if got := HTTPStatusFromCode(c); got != want {
+			t.Errorf("HTTPStatusFromCode(%s) = %d, want %d", c, got, want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const readyPingTimeout = 500 * time.Millisecond
+
+type HealthHandlers struct {
+	log *slog.Logger
+	rdb *redis.Client
+}
+
+func NewHealthHandlers(log *slog.Logger, rdb *redis.Client) *HealthHandlers {
+	return &HealthHandlers{log: log, rdb: rdb}
+}
+
+func (h *HealthHandlers) Live(c *gin.Context) {
+	noCache(c)
+	c.String(http.StatusOK, "ok")
+}
+
+func (h *HealthHandlers) Ready(c *gin.Context) {
+	noCache(c)
+
+	l := reqLog(c, h.log)
+
+	if h.rdb == nil {
+		l.Error("gateway.health.ready: redis client is nil")
+		c.String(http.StatusServiceUnavailable, "redis not ready")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyPingTimeout)
+	defer cancel()
+
+	if err := h.rdb.Ping(ctx).Err(); err != nil {
+		l.Error("gateway.health.ready: redis ping failed", slog.Any("err", err))
+		c.String(http.StatusServiceUnavailable, "redis not ready")
+		return
+	}
+
+	c.String(http.StatusOK, "ok")
+}
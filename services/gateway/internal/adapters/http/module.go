@@ -0,0 +1,69 @@
+package httpadp
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"goshop/services/gateway/api/checkoutpb"
+	"goshop/services/gateway/internal/adapters/http/handlers"
+)
+
+// Module — REST/JSON фасад над gRPC Checkout для клиентов, которые не умеют gRPC.
+// Запросы уходят в gRPC-сервер gateway через loopback-клиент, поэтому
+// проходят те же интерцепторы (метрики, логирование), что и нативные gRPC-вызовы.
+type Module struct {
+	log      *slog.Logger
+	rdb      *redis.Client
+	checkout checkoutpb.CheckoutClient
+	cart     checkoutpb.CartServiceClient
+}
+
+func NewModule(log *slog.Logger, rdb *redis.Client, checkout checkoutpb.CheckoutClient, cart checkoutpb.CartServiceClient) *Module {
+	return &Module{
+		log:      log,
+		rdb:      rdb,
+		checkout: checkout,
+		cart:     cart,
+	}
+}
+
+func (m *Module) Name() string { return "gateway.http" }
+
+func (m *Module) Mount(r *gin.Engine) error {
+	m.log.Info("http: mounting module", slog.String("module", m.Name()))
+
+	// Health
+	hh := handlers.NewHealthHandlers(m.log, m.rdb)
+	r.GET("/live", hh.Live)
+	r.GET("/ready", hh.Ready)
+
+	// Checkout
+	ch := handlers.NewCheckoutHandlers(m.log, m.checkout)
+
+	co := r.Group("/v1/checkout")
+	co.POST("/orders", ch.CreateOrder)
+	co.GET("/orders/:id", ch.GetOrder)
+	co.GET("/orders/:id/status", ch.GetOrderStatus)
+	co.POST("/orders/:id/payments", ch.RetryPayment)
+
+	// Cart — корзина владельца access-токена (Authorization уходит в gRPC)
+	cth := handlers.NewCartHandlers(m.log, m.cart)
+
+	ct := r.Group("/v1/cart")
+	ct.GET("", cth.Get)
+	ct.POST("/items", cth.AddItem)
+	ct.PATCH("/items/:sku", cth.UpdateItem)
+	ct.DELETE("/items/:sku", cth.RemoveItem)
+	ct.POST("/checkout", cth.Checkout)
+
+	m.log.Info("http: routes registered",
+		slog.String("module", m.Name()),
+		slog.String("base", "/v1"),
+		slog.String("group", "/v1/checkout"),
+		slog.String("cart_group", "/v1/cart"),
+	)
+
+	return nil
+}
@@ -30,9 +30,13 @@ type Options struct {
 	Addr           string
 	OrdersGRPCAddr string
 	OrdersTimeout  time.Duration
+	PaymentsAddr   string
+	PaymentsTO     time.Duration
 	Logger         *slog.Logger
 	EnableReflect  bool
 	Redis          *redis.Client
+	WatchHeartbeat time.Duration
+	Cart           service.CartOptions // Logger/Redis/Orders заполняются здесь
 	Unary          []UnaryInt
 	Stream         []StreamInt
 }
@@ -84,11 +88,14 @@ func Start(ctx context.Context, opt Options) error {
 
 	// checkout service
 	svc, err := service.NewCheckoutService(ctx, service.Options{
-		OrdersAddr:  opt.OrdersGRPCAddr,
-		OrdersTO:    opt.OrdersTimeout,
-		Logger:      log,
-		DefaultCurr: "RUB",
-		Redis:       opt.Redis,
+		OrdersAddr:     opt.OrdersGRPCAddr,
+		OrdersTO:       opt.OrdersTimeout,
+		PaymentsAddr:   opt.PaymentsAddr,
+		PaymentsTO:     opt.PaymentsTO,
+		Logger:         log,
+		DefaultCurr:    "RUB",
+		Redis:          opt.Redis,
+		WatchHeartbeat: opt.WatchHeartbeat,
 	})
 	if err != nil {
 		log.Error("gateway.server: checkout init failed", slog.Any("err", err))
@@ -97,6 +104,16 @@ func Start(ctx context.Context, opt Options) error {
 	}
 	checkoutpb.RegisterCheckoutServer(s, svc)
 
+	// cart service — тот же orders-клиент, что и у checkout
+	cartOpt := opt.Cart
+	cartOpt.Logger = log
+	cartOpt.Redis = opt.Redis
+	cartOpt.Orders = svc.Orders()
+	if cartOpt.Catalog == nil {
+		cartOpt.Catalog, _ = service.NewStaticCatalog(nil)
+	}
+	checkoutpb.RegisterCartServiceServer(s, service.NewCartService(cartOpt))
+
 	if opt.EnableReflect {
 		reflection.Register(s)
 	}
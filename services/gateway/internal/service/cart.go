@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/grpcauth"
+	"goshop/services/gateway/api/checkoutpb"
+	orderpb "goshop/services/orders/api/orderspb"
+)
+
+const checkoutLockTTL = 30 * time.Second
+
+// OrderCreator — создание заказа в orders (OrdersGRPCClient).
+type OrderCreator interface {
+	CreateOrder(ctx context.Context, userID string, amountCents int64, currency string, addr *orderpb.Address, promoCode string) (*orderpb.CreateOrderResponse, error)
+}
+
+// CartMethods — методы корзины: только владелец, пользователь берётся из access-токена.
+var CartMethods = grpcauth.Rules{
+	checkoutpb.CartService_GetCart_FullMethodName:            {},
+	checkoutpb.CartService_AddItem_FullMethodName:            {},
+	checkoutpb.CartService_UpdateItemQuantity_FullMethodName: {},
+	checkoutpb.CartService_RemoveItem_FullMethodName:         {},
+	checkoutpb.CartService_CheckoutCart_FullMethodName:       {},
+}
+
+type CartOptions struct {
+	Logger      *slog.Logger
+	Redis       *redis.Client
+	Orders      OrderCreator
+	Catalog     PriceBook
+	TTL         time.Duration
+	MaxItems    int
+	MaxQuantity int
+	Currency    string
+}
+
+// CartService — корзина пользователя в Redis:
+//
+//	cart:<user_id>          HASH sku -> quantity, TTL продлевается при изменении
+//	cart:<user_id>:checkout лок на время CheckoutCart (значение — токен владельца);
+//	                        изменения корзины в это время отклоняются
+//
+// Цены в корзине не хранятся: total считается по каталогу при каждом чтении.
+// Пользователь — из claims (CartMethods в grpcauth.RequireRole); user_id в запросе
+// необязателен, чужой отклоняется.
+type CartService struct {
+	checkoutpb.UnimplementedCartServiceServer
+	log         *slog.Logger
+	rdb         *redis.Client
+	orders      OrderCreator
+	catalog     PriceBook
+	ttl         time.Duration
+	maxItems    int
+	maxQuantity int
+	currency    string
+}
+
+func NewCartService(opt CartOptions) *CartService {
+	if opt.Logger == nil {
+		opt.Logger = slog.Default()
+	}
+	if opt.TTL <= 0 {
+		opt.TTL = 7 * 24 * time.Hour
+	}
+	if opt.MaxItems <= 0 {
+		opt.MaxItems = 50
+	}
+	if opt.MaxQuantity <= 0 {
+		opt.MaxQuantity = 99
+	}
+	if opt.Currency == "" {
+		opt.Currency = "RUB"
+	}
+	return &CartService{
+		log:         opt.Logger,
+		rdb:         opt.Redis,
+		orders:      opt.Orders,
+		catalog:     opt.Catalog,
+		ttl:         opt.TTL,
+		maxItems:    opt.MaxItems,
+		maxQuantity: opt.MaxQuantity,
+		currency:    strings.ToUpper(opt.Currency),
+	}
+}
+
+// Коды возврата cartMutateScript.
+const (
+	cartOK           = 0
+	cartLocked       = -1
+	cartTooMany      = -2
+	cartFull         = -3
+	cartItemNotFound = -4
+)
+
+// KEYS[1] — корзина, KEYS[2] — checkout-лок
+// ARGV: op (add|set|del), sku, quantity, max_quantity, max_items, ttl_ms
+var cartMutateScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 1 then return -1 end
+local op, sku = ARGV[1], ARGV[2]
+local qty = tonumber(ARGV[3])
+local cur = tonumber(redis.call('HGET', KEYS[1], sku) or '0')
+if op ~= 'add' and cur == 0 then return -4 end
+if op == 'add' then qty = cur + qty end
+if op == 'del' or qty <= 0 then
+  redis.call('HDEL', KEYS[1], sku)
+else
+  if qty > tonumber(ARGV[4]) then return -2 end
+  if cur == 0 and redis.call('HLEN', KEYS[1]) >= tonumber(ARGV[5]) then return -3 end
+  redis.call('HSET', KEYS[1], sku, qty)
+end
+if redis.call('EXISTS', KEYS[1]) == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[6])
+end
+return 0
+`)
+
+// releaseLockScript — снять лок, только если он ещё наш: после checkoutLockTTL его мог
+// взять параллельный checkout. KEYS[1] — лок, ARGV[1] — токен владельца.
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+func cartKey(userID string) string         { return "cart:" + userID }
+func cartCheckoutKey(userID string) string { return "cart:" + userID + ":checkout" }
+
+func (s *CartService) GetCart(ctx context.Context, in *checkoutpb.GetCartRequest) (*checkoutpb.Cart, error) {
+	uid, err := cartOwner(ctx, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return s.load(ctx, uid)
+}
+
+func (s *CartService) AddItem(ctx context.Context, in *checkoutpb.AddCartItemRequest) (*checkoutpb.Cart, error) {
+	uid, err := cartOwner(ctx, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetQuantity() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "positive quantity is required")
+	}
+	sku := strings.TrimSpace(in.GetSku())
+	if _, ok := s.catalog.Lookup(sku); !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown sku %q", sku)
+	}
+	return s.mutate(ctx, uid, "add", sku, in.GetQuantity())
+}
+
+func (s *CartService) UpdateItemQuantity(ctx context.Context, in *checkoutpb.UpdateCartItemRequest) (*checkoutpb.Cart, error) {
+	uid, err := cartOwner(ctx, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetQuantity() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must not be negative")
+	}
+	return s.mutate(ctx, uid, "set", strings.TrimSpace(in.GetSku()), in.GetQuantity())
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, in *checkoutpb.RemoveCartItemRequest) (*checkoutpb.Cart, error) {
+	uid, err := cartOwner(ctx, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return s.mutate(ctx, uid, "del", strings.TrimSpace(in.GetSku()), 0)
+}
+
+// CheckoutCart — заказ на сумму корзины через orders. Корзина удаляется только после
+// успешного CreateOrder; на время вызова она заблокирована, так что повторный
+// CheckoutCart или изменение корзины получат Aborted, а не второй заказ.
+func (s *CartService) CheckoutCart(ctx context.Context, in *checkoutpb.CheckoutCartRequest) (*checkoutpb.CreateOrderResponse, error) {
+	uid, err := cartOwner(ctx, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	lockKey, owner := cartCheckoutKey(uid), uuid.NewString()
+	ok, err := s.rdb.SetNX(ctx, lockKey, owner, checkoutLockTTL).Result()
+	if err != nil {
+		s.log.Warn("gateway.cart.redis: setnx lock failed", slog.String("key", lockKey), slog.Any("err", err))
+		return nil, status.Error(codes.Unavailable, "cart storage unavailable")
+	}
+	if !ok {
+		return nil, status.Error(codes.Aborted, "cart checkout is in progress, retry later")
+	}
+	defer func() {
+		if err := releaseLockScript.Run(context.Background(), s.rdb, []string{lockKey}, owner).Err(); err != nil {
+			s.log.Warn("gateway.cart.redis: release lock failed", slog.String("key", lockKey), slog.Any("err", err))
+		}
+	}()
+
+	cart, err := s.load(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+	}
+	for _, it := range cart.Items {
+		if !it.Available {
+			return nil, status.Errorf(codes.FailedPrecondition, "sku %q is not available, remove it from the cart", it.Sku)
+		}
+	}
+
+	total := cart.GetTotal()
+	out, err := s.orders.CreateOrder(ctx, uid, total.GetUnitsMinor(), total.GetCurrency(), toOrdersAddress(in.GetShippingAddress()), strings.TrimSpace(in.GetPromoCode()))
+	if err != nil {
+		s.log.Warn("gateway.cart.orders: create failed", slog.String("user_id", uid), slog.Any("err", err))
+		// коды orders (InvalidArgument и т.п.) пробрасываем как есть
+		if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+			return nil, st.Err()
+		}
+		return nil, status.Errorf(codes.Unavailable, "orders create failed: %v", err)
+	}
+
+	if err := s.rdb.Del(ctx, cartKey(uid)).Err(); err != nil {
+		// заказ уже создан — не фейлим ответ, корзина доживёт до TTL
+		s.log.Error("gateway.cart.redis: clear after checkout failed",
+			slog.String("user_id", uid),
+			slog.String("order_id", out.GetOrderId()),
+			slog.Any("err", err),
+		)
+	}
+	s.log.Info("gateway.cart: checked out",
+		slog.String("user_id", uid),
+		slog.String("order_id", out.GetOrderId()),
+		slog.Int("items", len(cart.Items)),
+		slog.Int64("total_minor", total.GetUnitsMinor()),
+	)
+
+	return mapCreateResponse(out), nil
+}
+
+func (s *CartService) mutate(ctx context.Context, uid, op, sku string, qty int32) (*checkoutpb.Cart, error) {
+	if sku == "" {
+		return nil, status.Error(codes.InvalidArgument, "sku is required")
+	}
+	rc, err := cartMutateScript.Run(ctx, s.rdb,
+		[]string{cartKey(uid), cartCheckoutKey(uid)},
+		op, sku, qty, s.maxQuantity, s.maxItems, s.ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		s.log.Warn("gateway.cart.redis: mutate failed", slog.String("op", op), slog.String("user_id", uid), slog.Any("err", err))
+		return nil, status.Error(codes.Unavailable, "cart storage unavailable")
+	}
+	switch rc {
+	case cartOK:
+	case cartLocked:
+		return nil, status.Error(codes.Aborted, "cart checkout is in progress, retry later")
+	case cartTooMany:
+		return nil, status.Errorf(codes.InvalidArgument, "quantity exceeds limit of %d", s.maxQuantity)
+	case cartFull:
+		return nil, status.Errorf(codes.FailedPrecondition, "cart is full (max %d items)", s.maxItems)
+	case cartItemNotFound:
+		return nil, status.Errorf(codes.NotFound, "sku %q is not in the cart", sku)
+	default:
+		return nil, status.Errorf(codes.Internal, "unexpected cart script result %d", rc)
+	}
+	return s.load(ctx, uid)
+}
+
+func (s *CartService) load(ctx context.Context, uid string) (*checkoutpb.Cart, error) {
+	key := cartKey(uid)
+	pipe := s.rdb.Pipeline()
+	all := pipe.HGetAll(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		s.log.Warn("gateway.cart.redis: load failed", slog.String("key", key), slog.Any("err", err))
+		return nil, status.Error(codes.Unavailable, "cart storage unavailable")
+	}
+
+	qty := make(map[string]int32, len(all.Val()))
+	for sku, v := range all.Val() {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n <= 0 {
+			continue
+		}
+		qty[sku] = int32(n)
+	}
+
+	cart, err := priceCart(qty, s.catalog, s.currency)
+	if err != nil {
+		return nil, err
+	}
+	cart.UserId = uid
+	if d := ttl.Val(); d > 0 && len(qty) > 0 {
+		cart.ExpiresAt = time.Now().Add(d).UTC().Format(time.RFC3339)
+	}
+	return cart, nil
+}
+
+// priceCart — позиции по SKU (отсортированы) с ценами из каталога. SKU, пропавший
+// из каталога или в чужой валюте, остаётся в корзине как недоступный и в total не входит.
+func priceCart(qty map[string]int32, book PriceBook, currency string) (*checkoutpb.Cart, error) {
+	skus := make([]string, 0, len(qty))
+	for sku := range qty {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	cart := &checkoutpb.Cart{Items: make([]*checkoutpb.CartItem, 0, len(skus))}
+	var total int64
+	for _, sku := range skus {
+		n := qty[sku]
+		it := &checkoutpb.CartItem{Sku: sku, Name: sku, Quantity: n}
+		if ci, ok := book.Lookup(sku); ok {
+			it.Name = ci.Name
+			it.UnitPrice = &checkoutpb.Money{UnitsMinor: ci.PriceMinor, Currency: ci.Currency}
+			if ci.Currency == currency {
+				if ci.PriceMinor > math.MaxInt64/int64(n) || total > math.MaxInt64-ci.PriceMinor*int64(n) {
+					return nil, status.Error(codes.OutOfRange, "cart total overflows")
+				}
+				line := ci.PriceMinor * int64(n)
+				it.LineTotal = &checkoutpb.Money{UnitsMinor: line, Currency: ci.Currency}
+				it.Available = true
+				total += line
+			}
+		}
+		cart.Items = append(cart.Items, it)
+	}
+	if len(cart.Items) > 0 {
+		cart.Total = &checkoutpb.Money{UnitsMinor: total, Currency: currency}
+	}
+	return cart, nil
+}
+
+// cartOwner — пользователь из access-токена; user_id из запроса должен с ним совпадать.
+func cartOwner(ctx context.Context, requested string) (string, error) {
+	return tokenUser(ctx, requested, "cart belongs to another user")
+}
+
+// tokenUser — пользователь из access-токена; непустой requested должен с ним совпадать
+// (иначе PermissionDenied с текстом denied).
+func tokenUser(ctx context.Context, requested, denied string) (string, error) {
+	claims, ok := grpcauth.ClaimsFromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing authorization")
+	}
+	id, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if requested = strings.TrimSpace(requested); requested != "" {
+		if rid, err := uuid.Parse(requested); err != nil || rid != id {
+			return "", status.Error(codes.PermissionDenied, denied)
+		}
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
+	"goshop/services/gateway/api/checkoutpb"
+	orderpb "goshop/services/orders/api/orderspb"
+)
+
+func TestPriceCart(t *testing.T) {
+	book, err := NewStaticCatalog([]CatalogItem{
+		{SKU: "tea", Name: "Чай", PriceMinor: 25000, Currency: "RUB"},
+		{SKU: "mug", PriceMinor: 49900, Currency: "rub"},
+		{SKU: "book", Name: "Book", PriceMinor: 1500, Currency: "USD"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := priceCart(map[string]int32{"tea": 2, "mug": 1, "book": 1, "gone": 3}, book, "RUB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cart.GetTotal(); got.GetUnitsMinor() != 2*25000+49900 || got.GetCurrency() != "RUB" {
+		t.Fatalf("total = %v", got)
+	}
+
+	want := []struct {
+		sku       string
+		available bool
+	}{{"book", false}, {"gone", false}, {"mug", true}, {"tea", true}}
+	if len(cart.Items) != len(want) {
+		t.Fatalf("items = %d, want %d", len(cart.Items), len(want))
+	}
+	for i, w := range want {
+		it := cart.Items[i]
+		if it.GetSku() != w.sku || it.GetAvailable() != w.available {
+			t.Fatalf("item %d = %s/%v, want %s/%v", i, it.GetSku(), it.GetAvailable(), w.sku, w.available)
+		}
+	}
+	if cart.Items[2].GetName() != "mug" || cart.Items[3].GetLineTotal().GetUnitsMinor() != 50000 {
+		t.Fatalf("unexpected pricing: %v", cart.Items)
+	}
+
+	empty, err := priceCart(nil, book, "RUB")
+	if err != nil || empty.GetTotal() != nil || len(empty.Items) != 0 {
+		t.Fatalf("empty cart = %v, %v", empty, err)
+	}
+}
+
+type stubOrders struct {
+	calls int
+	total int64
+	err   error
+	// inCall — пока идёт CreateOrder (проверки конкурентного checkout)
+	inCall func()
+}
+
+func (s *stubOrders) CreateOrder(_ context.Context, _ string, amount int64, _ string, _ *orderpb.Address, _ string) (*orderpb.CreateOrderResponse, error) {
+	s.calls++
+	s.total = amount
+	if s.inCall != nil {
+		s.inCall()
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &orderpb.CreateOrderResponse{OrderId: "o-1", Status: orderpb.OrderStatus_ORDER_STATUS_NEW}, nil
+}
+
+func newTestCart(t *testing.T, orders OrderCreator) (*CartService, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	book, err := NewStaticCatalog([]CatalogItem{{SKU: "tea", Name: "Чай", PriceMinor: 25000, Currency: "RUB"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewCartService(CartOptions{
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Redis:   redis.NewClient(&redis.Options{Addr: mr.Addr()}),
+		Orders:  orders,
+		Catalog: book,
+	}), mr
+}
+
+func asUser(uid string) context.Context {
+	return grpcauth.NewContext(context.Background(), &jwtauth.Claims{UserID: uid})
+}
+
+func TestCartOwner(t *testing.T) {
+	uid := uuid.NewString()
+	cases := []struct {
+		name      string
+		ctx       context.Context
+		requested string
+		want      codes.Code
+	}{
+		{"own cart", asUser(uid), "", codes.OK},
+		{"own cart by id", asUser(uid), uid, codes.OK},
+		{"other user's cart", asUser(uid), uuid.NewString(), codes.PermissionDenied},
+		{"garbage user_id", asUser(uid), "nope", codes.PermissionDenied},
+		{"no claims", context.Background(), uid, codes.Unauthenticated},
+		{"bad uid in token", asUser("nope"), "", codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := cartOwner(tc.ctx, tc.requested)
+			if status.Code(err) != tc.want {
+				t.Fatalf("code = %v, want %v (%v)", status.Code(err), tc.want, err)
+			}
+			if err == nil && got != uid {
+				t.Fatalf("owner = %q, want %q", got, uid)
+			}
+		})
+	}
+}
+
+func TestCheckoutCart(t *testing.T) {
+	orders := &stubOrders{}
+	s, mr := newTestCart(t, orders)
+	uid := uuid.NewString()
+	ctx := asUser(uid)
+
+	if _, err := s.CheckoutCart(ctx, &checkoutpb.CheckoutCartRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("empty cart: %v", err)
+	}
+	if _, err := s.AddItem(ctx, &checkoutpb.AddCartItemRequest{Sku: "tea", Quantity: 2}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	// orders недоступен — корзина остаётся, лок снят
+	orders.err = status.Error(codes.Unavailable, "orders down")
+	if _, err := s.CheckoutCart(ctx, &checkoutpb.CheckoutCartRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("orders failure: %v", err)
+	}
+	if !mr.Exists(cartKey(uid)) || mr.Exists(cartCheckoutKey(uid)) {
+		t.Fatalf("after failure: cart exists=%v, lock exists=%v", mr.Exists(cartKey(uid)), mr.Exists(cartCheckoutKey(uid)))
+	}
+
+	orders.err = nil
+	out, err := s.CheckoutCart(ctx, &checkoutpb.CheckoutCartRequest{})
+	if err != nil || out.GetOrderId() != "o-1" {
+		t.Fatalf("checkout: %v, %v", out, err)
+	}
+	if orders.total != 50000 {
+		t.Fatalf("order total = %d, want 50000", orders.total)
+	}
+	if mr.Exists(cartKey(uid)) || mr.Exists(cartCheckoutKey(uid)) {
+		t.Fatalf("cart or lock left after checkout")
+	}
+}
+
+// Пока идёт checkout, второй checkout и изменения корзины получают Aborted.
+func TestCheckoutCart_Locked(t *testing.T) {
+	uid := uuid.NewString()
+	ctx := asUser(uid)
+	var s *CartService
+	var nested, mutate error
+	orders := &stubOrders{inCall: func() {
+		_, nested = s.CheckoutCart(ctx, &checkoutpb.CheckoutCartRequest{})
+		_, mutate = s.AddItem(ctx, &checkoutpb.AddCartItemRequest{Sku: "tea", Quantity: 1})
+	}}
+	s, _ = newTestCart(t, orders)
+
+	if _, err := s.AddItem(ctx, &checkoutpb.AddCartItemRequest{Sku: "tea", Quantity: 1}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := s.CheckoutCart(ctx, &checkoutpb.CheckoutCartRequest{}); err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if status.Code(nested) != codes.Aborted || status.Code(mutate) != codes.Aborted {
+		t.Fatalf("during checkout: checkout=%v, add=%v", nested, mutate)
+	}
+	if orders.calls != 1 {
+		t.Fatalf("orders called %d times, want 1", orders.calls)
+	}
+}
+
+// Checkout, переживший checkoutLockTTL, не снимает лок, который уже взял другой.
+func TestCheckoutCart_ExpiredLockNotStolen(t *testing.T) {
+	uid := uuid.NewString()
+	ctx := asUser(uid)
+	var mr *miniredis.Miniredis
+	orders := &stubOrders{inCall: func() {
+		mr.FastForward(checkoutLockTTL + time.Second)
+		mr.Set(cartCheckoutKey(uid), "other-checkout")
+	}}
+	var s *CartService
+	s, mr = newTestCart(t, orders)
+
+	if _, err := s.AddItem(ctx, &checkoutpb.AddCartItemRequest{Sku: "tea", Quantity: 1}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := s.CheckoutCart(ctx, &checkoutpb.CheckoutCartRequest{}); err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if got, err := mr.Get(cartCheckoutKey(uid)); err != nil || got != "other-checkout" {
+		t.Fatalf("foreign lock = %q, %v; want it untouched", got, err)
+	}
+}
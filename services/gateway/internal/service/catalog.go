@@ -0,0 +1,51 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"goshop/pkg/money"
+)
+
+// CatalogItem — позиция прайс-листа; цена в минорных единицах валюты.
+type CatalogItem struct {
+	SKU        string
+	Name       string
+	PriceMinor int64
+	Currency   string
+}
+
+// PriceBook — источник цен для корзины. Сейчас статический список из конфига,
+// дальше его может заменить клиент к каталогу.
+type PriceBook interface {
+	Lookup(sku string) (CatalogItem, bool)
+}
+
+type StaticCatalog struct {
+	items map[string]CatalogItem
+}
+
+func NewStaticCatalog(items []CatalogItem) (*StaticCatalog, error) {
+	m := make(map[string]CatalogItem, len(items))
+	for _, it := range items {
+		it.SKU = strings.TrimSpace(it.SKU)
+		if it.SKU == "" || it.PriceMinor <= 0 {
+			return nil, fmt.Errorf("catalog: sku and positive price are required")
+		}
+		c, err := money.ParseCurrency(it.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("catalog %s: %w", it.SKU, err)
+		}
+		it.Currency = c.Code
+		if it.Name == "" {
+			it.Name = it.SKU
+		}
+		m[it.SKU] = it
+	}
+	return &StaticCatalog{items: m}, nil
+}
+
+func (c *StaticCatalog) Lookup(sku string) (CatalogItem, bool) {
+	it, ok := c.items[sku]
+	return it, ok
+}
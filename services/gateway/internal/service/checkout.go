@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -17,51 +18,103 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
+	"goshop/pkg/money"
 	"goshop/services/gateway/api/checkoutpb"
 	"goshop/services/orders/api/orderspb"
+	"goshop/services/payments/api/paymentspb"
+)
+
+// CheckoutMethods — методы Checkout только с access-токеном; владельца заказа проверяет сам метод,
+// CreateOrder создаёт заказ на владельца токена.
+// WatchOrderStatus — стриминговый: правила ставятся и в grpcauth.RequireRoleStream.
+var CheckoutMethods = grpcauth.Rules{
+	checkoutpb.Checkout_CreateOrder_FullMethodName:      {},
+	checkoutpb.Checkout_GetOrder_FullMethodName:         {},
+	checkoutpb.Checkout_GetOrderStatus_FullMethodName:   {},
+	checkoutpb.Checkout_WatchOrderStatus_FullMethodName: {},
+	checkoutpb.Checkout_RetryPayment_FullMethodName:     {},
+}
+
+// Роли, которым доступны чужие заказы: читать — admin и support, повторять оплату — только admin.
+var (
+	readAll  = []string{jwtauth.RoleAdmin, jwtauth.RoleSupport}
+	retryAll = []string{jwtauth.RoleAdmin}
 )
 
 type Options struct {
-	OrdersAddr  string
-	OrdersTO    time.Duration
-	Logger      *slog.Logger
-	DefaultCurr string
-	Redis       *redis.Client
+	OrdersAddr     string
+	OrdersTO       time.Duration
+	PaymentsAddr   string // пусто — GetOrder без последней попытки оплаты
+	PaymentsTO     time.Duration
+	Logger         *slog.Logger
+	DefaultCurr    string
+	Redis          *redis.Client
+	WatchHeartbeat time.Duration
 }
 
 type CheckoutService struct {
 	checkoutpb.UnimplementedCheckoutServer
-	log         *slog.Logger
-	orders      *OrdersGRPCClient
-	defaultCurr string
-	rdb         *redis.Client
+	log            *slog.Logger
+	orders         *OrdersGRPCClient
+	payments       *PaymentsGRPCClient // nil — payments не настроен
+	defaultCurr    string
+	rdb            *redis.Client
+	watchHeartbeat time.Duration
 }
 
 func NewCheckoutService(ctx context.Context, opt Options) (*CheckoutService, error) {
 	if opt.DefaultCurr == "" {
 		opt.DefaultCurr = "RUB"
 	}
+	if opt.WatchHeartbeat <= 0 {
+		opt.WatchHeartbeat = 15 * time.Second
+	}
 	cli, err := NewOrdersGRPCClient(ctx, opt.OrdersAddr, opt.OrdersTO, opt.Logger)
 	if err != nil {
 		return nil, err
 	}
+	var pay *PaymentsGRPCClient
+	if opt.PaymentsAddr != "" {
+		if pay, err = NewPaymentsGRPCClient(ctx, opt.PaymentsAddr, opt.PaymentsTO, opt.Logger); err != nil {
+			return nil, err
+		}
+	}
 	return &CheckoutService{
-		log:         opt.Logger,
-		orders:      cli,
-		defaultCurr: opt.DefaultCurr,
-		rdb:         opt.Redis,
+		log:            opt.Logger,
+		orders:         cli,
+		payments:       pay,
+		defaultCurr:    opt.DefaultCurr,
+		rdb:            opt.Redis,
+		watchHeartbeat: opt.WatchHeartbeat,
 	}, nil
 }
 
+// Orders — общий клиент orders для соседних сервисов (корзина).
+func (s *CheckoutService) Orders() *OrdersGRPCClient { return s.orders }
+
 func (s *CheckoutService) CreateOrder(ctx context.Context, in *checkoutpb.CreateOrderRequest) (*checkoutpb.CreateOrderResponse, error) {
 	// 0) валидация
-	if in == nil || in.UserId == "" || in.AmountCents <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "user_id and positive amount_cents are required")
+	if in == nil || in.AmountCents <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "positive amount_cents is required")
+	}
+	// заказ — на владельца токена: иначе чужим user_id обходится лимит промо-кода на пользователя
+	userID, err := tokenUser(ctx, in.GetUserId(), "user_id does not match the token")
+	if err != nil {
+		return nil, err
 	}
 	curr := in.Currency
-	if curr == "" {
+	if strings.TrimSpace(curr) == "" {
 		curr = s.defaultCurr
 	}
+	cur, err := money.ParseCurrency(curr)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "unsupported currency")
+	}
+	curr = cur.Code
+	addr := toOrdersAddress(in.GetShippingAddress())
+	promo := strings.ToUpper(strings.TrimSpace(in.GetPromoCode()))
 
 	// 1) вытащим идемпотентный ключ из gRPC metadata (оба варианта заголовка)
 	var idemKeyStr string
@@ -76,18 +129,12 @@ func (s *CheckoutService) CreateOrder(ctx context.Context, in *checkoutpb.Create
 
 	// если ключ не передан — обычный путь без идемпотентности
 	if idemKeyStr == "" {
-		out, err := s.orders.CreateOrder(ctx, in.UserId, in.AmountCents, curr)
+		out, err := s.orders.CreateOrder(ctx, userID, in.AmountCents, curr, addr, promo)
 		if err != nil {
 			s.log.Warn("gateway.checkout.orders: create failed", slog.Any("err", err))
 			return nil, status.Errorf(codes.FailedPrecondition, "orders create failed: %v", err)
 		}
-		return &checkoutpb.CreateOrderResponse{
-			OrderId:     out.GetOrderId(),
-			Status:      mapOrdersStatus(out.GetStatus()),
-			Currency:    out.GetCurrency(),
-			TotalAmount: out.GetTotalAmount(),
-			CreatedAt:   out.GetCreatedAt(),
-		}, nil
+		return mapCreateResponse(out), nil
 	}
 
 	// 2) ключи в Redis
@@ -97,7 +144,7 @@ func (s *CheckoutService) CreateOrder(ctx context.Context, in *checkoutpb.Create
 	runTTL := 60 * time.Second // пока выполняется первый запрос
 	finalTTL := time.Hour      // TTL успешного результата
 	nowRFC3339 := time.Now().UTC().Format(time.RFC3339)
-	ph := payloadHash(in.UserId, in.AmountCents, curr)
+	ph := payloadHash(userID, in.AmountCents, curr, addr, promo)
 
 	// 3) быстрый путь — ключ уже есть
 	m, err := s.rdb.HGetAll(ctx, idemKey).Result()
@@ -111,6 +158,10 @@ func (s *CheckoutService) CreateOrder(ctx context.Context, in *checkoutpb.Create
 			if js := m["resp"]; js != "" {
 				var out checkoutpb.CreateOrderResponse
 				if err := protojson.Unmarshal([]byte(js), &out); err == nil {
+					// ответ, закэшированный до появления total
+					if out.Total == nil {
+						out.Total = legacyMoney(out.GetTotalAmount(), out.GetCurrency())
+					}
 					s.log.Info("gateway.checkout.idem: replay", slog.String("key", idemKeyStr))
 					return &out, nil
 				}
@@ -150,7 +201,7 @@ func (s *CheckoutService) CreateOrder(ctx context.Context, in *checkoutpb.Create
 	s.log.Info("gateway.checkout.idem: begin", slog.String("key", idemKeyStr))
 
 	// 6) основной вызов в orders
-	out, err := s.orders.CreateOrder(ctx, in.UserId, in.AmountCents, curr)
+	out, err := s.orders.CreateOrder(ctx, userID, in.AmountCents, curr, addr, promo)
 	if err != nil {
 		s.log.Warn("gateway.checkout.orders: create failed", slog.Any("err", err))
 		_ = s.rdb.HSet(ctx, idemKey, map[string]any{
@@ -164,13 +215,7 @@ func (s *CheckoutService) CreateOrder(ctx context.Context, in *checkoutpb.Create
 		return nil, status.Errorf(codes.FailedPrecondition, "orders create failed: %v", err)
 	}
 
-	resp := &checkoutpb.CreateOrderResponse{
-		OrderId:     out.GetOrderId(),
-		Status:      mapOrdersStatus(out.GetStatus()),
-		Currency:    out.GetCurrency(),
-		TotalAmount: out.GetTotalAmount(),
-		CreatedAt:   out.GetCreatedAt(),
-	}
+	resp := mapCreateResponse(out)
 
 	js, _ := protojson.Marshal(resp)
 	if err := s.rdb.HSet(ctx, idemKey, map[string]any{
@@ -193,7 +238,11 @@ func (s *CheckoutService) GetOrderStatus(ctx context.Context, in *checkoutpb.Get
 		return nil, status.Error(codes.InvalidArgument, "order_id is required")
 	}
 
-	key := "order:" + in.OrderId + ":status"
+	if _, err := s.ownedOrder(ctx, in.OrderId); err != nil {
+		return nil, err
+	}
+
+	key := statusKey(in.OrderId)
 
 	v, err := s.rdb.Get(ctx, key).Result()
 	if err == redis.Nil {
@@ -206,28 +255,69 @@ func (s *CheckoutService) GetOrderStatus(ctx context.Context, in *checkoutpb.Get
 		return nil, status.Error(codes.Unavailable, "status cache unavailable")
 	}
 
-	var st checkoutpb.OrderStatus
+	return &checkoutpb.GetOrderStatusResponse{Status: mapCacheStatus(v)}, nil
+}
+
+// --- helpers ---
+
+func payloadHash(userID string, amountCents int64, currency string, addr *orderspb.Address, promoCode string) string {
+	c := strings.ToUpper(strings.TrimSpace(currency))
+	base := fmt.Sprintf("%s|%d|%s", strings.TrimSpace(userID), amountCents, c)
+	if promoCode != "" {
+		base += "|promo=" + promoCode
+	}
+	if addr != nil {
+		// тот же ключ с другим адресом — другой запрос
+		base += "|" + strings.Join([]string{
+			addr.GetRecipient(), addr.GetPhone(), addr.GetCountry(), addr.GetRegion(),
+			addr.GetCity(), addr.GetPostalCode(), addr.GetLine1(), addr.GetLine2(),
+		}, "|")
+	}
+	sum := sha256.Sum256([]byte(base))
+	return hex.EncodeToString(sum[:])
+}
+
+// statusKey — ключ кэша статуса, который пишет orders (consumer.Processor.setStatusCache)
+func statusKey(orderID string) string {
+	return "order:" + orderID + ":status"
+}
+
+func mapCacheStatus(v string) checkoutpb.OrderStatus {
 	switch v {
 	case "new":
-		st = checkoutpb.OrderStatus_ORDER_STATUS_NEW
+		return checkoutpb.OrderStatus_ORDER_STATUS_NEW
 	case "paid":
-		st = checkoutpb.OrderStatus_ORDER_STATUS_PAID
+		return checkoutpb.OrderStatus_ORDER_STATUS_PAID
 	case "cancelled", "canceled":
-		st = checkoutpb.OrderStatus_ORDER_STATUS_CANCELLED
+		return checkoutpb.OrderStatus_ORDER_STATUS_CANCELLED
+	case "shipped":
+		return checkoutpb.OrderStatus_ORDER_STATUS_SHIPPED
+	case "delivered":
+		return checkoutpb.OrderStatus_ORDER_STATUS_DELIVERED
+	case "payment_failed":
+		return checkoutpb.OrderStatus_ORDER_STATUS_PAYMENT_FAILED
 	default:
-		st = checkoutpb.OrderStatus_ORDER_STATUS_UNSPECIFIED
+		return checkoutpb.OrderStatus_ORDER_STATUS_UNSPECIFIED
 	}
-
-	return &checkoutpb.GetOrderStatusResponse{Status: st}, nil
 }
 
-// --- helpers ---
+// mapOrdersMoney — total из orders; если orders ещё старый (без total), восстанавливаем из total_amount.
+func mapOrdersMoney(m *orderspb.Money, legacy float64, currency string) *checkoutpb.Money {
+	if m != nil {
+		return fromOrdersMoney(m)
+	}
+	return legacyMoney(legacy, currency)
+}
 
-func payloadHash(userID string, amountCents int64, currency string) string {
-	c := strings.ToUpper(strings.TrimSpace(currency))
-	base := fmt.Sprintf("%s|%d|%s", strings.TrimSpace(userID), amountCents, c)
-	sum := sha256.Sum256([]byte(base))
-	return hex.EncodeToString(sum[:])
+func legacyMoney(amount float64, currency string) *checkoutpb.Money {
+	c, err := money.ParseCurrency(currency)
+	if err != nil {
+		return nil
+	}
+	return &checkoutpb.Money{
+		UnitsMinor: int64(math.Round(amount * float64(c.Factor()))),
+		Currency:   c.Code,
+	}
 }
 
 func mapOrdersStatus(s orderspb.OrderStatus) checkoutpb.OrderStatus {
@@ -238,11 +328,213 @@ func mapOrdersStatus(s orderspb.OrderStatus) checkoutpb.OrderStatus {
 		return checkoutpb.OrderStatus_ORDER_STATUS_PAID
 	case orderspb.OrderStatus_ORDER_STATUS_CANCELLED:
 		return checkoutpb.OrderStatus_ORDER_STATUS_CANCELLED
+	case orderspb.OrderStatus_ORDER_STATUS_SHIPPED:
+		return checkoutpb.OrderStatus_ORDER_STATUS_SHIPPED
+	case orderspb.OrderStatus_ORDER_STATUS_DELIVERED:
+		return checkoutpb.OrderStatus_ORDER_STATUS_DELIVERED
+	case orderspb.OrderStatus_ORDER_STATUS_PAYMENT_FAILED:
+		return checkoutpb.OrderStatus_ORDER_STATUS_PAYMENT_FAILED
 	default:
 		return checkoutpb.OrderStatus_ORDER_STATUS_UNSPECIFIED
 	}
 }
 
-func (s *CheckoutService) GetOrder(context.Context, *checkoutpb.GetOrderRequest) (*checkoutpb.GetOrderResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "GetOrder is not implemented yet")
+func (s *CheckoutService) GetOrder(ctx context.Context, in *checkoutpb.GetOrderRequest) (*checkoutpb.GetOrderResponse, error) {
+	if in == nil || in.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	out, err := s.ownedOrder(ctx, in.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkoutpb.GetOrderResponse{
+		OrderId:     out.GetOrderId(),
+		UserId:      out.GetUserId(),
+		Status:      mapOrdersStatus(out.GetStatus()),
+		Currency:    out.GetCurrency(),
+		TotalAmount: out.GetTotalAmount(), // deprecated, до v2
+		CreatedAt:   out.GetCreatedAt(),
+		UpdatedAt:   out.GetUpdatedAt(),
+		Total:       mapOrdersMoney(out.GetTotal(), out.GetTotalAmount(), out.GetCurrency()),
+
+		ShippingAddress: fromOrdersAddress(out.GetShippingAddress()),
+		Shipment:        fromOrdersShipment(out.GetShipment()),
+		Discount:        fromOrdersMoney(out.GetDiscount()),
+		PromoCode:       out.GetPromoCode(),
+		Payment:         s.lastPayment(ctx, out.GetOrderId()),
+	}, nil
+}
+
+// RetryPayment — новая попытка оплаты. Статус заказа проверяем здесь: payments знает только свои попытки.
+// Заказ перейдёт в PAID/CANCELLED асинхронно, когда orders получит событие попытки.
+func (s *CheckoutService) RetryPayment(ctx context.Context, in *checkoutpb.RetryPaymentRequest) (*checkoutpb.Payment, error) {
+	if in == nil || in.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	if s.payments == nil {
+		return nil, status.Error(codes.Unimplemented, "payments are not configured")
+	}
+
+	ord, err := s.getOrder(ctx, in.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if err := orderOwner(ctx, ord.GetUserId(), retryAll...); err != nil {
+		return nil, err
+	}
+	if ord.GetStatus() != orderspb.OrderStatus_ORDER_STATUS_PAYMENT_FAILED {
+		return nil, status.Errorf(codes.FailedPrecondition, "order is %s, payment retry is not allowed",
+			strings.ToLower(strings.TrimPrefix(ord.GetStatus().String(), "ORDER_STATUS_")))
+	}
+
+	p, err := s.payments.Retry(ctx, in.OrderId)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+			return nil, st.Err()
+		}
+		return nil, status.Errorf(codes.Unavailable, "payments retry failed: %v", err)
+	}
+	s.log.Info("gateway.checkout.payments: retried",
+		slog.String("order_id", in.OrderId),
+		slog.Int("attempt", int(p.GetAttempt())),
+		slog.String("status", p.GetStatus().String()),
+	)
+	return fromPaymentsPayment(p), nil
+}
+
+// getOrder — заказ из orders; коды orders (NotFound/InvalidArgument) пробрасываем как есть.
+func (s *CheckoutService) getOrder(ctx context.Context, orderID string) (*orderspb.GetOrderResponse, error) {
+	out, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+			return nil, st.Err()
+		}
+		return nil, status.Errorf(codes.Unavailable, "orders get failed: %v", err)
+	}
+	return out, nil
+}
+
+// ownedOrder — заказ для чтения: владельцу токена, admin или support.
+func (s *CheckoutService) ownedOrder(ctx context.Context, orderID string) (*orderspb.GetOrderResponse, error) {
+	out, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := orderOwner(ctx, out.GetUserId(), readAll...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// orderOwner — заказ принадлежит владельцу токена (CheckoutMethods) или у вызывающего одна из roles.
+func orderOwner(ctx context.Context, userID string, roles ...string) error {
+	claims, ok := grpcauth.ClaimsFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing authorization")
+	}
+	if len(roles) > 0 && claims.HasRole(roles...) {
+		return nil
+	}
+	if claims.UserID == "" || claims.UserID != userID {
+		return status.Error(codes.PermissionDenied, "order belongs to another user")
+	}
+	return nil
+}
+
+// lastPayment — best effort: без payments заказ отдаём как есть.
+func (s *CheckoutService) lastPayment(ctx context.Context, orderID string) *checkoutpb.Payment {
+	if s.payments == nil {
+		return nil
+	}
+	p, err := s.payments.LastByOrder(ctx, orderID)
+	if err != nil || p == nil {
+		return nil
+	}
+	return fromPaymentsPayment(p)
+}
+
+func mapCreateResponse(out *orderspb.CreateOrderResponse) *checkoutpb.CreateOrderResponse {
+	return &checkoutpb.CreateOrderResponse{
+		OrderId:     out.GetOrderId(),
+		Status:      mapOrdersStatus(out.GetStatus()),
+		Currency:    out.GetCurrency(),
+		TotalAmount: out.GetTotalAmount(), // deprecated, до v2
+		CreatedAt:   out.GetCreatedAt(),
+		Total:       mapOrdersMoney(out.GetTotal(), out.GetTotalAmount(), out.GetCurrency()),
+		Discount:    fromOrdersMoney(out.GetDiscount()),
+		PromoCode:   out.GetPromoCode(),
+	}
+}
+
+func fromOrdersMoney(m *orderspb.Money) *checkoutpb.Money {
+	if m == nil {
+		return nil
+	}
+	return &checkoutpb.Money{UnitsMinor: m.GetUnitsMinor(), Currency: m.GetCurrency()}
+}
+
+func fromPaymentsPayment(p *paymentspb.Payment) *checkoutpb.Payment {
+	out := &checkoutpb.Payment{
+		PaymentId: p.GetPaymentId(),
+		Reason:    p.GetReason(),
+		CreatedAt: p.GetCreatedAt(),
+		Attempt:   p.GetAttempt(),
+	}
+	switch p.GetStatus() {
+	case paymentspb.PaymentStatus_PAYMENT_STATUS_CONFIRMED:
+		out.Status = "confirmed"
+	case paymentspb.PaymentStatus_PAYMENT_STATUS_FAILED:
+		out.Status = "failed"
+	}
+	if m := p.GetAmount(); m != nil {
+		out.Amount = &checkoutpb.Money{UnitsMinor: m.GetUnitsMinor(), Currency: m.GetCurrency()}
+	}
+	return out
+}
+
+func toOrdersAddress(a *checkoutpb.Address) *orderspb.Address {
+	if a == nil {
+		return nil
+	}
+	return &orderspb.Address{
+		Recipient:  a.GetRecipient(),
+		Phone:      a.GetPhone(),
+		Country:    a.GetCountry(),
+		Region:     a.GetRegion(),
+		City:       a.GetCity(),
+		PostalCode: a.GetPostalCode(),
+		Line1:      a.GetLine1(),
+		Line2:      a.GetLine2(),
+	}
+}
+
+func fromOrdersAddress(a *orderspb.Address) *checkoutpb.Address {
+	if a == nil {
+		return nil
+	}
+	return &checkoutpb.Address{
+		Recipient:  a.GetRecipient(),
+		Phone:      a.GetPhone(),
+		Country:    a.GetCountry(),
+		Region:     a.GetRegion(),
+		City:       a.GetCity(),
+		PostalCode: a.GetPostalCode(),
+		Line1:      a.GetLine1(),
+		Line2:      a.GetLine2(),
+	}
+}
+
+func fromOrdersShipment(s *orderspb.Shipment) *checkoutpb.Shipment {
+	if s == nil {
+		return nil
+	}
+	return &checkoutpb.Shipment{
+		Status:         s.GetStatus(),
+		Carrier:        s.GetCarrier(),
+		TrackingNumber: s.GetTrackingNumber(),
+		ShippedAt:      s.GetShippedAt(),
+		DeliveredAt:    s.GetDeliveredAt(),
+	}
 }
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
+	"goshop/services/gateway/api/checkoutpb"
+	"goshop/services/orders/api/orderspb"
+)
+
+func TestOrderOwner(t *testing.T) {
+	uid := uuid.NewString()
+	admin := grpcauth.NewContext(context.Background(), &jwtauth.Claims{UserID: uuid.NewString(), Roles: []string{jwtauth.RoleAdmin}})
+	support := grpcauth.NewContext(context.Background(), &jwtauth.Claims{UserID: uuid.NewString(), Roles: []string{jwtauth.RoleSupport}})
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"owner", asUser(uid), codes.OK},
+		{"admin", admin, codes.OK},
+		{"support", support, codes.PermissionDenied}, // читать может, повторять чужую оплату — нет
+		{"other user", asUser(uuid.NewString()), codes.PermissionDenied},
+		{"empty uid", asUser(""), codes.PermissionDenied},
+		{"no claims", context.Background(), codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := orderOwner(tc.ctx, uid, retryAll...); status.Code(err) != tc.want {
+				t.Fatalf("code = %v, want %v (%v)", status.Code(err), tc.want, err)
+			}
+		})
+	}
+}
+
+type stubOrdersClient struct {
+	orderspb.OrdersClient
+	userID string
+}
+
+func (c stubOrdersClient) GetOrder(_ context.Context, in *orderspb.GetOrderRequest, _ ...grpc.CallOption) (*orderspb.GetOrderResponse, error) {
+	return &orderspb.GetOrderResponse{OrderId: in.GetOrderId(), UserId: c.userID, Status: orderspb.OrderStatus_ORDER_STATUS_NEW}, nil
+}
+
+type stubWatchStream struct {
+	checkoutpb.Checkout_WatchOrderStatusServer
+	ctx context.Context
+}
+
+func (s stubWatchStream) Context() context.Context { return s.ctx }
+
+// Заказ, его статус и стрим статуса — только владельцу (и admin/support): по UUID чужого
+// заказа не отдаются ни адрес, ни платёж, ни переходы статуса.
+func TestCheckout_OrderReadsRequireOwner(t *testing.T) {
+	uid := uuid.NewString()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := &CheckoutService{
+		log:    log,
+		orders: &OrdersGRPCClient{cli: stubOrdersClient{userID: uid}, log: log, timeout: time.Second},
+		rdb:    rdb,
+	}
+	support := grpcauth.NewContext(context.Background(), &jwtauth.Claims{UserID: uuid.NewString(), Roles: []string{jwtauth.RoleSupport}})
+	orderID := uuid.NewString()
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"owner", asUser(uid), codes.OK},
+		{"support", support, codes.OK},
+		{"other user", asUser(uuid.NewString()), codes.PermissionDenied},
+		{"no token", context.Background(), codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := s.GetOrder(tc.ctx, &checkoutpb.GetOrderRequest{OrderId: orderID}); status.Code(err) != tc.want {
+				t.Errorf("GetOrder: code = %v, want %v (%v)", status.Code(err), tc.want, err)
+			}
+			if _, err := s.GetOrderStatus(tc.ctx, &checkoutpb.GetOrderStatusRequest{OrderId: orderID}); status.Code(err) != tc.want {
+				t.Errorf("GetOrderStatus: code = %v, want %v (%v)", status.Code(err), tc.want, err)
+			}
+			if tc.want == codes.OK {
+				return
+			}
+			err := s.WatchOrderStatus(&checkoutpb.WatchOrderStatusRequest{OrderId: orderID}, stubWatchStream{ctx: tc.ctx})
+			if status.Code(err) != tc.want {
+				t.Errorf("WatchOrderStatus: code = %v, want %v (%v)", status.Code(err), tc.want, err)
+			}
+		})
+	}
+}
+
+type recordingWatchStream struct {
+	stubWatchStream
+	sent chan *checkoutpb.WatchOrderStatusResponse
+}
+
+func (s recordingWatchStream) Send(m *checkoutpb.WatchOrderStatusResponse) error {
+	s.sent <- m
+	return nil
+}
+
+// Переход, записанный в кэш без публикации (пропущенное pub/sub-сообщение), уходит на ближайшем
+// heartbeat, а терминальный статус закрывает стрим.
+func TestWatchOrderStatus_HeartbeatRereadsCache(t *testing.T) {
+	uid := uuid.NewString()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := &CheckoutService{
+		log:            log,
+		orders:         &OrdersGRPCClient{cli: stubOrdersClient{userID: uid}, log: log, timeout: time.Second},
+		rdb:            rdb,
+		watchHeartbeat: 20 * time.Millisecond,
+	}
+	orderID := uuid.NewString()
+	mr.Set(statusKey(orderID), "paid")
+
+	ctx, cancel := context.WithTimeout(asUser(uid), 5*time.Second)
+	defer cancel()
+	stream := recordingWatchStream{stubWatchStream{ctx: ctx}, make(chan *checkoutpb.WatchOrderStatusResponse, 16)}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WatchOrderStatus(&checkoutpb.WatchOrderStatusRequest{OrderId: orderID}, stream)
+	}()
+
+	if m := <-stream.sent; m.GetStatus() != checkoutpb.OrderStatus_ORDER_STATUS_PAID || m.GetHeartbeat() {
+		t.Fatalf("first message = %v, want PAID snapshot", m)
+	}
+	mr.Set(statusKey(orderID), "delivered") // без PUBLISH
+
+	var last *checkoutpb.WatchOrderStatusResponse
+	for last == nil || last.GetHeartbeat() {
+		select {
+		case last = <-stream.sent:
+		case <-ctx.Done():
+			t.Fatal("missed transition was not sent")
+		}
+	}
+	if last.GetStatus() != checkoutpb.OrderStatus_ORDER_STATUS_DELIVERED {
+		t.Fatalf("status = %v, want DELIVERED", last.GetStatus())
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("WatchOrderStatus: %v, want closed stream", err)
+	}
+}
+
+type createOrdersClient struct {
+	orderspb.OrdersClient
+	users *[]string
+}
+
+func (c createOrdersClient) CreateOrder(_ context.Context, in *orderspb.CreateOrderRequest, _ ...grpc.CallOption) (*orderspb.CreateOrderResponse, error) {
+	*c.users = append(*c.users, in.GetUserId())
+	return &orderspb.CreateOrderResponse{OrderId: uuid.NewString(), Status: orderspb.OrderStatus_ORDER_STATUS_NEW}, nil
+}
+
+// Заказ создаётся на владельца токена: чужой user_id в теле не проходит, иначе им обходился бы
+// лимит промо-кода на пользователя.
+func TestCheckout_CreateOrderForTokenUser(t *testing.T) {
+	uid := uuid.NewString()
+	var users []string
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := &CheckoutService{
+		log:         log,
+		orders:      &OrdersGRPCClient{cli: createOrdersClient{users: &users}, log: log, timeout: time.Second},
+		defaultCurr: "RUB",
+	}
+
+	cases := []struct {
+		name   string
+		ctx    context.Context
+		userID string
+		want   codes.Code
+	}{
+		{"user_id from token", asUser(uid), "", codes.OK},
+		{"same user_id", asUser(uid), uid, codes.OK},
+		{"other user_id", asUser(uid), uuid.NewString(), codes.PermissionDenied},
+		{"no token", context.Background(), uid, codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.CreateOrder(tc.ctx, &checkoutpb.CreateOrderRequest{UserId: tc.userID, AmountCents: 19901})
+			if status.Code(err) != tc.want {
+				t.Fatalf("code = %v, want %v (%v)", status.Code(err), tc.want, err)
+			}
+		})
+	}
+	if len(users) != 2 || users[0] != uid || users[1] != uid {
+		t.Fatalf("orders got user_id %v, want %s twice", users, uid)
+	}
+}
@@ -60,14 +60,16 @@ func NewOrdersGRPCClient(ctx context.Context, addr string, timeout time.Duration
 
 func (c *OrdersGRPCClient) Close() error { return c.cc.Close() }
 
-func (c *OrdersGRPCClient) CreateOrder(ctx context.Context, userID string, amountCents int64, currency string) (*orderpb.CreateOrderResponse, error) {
+func (c *OrdersGRPCClient) CreateOrder(ctx context.Context, userID string, amountCents int64, currency string, addr *orderpb.Address, promoCode string) (*orderpb.CreateOrderResponse, error) {
 	rctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	req := &orderpb.CreateOrderRequest{
-		UserId:      userID,
-		AmountCents: amountCents,
-		Currency:    currency,
+		UserId:          userID,
+		AmountCents:     amountCents,
+		Currency:        currency,
+		ShippingAddress: addr,
+		PromoCode:       promoCode,
 	}
 
 	resp, err := c.cli.CreateOrder(rctx, req)
@@ -80,3 +82,19 @@ func (c *OrdersGRPCClient) CreateOrder(ctx context.Context, userID string, amoun
 	}
 	return resp, nil
 }
+
+func (c *OrdersGRPCClient) GetOrder(ctx context.Context, orderID string) (*orderpb.GetOrderResponse, error) {
+	rctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.cli.GetOrder(rctx, &orderpb.GetOrderRequest{OrderId: orderID})
+	if err != nil {
+		c.log.Warn("gateway.orders.client: get failed",
+			slog.String("order_id", orderID),
+			slog.String("grpc_code", status.Code(err).String()),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	return resp, nil
+}
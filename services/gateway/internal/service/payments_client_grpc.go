@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"goshop/services/payments/api/paymentspb"
+)
+
+type PaymentsGRPCClient struct {
+	cc      *grpc.ClientConn
+	cli     paymentspb.PaymentsClient
+	log     *slog.Logger
+	timeout time.Duration
+}
+
+func NewPaymentsGRPCClient(ctx context.Context, addr string, timeout time.Duration, log *slog.Logger) (*PaymentsGRPCClient, error) {
+	if log == nil {
+		log = slog.Default()
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+
+	// без WaitForReady: payments — дополнительные данные к заказу, ждать его не стоит
+	cc, err := grpc.DialContext(
+		ctx,
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDisableRetry(),
+	)
+	if err != nil {
+		log.Error("gateway.payments.client: dial failed",
+			slog.String("addr", addr),
+			slog.Any("err", err),
+		)
+		return nil, fmt.Errorf("dial payments-grpc %s: %w", addr, err)
+	}
+
+	log.Info("gateway.payments.client: dialed",
+		slog.String("addr", addr),
+		slog.Int64("timeout_ms", timeout.Milliseconds()),
+	)
+
+	return &PaymentsGRPCClient{
+		cc:      cc,
+		cli:     paymentspb.NewPaymentsClient(cc),
+		log:     log,
+		timeout: timeout,
+	}, nil
+}
+
+func (c *PaymentsGRPCClient) Close() error { return c.cc.Close() }
+
+// withAuth — payments проверяет владельца по access-токену: передаём authorization вызывающего как есть.
+func withAuth(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	rctx, cancel := context.WithTimeout(ctx, timeout)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			rctx = metadata.AppendToOutgoingContext(rctx, "authorization", v[0])
+		}
+	}
+	return rctx, cancel
+}
+
+// LastByOrder — последняя попытка оплаты заказа; nil, nil — попыток ещё не было.
+// Без access-токена вызывающего payments не ответит — не спрашиваем.
+func (c *PaymentsGRPCClient) LastByOrder(ctx context.Context, orderID string) (*paymentspb.Payment, error) {
+	if md, _ := metadata.FromIncomingContext(ctx); len(md.Get("authorization")) == 0 {
+		return nil, nil
+	}
+	rctx, cancel := withAuth(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.cli.ListPaymentsByOrder(rctx, &paymentspb.ListPaymentsByOrderRequest{OrderId: orderID})
+	if err != nil {
+		c.log.Warn("gateway.payments.client: list by order failed",
+			slog.String("order_id", orderID),
+			slog.String("grpc_code", status.Code(err).String()),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	if len(resp.GetPayments()) == 0 {
+		return nil, nil
+	}
+	return resp.GetPayments()[0], nil
+}
+
+func (c *PaymentsGRPCClient) Retry(ctx context.Context, orderID string) (*paymentspb.Payment, error) {
+	rctx, cancel := withAuth(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.cli.RetryPayment(rctx, &paymentspb.RetryPaymentRequest{OrderId: orderID})
+	if err != nil {
+		c.log.Warn("gateway.payments.client: retry failed",
+			slog.String("order_id", orderID),
+			slog.String("grpc_code", status.Code(err).String()),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	return resp, nil
+}
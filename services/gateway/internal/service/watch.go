@@ -0,0 +1,138 @@
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"goshop/services/gateway/api/checkoutpb"
+)
+
+// WatchOrderStatus стримит переходы статуса заказа.
+// Источник — Redis pub/sub, в который orders публикует при обновлении кэша статуса.
+// Pub/sub не гарантирует доставку (переподключение, медленный подписчик), поэтому раз в watchHeartbeat
+// кэш перечитывается: пропущенный переход уходит обычным сообщением, иначе — heartbeat с последним статусом.
+// Стрим закрывается, как только заказ пришёл в терминальный статус (DELIVERED/CANCELLED; PAID — нет).
+func (s *CheckoutService) WatchOrderStatus(in *checkoutpb.WatchOrderStatusRequest, stream checkoutpb.Checkout_WatchOrderStatusServer) error {
+	if in == nil || in.OrderId == "" {
+		return status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	ctx := stream.Context()
+	if _, err := s.ownedOrder(ctx, in.OrderId); err != nil {
+		return err
+	}
+	key := statusKey(in.OrderId)
+	ch := key + ":updates"
+
+	// 1) сначала подписка, потом чтение кэша — иначе переход между ними потеряется
+	ps := s.rdb.Subscribe(ctx, ch)
+	defer func() { _ = ps.Close() }()
+	if _, err := ps.Receive(ctx); err != nil {
+		s.log.Warn("gateway.checkout.redis: subscribe failed", slog.String("channel", ch), slog.Any("err", err))
+		return status.Error(codes.Unavailable, "status updates unavailable")
+	}
+	updates := ps.Channel()
+
+	send := func(st checkoutpb.OrderStatus, heartbeat bool) error {
+		return stream.Send(&checkoutpb.WatchOrderStatusResponse{
+			Status:    st,
+			Heartbeat: heartbeat,
+			At:        time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	// read — статус из кэша; ключа нет — UNSPECIFIED (ждём событий)
+	read := func() (checkoutpb.OrderStatus, error) {
+		v, err := s.rdb.Get(ctx, key).Result()
+		switch {
+		case err == nil:
+			return mapCacheStatus(v), nil
+		case err == redis.Nil:
+			return checkoutpb.OrderStatus_ORDER_STATUS_UNSPECIFIED, nil
+		}
+		s.log.Warn("gateway.checkout.redis: get failed", slog.String("key", key), slog.Any("err", err))
+		return checkoutpb.OrderStatus_ORDER_STATUS_UNSPECIFIED, err
+	}
+
+	// 2) текущее состояние
+	last, err := read()
+	if err != nil {
+		return status.Error(codes.Unavailable, "status cache unavailable")
+	}
+	if err := send(last, false); err != nil {
+		return err
+	}
+	if isTerminal(last) {
+		return nil
+	}
+
+	s.log.Info("gateway.checkout.watch: begin",
+		slog.String("order_id", in.OrderId),
+		slog.String("status", last.String()),
+	)
+
+	hb := time.NewTicker(s.watchHeartbeat)
+	defer hb.Stop()
+
+	// apply отправляет новый статус; true — он терминальный, стрим закрывается
+	apply := func(st checkoutpb.OrderStatus) (bool, error) {
+		if err := send(st, false); err != nil {
+			return false, err
+		}
+		last = st
+		hb.Reset(s.watchHeartbeat)
+		if !isTerminal(st) {
+			return false, nil
+		}
+		s.log.Info("gateway.checkout.watch: terminal status",
+			slog.String("order_id", in.OrderId),
+			slog.String("status", st.String()),
+		)
+		return true, nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("gateway.checkout.watch: client gone", slog.String("order_id", in.OrderId))
+			return status.FromContextError(ctx.Err()).Err()
+
+		case m, ok := <-updates:
+			if !ok {
+				return status.Error(codes.Unavailable, "status updates channel closed")
+			}
+			st := mapCacheStatus(m.Payload)
+			if st == last {
+				continue
+			}
+			if done, err := apply(st); done || err != nil {
+				return err
+			}
+
+		case <-hb.C:
+			// ошибка кэша не рвёт стрим — отправим последний известный статус, перечитаем на следующем тике
+			st, err := read()
+			if err == nil && st != last && st != checkoutpb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+				s.log.Info("gateway.checkout.watch: missed transition",
+					slog.String("order_id", in.OrderId),
+					slog.String("status", st.String()),
+				)
+				if done, err := apply(st); done || err != nil {
+					return err
+				}
+				continue
+			}
+			if err := send(last, true); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func isTerminal(st checkoutpb.OrderStatus) bool {
+	return st == checkoutpb.OrderStatus_ORDER_STATUS_DELIVERED ||
+		st == checkoutpb.OrderStatus_ORDER_STATUS_CANCELLED
+}
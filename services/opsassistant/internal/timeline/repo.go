@@ -14,7 +14,7 @@ func New(db *pgxpool.Pool) *Repo { return &Repo{db: db} }
 
 type Event struct {
 	At     string
-	Source string // orders|payments|outbox|inbox
+	Source string // orders|shipments|payments|outbox|inbox
 	Type   string
 	Detail string
 }
@@ -26,9 +26,9 @@ WITH o AS (
          'orders' AS src,
          'order.created' AS type,
          format(
-           'status=%s amount=%s %s user=%s',
+           'status=%s amount_minor=%s %s user=%s',
            status,
-           to_char(total_amount, 'FM9999999990.00'),
+           amount_minor,
            currency,
            user_id::text
          ) AS detail
@@ -51,14 +51,30 @@ oo AS (
   FROM orders_outbox
   WHERE agg_id = $1::uuid
 ),
+sh AS (
+  SELECT created_at AS ts, 'shipments' AS src, 'shipment.created' AS type,
+         'status=pending' AS detail
+  FROM shipments
+  WHERE order_id = $1::uuid
+  UNION ALL
+  SELECT shipped_at, 'shipments', 'order.shipped',
+         format('carrier=%s tracking=%s', carrier, tracking_number)
+  FROM shipments
+  WHERE order_id = $1::uuid AND shipped_at IS NOT NULL
+  UNION ALL
+  SELECT delivered_at, 'shipments', 'order.delivered',
+         format('carrier=%s tracking=%s', carrier, tracking_number)
+  FROM shipments
+  WHERE order_id = $1::uuid AND delivered_at IS NOT NULL
+),
 p AS (
   SELECT created_at AS ts,
          'payments' AS src,
          CASE WHEN status='confirmed' THEN 'payment.confirmed'
               ELSE 'payment.failed' END AS type,
          format(
-           'amount=%s %s pay_id=%s',
-           to_char(amount_cents/100.0, 'FM999999990.00'),
+           'amount_minor=%s %s pay_id=%s',
+           amount_cents,
            currency,
            id::text
          ) AS detail
@@ -87,6 +103,7 @@ FROM (
   SELECT * FROM o
   UNION ALL SELECT * FROM oi
   UNION ALL SELECT * FROM oo
+  UNION ALL SELECT * FROM sh
   UNION ALL SELECT * FROM p
   UNION ALL SELECT * FROM pi
   UNION ALL SELECT * FROM po
@@ -21,14 +21,16 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Енум статусов заказа
 type OrderStatus int32
 
 const (
-	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
-	OrderStatus_ORDER_STATUS_NEW         OrderStatus = 1
-	OrderStatus_ORDER_STATUS_PAID        OrderStatus = 2
-	OrderStatus_ORDER_STATUS_CANCELLED   OrderStatus = 3
+	OrderStatus_ORDER_STATUS_UNSPECIFIED    OrderStatus = 0
+	OrderStatus_ORDER_STATUS_NEW            OrderStatus = 1
+	OrderStatus_ORDER_STATUS_PAID           OrderStatus = 2
+	OrderStatus_ORDER_STATUS_CANCELLED      OrderStatus = 3
+	OrderStatus_ORDER_STATUS_SHIPPED        OrderStatus = 4
+	OrderStatus_ORDER_STATUS_DELIVERED      OrderStatus = 5
+	OrderStatus_ORDER_STATUS_PAYMENT_FAILED OrderStatus = 6 // оплата не прошла, ждём повтор (RetryPayment) до исчерпания попыток
 )
 
 // Enum value maps for OrderStatus.
@@ -38,12 +40,18 @@ var (
 		1: "ORDER_STATUS_NEW",
 		2: "ORDER_STATUS_PAID",
 		3: "ORDER_STATUS_CANCELLED",
+		4: "ORDER_STATUS_SHIPPED",
+		5: "ORDER_STATUS_DELIVERED",
+		6: "ORDER_STATUS_PAYMENT_FAILED",
 	}
 	OrderStatus_value = map[string]int32{
-		"ORDER_STATUS_UNSPECIFIED": 0,
-		"ORDER_STATUS_NEW":         1,
-		"ORDER_STATUS_PAID":        2,
-		"ORDER_STATUS_CANCELLED":   3,
+		"ORDER_STATUS_UNSPECIFIED":    0,
+		"ORDER_STATUS_NEW":            1,
+		"ORDER_STATUS_PAID":           2,
+		"ORDER_STATUS_CANCELLED":      3,
+		"ORDER_STATUS_SHIPPED":        4,
+		"ORDER_STATUS_DELIVERED":      5,
+		"ORDER_STATUS_PAYMENT_FAILED": 6,
 	}
 )
 
@@ -74,19 +82,349 @@ func (OrderStatus) EnumDescriptor() ([]byte, []int) {
 	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{0}
 }
 
-// Создание заказа
-type CreateOrderRequest struct {
+type OrderSort int32
+
+const (
+	OrderSort_ORDER_SORT_UNSPECIFIED     OrderSort = 0 // = CREATED_AT_DESC
+	OrderSort_ORDER_SORT_CREATED_AT_DESC OrderSort = 1
+	OrderSort_ORDER_SORT_CREATED_AT_ASC  OrderSort = 2
+)
+
+// Enum value maps for OrderSort.
+var (
+	OrderSort_name = map[int32]string{
+		0: "ORDER_SORT_UNSPECIFIED",
+		1: "ORDER_SORT_CREATED_AT_DESC",
+		2: "ORDER_SORT_CREATED_AT_ASC",
+	}
+	OrderSort_value = map[string]int32{
+		"ORDER_SORT_UNSPECIFIED":     0,
+		"ORDER_SORT_CREATED_AT_DESC": 1,
+		"ORDER_SORT_CREATED_AT_ASC":  2,
+	}
+)
+
+func (x OrderSort) Enum() *OrderSort {
+	p := new(OrderSort)
+	*p = x
+	return p
+}
+
+func (x OrderSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_orders_api_orderspb_orders_proto_enumTypes[1].Descriptor()
+}
+
+func (OrderSort) Type() protoreflect.EnumType {
+	return &file_services_orders_api_orderspb_orders_proto_enumTypes[1]
+}
+
+func (x OrderSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderSort.Descriptor instead.
+func (OrderSort) EnumDescriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{1}
+}
+
+type PromotionKind int32
+
+const (
+	PromotionKind_PROMOTION_KIND_UNSPECIFIED PromotionKind = 0
+	PromotionKind_PROMOTION_KIND_PERCENT     PromotionKind = 1
+	PromotionKind_PROMOTION_KIND_FIXED       PromotionKind = 2
+)
+
+// Enum value maps for PromotionKind.
+var (
+	PromotionKind_name = map[int32]string{
+		0: "PROMOTION_KIND_UNSPECIFIED",
+		1: "PROMOTION_KIND_PERCENT",
+		2: "PROMOTION_KIND_FIXED",
+	}
+	PromotionKind_value = map[string]int32{
+		"PROMOTION_KIND_UNSPECIFIED": 0,
+		"PROMOTION_KIND_PERCENT":     1,
+		"PROMOTION_KIND_FIXED":       2,
+	}
+)
+
+func (x PromotionKind) Enum() *PromotionKind {
+	p := new(PromotionKind)
+	*p = x
+	return p
+}
+
+func (x PromotionKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PromotionKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_orders_api_orderspb_orders_proto_enumTypes[2].Descriptor()
+}
+
+func (PromotionKind) Type() protoreflect.EnumType {
+	return &file_services_orders_api_orderspb_orders_proto_enumTypes[2]
+}
+
+func (x PromotionKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PromotionKind.Descriptor instead.
+func (PromotionKind) EnumDescriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{2}
+}
+
+// Money — точная сумма: units_minor в минорных единицах currency (ISO-4217).
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UnitsMinor    int64                  `protobuf:"varint,1,opt,name=units_minor,json=unitsMinor,proto3" json:"units_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetUnitsMinor() int64 {
+	if x != nil {
+		return x.UnitsMinor
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Address — адрес доставки; country — ISO 3166-1 alpha-2.
+type Address struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                 // UUID пользователя
-	AmountCents   int64                  `protobuf:"varint,2,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"` // сумма в минорных единицах
-	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`                           // напр. "RUB"
+	Recipient     string                 `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Phone         string                 `protobuf:"bytes,2,opt,name=phone,proto3" json:"phone,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	City          string                 `protobuf:"bytes,5,opt,name=city,proto3" json:"city,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Line1         string                 `protobuf:"bytes,7,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,8,opt,name=line2,proto3" json:"line2,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Address) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Address) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+// Shipment — доставка: pending (после оплаты) -> shipped -> delivered.
+type Shipment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Status         string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Carrier        string                 `protobuf:"bytes,2,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,3,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	ShippedAt      string                 `protobuf:"bytes,4,opt,name=shipped_at,json=shippedAt,proto3" json:"shipped_at,omitempty"`       // RFC3339, пусто — ещё не отправлен
+	DeliveredAt    string                 `protobuf:"bytes,5,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"` // RFC3339, пусто — ещё не доставлен
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Shipment) Reset() {
+	*x = Shipment{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shipment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shipment) ProtoMessage() {}
+
+func (x *Shipment) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shipment.ProtoReflect.Descriptor instead.
+func (*Shipment) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Shipment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Shipment) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *Shipment) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *Shipment) GetShippedAt() string {
+	if x != nil {
+		return x.ShippedAt
+	}
+	return ""
+}
+
+func (x *Shipment) GetDeliveredAt() string {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return ""
+}
+
+type CreateOrderRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AmountCents     int64                  `protobuf:"varint,2,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	Currency        string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	ShippingAddress *Address               `protobuf:"bytes,4,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"` // опционально
+	PromoCode       string                 `protobuf:"bytes,5,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`                   // опционально; amount_cents — сумма до скидки
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *CreateOrderRequest) Reset() {
 	*x = CreateOrderRequest{}
-	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[0]
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -98,7 +436,7 @@ func (x *CreateOrderRequest) String() string {
 func (*CreateOrderRequest) ProtoMessage() {}
 
 func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[0]
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -111,7 +449,7 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{0}
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateOrderRequest) GetUserId() string {
@@ -135,20 +473,38 @@ func (x *CreateOrderRequest) GetCurrency() string {
 	return ""
 }
 
+func (x *CreateOrderRequest) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
 type CreateOrderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"` // enum, не string
-	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
-	TotalAmount   float64                `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"` // в мажорных единицах
-	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`         // RFC3339
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	OrderId  string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status   OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"`
+	Currency string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Deprecated: Marked as deprecated in services/orders/api/orderspb/orders.proto.
+	TotalAmount   float64 `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"` // используйте total; заполняется до v2
+	CreatedAt     string  `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Total         *Money  `protobuf:"bytes,6,opt,name=total,proto3" json:"total,omitempty"`       // к оплате, после скидки
+	Discount      *Money  `protobuf:"bytes,7,opt,name=discount,proto3" json:"discount,omitempty"` // пусто — без промо-кода
+	PromoCode     string  `protobuf:"bytes,8,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateOrderResponse) Reset() {
 	*x = CreateOrderResponse{}
-	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[1]
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -160,7 +516,7 @@ func (x *CreateOrderResponse) String() string {
 func (*CreateOrderResponse) ProtoMessage() {}
 
 func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[1]
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -173,7 +529,7 @@ func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
 func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
-	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{1}
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateOrderResponse) GetOrderId() string {
@@ -197,6 +553,7 @@ func (x *CreateOrderResponse) GetCurrency() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in services/orders/api/orderspb/orders.proto.
 func (x *CreateOrderResponse) GetTotalAmount() float64 {
 	if x != nil {
 		return x.TotalAmount
@@ -211,6 +568,27 @@ func (x *CreateOrderResponse) GetCreatedAt() string {
 	return ""
 }
 
+func (x *CreateOrderResponse) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *CreateOrderResponse) GetDiscount() *Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *CreateOrderResponse) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
 type GetOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
@@ -220,7 +598,7 @@ type GetOrderRequest struct {
 
 func (x *GetOrderRequest) Reset() {
 	*x = GetOrderRequest{}
-	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[2]
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -232,7 +610,7 @@ func (x *GetOrderRequest) String() string {
 func (*GetOrderRequest) ProtoMessage() {}
 
 func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[2]
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -245,7 +623,7 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{2}
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetOrderRequest) GetOrderId() string {
@@ -256,21 +634,27 @@ func (x *GetOrderRequest) GetOrderId() string {
 }
 
 type GetOrderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"`
-	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
-	TotalAmount   float64                `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	OrderId  string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId   string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status   OrderStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"`
+	Currency string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Deprecated: Marked as deprecated in services/orders/api/orderspb/orders.proto.
+	TotalAmount     float64   `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"` // используйте total; заполняется до v2
+	CreatedAt       string    `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       string    `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Total           *Money    `protobuf:"bytes,8,opt,name=total,proto3" json:"total,omitempty"`
+	ShippingAddress *Address  `protobuf:"bytes,9,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	Shipment        *Shipment `protobuf:"bytes,10,opt,name=shipment,proto3" json:"shipment,omitempty"`
+	Discount        *Money    `protobuf:"bytes,11,opt,name=discount,proto3" json:"discount,omitempty"` // пусто — без промо-кода
+	PromoCode       string    `protobuf:"bytes,12,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GetOrderResponse) Reset() {
 	*x = GetOrderResponse{}
-	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[3]
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -282,7 +666,7 @@ func (x *GetOrderResponse) String() string {
 func (*GetOrderResponse) ProtoMessage() {}
 
 func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[3]
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -295,7 +679,7 @@ func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
 func (*GetOrderResponse) Descriptor() ([]byte, []int) {
-	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{3}
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetOrderResponse) GetOrderId() string {
@@ -326,6 +710,7 @@ func (x *GetOrderResponse) GetCurrency() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in services/orders/api/orderspb/orders.proto.
 func (x *GetOrderResponse) GetTotalAmount() float64 {
 	if x != nil {
 		return x.TotalAmount
@@ -347,42 +732,901 @@ func (x *GetOrderResponse) GetUpdatedAt() string {
 	return ""
 }
 
-var File_services_orders_api_orderspb_orders_proto protoreflect.FileDescriptor
+func (x *GetOrderResponse) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetShipment() *Shipment {
+	if x != nil {
+		return x.Shipment
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetDiscount() *Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"`
+	Total         *Money                 `protobuf:"bytes,4,opt,name=total,proto3" json:"total,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Order) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Order) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *Order) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Order) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Order) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// ListOrders — admin: требует metadata "x-admin-token".
+type ListOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // пусто — все пользователи
+	Statuses      []OrderStatus          `protobuf:"varint,2,rep,packed,name=statuses,proto3,enum=orders.v1.OrderStatus" json:"statuses,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	CreatedFrom   string                 `protobuf:"bytes,4,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"` // RFC3339, включительно
+	CreatedTo     string                 `protobuf:"bytes,5,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`       // RFC3339, не включительно
+	Sort          OrderSort              `protobuf:"varint,6,opt,name=sort,proto3,enum=orders.v1.OrderSort" json:"sort,omitempty"`
+	PageSize      int32                  `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // по умолчанию 20, максимум 100
+	PageToken     string                 `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token предыдущей страницы
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListOrdersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetStatuses() []OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListOrdersRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetCreatedFrom() string {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetCreatedTo() string {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetSort() OrderSort {
+	if x != nil {
+		return x.Sort
+	}
+	return OrderSort_ORDER_SORT_UNSPECIFIED
+}
+
+func (x *ListOrdersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // пусто — последняя страница
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ShipOrder / DeliverOrder — admin: требуют metadata "x-admin-token".
+type ShipOrderRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrderId        string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Carrier        string                 `protobuf:"bytes,2,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,3,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ShipOrderRequest) Reset() {
+	*x = ShipOrderRequest{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipOrderRequest) ProtoMessage() {}
+
+func (x *ShipOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipOrderRequest.ProtoReflect.Descriptor instead.
+func (*ShipOrderRequest) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ShipOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ShipOrderRequest) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *ShipOrderRequest) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+type DeliverOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliverOrderRequest) Reset() {
+	*x = DeliverOrderRequest{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliverOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliverOrderRequest) ProtoMessage() {}
+
+func (x *DeliverOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliverOrderRequest.ProtoReflect.Descriptor instead.
+func (*DeliverOrderRequest) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeliverOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type FulfillmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"`
+	Shipment      *Shipment              `protobuf:"bytes,3,opt,name=shipment,proto3" json:"shipment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FulfillmentResponse) Reset() {
+	*x = FulfillmentResponse{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FulfillmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FulfillmentResponse) ProtoMessage() {}
+
+func (x *FulfillmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FulfillmentResponse.ProtoReflect.Descriptor instead.
+func (*FulfillmentResponse) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FulfillmentResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *FulfillmentResponse) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *FulfillmentResponse) GetShipment() *Shipment {
+	if x != nil {
+		return x.Shipment
+	}
+	return nil
+}
+
+type Promotion struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Code           string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Kind           PromotionKind          `protobuf:"varint,2,opt,name=kind,proto3,enum=orders.v1.PromotionKind" json:"kind,omitempty"`
+	PercentOff     int32                  `protobuf:"varint,3,opt,name=percent_off,json=percentOff,proto3" json:"percent_off,omitempty"`             // 1..99 для PERCENT
+	AmountOff      *Money                 `protobuf:"bytes,4,opt,name=amount_off,json=amountOff,proto3" json:"amount_off,omitempty"`                 // для FIXED
+	MinAmount      *Money                 `protobuf:"bytes,5,opt,name=min_amount,json=minAmount,proto3" json:"min_amount,omitempty"`                 // пусто — без минимума
+	Currency       string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`                                    // пусто — любая валюта (только PERCENT без min_amount)
+	StartsAt       string                 `protobuf:"bytes,7,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`                    // RFC3339
+	EndsAt         string                 `protobuf:"bytes,8,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`                          // RFC3339, пусто — бессрочно
+	MaxRedemptions int32                  `protobuf:"varint,9,opt,name=max_redemptions,json=maxRedemptions,proto3" json:"max_redemptions,omitempty"` // 0 — без общего лимита
+	PerUserLimit   int32                  `protobuf:"varint,10,opt,name=per_user_limit,json=perUserLimit,proto3" json:"per_user_limit,omitempty"`    // по умолчанию 1
+	Active         bool                   `protobuf:"varint,11,opt,name=active,proto3" json:"active,omitempty"`
+	Redemptions    int64                  `protobuf:"varint,12,opt,name=redemptions,proto3" json:"redemptions,omitempty"` // только в ответах; без отменённых заказов
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Promotion) Reset() {
+	*x = Promotion{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Promotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Promotion) ProtoMessage() {}
+
+func (x *Promotion) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Promotion.ProtoReflect.Descriptor instead.
+func (*Promotion) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Promotion) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Promotion) GetKind() PromotionKind {
+	if x != nil {
+		return x.Kind
+	}
+	return PromotionKind_PROMOTION_KIND_UNSPECIFIED
+}
+
+func (x *Promotion) GetPercentOff() int32 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *Promotion) GetAmountOff() *Money {
+	if x != nil {
+		return x.AmountOff
+	}
+	return nil
+}
+
+func (x *Promotion) GetMinAmount() *Money {
+	if x != nil {
+		return x.MinAmount
+	}
+	return nil
+}
+
+func (x *Promotion) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Promotion) GetStartsAt() string {
+	if x != nil {
+		return x.StartsAt
+	}
+	return ""
+}
+
+func (x *Promotion) GetEndsAt() string {
+	if x != nil {
+		return x.EndsAt
+	}
+	return ""
+}
+
+func (x *Promotion) GetMaxRedemptions() int32 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *Promotion) GetPerUserLimit() int32 {
+	if x != nil {
+		return x.PerUserLimit
+	}
+	return 0
+}
+
+func (x *Promotion) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Promotion) GetRedemptions() int64 {
+	if x != nil {
+		return x.Redemptions
+	}
+	return 0
+}
+
+type CreatePromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotion     *Promotion             `protobuf:"bytes,1,opt,name=promotion,proto3" json:"promotion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePromotionRequest) Reset() {
+	*x = CreatePromotionRequest{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromotionRequest) ProtoMessage() {}
+
+func (x *CreatePromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromotionRequest.ProtoReflect.Descriptor instead.
+func (*CreatePromotionRequest) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreatePromotionRequest) GetPromotion() *Promotion {
+	if x != nil {
+		return x.Promotion
+	}
+	return nil
+}
+
+type ListPromotionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActiveOnly    bool                   `protobuf:"varint,1,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromotionsRequest) Reset() {
+	*x = ListPromotionsRequest{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromotionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromotionsRequest) ProtoMessage() {}
+
+func (x *ListPromotionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromotionsRequest.ProtoReflect.Descriptor instead.
+func (*ListPromotionsRequest) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListPromotionsRequest) GetActiveOnly() bool {
+	if x != nil {
+		return x.ActiveOnly
+	}
+	return false
+}
+
+type ListPromotionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotions    []*Promotion           `protobuf:"bytes,1,rep,name=promotions,proto3" json:"promotions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromotionsResponse) Reset() {
+	*x = ListPromotionsResponse{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromotionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromotionsResponse) ProtoMessage() {}
+
+func (x *ListPromotionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromotionsResponse.ProtoReflect.Descriptor instead.
+func (*ListPromotionsResponse) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListPromotionsResponse) GetPromotions() []*Promotion {
+	if x != nil {
+		return x.Promotions
+	}
+	return nil
+}
+
+type DeactivatePromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivatePromotionRequest) Reset() {
+	*x = DeactivatePromotionRequest{}
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivatePromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivatePromotionRequest) ProtoMessage() {}
+
+func (x *DeactivatePromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_orders_api_orderspb_orders_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivatePromotionRequest.ProtoReflect.Descriptor instead.
+func (*DeactivatePromotionRequest) Descriptor() ([]byte, []int) {
+	return file_services_orders_api_orderspb_orders_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeactivatePromotionRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+var File_services_orders_api_orderspb_orders_proto protoreflect.FileDescriptor
 
 const file_services_orders_api_orderspb_orders_proto_rawDesc = "" +
 	"\n" +
-	")services/orders/api/orderspb/orders.proto\x12\torders.v1\"l\n" +
+	")services/orders/api/orderspb/orders.proto\x12\torders.v1\"D\n" +
+	"\x05Money\x12\x1f\n" +
+	"\vunits_minor\x18\x01 \x01(\x03R\n" +
+	"unitsMinor\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xd0\x01\n" +
+	"\aAddress\x12\x1c\n" +
+	"\trecipient\x18\x01 \x01(\tR\trecipient\x12\x14\n" +
+	"\x05phone\x18\x02 \x01(\tR\x05phone\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x04 \x01(\tR\x06region\x12\x12\n" +
+	"\x04city\x18\x05 \x01(\tR\x04city\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12\x14\n" +
+	"\x05line1\x18\a \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\b \x01(\tR\x05line2\"\xa7\x01\n" +
+	"\bShipment\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\acarrier\x18\x02 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x03 \x01(\tR\x0etrackingNumber\x12\x1d\n" +
+	"\n" +
+	"shipped_at\x18\x04 \x01(\tR\tshippedAt\x12!\n" +
+	"\fdelivered_at\x18\x05 \x01(\tR\vdeliveredAt\"\xca\x01\n" +
 	"\x12CreateOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
 	"\famount_cents\x18\x02 \x01(\x03R\vamountCents\x12\x1a\n" +
-	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\xbe\x01\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12=\n" +
+	"\x10shipping_address\x18\x04 \x01(\v2\x12.orders.v1.AddressR\x0fshippingAddress\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\x05 \x01(\tR\tpromoCode\"\xb7\x02\n" +
 	"\x13CreateOrderResponse\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12.\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x16.orders.v1.OrderStatusR\x06status\x12\x1a\n" +
-	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12!\n" +
-	"\ftotal_amount\x18\x04 \x01(\x01R\vtotalAmount\x12\x1d\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12%\n" +
+	"\ftotal_amount\x18\x04 \x01(\x01B\x02\x18\x01R\vtotalAmount\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12&\n" +
+	"\x05total\x18\x06 \x01(\v2\x10.orders.v1.MoneyR\x05total\x12,\n" +
+	"\bdiscount\x18\a \x01(\v2\x10.orders.v1.MoneyR\bdiscount\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\tR\tcreatedAt\",\n" +
+	"promo_code\x18\b \x01(\tR\tpromoCode\",\n" +
 	"\x0fGetOrderRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\tR\aorderId\"\xf3\x01\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\xdc\x03\n" +
 	"\x10GetOrderResponse\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12.\n" +
 	"\x06status\x18\x03 \x01(\x0e2\x16.orders.v1.OrderStatusR\x06status\x12\x1a\n" +
-	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12!\n" +
-	"\ftotal_amount\x18\x05 \x01(\x01R\vtotalAmount\x12\x1d\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12%\n" +
+	"\ftotal_amount\x18\x05 \x01(\x01B\x02\x18\x01R\vtotalAmount\x12\x1d\n" +
 	"\n" +
 	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\tR\tupdatedAt*t\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x12&\n" +
+	"\x05total\x18\b \x01(\v2\x10.orders.v1.MoneyR\x05total\x12=\n" +
+	"\x10shipping_address\x18\t \x01(\v2\x12.orders.v1.AddressR\x0fshippingAddress\x12/\n" +
+	"\bshipment\x18\n" +
+	" \x01(\v2\x13.orders.v1.ShipmentR\bshipment\x12,\n" +
+	"\bdiscount\x18\v \x01(\v2\x10.orders.v1.MoneyR\bdiscount\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\f \x01(\tR\tpromoCode\"\xd1\x01\n" +
+	"\x05Order\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12.\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x16.orders.v1.OrderStatusR\x06status\x12&\n" +
+	"\x05total\x18\x04 \x01(\v2\x10.orders.v1.MoneyR\x05total\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\"\xa4\x02\n" +
+	"\x11ListOrdersRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x122\n" +
+	"\bstatuses\x18\x02 \x03(\x0e2\x16.orders.v1.OrderStatusR\bstatuses\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12!\n" +
+	"\fcreated_from\x18\x04 \x01(\tR\vcreatedFrom\x12\x1d\n" +
+	"\n" +
+	"created_to\x18\x05 \x01(\tR\tcreatedTo\x12(\n" +
+	"\x04sort\x18\x06 \x01(\x0e2\x14.orders.v1.OrderSortR\x04sort\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageToken\"f\n" +
+	"\x12ListOrdersResponse\x12(\n" +
+	"\x06orders\x18\x01 \x03(\v2\x10.orders.v1.OrderR\x06orders\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"p\n" +
+	"\x10ShipOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x18\n" +
+	"\acarrier\x18\x02 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x03 \x01(\tR\x0etrackingNumber\"0\n" +
+	"\x13DeliverOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\x91\x01\n" +
+	"\x13FulfillmentResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.orders.v1.OrderStatusR\x06status\x12/\n" +
+	"\bshipment\x18\x03 \x01(\v2\x13.orders.v1.ShipmentR\bshipment\"\xab\x03\n" +
+	"\tPromotion\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12,\n" +
+	"\x04kind\x18\x02 \x01(\x0e2\x18.orders.v1.PromotionKindR\x04kind\x12\x1f\n" +
+	"\vpercent_off\x18\x03 \x01(\x05R\n" +
+	"percentOff\x12/\n" +
+	"\n" +
+	"amount_off\x18\x04 \x01(\v2\x10.orders.v1.MoneyR\tamountOff\x12/\n" +
+	"\n" +
+	"min_amount\x18\x05 \x01(\v2\x10.orders.v1.MoneyR\tminAmount\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x1b\n" +
+	"\tstarts_at\x18\a \x01(\tR\bstartsAt\x12\x17\n" +
+	"\aends_at\x18\b \x01(\tR\x06endsAt\x12'\n" +
+	"\x0fmax_redemptions\x18\t \x01(\x05R\x0emaxRedemptions\x12$\n" +
+	"\x0eper_user_limit\x18\n" +
+	" \x01(\x05R\fperUserLimit\x12\x16\n" +
+	"\x06active\x18\v \x01(\bR\x06active\x12 \n" +
+	"\vredemptions\x18\f \x01(\x03R\vredemptions\"L\n" +
+	"\x16CreatePromotionRequest\x122\n" +
+	"\tpromotion\x18\x01 \x01(\v2\x14.orders.v1.PromotionR\tpromotion\"8\n" +
+	"\x15ListPromotionsRequest\x12\x1f\n" +
+	"\vactive_only\x18\x01 \x01(\bR\n" +
+	"activeOnly\"N\n" +
+	"\x16ListPromotionsResponse\x124\n" +
+	"\n" +
+	"promotions\x18\x01 \x03(\v2\x14.orders.v1.PromotionR\n" +
+	"promotions\"0\n" +
+	"\x1aDeactivatePromotionRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code*\xcb\x01\n" +
 	"\vOrderStatus\x12\x1c\n" +
 	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x14\n" +
 	"\x10ORDER_STATUS_NEW\x10\x01\x12\x15\n" +
 	"\x11ORDER_STATUS_PAID\x10\x02\x12\x1a\n" +
-	"\x16ORDER_STATUS_CANCELLED\x10\x032\x9b\x01\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x03\x12\x18\n" +
+	"\x14ORDER_STATUS_SHIPPED\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_DELIVERED\x10\x05\x12\x1f\n" +
+	"\x1bORDER_STATUS_PAYMENT_FAILED\x10\x06*f\n" +
+	"\tOrderSort\x12\x1a\n" +
+	"\x16ORDER_SORT_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aORDER_SORT_CREATED_AT_DESC\x10\x01\x12\x1d\n" +
+	"\x19ORDER_SORT_CREATED_AT_ASC\x10\x02*e\n" +
+	"\rPromotionKind\x12\x1e\n" +
+	"\x1aPROMOTION_KIND_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16PROMOTION_KIND_PERCENT\x10\x01\x12\x18\n" +
+	"\x14PROMOTION_KIND_FIXED\x10\x022\xf7\x04\n" +
 	"\x06Orders\x12L\n" +
 	"\vCreateOrder\x12\x1d.orders.v1.CreateOrderRequest\x1a\x1e.orders.v1.CreateOrderResponse\x12C\n" +
-	"\bGetOrder\x12\x1a.orders.v1.GetOrderRequest\x1a\x1b.orders.v1.GetOrderResponseB)Z'./services/orders/api/orderspb;orderspbb\x06proto3"
+	"\bGetOrder\x12\x1a.orders.v1.GetOrderRequest\x1a\x1b.orders.v1.GetOrderResponse\x12I\n" +
+	"\n" +
+	"ListOrders\x12\x1c.orders.v1.ListOrdersRequest\x1a\x1d.orders.v1.ListOrdersResponse\x12H\n" +
+	"\tShipOrder\x12\x1b.orders.v1.ShipOrderRequest\x1a\x1e.orders.v1.FulfillmentResponse\x12N\n" +
+	"\fDeliverOrder\x12\x1e.orders.v1.DeliverOrderRequest\x1a\x1e.orders.v1.FulfillmentResponse\x12J\n" +
+	"\x0fCreatePromotion\x12!.orders.v1.CreatePromotionRequest\x1a\x14.orders.v1.Promotion\x12U\n" +
+	"\x0eListPromotions\x12 .orders.v1.ListPromotionsRequest\x1a!.orders.v1.ListPromotionsResponse\x12R\n" +
+	"\x13DeactivatePromotion\x12%.orders.v1.DeactivatePromotionRequest\x1a\x14.orders.v1.PromotionB)Z'./services/orders/api/orderspb;orderspbb\x06proto3"
 
 var (
 	file_services_orders_api_orderspb_orders_proto_rawDescOnce sync.Once
@@ -396,27 +1640,74 @@ func file_services_orders_api_orderspb_orders_proto_rawDescGZIP() []byte {
 	return file_services_orders_api_orderspb_orders_proto_rawDescData
 }
 
-var file_services_orders_api_orderspb_orders_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_services_orders_api_orderspb_orders_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_services_orders_api_orderspb_orders_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_services_orders_api_orderspb_orders_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_services_orders_api_orderspb_orders_proto_goTypes = []any{
-	(OrderStatus)(0),            // 0: orders.v1.OrderStatus
-	(*CreateOrderRequest)(nil),  // 1: orders.v1.CreateOrderRequest
-	(*CreateOrderResponse)(nil), // 2: orders.v1.CreateOrderResponse
-	(*GetOrderRequest)(nil),     // 3: orders.v1.GetOrderRequest
-	(*GetOrderResponse)(nil),    // 4: orders.v1.GetOrderResponse
+	(OrderStatus)(0),                   // 0: orders.v1.OrderStatus
+	(OrderSort)(0),                     // 1: orders.v1.OrderSort
+	(PromotionKind)(0),                 // 2: orders.v1.PromotionKind
+	(*Money)(nil),                      // 3: orders.v1.Money
+	(*Address)(nil),                    // 4: orders.v1.Address
+	(*Shipment)(nil),                   // 5: orders.v1.Shipment
+	(*CreateOrderRequest)(nil),         // 6: orders.v1.CreateOrderRequest
+	(*CreateOrderResponse)(nil),        // 7: orders.v1.CreateOrderResponse
+	(*GetOrderRequest)(nil),            // 8: orders.v1.GetOrderRequest
+	(*GetOrderResponse)(nil),           // 9: orders.v1.GetOrderResponse
+	(*Order)(nil),                      // 10: orders.v1.Order
+	(*ListOrdersRequest)(nil),          // 11: orders.v1.ListOrdersRequest
+	(*ListOrdersResponse)(nil),         // 12: orders.v1.ListOrdersResponse
+	(*ShipOrderRequest)(nil),           // 13: orders.v1.ShipOrderRequest
+	(*DeliverOrderRequest)(nil),        // 14: orders.v1.DeliverOrderRequest
+	(*FulfillmentResponse)(nil),        // 15: orders.v1.FulfillmentResponse
+	(*Promotion)(nil),                  // 16: orders.v1.Promotion
+	(*CreatePromotionRequest)(nil),     // 17: orders.v1.CreatePromotionRequest
+	(*ListPromotionsRequest)(nil),      // 18: orders.v1.ListPromotionsRequest
+	(*ListPromotionsResponse)(nil),     // 19: orders.v1.ListPromotionsResponse
+	(*DeactivatePromotionRequest)(nil), // 20: orders.v1.DeactivatePromotionRequest
 }
 var file_services_orders_api_orderspb_orders_proto_depIdxs = []int32{
-	0, // 0: orders.v1.CreateOrderResponse.status:type_name -> orders.v1.OrderStatus
-	0, // 1: orders.v1.GetOrderResponse.status:type_name -> orders.v1.OrderStatus
-	1, // 2: orders.v1.Orders.CreateOrder:input_type -> orders.v1.CreateOrderRequest
-	3, // 3: orders.v1.Orders.GetOrder:input_type -> orders.v1.GetOrderRequest
-	2, // 4: orders.v1.Orders.CreateOrder:output_type -> orders.v1.CreateOrderResponse
-	4, // 5: orders.v1.Orders.GetOrder:output_type -> orders.v1.GetOrderResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	4,  // 0: orders.v1.CreateOrderRequest.shipping_address:type_name -> orders.v1.Address
+	0,  // 1: orders.v1.CreateOrderResponse.status:type_name -> orders.v1.OrderStatus
+	3,  // 2: orders.v1.CreateOrderResponse.total:type_name -> orders.v1.Money
+	3,  // 3: orders.v1.CreateOrderResponse.discount:type_name -> orders.v1.Money
+	0,  // 4: orders.v1.GetOrderResponse.status:type_name -> orders.v1.OrderStatus
+	3,  // 5: orders.v1.GetOrderResponse.total:type_name -> orders.v1.Money
+	4,  // 6: orders.v1.GetOrderResponse.shipping_address:type_name -> orders.v1.Address
+	5,  // 7: orders.v1.GetOrderResponse.shipment:type_name -> orders.v1.Shipment
+	3,  // 8: orders.v1.GetOrderResponse.discount:type_name -> orders.v1.Money
+	0,  // 9: orders.v1.Order.status:type_name -> orders.v1.OrderStatus
+	3,  // 10: orders.v1.Order.total:type_name -> orders.v1.Money
+	0,  // 11: orders.v1.ListOrdersRequest.statuses:type_name -> orders.v1.OrderStatus
+	1,  // 12: orders.v1.ListOrdersRequest.sort:type_name -> orders.v1.OrderSort
+	10, // 13: orders.v1.ListOrdersResponse.orders:type_name -> orders.v1.Order
+	0,  // 14: orders.v1.FulfillmentResponse.status:type_name -> orders.v1.OrderStatus
+	5,  // 15: orders.v1.FulfillmentResponse.shipment:type_name -> orders.v1.Shipment
+	2,  // 16: orders.v1.Promotion.kind:type_name -> orders.v1.PromotionKind
+	3,  // 17: orders.v1.Promotion.amount_off:type_name -> orders.v1.Money
+	3,  // 18: orders.v1.Promotion.min_amount:type_name -> orders.v1.Money
+	16, // 19: orders.v1.CreatePromotionRequest.promotion:type_name -> orders.v1.Promotion
+	16, // 20: orders.v1.ListPromotionsResponse.promotions:type_name -> orders.v1.Promotion
+	6,  // 21: orders.v1.Orders.CreateOrder:input_type -> orders.v1.CreateOrderRequest
+	8,  // 22: orders.v1.Orders.GetOrder:input_type -> orders.v1.GetOrderRequest
+	11, // 23: orders.v1.Orders.ListOrders:input_type -> orders.v1.ListOrdersRequest
+	13, // 24: orders.v1.Orders.ShipOrder:input_type -> orders.v1.ShipOrderRequest
+	14, // 25: orders.v1.Orders.DeliverOrder:input_type -> orders.v1.DeliverOrderRequest
+	17, // 26: orders.v1.Orders.CreatePromotion:input_type -> orders.v1.CreatePromotionRequest
+	18, // 27: orders.v1.Orders.ListPromotions:input_type -> orders.v1.ListPromotionsRequest
+	20, // 28: orders.v1.Orders.DeactivatePromotion:input_type -> orders.v1.DeactivatePromotionRequest
+	7,  // 29: orders.v1.Orders.CreateOrder:output_type -> orders.v1.CreateOrderResponse
+	9,  // 30: orders.v1.Orders.GetOrder:output_type -> orders.v1.GetOrderResponse
+	12, // 31: orders.v1.Orders.ListOrders:output_type -> orders.v1.ListOrdersResponse
+	15, // 32: orders.v1.Orders.ShipOrder:output_type -> orders.v1.FulfillmentResponse
+	15, // 33: orders.v1.Orders.DeliverOrder:output_type -> orders.v1.FulfillmentResponse
+	16, // 34: orders.v1.Orders.CreatePromotion:output_type -> orders.v1.Promotion
+	19, // 35: orders.v1.Orders.ListPromotions:output_type -> orders.v1.ListPromotionsResponse
+	16, // 36: orders.v1.Orders.DeactivatePromotion:output_type -> orders.v1.Promotion
+	29, // [29:37] is the sub-list for method output_type
+	21, // [21:29] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_services_orders_api_orderspb_orders_proto_init() }
@@ -429,8 +1720,8 @@ func file_services_orders_api_orderspb_orders_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_services_orders_api_orderspb_orders_proto_rawDesc), len(file_services_orders_api_orderspb_orders_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   4,
+			NumEnums:      3,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -17,13 +17,22 @@ import (
 	"goshop/pkg/httpx"
 	"goshop/pkg/jwtauth"
 	"goshop/pkg/logger"
+	"goshop/pkg/metrics"
+	"goshop/pkg/money"
 	"goshop/pkg/postgres"
+	"goshop/pkg/ratelimit"
+	"goshop/pkg/revocation"
 
 	"goshop/services/orders/config"
 	httpadp "goshop/services/orders/internal/adapters/http"
+	"goshop/services/orders/internal/adapters/repo/fxpg"
 	"goshop/services/orders/internal/adapters/repo/orderpg"
 	"goshop/services/orders/internal/consumer"
+	"goshop/services/orders/internal/expiry"
+	"goshop/services/orders/internal/fulfillment"
 	grpcsvr "goshop/services/orders/internal/grpc"
+	"goshop/services/orders/internal/promotions"
+	"goshop/services/orders/internal/statuscache"
 )
 
 const shutdownHTTP = 10 * time.Second
@@ -52,6 +61,24 @@ func main() {
 		slog.String("redis.addr", cfg.Redis.Addr),
 	)
 
+	// Metrics (Prometheus): пока только решения rate limiter
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = "docker"
+	}
+	met, err := metrics.Init(log, metrics.Config{
+		Service:   "orders",
+		Namespace: "goshop",
+		Addr:      ":2112",
+		Version:   "dev",
+		Env:       env,
+	})
+	if err != nil {
+		log.Error("metrics: init failed", slog.Any("err", err))
+		return
+	}
+	defer func() { _ = met.Shutdown(context.Background()) }()
+
 	// Postgres
 	pgStart := time.Now()
 	pool, err := postgres.NewPool(ctx, cfg.Postgres)
@@ -70,22 +97,49 @@ func main() {
 	)
 	defer pool.Close()
 
-	// Repository
-	repo := orderpg.NewRepo(pool)
+	// Repository + промо-коды (погашаются в транзакции создания заказа)
+	promo := promotions.New(log, pool)
+	repo := orderpg.NewRepo(pool, promo)
 
-	// JWT
-	jwtm := jwtauth.New(jwtauth.Config{
-		Secret:     cfg.JWT.Secret,
-		Issuer:     cfg.JWT.Issuer,
-		AccessTTL:  cfg.JWT.AccessTTL,
-		RefreshTTL: cfg.JWT.RefreshTTL,
-		// если в конфиге есть audience — можно добавить, но поведение не меняем
-	})
-	log.Info("jwt: manager initialized", slog.String("issuer", cfg.JWT.Issuer))
+	// FX: базовая валюта + стартовые курсы из конфига
+	fxBase := money.MustCurrency(cfg.FX.Base)
+	fxRepo := fxpg.NewRepo(pool, fxBase)
+	for code, rate := range cfg.FX.Rates {
+		if err := fxRepo.Upsert(ctx, money.MustCurrency(code), fxBase, rate); err != nil {
+			log.Error("fx: seed rate failed", slog.String("pair", code+"/"+fxBase.Code), slog.Any("err", err))
+			return
+		}
+	}
+	log.Info("fx: ready", slog.String("base", fxBase.Code), slog.Int("seeded", len(cfg.FX.Rates)))
 
-	// HTTP module + server
-	ordersHTTP := httpadp.NewModule(log, pool, repo, jwtm)
-	srv := httpx.NewServer(cfg.HTTP, log, httpx.WithModules(ordersHTTP))
+	// JWT: публичные ключи из JWKS users; secret — только для HS256-токенов, выданных до перехода
+	var jwtm jwtauth.TokenVerifier
+	if cfg.JWT.JWKSURL != "" {
+		v := jwtauth.NewVerifier(jwtauth.VerifierConfig{
+			JWKSURL: cfg.JWT.JWKSURL,
+			Issuer:  cfg.JWT.Issuer,
+			Secret:  cfg.JWT.Secret,
+			Refresh: cfg.JWT.JWKSRefresh,
+		})
+		// users может подняться позже — ключи догрузятся на первом запросе
+		if err := v.Refresh(ctx); err != nil {
+			log.Warn("jwt: jwks prefetch failed", slog.String("url", cfg.JWT.JWKSURL), slog.Any("err", err))
+		}
+		jwtm = v
+		log.Info("jwt: jwks verifier initialized",
+			slog.String("issuer", cfg.JWT.Issuer),
+			slog.String("jwks_url", cfg.JWT.JWKSURL),
+			slog.Bool("hs256_fallback", cfg.JWT.Secret != ""),
+		)
+	} else {
+		jwtm = jwtauth.New(jwtauth.Config{
+			Secret:     cfg.JWT.Secret,
+			Issuer:     cfg.JWT.Issuer,
+			AccessTTL:  cfg.JWT.AccessTTL,
+			RefreshTTL: cfg.JWT.RefreshTTL,
+		})
+		log.Info("jwt: manager initialized", slog.String("issuer", cfg.JWT.Issuer))
+	}
 
 	// Kafka client (read payments.events)
 	kopts := []kgo.Opt{
@@ -125,8 +179,26 @@ func main() {
 	)
 	defer func() { _ = rds.Close() }()
 
+	// HTTP module + server
+	httpOpts := []httpx.Option{}
+	if cfg.RateLimit.Enabled {
+		lim := ratelimit.New(rds, ratelimit.RulesFromConfig(cfg.RateLimit),
+			ratelimit.WithPrefix("rl:orders:"),
+			ratelimit.WithObserver(metrics.NewRateLimitMetrics(met.Registry(), "goshop", "orders")),
+		)
+		httpOpts = append(httpOpts, httpx.WithMiddleware(httpx.RateLimit(log, lim, jwtm)))
+		log.Info("http: rate limiting enabled", slog.Int("rules", len(cfg.RateLimit.Rules)))
+	}
+	rev := revocation.New(rds)
+	ordersHTTP := httpadp.NewModule(log, pool, repo, fxRepo, fxBase, jwtm, rev)
+	srv := httpx.NewServer(cfg.HTTP, log, append(httpOpts, httpx.WithModules(ordersHTTP))...)
+
+	// Status cache + fulfillment (отправка после оплаты, shipped/delivered через admin gRPC)
+	cache := statuscache.New(log, rds, cfg.Redis.TTLStatus)
+	ful := fulfillment.New(log, pool, cache, "orders.events")
+
 	// Processor
-	proc := consumer.NewProcessor(log, pool, rds, cfg.Redis.TTLStatus)
+	proc := consumer.NewProcessor(log, pool, cache, ful)
 
 	// Runner
 	rcfg := consumer.Config{
@@ -145,6 +217,23 @@ func main() {
 		}
 	}()
 
+	// Expiry: отмена заказов, не оплаченных за expiry.ttl
+	if cfg.Expiry.Enabled {
+		exp := expiry.New(log, pool, cache, expiry.Config{
+			TTL:      cfg.Expiry.TTL,
+			Grace:    cfg.Expiry.Grace,
+			Interval: cfg.Expiry.Interval,
+			Batch:    cfg.Expiry.Batch,
+			Topic:    "orders.events",
+		})
+		go func() {
+			if err := exp.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				log.Error("orders-expiry: stopped with error", slog.Any("err", err))
+				stop()
+			}
+		}()
+	}
+
 	// HTTP
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -156,9 +245,15 @@ func main() {
 	// gRPC
 	go func() {
 		if err := grpcsvr.Start(ctx, grpcsvr.Options{
-			Addr:   cfg.GRPC.Addr,
-			Logger: log,
-			Repo:   repo,
+			Addr:        cfg.GRPC.Addr,
+			Logger:      log,
+			Repo:        repo,
+			Fulfillment: ful,
+			Promotions:  promo,
+			Verifier:    jwtm,
+			Revoked:     rev,
+			Audience:    "api", // как у HTTP API: refresh-токен не подходит
+			AdminToken:  cfg.GRPC.AdminToken,
 		}); err != nil && !errors.Is(err, context.Canceled) {
 			log.Error("orders-grpc: stopped with error", slog.Any("err", err))
 			stop()
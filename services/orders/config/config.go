@@ -6,6 +6,7 @@ import (
 	"time"
 
 	cfg "goshop/pkg/config"
+	"goshop/pkg/money"
 )
 
 type Orders struct {
@@ -18,6 +19,10 @@ type Orders struct {
 	Redis    Redis        `mapstructure:"redis"`
 	Consumer Consumer     `mapstructure:"consumer"`
 	GRPC     GRPC         `mapstructure:"grpc"`
+	FX       FX           `mapstructure:"fx"`
+	Expiry   Expiry       `mapstructure:"expiry"`
+
+	RateLimit cfg.RateLimit `mapstructure:"rate_limit"`
 }
 
 type Consumer struct {
@@ -35,7 +40,24 @@ type Redis struct {
 }
 
 type GRPC struct {
-	Addr string `mapstructure:"addr"`
+	Addr       string `mapstructure:"addr"`
+	AdminToken string `mapstructure:"admin_token"` // metadata x-admin-token для admin-методов без JWT; пусто — только роли
+}
+
+// FX — базовая валюта отчётов и стартовые курсы (1 CODE = rate Base), пишутся в fx_rates при старте.
+type FX struct {
+	Base  string            `mapstructure:"base"`
+	Rates map[string]string `mapstructure:"rates"`
+}
+
+// Expiry — автоотмена неоплаченных заказов (new/payment_failed старше TTL) с событием order.expired;
+// Grace — сколько ждать после последнего платёжного события.
+type Expiry struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	TTL      time.Duration `mapstructure:"ttl"`
+	Grace    time.Duration `mapstructure:"grace"`
+	Interval time.Duration `mapstructure:"interval"`
+	Batch    int           `mapstructure:"batch"`
 }
 
 func (o *Orders) Validate() error {
@@ -45,8 +67,8 @@ func (o *Orders) Validate() error {
 	if err := o.Postgres.Validate(); err != nil {
 		return fmt.Errorf("postgres: %w", err)
 	}
-	if o.JWT.Secret == "" {
-		return errors.New("jwt.secret is required (for token verification)")
+	if o.JWT.Secret == "" && o.JWT.JWKSURL == "" {
+		return errors.New("jwt.jwks_url or jwt.secret is required (for token verification)")
 	}
 	if o.Redis.Addr == "" {
 		o.Redis.Addr = "localhost:6379"
@@ -54,6 +76,37 @@ func (o *Orders) Validate() error {
 	if o.Redis.TTLStatus <= 0 {
 		o.Redis.TTLStatus = 24 * time.Hour
 	}
+	if err := o.RateLimit.Validate(); err != nil {
+		return err
+	}
+	if o.Expiry.TTL <= 0 {
+		o.Expiry.TTL = 30 * time.Minute
+	}
+	if o.Expiry.Grace <= 0 {
+		o.Expiry.Grace = 5 * time.Minute
+	}
+	if o.Expiry.Interval <= 0 {
+		o.Expiry.Interval = time.Minute
+	}
+	if o.Expiry.Batch <= 0 {
+		o.Expiry.Batch = 100
+	}
+	if o.FX.Base == "" {
+		o.FX.Base = "RUB"
+	}
+	base, err := money.ParseCurrency(o.FX.Base)
+	if err != nil {
+		return fmt.Errorf("fx.base: %w", err)
+	}
+	o.FX.Base = base.Code
+	for code, rate := range o.FX.Rates {
+		if _, err := money.ParseCurrency(code); err != nil {
+			return fmt.Errorf("fx.rates: %w", err)
+		}
+		if _, err := money.ParseRate(rate); err != nil {
+			return fmt.Errorf("fx.rates.%s: %w", code, err)
+		}
+	}
 	return nil
 }
 
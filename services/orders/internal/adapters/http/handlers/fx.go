@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"goshop/pkg/httpx"
+	"goshop/pkg/money"
+	"goshop/services/orders/internal/adapters/repo/fxpg"
+	"goshop/services/orders/internal/adapters/repo/orderpg"
+)
+
+type FXHandlers struct {
+	log    *slog.Logger
+	fx     *fxpg.Repository
+	orders *orderpg.Repository
+	base   money.Currency
+}
+
+func NewFXHandlers(log *slog.Logger, fx *fxpg.Repository, orders *orderpg.Repository, base money.Currency) *FXHandlers {
+	return &FXHandlers{log: log, fx: fx, orders: orders, base: base}
+}
+
+type moneyResp struct {
+	AmountMinor int64  `json:"amount_minor"`
+	Amount      string `json:"amount"` // десятичная строка в основных единицах
+	Currency    string `json:"currency"`
+}
+
+func toMoneyResp(m money.Money) moneyResp {
+	return moneyResp{AmountMinor: m.Amount, Amount: m.Decimal(), Currency: m.Currency.Code}
+}
+
+type rateResp struct {
+	Base      string `json:"base"`
+	Quote     string `json:"quote"`
+	Rate      string `json:"rate"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// GET /v1/fx/rates
+func (h *FXHandlers) Rates(c *gin.Context) {
+	noCache(c)
+	l := reqLog(c, h.log)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	rates, err := h.fx.List(ctx)
+	if err != nil {
+		l.Error("fx.rates: list failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	out := make([]rateResp, 0, len(rates))
+	for _, r := range rates {
+		out = append(out, rateResp{
+			Base:      r.Base,
+			Quote:     r.Quote,
+			Rate:      r.Rate,
+			UpdatedAt: r.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"base": h.base.Code, "rates": out})
+}
+
+// GET /v1/fx/convert?amount_minor=1999&from=USD&to=RUB
+func (h *FXHandlers) Convert(c *gin.Context) {
+	noCache(c)
+	l := reqLog(c, h.log)
+
+	amount, err := strconv.ParseInt(c.Query("amount_minor"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount_minor must be an integer"})
+		return
+	}
+	from, err := money.ParseCurrency(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported currency: from"})
+		return
+	}
+	to := h.base
+	if q := c.Query("to"); q != "" {
+		if to, err = money.ParseCurrency(q); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported currency: to"})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	rate, err := h.fx.Rate(ctx, from, to)
+	if err != nil {
+		h.rateError(c, l, "fx.convert", err)
+		return
+	}
+	res, err := money.Convert(money.Money{Amount: amount, Currency: from}, to, rate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from": toMoneyResp(money.Money{Amount: amount, Currency: from}),
+		"to":   toMoneyResp(res),
+		"rate": rate.FloatString(12),
+	})
+}
+
+type currencyTotalResp struct {
+	Orders    int64     `json:"orders"`
+	Total     moneyResp `json:"total"`
+	Converted moneyResp `json:"converted"`
+}
+
+// GET /v1/orders/totals?currency=EUR — суммы заказов пользователя, пересчитанные в базовую (или указанную) валюту.
+func (h *FXHandlers) Totals(c *gin.Context) {
+	noCache(c)
+	l := reqLog(c, h.log)
+
+	claims, ok := httpx.GetJWTClaims(c)
+	if !ok || claims.UserID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	base := h.base
+	if q := c.Query("currency"); q != "" {
+		if base, err = money.ParseCurrency(q); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported currency"})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	totals, err := h.orders.TotalsByCurrency(ctx, userUUID)
+	if err != nil {
+		l.Error("orders.totals: repo failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	sum := money.Money{Currency: base}
+	by := make([]currencyTotalResp, 0, len(totals))
+	for _, t := range totals {
+		cur, err := money.ParseCurrency(t.Currency)
+		if err != nil {
+			l.Warn("orders.totals: skip unknown currency", slog.String("currency", t.Currency))
+			continue
+		}
+		m := money.Money{Amount: t.AmountMinor, Currency: cur}
+
+		rate, err := h.fx.Rate(ctx, cur, base)
+		if err != nil {
+			h.rateError(c, l, "orders.totals", err)
+			return
+		}
+		conv, err := money.Convert(m, base, rate)
+		if err != nil {
+			l.Error("orders.totals: convert failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		sum.Amount += conv.Amount
+
+		by = append(by, currencyTotalResp{
+			Orders:    t.Orders,
+			Total:     toMoneyResp(m),
+			Converted: toMoneyResp(conv),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"currency":    base.Code,
+		"total":       toMoneyResp(sum),
+		"by_currency": by,
+	})
+}
+
+func (h *FXHandlers) rateError(c *gin.Context, l *slog.Logger, op string, err error) {
+	if errors.Is(err, fxpg.ErrRateNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	l.Error(op+": rate lookup failed", slog.Any("err", err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+}
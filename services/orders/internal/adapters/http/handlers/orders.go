@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,7 +15,9 @@ import (
 	"github.com/google/uuid"
 
 	"goshop/pkg/httpx"
+	"goshop/pkg/money"
 	"goshop/services/orders/internal/adapters/repo/orderpg"
+	"goshop/services/orders/internal/promotions"
 )
 
 type OrdersHandlers struct {
@@ -27,18 +30,38 @@ func NewOrdersHandlers(log *slog.Logger, repo *orderpg.Repository) *OrdersHandle
 }
 
 type createOrderReq struct {
-	AmountCents int64  `json:"amount_cents"`
-	Currency    string `json:"currency"`
+	AmountCents     int64            `json:"amount_cents"`
+	Currency        string           `json:"currency"`
+	ShippingAddress *orderpg.Address `json:"shipping_address,omitempty"`
+	PromoCode       string           `json:"promo_code,omitempty"` // amount_cents — сумма до скидки
 }
 
-type createOrderResp struct {
+type orderResp struct {
 	ID          string  `json:"id"`
 	UserID      string  `json:"user_id"`
 	Status      string  `json:"status"`
-	TotalAmount float64 `json:"total_amount"`
+	TotalAmount float64 `json:"total_amount"` // legacy, основные единицы
+	AmountMinor int64   `json:"amount_minor"` // к оплате
+	Amount      string  `json:"amount"`
 	Currency    string  `json:"currency"`
 	CreatedAt   string  `json:"created_at"`
 	UpdatedAt   string  `json:"updated_at"`
+
+	// только для заказов с промо-кодом
+	OriginalAmountMinor int64  `json:"original_amount_minor,omitempty"`
+	DiscountMinor       int64  `json:"discount_minor,omitempty"`
+	PromoCode           string `json:"promo_code,omitempty"`
+
+	ShippingAddress *orderpg.Address `json:"shipping_address,omitempty"`
+	Shipment        *shipmentResp    `json:"shipment,omitempty"`
+}
+
+type shipmentResp struct {
+	Status         string  `json:"status"`
+	Carrier        string  `json:"carrier,omitempty"`
+	TrackingNumber string  `json:"tracking_number,omitempty"`
+	ShippedAt      *string `json:"shipped_at,omitempty"`
+	DeliveredAt    *string `json:"delivered_at,omitempty"`
 }
 
 func (h *OrdersHandlers) Create(c *gin.Context) {
@@ -70,9 +93,20 @@ func (h *OrdersHandlers) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "amount_cents must be > 0"})
 		return
 	}
-	cur := strings.ToUpper(strings.TrimSpace(in.Currency))
-	if cur == "" {
-		cur = "RUB"
+	if strings.TrimSpace(in.Currency) == "" {
+		in.Currency = "RUB"
+	}
+	cur, err := money.ParseCurrency(in.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported currency"})
+		return
+	}
+	if a := in.ShippingAddress; a != nil {
+		a.Normalize()
+		if err := a.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	// create order (with outbox)
@@ -82,7 +116,9 @@ func (h *OrdersHandlers) Create(c *gin.Context) {
 	ord, err := h.repo.Create(ctx, orderpg.CreateParams{
 		UserID:      userUUID,
 		AmountCents: in.AmountCents,
-		Currency:    cur,
+		Currency:    cur.Code,
+		Address:     in.ShippingAddress,
+		PromoCode:   in.PromoCode,
 		OutboxTopic: "orders.events",
 		OutboxHeaders: map[string]string{
 			"event-type": "order.created",
@@ -90,6 +126,11 @@ func (h *OrdersHandlers) Create(c *gin.Context) {
 		},
 	})
 	if err != nil {
+		if errors.Is(err, promotions.ErrNotApplicable) || errors.Is(err, orderpg.ErrPromoDisabled) {
+			l.Info("orders.create: promo code rejected", slog.String("promo_code", in.PromoCode), slog.String("reason", err.Error()))
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		l.Error("orders.create: repo.Create failed", slog.Any("err", err))
 		var se *json.SyntaxError
 		if errors.As(err, &se) {
@@ -105,15 +146,204 @@ func (h *OrdersHandlers) Create(c *gin.Context) {
 	c.Header("Cache-Control", "no-store")
 	c.Header("Location", fmt.Sprintf("/v1/orders/%s", ord.ID.String()))
 	c.Status(http.StatusCreated)
-	_ = json.NewEncoder(c.Writer).Encode(createOrderResp{
+	_ = json.NewEncoder(c.Writer).Encode(toOrderResp(ord))
+}
+
+// GET /v1/orders/:id — только свой заказ; ETag по updated_at, If-None-Match -> 304.
+func (h *OrdersHandlers) Get(c *gin.Context) {
+	l := reqLog(c, h.log)
+
+	ord, ok := h.loadOwned(c, l, "orders.get")
+	if !ok {
+		return
+	}
+
+	etag := httpx.ETag(ord.UpdatedAt)
+	// клиент может кэшировать, но обязан ревалидировать
+	c.Header("Cache-Control", "private, no-cache")
+	if httpx.NotModified(c, etag) {
+		return
+	}
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, toOrderResp(ord))
+}
+
+// loadOwned — заказ из :id, принадлежащий пользователю из JWT.
+// Чужой заказ отдаём как 404, чтобы не раскрывать существование.
+// Для изменяющих ручек: после loadOwned — httpx.IfMatch(c, httpx.ETag(ord.UpdatedAt)).
+func (h *OrdersHandlers) loadOwned(c *gin.Context, l *slog.Logger, op string) (*orderpg.Order, bool) {
+	claims, ok := httpx.GetJWTClaims(c)
+	if !ok || claims.UserID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
+	}
+	userUUID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad order id"})
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	ord, err := h.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, orderpg.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			return nil, false
+		}
+		l.Error(op+": repo.GetByID failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+	if ord.UserID != userUUID {
+		l.Warn(op+": foreign order", slog.String("order_id", id.String()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return nil, false
+	}
+	return ord, true
+}
+
+type listOrdersResp struct {
+	Orders        []orderResp `json:"orders"`
+	NextPageToken string      `json:"next_page_token,omitempty"`
+}
+
+// GET /v1/orders?status=new,paid&currency=RUB&created_from=...&created_to=...&sort=-created_at&limit=20&page_token=...
+func (h *OrdersHandlers) List(c *gin.Context) {
+	noCache(c)
+
+	l := reqLog(c, h.log)
+
+	claims, ok := httpx.GetJWTClaims(c)
+	if !ok || claims.UserID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	p := orderpg.ListParams{UserID: &userUUID, PageToken: c.Query("page_token")}
+
+	for _, raw := range c.QueryArray("status") {
+		for _, st := range strings.Split(raw, ",") {
+			st = strings.ToLower(strings.TrimSpace(st))
+			switch st {
+			case "":
+				continue
+			case "new", "payment_failed", "paid", "shipped", "delivered":
+			case "cancelled", "canceled":
+				st = "cancelled"
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status: " + st})
+				return
+			}
+			p.Statuses = append(p.Statuses, st)
+		}
+	}
+	if q := c.Query("currency"); q != "" {
+		cur, err := money.ParseCurrency(q)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported currency"})
+			return
+		}
+		p.Currency = cur.Code
+	}
+	for name, dst := range map[string]*time.Time{"created_from": &p.CreatedFrom, "created_to": &p.CreatedTo} {
+		if q := c.Query(name); q != "" {
+			t, err := time.Parse(time.RFC3339, q)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be RFC3339"})
+				return
+			}
+			*dst = t
+		}
+	}
+	switch c.DefaultQuery("sort", "-created_at") {
+	case "-created_at":
+		p.Sort = orderpg.SortCreatedDesc
+	case "created_at":
+		p.Sort = orderpg.SortCreatedAsc
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be created_at or -created_at"})
+		return
+	}
+	if q := c.Query("limit"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n <= 0 || n > orderpg.MaxPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be 1..%d", orderpg.MaxPageSize)})
+			return
+		}
+		p.Limit = n
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	page, err := h.repo.List(ctx, p)
+	if err != nil {
+		if errors.Is(err, orderpg.ErrBadCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page_token"})
+			return
+		}
+		l.Error("orders.list: repo.List failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	out := listOrdersResp{Orders: make([]orderResp, 0, len(page.Orders)), NextPageToken: page.NextPageToken}
+	for i := range page.Orders {
+		out.Orders = append(out.Orders, toOrderResp(&page.Orders[i]))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func toOrderResp(ord *orderpg.Order) orderResp {
+	amt := ord.Money()
+	out := orderResp{
 		ID:          ord.ID.String(),
 		UserID:      ord.UserID.String(),
 		Status:      ord.Status,
-		TotalAmount: ord.TotalAmount,
+		TotalAmount: amt.Major(),
+		AmountMinor: amt.Amount,
+		Amount:      amt.Decimal(),
 		Currency:    ord.Currency,
 		CreatedAt:   ord.CreatedAt.UTC().Format(time.RFC3339),
 		UpdatedAt:   ord.UpdatedAt.UTC().Format(time.RFC3339),
-	})
+
+		ShippingAddress: ord.ShippingAddress,
+		Shipment:        toShipmentResp(ord.Shipment),
+	}
+	if ord.DiscountMinor > 0 {
+		out.OriginalAmountMinor = ord.Original().Amount
+		out.DiscountMinor = ord.DiscountMinor
+		out.PromoCode = ord.PromoCode
+	}
+	return out
+}
+
+func toShipmentResp(s *orderpg.Shipment) *shipmentResp {
+	if s == nil {
+		return nil
+	}
+	out := &shipmentResp{Status: s.Status, Carrier: s.Carrier, TrackingNumber: s.TrackingNumber}
+	if s.ShippedAt != nil {
+		v := s.ShippedAt.UTC().Format(time.RFC3339)
+		out.ShippedAt = &v
+	}
+	if s.DeliveredAt != nil {
+		v := s.DeliveredAt.UTC().Format(time.RFC3339)
+		out.DeliveredAt = &v
+	}
+	return out
 }
 
 // local helpers
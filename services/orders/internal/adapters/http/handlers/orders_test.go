@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"goshop/pkg/httpx"
+	"goshop/pkg/jwtauth"
+)
+
+// Невалидные параметры отклоняются до обращения к репозиторию (repo == nil).
+func TestOrdersHandlers_List_InvalidParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewOrdersHandlers(slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	r := gin.New()
+	r.GET("/v1/orders", func(c *gin.Context) {
+		c.Set(httpx.CtxKeyJWTClaims, &jwtauth.Claims{UserID: uuid.NewString()})
+	}, h.List)
+
+	cases := []struct {
+		name  string
+		query string
+	}{
+		{"unknown status", "status=paid,lost"},
+		{"bad currency", "currency=XXX1"},
+		{"bad created_from", "created_from=2025-09-01"},
+		{"bad created_to", "created_to=yesterday"},
+		{"bad sort", "sort=amount"},
+		{"zero limit", "limit=0"},
+		{"limit too large", "limit=101"},
+		{"limit not a number", "limit=ten"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/orders?"+tc.query, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d, body=%s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOrdersHandlers_List_Unauthorized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewOrdersHandlers(slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	r := gin.New()
+	r.GET("/v1/orders", h.List)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/orders", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -9,24 +9,33 @@ import (
 
 	"goshop/pkg/httpx"
 	"goshop/pkg/jwtauth"
+	"goshop/pkg/money"
+	"goshop/pkg/revocation"
 
 	"goshop/services/orders/internal/adapters/http/handlers"
+	"goshop/services/orders/internal/adapters/repo/fxpg"
 	"goshop/services/orders/internal/adapters/repo/orderpg"
 )
 
 type Module struct {
-	log  *slog.Logger
-	db   *pgxpool.Pool
-	repo *orderpg.Repository
-	jwtm *jwtauth.Manager
+	log    *slog.Logger
+	db     *pgxpool.Pool
+	repo   *orderpg.Repository
+	fx     *fxpg.Repository
+	fxBase money.Currency
+	jwtm   jwtauth.TokenVerifier
+	rev    *revocation.List
 }
 
-func NewModule(log *slog.Logger, db *pgxpool.Pool, repo *orderpg.Repository, jwtm *jwtauth.Manager) *Module {
+func NewModule(log *slog.Logger, db *pgxpool.Pool, repo *orderpg.Repository, fx *fxpg.Repository, fxBase money.Currency, jwtm jwtauth.TokenVerifier, rev *revocation.List) *Module {
 	return &Module{
-		log:  log,
-		db:   db,
-		repo: repo,
-		jwtm: jwtm,
+		log:    log,
+		db:     db,
+		repo:   repo,
+		fx:     fx,
+		fxBase: fxBase,
+		jwtm:   jwtm,
+		rev:    rev,
 	}
 }
 
@@ -47,10 +56,18 @@ func (m *Module) Mount(r *gin.Engine) error {
 	// Orders
 	oh := handlers.NewOrdersHandlers(m.log, m.repo)
 
-	// Secured (Access JWT с aud="api")
+	// Secured (Access JWT с aud="api", не отозванный logout'ом)
 	secured := v1.Group("")
-	secured.Use(httpx.AuthJWTExpectAudience(m.log, m.jwtm, "api"))
+	secured.Use(httpx.AuthJWTRevocable(m.log, m.jwtm, m.rev, "api"))
 	secured.POST("/orders", oh.Create)
+	secured.GET("/orders", oh.List)
+	secured.GET("/orders/:id", oh.Get)
+
+	// FX: курсы, конвертация, итоги в базовой валюте
+	fh := handlers.NewFXHandlers(m.log, m.fx, m.repo, m.fxBase)
+	secured.GET("/orders/totals", fh.Totals)
+	secured.GET("/fx/rates", fh.Rates)
+	secured.GET("/fx/convert", fh.Convert)
 
 	m.log.Info("http: routes registered",
 		slog.String("module", m.Name()),
@@ -0,0 +1,127 @@
+package fxpg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"goshop/pkg/money"
+)
+
+var ErrRateNotFound = errors.New("fx rate not found")
+
+type Repository struct {
+	db    *pgxpool.Pool
+	pivot money.Currency
+}
+
+// NewRepo: pivot — валюта, через которую считаем кросс-курс, если прямого нет (обычно базовая).
+func NewRepo(db *pgxpool.Pool, pivot money.Currency) *Repository {
+	return &Repository{db: db, pivot: pivot}
+}
+
+// Rate — 1 Base = Rate Quote.
+type Rate struct {
+	Base      string
+	Quote     string
+	Rate      string // NUMERIC как текст, без потерь
+	UpdatedAt time.Time
+}
+
+func (r *Repository) List(ctx context.Context) ([]Rate, error) {
+	const q = `
+		SELECT base, quote, rate::text, updated_at
+		FROM fx_rates
+		ORDER BY base, quote;
+	`
+	rows, err := r.db.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("select fx_rates: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Rate
+	for rows.Next() {
+		var x Rate
+		if err := rows.Scan(&x.Base, &x.Quote, &x.Rate, &x.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan fx_rates: %w", err)
+		}
+		out = append(out, x)
+	}
+	return out, rows.Err()
+}
+
+// Upsert — записать курс 1 base = rate quote.
+func (r *Repository) Upsert(ctx context.Context, base, quote money.Currency, rate string) error {
+	if _, err := money.ParseRate(rate); err != nil {
+		return err
+	}
+	const q = `
+		INSERT INTO fx_rates (base, quote, rate)
+		VALUES ($1, $2, $3::numeric)
+		ON CONFLICT (base, quote) DO UPDATE
+		SET rate = EXCLUDED.rate, updated_at = now();
+	`
+	if _, err := r.db.Exec(ctx, q, base.Code, quote.Code, rate); err != nil {
+		return fmt.Errorf("upsert fx_rate %s/%s: %w", base.Code, quote.Code, err)
+	}
+	return nil
+}
+
+// Rate — сколько единиц to за одну единицу from.
+// Порядок: прямой курс, обратный, кросс через pivot.
+func (r *Repository) Rate(ctx context.Context, from, to money.Currency) (*big.Rat, error) {
+	if from.Code == to.Code {
+		return big.NewRat(1, 1), nil
+	}
+	if rate, err := r.pair(ctx, from.Code, to.Code); !errors.Is(err, ErrRateNotFound) {
+		return rate, err
+	}
+	if from.Code == r.pivot.Code || to.Code == r.pivot.Code {
+		return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from.Code, to.Code)
+	}
+
+	a, err := r.pair(ctx, from.Code, r.pivot.Code)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s via %s: %w", from.Code, to.Code, r.pivot.Code, err)
+	}
+	b, err := r.pair(ctx, r.pivot.Code, to.Code)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s via %s: %w", from.Code, to.Code, r.pivot.Code, err)
+	}
+	return new(big.Rat).Mul(a, b), nil
+}
+
+// pair — прямой или обратный курс из таблицы.
+func (r *Repository) pair(ctx context.Context, from, to string) (*big.Rat, error) {
+	const q = `
+		SELECT rate::text, base = $1
+		FROM fx_rates
+		WHERE (base = $1 AND quote = $2) OR (base = $2 AND quote = $1)
+		ORDER BY base = $1 DESC
+		LIMIT 1;
+	`
+	var (
+		s      string
+		direct bool
+	)
+	if err := r.db.QueryRow(ctx, q, from, to).Scan(&s, &direct); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+		}
+		return nil, fmt.Errorf("select fx_rate: %w", err)
+	}
+	rate, err := money.ParseRate(s)
+	if err != nil {
+		return nil, err
+	}
+	if !direct {
+		rate.Inv(rate)
+	}
+	return rate, nil
+}
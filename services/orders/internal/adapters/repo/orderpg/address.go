@@ -0,0 +1,46 @@
+package orderpg
+
+import (
+	"errors"
+	"strings"
+)
+
+// Address — адрес доставки заказа.
+type Address struct {
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone,omitempty"`
+	Country    string `json:"country"` // ISO 3166-1 alpha-2
+	Region     string `json:"region,omitempty"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+}
+
+// Normalize — trim всех полей, страна в верхнем регистре.
+func (a *Address) Normalize() {
+	a.Recipient = strings.TrimSpace(a.Recipient)
+	a.Phone = strings.TrimSpace(a.Phone)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	a.Region = strings.TrimSpace(a.Region)
+	a.City = strings.TrimSpace(a.City)
+	a.PostalCode = strings.TrimSpace(a.PostalCode)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+}
+
+func (a *Address) Validate() error {
+	switch {
+	case a.Recipient == "":
+		return errors.New("shipping_address.recipient is required")
+	case len(a.Country) != 2 || strings.Trim(a.Country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "":
+		return errors.New("shipping_address.country must be ISO 3166-1 alpha-2")
+	case a.City == "":
+		return errors.New("shipping_address.city is required")
+	case a.PostalCode == "":
+		return errors.New("shipping_address.postal_code is required")
+	case a.Line1 == "":
+		return errors.New("shipping_address.line1 is required")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package orderpg
+
+import "testing"
+
+func TestAddress_Validate(t *testing.T) {
+	valid := Address{Recipient: "Ann Smith", Country: "RU", City: "Moscow", PostalCode: "101000", Line1: "Tverskaya 1"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("valid address: %v", err)
+	}
+
+	cases := map[string]func(a *Address){
+		"no recipient":   func(a *Address) { a.Recipient = "" },
+		"no country":     func(a *Address) { a.Country = "" },
+		"alpha-3":        func(a *Address) { a.Country = "RUS" },
+		"lower case":     func(a *Address) { a.Country = "ru" },
+		"digits":         func(a *Address) { a.Country = "R1" },
+		"no city":        func(a *Address) { a.City = "" },
+		"no postal code": func(a *Address) { a.PostalCode = "" },
+		"no line1":       func(a *Address) { a.Line1 = "" },
+	}
+	for name, mutate := range cases {
+		a := valid
+		mutate(&a)
+		if err := a.Validate(); err == nil {
+			t.Fatalf("%s: Validate() = nil", name)
+		}
+	}
+}
+
+func TestAddress_Normalize(t *testing.T) {
+	a := Address{Recipient: " Ann ", Country: " ru ", City: " Moscow", PostalCode: "101000 ", Line1: " Tverskaya 1 ", Line2: "  "}
+	a.Normalize()
+	want := Address{Recipient: "Ann", Country: "RU", City: "Moscow", PostalCode: "101000", Line1: "Tverskaya 1"}
+	if a != want {
+		t.Fatalf("Normalize() = %+v, want %+v", a, want)
+	}
+	if err := a.Validate(); err != nil {
+		t.Fatalf("normalized address: %v", err)
+	}
+}
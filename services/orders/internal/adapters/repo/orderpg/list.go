@@ -0,0 +1,171 @@
+package orderpg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrBadCursor = errors.New("invalid page token")
+
+type SortOrder int
+
+const (
+	SortCreatedDesc SortOrder = iota // по умолчанию: новые сначала
+	SortCreatedAsc
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ListParams — фильтры и страница. UserID == nil — по всем пользователям (admin).
+type ListParams struct {
+	UserID      *uuid.UUID
+	Statuses    []string
+	Currency    string
+	CreatedFrom time.Time // включительно; zero — без ограничения
+	CreatedTo   time.Time // не включительно; zero — без ограничения
+	Sort        SortOrder
+	Limit       int
+	PageToken   string
+}
+
+type Page struct {
+	Orders        []Order
+	NextPageToken string // пусто — страниц больше нет
+}
+
+// cursor — позиция keyset-пагинации; F — отпечаток фильтров, чтобы токен нельзя было
+// применить к другому запросу.
+type cursor struct {
+	T time.Time `json:"t"`
+	I uuid.UUID `json:"i"`
+	F string    `json:"f"`
+}
+
+// List — заказы по фильтрам, keyset по (created_at, id).
+func (r *Repository) List(ctx context.Context, p ListParams) (*Page, error) {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageSize
+	}
+	if p.Limit > MaxPageSize {
+		p.Limit = MaxPageSize
+	}
+	fp := p.fingerprint()
+
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if p.UserID != nil {
+		where = append(where, "user_id = "+arg(*p.UserID))
+	}
+	if len(p.Statuses) > 0 {
+		where = append(where, "status = ANY("+arg(p.Statuses)+")")
+	}
+	if p.Currency != "" {
+		where = append(where, "currency = "+arg(p.Currency))
+	}
+	if !p.CreatedFrom.IsZero() {
+		where = append(where, "created_at >= "+arg(p.CreatedFrom))
+	}
+	if !p.CreatedTo.IsZero() {
+		where = append(where, "created_at < "+arg(p.CreatedTo))
+	}
+
+	cmp, dir := "<", "DESC"
+	if p.Sort == SortCreatedAsc {
+		cmp, dir = ">", "ASC"
+	}
+	if p.PageToken != "" {
+		c, err := decodeCursor(p.PageToken)
+		if err != nil || c.F != fp {
+			return nil, ErrBadCursor
+		}
+		where = append(where, fmt.Sprintf("(created_at, id) %s (%s, %s)", cmp, arg(c.T), arg(c.I)))
+	}
+
+	q := `
+		SELECT id, user_id, status, amount_minor, discount_minor, COALESCE(promo_code, ''), currency, created_at, updated_at
+		FROM orders`
+	if len(where) > 0 {
+		q += "\n\t\tWHERE " + strings.Join(where, " AND ")
+	}
+	q += fmt.Sprintf("\n\t\tORDER BY created_at %s, id %s\n\t\tLIMIT %s;", dir, dir, arg(p.Limit+1))
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select orders: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Order, 0, p.Limit+1)
+	for rows.Next() {
+		var o Order
+		if err := rows.Scan(&o.ID, &o.UserID, &o.Status, &o.AmountMinor, &o.DiscountMinor, &o.PromoCode, &o.Currency, &o.CreatedAt, &o.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan order: %w", err)
+		}
+		out = append(out, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select orders: %w", err)
+	}
+
+	page := &Page{Orders: out}
+	if len(out) > p.Limit {
+		page.Orders = out[:p.Limit]
+		last := page.Orders[p.Limit-1]
+		page.NextPageToken = encodeCursor(cursor{T: last.CreatedAt, I: last.ID, F: fp})
+	}
+	return page, nil
+}
+
+func (p ListParams) fingerprint() string {
+	st := append([]string(nil), p.Statuses...)
+	sort.Strings(st)
+
+	uid := ""
+	if p.UserID != nil {
+		uid = p.UserID.String()
+	}
+	s := fmt.Sprintf("%s|%s|%s|%d|%d|%d",
+		uid, strings.Join(st, ","), p.Currency, p.CreatedFrom.UnixNano(), p.CreatedTo.UnixNano(), p.Sort)
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, err
+	}
+	if c.T.IsZero() || c.I == uuid.Nil {
+		return c, ErrBadCursor
+	}
+	return c, nil
+}
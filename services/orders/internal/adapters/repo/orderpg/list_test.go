@@ -0,0 +1,96 @@
+package orderpg
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	in := cursor{T: time.Date(2025, 9, 22, 13, 0, 0, 123456789, time.UTC), I: uuid.New(), F: "abcd"}
+	out, err := decodeCursor(encodeCursor(in))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !out.T.Equal(in.T) || out.I != in.I || out.F != in.F {
+		t.Fatalf("round trip: got %+v, want %+v", out, in)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	b64 := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "!!!"},
+		{"not json", b64("garbage")},
+		{"no time", b64(`{"i":"` + uuid.NewString() + `","f":"x"}`)},
+		{"no id", b64(`{"t":"2025-09-22T13:00:00Z","f":"x"}`)},
+		{"bad id", b64(`{"t":"2025-09-22T13:00:00Z","i":"nope","f":"x"}`)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := decodeCursor(tc.token); err == nil {
+				t.Fatalf("decodeCursor(%q) = nil error", tc.token)
+			}
+		})
+	}
+}
+
+func TestListParams_Fingerprint(t *testing.T) {
+	uid := uuid.New()
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	base := ListParams{UserID: &uid, Statuses: []string{"paid", "new"}, Currency: "RUB", CreatedFrom: from}
+
+	same := base
+	same.Statuses = []string{"new", "paid"} // порядок статусов не важен
+	same.Limit = 50                         // размер страницы и токен в отпечаток не входят
+	same.PageToken = "x"
+	if base.fingerprint() != same.fingerprint() {
+		t.Fatalf("fingerprint depends on status order, limit or token")
+	}
+
+	other := uuid.New()
+	variants := map[string]func(p *ListParams){
+		"user":     func(p *ListParams) { p.UserID = &other },
+		"all":      func(p *ListParams) { p.UserID = nil },
+		"statuses": func(p *ListParams) { p.Statuses = []string{"paid"} },
+		"currency": func(p *ListParams) { p.Currency = "USD" },
+		"from":     func(p *ListParams) { p.CreatedFrom = from.Add(time.Second) },
+		"to":       func(p *ListParams) { p.CreatedTo = from.Add(time.Hour) },
+		"sort":     func(p *ListParams) { p.Sort = SortCreatedAsc },
+	}
+	for name, mutate := range variants {
+		p := base
+		mutate(&p)
+		if p.fingerprint() == base.fingerprint() {
+			t.Fatalf("%s: fingerprint unchanged", name)
+		}
+	}
+}
+
+// Чужой или битый токен отклоняется до запроса в БД.
+func TestList_BadCursor(t *testing.T) {
+	uid := uuid.New()
+	p := ListParams{UserID: &uid, Statuses: []string{"paid"}}
+	token := encodeCursor(cursor{T: time.Now(), I: uuid.New(), F: p.fingerprint()})
+
+	other := uuid.New()
+	cases := map[string]ListParams{
+		"other user":     {UserID: &other, Statuses: []string{"paid"}, PageToken: token},
+		"other statuses": {UserID: &uid, Statuses: []string{"new"}, PageToken: token},
+		"other sort":     {UserID: &uid, Statuses: []string{"paid"}, Sort: SortCreatedAsc, PageToken: token},
+		"garbage":        {UserID: &uid, Statuses: []string{"paid"}, PageToken: "garbage"},
+	}
+	r := &Repository{}
+	for name, params := range cases {
+		if _, err := r.List(context.Background(), params); !errors.Is(err, ErrBadCursor) {
+			t.Fatalf("%s: err = %v, want ErrBadCursor", name, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package orderpg
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"goshop/pkg/money"
+)
+
+var ErrPromoDisabled = errors.New("promo codes are not enabled")
+
+// Discount — скидка по промо-коду, посчитанная для конкретного заказа.
+type Discount struct {
+	PromotionID uuid.UUID
+	Code        string
+	AmountMinor int64 // в валюте заказа, всегда меньше суммы заказа
+	Currency    string
+}
+
+// Coupons — промо-коды внутри транзакции Create (реализация — promotions.Service).
+type Coupons interface {
+	// QuoteTx блокирует акцию до конца tx, проверяет окно, лимиты и минимальную сумму и считает скидку.
+	QuoteTx(ctx context.Context, tx pgx.Tx, code string, userID uuid.UUID, amount money.Money) (*Discount, error)
+	// RedeemTx фиксирует использование кода созданным заказом.
+	RedeemTx(ctx context.Context, tx pgx.Tx, orderID, userID uuid.UUID, d *Discount) error
+}
+
+// NormalizePromoCode — коды регистронезависимы, храним в верхнем регистре.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -10,30 +10,71 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"goshop/pkg/money"
 )
 
+var ErrNotFound = errors.New("order not found")
+
 type Repository struct {
-	db *pgxpool.Pool
+	db      *pgxpool.Pool
+	coupons Coupons
 }
 
-func NewRepo(db *pgxpool.Pool) *Repository {
-	return &Repository{db: db}
+// NewRepo — coupons может быть nil: тогда заказ с promo_code отклоняется (ErrPromoDisabled).
+func NewRepo(db *pgxpool.Pool, coupons Coupons) *Repository {
+	return &Repository{db: db, coupons: coupons}
 }
 
 type Order struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
 	Status      string
-	TotalAmount float64
+	AmountMinor int64 // к оплате, в минорных единицах Currency
 	Currency    string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	DiscountMinor int64  // скидка по промо-коду; исходная сумма = AmountMinor + DiscountMinor
+	PromoCode     string // пусто — без промо-кода
+
+	// заполняются в GetByID; nil — адреса/отправки нет
+	ShippingAddress *Address
+	Shipment        *Shipment
+}
+
+// Shipment — состояние доставки (пишет fulfillment).
+type Shipment struct {
+	Status         string // pending|shipped|delivered
+	Carrier        string
+	TrackingNumber string
+	CreatedAt      time.Time
+	ShippedAt      *time.Time
+	DeliveredAt    *time.Time
+}
+
+// Money — сумма заказа; для кода вне ISO-4217 (старые строки) считаем 2 знака.
+func (o *Order) Money() money.Money {
+	c, err := money.ParseCurrency(o.Currency)
+	if err != nil {
+		c = money.Currency{Code: o.Currency, Minor: 2}
+	}
+	return money.Money{Amount: o.AmountMinor, Currency: c}
+}
+
+// Original — сумма заказа до скидки.
+func (o *Order) Original() money.Money {
+	m := o.Money()
+	m.Amount += o.DiscountMinor
+	return m
 }
 
 type CreateParams struct {
 	UserID        uuid.UUID
-	AmountCents   int64
+	AmountCents   int64 // до скидки
 	Currency      string
+	Address       *Address // опционально
+	PromoCode     string   // опционально; погашается в той же транзакции
 	OutboxTopic   string
 	OutboxHeaders map[string]string
 }
@@ -48,9 +89,24 @@ func (r *Repository) Create(ctx context.Context, p CreateParams) (*Order, error)
 	if p.Currency == "" {
 		p.Currency = "RUB"
 	}
+	cur, err := money.ParseCurrency(p.Currency)
+	if err != nil {
+		return nil, err
+	}
+	p.Currency = cur.Code
+	if p.Address != nil {
+		p.Address.Normalize()
+		if err := p.Address.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	if p.OutboxTopic == "" {
 		p.OutboxTopic = "orders.events"
 	}
+	p.PromoCode = NormalizePromoCode(p.PromoCode)
+	if p.PromoCode != "" && r.coupons == nil {
+		return nil, ErrPromoDisabled
+	}
 
 	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -58,27 +114,68 @@ func (r *Repository) Create(ctx context.Context, p CreateParams) (*Order, error)
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
+	// промо-код: QuoteTx держит блокировку акции до commit, поэтому лимиты
+	// проверяются и списываются атомарно с созданием заказа
+	var disc *Discount
+	if p.PromoCode != "" {
+		disc, err = r.coupons.QuoteTx(ctx, tx, p.PromoCode, p.UserID, money.Money{Amount: p.AmountCents, Currency: cur})
+		if err != nil {
+			return nil, err
+		}
+	}
+	amount, discount := p.AmountCents, int64(0)
+	var promoCode *string
+	if disc != nil {
+		amount -= disc.AmountMinor
+		discount = disc.AmountMinor
+		promoCode = &disc.Code
+	}
+
 	const insOrder = `
-		INSERT INTO orders (user_id, status, total_amount, currency)
-		VALUES ($1, 'new', $2/100.0, $3)
-		RETURNING id, user_id, status, total_amount, currency, created_at, updated_at;
+		INSERT INTO orders (user_id, status, amount_minor, discount_minor, promo_code, currency)
+		VALUES ($1, 'new', $2, $3, $4, $5)
+		RETURNING id, user_id, status, amount_minor, discount_minor, COALESCE(promo_code, ''), currency, created_at, updated_at;
 	`
 
 	var ord Order
 	if err := tx.QueryRow(ctx, insOrder,
-		p.UserID, p.AmountCents, p.Currency,
-	).Scan(&ord.ID, &ord.UserID, &ord.Status, &ord.TotalAmount, &ord.Currency, &ord.CreatedAt, &ord.UpdatedAt); err != nil {
+		p.UserID, amount, discount, promoCode, p.Currency,
+	).Scan(&ord.ID, &ord.UserID, &ord.Status, &ord.AmountMinor, &ord.DiscountMinor, &ord.PromoCode, &ord.Currency, &ord.CreatedAt, &ord.UpdatedAt); err != nil {
 		return nil, fmt.Errorf("insert order: %w", err)
 	}
 
+	if disc != nil {
+		if err := r.coupons.RedeemTx(ctx, tx, ord.ID, ord.UserID, disc); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Address != nil {
+		const insAddr = `
+			INSERT INTO order_addresses (order_id, recipient, phone, country, region, city, postal_code, line1, line2)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+		`
+		a := p.Address
+		if _, err := tx.Exec(ctx, insAddr,
+			ord.ID, a.Recipient, a.Phone, a.Country, a.Region, a.City, a.PostalCode, a.Line1, a.Line2,
+		); err != nil {
+			return nil, fmt.Errorf("insert address: %w", err)
+		}
+		ord.ShippingAddress = a
+	}
+
 	type OrderCreated struct {
 		Event     string    `json:"event"`
 		Version   int       `json:"version"`
 		OrderID   uuid.UUID `json:"order_id"`
 		UserID    uuid.UUID `json:"user_id"`
-		Amount    int64     `json:"amount_cents"`
+		Amount    int64     `json:"amount_cents"` // к оплате, минорные единицы currency
+		Original  int64     `json:"original_amount_cents"`
+		Discount  int64     `json:"discount_cents"`
+		PromoCode string    `json:"promo_code,omitempty"`
 		Currency  string    `json:"currency"`
 		Status    string    `json:"status"`
+		Address   *Address  `json:"shipping_address,omitempty"`
 		CreatedAt time.Time `json:"created_at"`
 	}
 	payload := OrderCreated{
@@ -86,9 +183,13 @@ func (r *Repository) Create(ctx context.Context, p CreateParams) (*Order, error)
 		Version:   1,
 		OrderID:   ord.ID,
 		UserID:    ord.UserID,
-		Amount:    p.AmountCents,
+		Amount:    ord.AmountMinor,
+		Original:  p.AmountCents,
+		Discount:  ord.DiscountMinor,
+		PromoCode: ord.PromoCode,
 		Currency:  p.Currency,
 		Status:    ord.Status,
+		Address:   p.Address,
 		CreatedAt: ord.CreatedAt.UTC(),
 	}
 	payloadJSON, err := json.Marshal(payload)
@@ -129,3 +230,92 @@ func (r *Repository) Create(ctx context.Context, p CreateParams) (*Order, error)
 
 	return &ord, nil
 }
+
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*Order, error) {
+	const q = `
+		SELECT o.id, o.user_id, o.status, o.amount_minor, o.discount_minor, COALESCE(o.promo_code, ''), o.currency, o.created_at, o.updated_at,
+		       a.recipient, a.phone, a.country, a.region, a.city, a.postal_code, a.line1, a.line2,
+		       s.status, s.carrier, s.tracking_number, s.created_at, s.shipped_at, s.delivered_at
+		FROM orders o
+		LEFT JOIN order_addresses a ON a.order_id = o.id
+		LEFT JOIN shipments s       ON s.order_id = o.id
+		WHERE o.id = $1;
+	`
+	var (
+		ord  Order
+		addr struct {
+			Recipient, Phone, Country, Region, City, PostalCode, Line1, Line2 *string
+		}
+		shp struct {
+			Status, Carrier, Tracking *string
+			CreatedAt                 *time.Time
+			ShippedAt, DeliveredAt    *time.Time
+		}
+	)
+	if err := r.db.QueryRow(ctx, q, id).Scan(
+		&ord.ID, &ord.UserID, &ord.Status, &ord.AmountMinor, &ord.DiscountMinor, &ord.PromoCode, &ord.Currency, &ord.CreatedAt, &ord.UpdatedAt,
+		&addr.Recipient, &addr.Phone, &addr.Country, &addr.Region, &addr.City, &addr.PostalCode, &addr.Line1, &addr.Line2,
+		&shp.Status, &shp.Carrier, &shp.Tracking, &shp.CreatedAt, &shp.ShippedAt, &shp.DeliveredAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("select order: %w", err)
+	}
+	if addr.Recipient != nil {
+		ord.ShippingAddress = &Address{
+			Recipient:  *addr.Recipient,
+			Phone:      *addr.Phone,
+			Country:    *addr.Country,
+			Region:     *addr.Region,
+			City:       *addr.City,
+			PostalCode: *addr.PostalCode,
+			Line1:      *addr.Line1,
+			Line2:      *addr.Line2,
+		}
+	}
+	if shp.Status != nil {
+		ord.Shipment = &Shipment{
+			Status:         *shp.Status,
+			Carrier:        *shp.Carrier,
+			TrackingNumber: *shp.Tracking,
+			CreatedAt:      *shp.CreatedAt,
+			ShippedAt:      shp.ShippedAt,
+			DeliveredAt:    shp.DeliveredAt,
+		}
+	}
+	return &ord, nil
+}
+
+// CurrencyTotal — сумма заказов пользователя в одной валюте.
+type CurrencyTotal struct {
+	Currency    string
+	AmountMinor int64
+	Orders      int64
+}
+
+// TotalsByCurrency — суммы заказов пользователя по валютам (отменённые не считаем).
+func (r *Repository) TotalsByCurrency(ctx context.Context, userID uuid.UUID) ([]CurrencyTotal, error) {
+	const q = `
+		SELECT currency, COALESCE(sum(amount_minor), 0)::bigint, count(*)
+		FROM orders
+		WHERE user_id = $1 AND status NOT IN ('canceled', 'cancelled')
+		GROUP BY currency
+		ORDER BY currency;
+	`
+	rows, err := r.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("select totals: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CurrencyTotal
+	for rows.Next() {
+		var t CurrencyTotal
+		if err := rows.Scan(&t.Currency, &t.AmountMinor, &t.Orders); err != nil {
+			return nil, fmt.Errorf("scan totals: %w", err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
@@ -8,9 +8,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/redis/go-redis/v9"
 	"github.com/twmb/franz-go/pkg/kgo"
+
+	"goshop/services/orders/internal/fulfillment"
+	"goshop/services/orders/internal/statuscache"
 )
 
 type paymentEvent struct {
@@ -23,17 +26,20 @@ type paymentEvent struct {
 	Currency  string    `json:"currency"`
 	Status    string    `json:"status"` // "confirmed" | "failed"
 	Reason    *string   `json:"reason,omitempty"`
+	// v2: failed с retryable=true — попытки ещё есть, заказ ждёт RetryPayment; v1 без поля — отказ окончательный
+	Attempt   int  `json:"attempt"`
+	Retryable bool `json:"retryable"`
 }
 
 type Processor struct {
-	log *slog.Logger
-	db  *pgxpool.Pool
-	rds *redis.Client
-	ttl time.Duration
+	log   *slog.Logger
+	db    *pgxpool.Pool
+	cache *statuscache.Cache
+	ful   *fulfillment.Service
 }
 
-func NewProcessor(log *slog.Logger, db *pgxpool.Pool, rds *redis.Client, ttl time.Duration) *Processor {
-	return &Processor{log: log, db: db, rds: rds, ttl: ttl}
+func NewProcessor(log *slog.Logger, db *pgxpool.Pool, cache *statuscache.Cache, ful *fulfillment.Service) *Processor {
+	return &Processor{log: log, db: db, cache: cache, ful: ful}
 }
 
 func (p *Processor) ProcessRecord(ctx context.Context, rec *kgo.Record) error {
@@ -63,39 +69,94 @@ func (p *Processor) ProcessRecord(ctx context.Context, rec *kgo.Record) error {
 	}
 }
 
+// refundEvent — компенсация: деньги списаны, а заказ уже отменён (автоотмена успела раньше оплаты).
+type refundEvent struct {
+	Event     string    `json:"event"` // order.refund_required
+	Version   int       `json:"version"`
+	OrderID   uuid.UUID `json:"order_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	PaymentID uuid.UUID `json:"payment_id"`
+	Amount    int64     `json:"amount_cents"`
+	Currency  string    `json:"currency"`
+	Reason    string    `json:"reason"`
+	At        time.Time `json:"at"`
+}
+
+// targetStatus — куда платёжное событие переводит заказ; "" — событие не про статус.
+func targetStatus(ev paymentEvent) string {
+	switch {
+	case ev.Event == "payment.confirmed":
+		return "paid"
+	case ev.Event == "payment.failed" && ev.Retryable:
+		return "payment_failed"
+	case ev.Event == "payment.failed":
+		return "cancelled"
+	}
+	return ""
+}
+
+// fromStatuses — из каких статусов платёжное событие переводит заказ в want. Только из ожидания оплаты:
+// shipped/delivered — дальше по жизненному циклу, запоздавший failed не отменяет оплаченный заказ,
+// cancelled не воскрешаем (вместо этого order.refund_required).
+func fromStatuses(want string) []string {
+	switch want {
+	case "paid", "cancelled":
+		return []string{"new", "payment_failed"}
+	case "payment_failed":
+		return []string{"new"}
+	}
+	return nil
+}
+
+// needsRefund — подтверждённая оплата пришла к отменённому заказу.
+func needsRefund(ev paymentEvent, cur string) bool {
+	return ev.Event == "payment.confirmed" && cur == "cancelled"
+}
+
 func (p *Processor) applyPayment(ctx context.Context, ev paymentEvent) error {
-	var want string
-	switch ev.Event {
-	case "payment.confirmed":
-		want = "paid"
-	case "payment.failed":
-		want = "cancelled"
-	default:
+	want := targetStatus(ev)
+	if want == "" {
 		return nil
 	}
 
+	tx, err := p.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	const qUpdate = `
 		UPDATE orders
 		SET status = $2, updated_at = now()
-		WHERE id = $1 AND status <> $2
+		WHERE id = $1 AND status = ANY($3)
 	`
-	tag, err := p.db.Exec(ctx, qUpdate, ev.OrderID, want)
+	tag, err := tx.Exec(ctx, qUpdate, ev.OrderID, want, fromStatuses(want))
 	if err != nil {
 		return fmt.Errorf("update orders status: %w", err)
 	}
 
 	if tag.RowsAffected() > 0 {
-		p.setStatusCache(ctx, ev.OrderID.String(), want)
+		if want == "paid" && p.ful != nil {
+			if err := p.ful.CreateTx(ctx, tx, ev.OrderID); err != nil {
+				return fmt.Errorf("fulfillment: %w", err)
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("commit: %w", err)
+		}
+
+		p.cache.Set(ctx, ev.OrderID.String(), want)
 		p.log.Info("orders.processor: status updated",
 			slog.String("order_id", ev.OrderID.String()),
 			slog.String("to", want),
 			slog.String("event", ev.Event),
+			slog.Int("attempt", ev.Attempt),
 		)
 		return nil
 	}
 
 	var cur string
-	if err := p.db.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1`, ev.OrderID).Scan(&cur); err != nil {
+	if err := tx.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1`, ev.OrderID).Scan(&cur); err != nil {
 		p.log.Warn("orders.processor: read current status after noop failed",
 			slog.String("order_id", ev.OrderID.String()),
 			slog.Any("err", err),
@@ -103,7 +164,22 @@ func (p *Processor) applyPayment(ctx context.Context, ev paymentEvent) error {
 		return nil
 	}
 
-	p.setStatusCache(ctx, ev.OrderID.String(), cur)
+	if needsRefund(ev, cur) {
+		if err := p.insertRefundRequired(ctx, tx, ev); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("commit: %w", err)
+		}
+		p.log.Warn("orders.processor: payment confirmed for cancelled order, refund required",
+			slog.String("order_id", ev.OrderID.String()),
+			slog.String("payment_id", ev.PaymentID.String()),
+			slog.Int64("amount_cents", ev.Amount),
+			slog.String("currency", ev.Currency),
+		)
+	}
+
+	p.cache.Set(ctx, ev.OrderID.String(), cur)
 	p.log.Info("orders.processor: payment applied (noop)",
 		slog.String("order_id", ev.OrderID.String()),
 		slog.String("kept", cur),
@@ -112,22 +188,45 @@ func (p *Processor) applyPayment(ctx context.Context, ev paymentEvent) error {
 	return nil
 }
 
-func (p *Processor) setStatusCache(ctx context.Context, orderID, status string) {
-	if p.rds == nil {
-		return
+// insertRefundRequired — order.refund_required в orders_outbox; повторная доставка того же
+// payment.confirmed второе событие не пишет.
+func (p *Processor) insertRefundRequired(ctx context.Context, tx pgx.Tx, ev paymentEvent) error {
+	payload, err := json.Marshal(refundEvent{
+		Event:     "order.refund_required",
+		Version:   1,
+		OrderID:   ev.OrderID,
+		UserID:    ev.UserID,
+		PaymentID: ev.PaymentID,
+		Amount:    ev.Amount,
+		Currency:  ev.Currency,
+		Reason:    "order_cancelled",
+		At:        time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal refund event: %w", err)
 	}
-	key := "order:" + orderID + ":status"
-	if err := p.rds.Set(ctx, key, status, p.ttl).Err(); err != nil {
-		p.log.Warn("orders.processor.redis: set status failed",
-			slog.String("key", key),
-			slog.String("status", status),
-			slog.Any("err", err),
-		)
-		return
+	headers, err := json.Marshal([]struct{ K, V string }{
+		{K: "event-type", V: "order.refund_required"},
+		{K: "source", V: "orders-consumer"},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal headers: %w", err)
 	}
-	p.log.Debug("orders.processor: status cached",
-		slog.String("key", key),
-		slog.String("status", status),
-		slog.Int64("ttl_ms", p.ttl.Milliseconds()),
-	)
+
+	const insOutbox = `
+		INSERT INTO orders_outbox (agg_type, agg_id, topic, key, headers, payload)
+		SELECT 'order', $1, 'orders.events', $2, $3::jsonb, $4::jsonb
+		WHERE NOT EXISTS (
+			SELECT 1 FROM orders_outbox
+			WHERE agg_type = 'order' AND agg_id = $1
+			  AND payload->>'event' = 'order.refund_required'
+			  AND payload->>'payment_id' = $5
+		);
+	`
+	if _, err := tx.Exec(ctx, insOutbox,
+		ev.OrderID, ev.OrderID[:], headers, payload, ev.PaymentID.String(),
+	); err != nil {
+		return fmt.Errorf("insert outbox: %w", err)
+	}
+	return nil
 }
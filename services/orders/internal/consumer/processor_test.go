@@ -0,0 +1,69 @@
+package consumer
+
+import "testing"
+
+func TestTargetStatus(t *testing.T) {
+	cases := []struct {
+		ev   paymentEvent
+		want string
+	}{
+		{paymentEvent{Event: "payment.confirmed"}, "paid"},
+		{paymentEvent{Event: "payment.failed", Version: 2, Attempt: 1, Retryable: true}, "payment_failed"},
+		{paymentEvent{Event: "payment.failed", Version: 2, Attempt: 3}, "cancelled"},
+		{paymentEvent{Event: "payment.failed", Version: 1}, "cancelled"}, // v1 без retryable — окончательный отказ
+		{paymentEvent{Event: "payment.refunded"}, ""},
+	}
+	for _, tc := range cases {
+		if got := targetStatus(tc.ev); got != tc.want {
+			t.Fatalf("%+v: targetStatus = %q, want %q", tc.ev, got, tc.want)
+		}
+	}
+}
+
+func TestNeedsRefund(t *testing.T) {
+	confirmed := paymentEvent{Event: "payment.confirmed"}
+	if !needsRefund(confirmed, "cancelled") {
+		t.Fatal("confirmed for cancelled order: refund not required")
+	}
+	for _, cur := range []string{"paid", "shipped", "delivered"} {
+		if needsRefund(confirmed, cur) {
+			t.Fatalf("confirmed for %s order: refund required", cur)
+		}
+	}
+	if needsRefund(paymentEvent{Event: "payment.failed"}, "cancelled") {
+		t.Fatal("failed for cancelled order: refund required")
+	}
+}
+
+func TestFromStatuses(t *testing.T) {
+	cases := []struct {
+		cur, want string
+		ok        bool
+	}{
+		// повторяемый отказ: ждём RetryPayment
+		{"new", "payment_failed", true},
+		{"payment_failed", "payment_failed", false}, // ещё один повторяемый отказ — без изменений
+		// повтор прошёл / окончательный отказ
+		{"payment_failed", "paid", true},
+		{"payment_failed", "cancelled", true},
+		{"new", "paid", true},
+		{"new", "cancelled", true},
+		// запоздавшие события
+		{"cancelled", "paid", false}, // автоотмена успела раньше — order.refund_required
+		{"paid", "cancelled", false},
+		{"paid", "payment_failed", false},
+		{"shipped", "paid", false},
+		{"delivered", "cancelled", false},
+	}
+	for _, tc := range cases {
+		got := false
+		for _, s := range fromStatuses(tc.want) {
+			if s == tc.cur {
+				got = true
+			}
+		}
+		if got != tc.ok {
+			t.Fatalf("%s -> %s: allowed = %v, want %v", tc.cur, tc.want, got, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package expiry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"goshop/services/orders/internal/statuscache"
+)
+
+type Config struct {
+	TTL      time.Duration // сколько заказ может ждать оплаты в new/payment_failed (от created_at)
+	Grace    time.Duration // пауза после последнего платёжного события (updated_at) — повтор оплаты может быть в пути
+	Interval time.Duration // период проверки
+	Batch    int           // заказов за одну транзакцию
+	Topic    string
+}
+
+// Scheduler отменяет заказы, не оплаченные за TTL (new — payments лежал, событие потерялось;
+// payment_failed — клиент так и не повторил оплату),
+// с событием order.expired в orders_outbox. Реплики не мешают друг другу:
+// каждая забирает свою пачку через FOR UPDATE SKIP LOCKED.
+// Заказ с оплатой в процессе не трогаем: его order.created ещё не ушёл из outbox
+// (payments спишет, как только получит) или платёжное событие было меньше Grace назад.
+type Scheduler struct {
+	log   *slog.Logger
+	db    *pgxpool.Pool
+	cache *statuscache.Cache
+	cfg   Config
+}
+
+func New(log *slog.Logger, db *pgxpool.Pool, cache *statuscache.Cache, cfg Config) *Scheduler {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 30 * time.Minute
+	}
+	if cfg.Grace <= 0 {
+		cfg.Grace = 5 * time.Minute
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Batch <= 0 {
+		cfg.Batch = 100
+	}
+	if cfg.Topic == "" {
+		cfg.Topic = "orders.events"
+	}
+	return &Scheduler{log: log, db: db, cache: cache, cfg: cfg}
+}
+
+type expiredEvent struct {
+	Event     string    `json:"event"`
+	Version   int       `json:"version"`
+	OrderID   uuid.UUID `json:"order_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	Amount    int64     `json:"amount_cents"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+	At        time.Time `json:"at"`
+}
+
+// Run — до отмены ctx; ошибка прохода логируется, следующий тик пробует снова.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.log.Info("orders.expiry: started",
+		slog.Duration("ttl", s.cfg.TTL),
+		slog.Duration("grace", s.cfg.Grace),
+		slog.Duration("interval", s.cfg.Interval),
+		slog.Int("batch", s.cfg.Batch),
+	)
+	t := time.NewTicker(s.cfg.Interval)
+	defer t.Stop()
+
+	for {
+		if n, err := s.Sweep(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.log.Error("orders.expiry: sweep failed", slog.Any("err", err))
+		} else if n > 0 {
+			s.log.Info("orders.expiry: orders expired", slog.Int("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Sweep отменяет все просроченные заказы пачками по Batch; возвращает их число.
+func (s *Scheduler) Sweep(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := s.expireBatch(ctx)
+		total += n
+		if err != nil || n < s.cfg.Batch {
+			return total, err
+		}
+	}
+}
+
+// cutoffs — заказ просрочен, если создан раньше created и последнее изменение (платёжное событие) раньше touched.
+func (s *Scheduler) cutoffs(now time.Time) (created, touched time.Time) {
+	return now.Add(-s.cfg.TTL), now.Add(-s.cfg.Grace)
+}
+
+func (s *Scheduler) expireBatch(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// заказы, которые прямо сейчас обновляет consumer или другая реплика, пропускаем —
+	// они либо уже не new, либо попадут в следующий проход;
+	// неотправленное событие заказа в outbox — оплата ещё впереди, отменять рано
+	created, touched := s.cutoffs(time.Now())
+	rows, err := tx.Query(ctx, `
+		SELECT o.id, o.user_id, o.amount_minor, o.currency, o.created_at
+		FROM orders o
+		WHERE o.status IN ('new', 'payment_failed') AND o.created_at < $1 AND o.updated_at < $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM orders_outbox ob
+			WHERE ob.agg_type = 'order' AND ob.agg_id = o.id AND ob.published_at IS NULL
+		  )
+		ORDER BY o.created_at
+		LIMIT $3
+		FOR UPDATE OF o SKIP LOCKED;
+	`, created, touched, s.cfg.Batch)
+	if err != nil {
+		return 0, fmt.Errorf("select expired: %w", err)
+	}
+	var evs []expiredEvent
+	for rows.Next() {
+		ev := expiredEvent{Event: "order.expired", Version: 1, Status: "cancelled", Reason: "payment_timeout"}
+		if err := rows.Scan(&ev.OrderID, &ev.UserID, &ev.Amount, &ev.Currency, &ev.CreatedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan expired: %w", err)
+		}
+		evs = append(evs, ev)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("select expired: %w", err)
+	}
+	if len(evs) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(evs))
+	for _, ev := range evs {
+		ids = append(ids, ev.OrderID)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE orders SET status = 'cancelled', updated_at = now() WHERE id = ANY($1)`, ids); err != nil {
+		return 0, fmt.Errorf("update orders status: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for i := range evs {
+		evs[i].CreatedAt = evs[i].CreatedAt.UTC()
+		evs[i].At = now
+		if err := s.insertOutbox(ctx, tx, evs[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+
+	for _, ev := range evs {
+		s.cache.Set(ctx, ev.OrderID.String(), "cancelled")
+		s.log.Info("orders.expiry: order expired",
+			slog.String("order_id", ev.OrderID.String()),
+			slog.Time("created_at", ev.CreatedAt),
+		)
+	}
+	return len(evs), nil
+}
+
+func (s *Scheduler) insertOutbox(ctx context.Context, tx pgx.Tx, ev expiredEvent) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshal outbox payload: %w", err)
+	}
+	headers, err := json.Marshal([]struct{ K, V string }{
+		{K: "event-type", V: ev.Event},
+		{K: "source", V: "orders-expiry"},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal headers: %w", err)
+	}
+
+	const insOutbox = `
+		INSERT INTO orders_outbox (agg_type, agg_id, topic, key, headers, payload)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6::jsonb);
+	`
+	if _, err := tx.Exec(ctx, insOutbox,
+		"order", ev.OrderID, s.cfg.Topic, ev.OrderID[:], headers, payload,
+	); err != nil {
+		return fmt.Errorf("insert outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package expiry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNew_Defaults(t *testing.T) {
+	s := New(nil, nil, nil, Config{})
+	want := Config{TTL: 30 * time.Minute, Grace: 5 * time.Minute, Interval: time.Minute, Batch: 100, Topic: "orders.events"}
+	if s.cfg != want {
+		t.Fatalf("cfg = %+v, want %+v", s.cfg, want)
+	}
+
+	custom := Config{TTL: time.Hour, Grace: time.Minute, Interval: time.Second, Batch: 10, Topic: "t"}
+	if s := New(nil, nil, nil, custom); s.cfg != custom {
+		t.Fatalf("cfg = %+v, want %+v", s.cfg, custom)
+	}
+}
+
+// Просрочен заказ, созданный раньше TTL, по которому платёжного события не было Grace.
+func TestCutoffs(t *testing.T) {
+	s := New(nil, nil, nil, Config{TTL: 30 * time.Minute, Grace: 5 * time.Minute})
+	now := time.Date(2025, 9, 22, 12, 0, 0, 0, time.UTC)
+	created, touched := s.cutoffs(now)
+	if want := now.Add(-30 * time.Minute); !created.Equal(want) {
+		t.Fatalf("created cutoff = %v, want %v", created, want)
+	}
+	if want := now.Add(-5 * time.Minute); !touched.Equal(want) {
+		t.Fatalf("touched cutoff = %v, want %v", touched, want)
+	}
+}
@@ -0,0 +1,235 @@
+package fulfillment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"goshop/services/orders/internal/adapters/repo/orderpg"
+	"goshop/services/orders/internal/statuscache"
+)
+
+var (
+	ErrNotFound          = errors.New("order not found")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+	ErrInvalidArgument   = errors.New("invalid argument")
+)
+
+// Жизненный цикл после оплаты:
+//
+//	paid (shipment pending) -> shipped -> delivered
+//
+// pending-отправка создаётся consumer'ом по payment.confirmed (CreateTx),
+// shipped/delivered выставляет admin API (Ship/Deliver). Каждый шаг — событие в orders_outbox.
+type Service struct {
+	log   *slog.Logger
+	db    *pgxpool.Pool
+	cache *statuscache.Cache
+	topic string
+}
+
+func New(log *slog.Logger, db *pgxpool.Pool, cache *statuscache.Cache, topic string) *Service {
+	if topic == "" {
+		topic = "orders.events"
+	}
+	return &Service{log: log, db: db, cache: cache, topic: topic}
+}
+
+type shipmentEvent struct {
+	Event          string     `json:"event"`
+	Version        int        `json:"version"`
+	OrderID        uuid.UUID  `json:"order_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Status         string     `json:"status"`
+	Carrier        string     `json:"carrier,omitempty"`
+	TrackingNumber string     `json:"tracking_number,omitempty"`
+	ShippedAt      *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	At             time.Time  `json:"at"`
+}
+
+// CreateTx — pending-отправка в транзакции, которая переводит заказ в paid.
+// Повторный вызов (редоставка payment.confirmed) — noop.
+func (s *Service) CreateTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) error {
+	var userID uuid.UUID
+	var createdAt time.Time
+	err := tx.QueryRow(ctx, `
+		INSERT INTO shipments (order_id)
+		SELECT id FROM orders WHERE id = $1
+		ON CONFLICT (order_id) DO NOTHING
+		RETURNING (SELECT user_id FROM orders WHERE id = $1), created_at;
+	`, orderID).Scan(&userID, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("insert shipment: %w", err)
+	}
+
+	return s.insertOutbox(ctx, tx, shipmentEvent{
+		Event:   "shipment.created",
+		Version: 1,
+		OrderID: orderID,
+		UserID:  userID,
+		Status:  "pending",
+		At:      createdAt.UTC(),
+	})
+}
+
+// Ship: paid -> shipped. Повтор для уже отправленного заказа возвращает текущее состояние.
+func (s *Service) Ship(ctx context.Context, orderID uuid.UUID, carrier, tracking string) (*orderpg.Shipment, error) {
+	carrier, tracking = strings.TrimSpace(carrier), strings.TrimSpace(tracking)
+	if carrier == "" || tracking == "" {
+		return nil, fmt.Errorf("%w: carrier and tracking_number are required", ErrInvalidArgument)
+	}
+	return s.transition(ctx, orderID, "paid", "shipped", func(ctx context.Context, tx pgx.Tx) (*orderpg.Shipment, error) {
+		return scanShipment(tx.QueryRow(ctx, `
+			INSERT INTO shipments (order_id, status, carrier, tracking_number, shipped_at)
+			VALUES ($1, 'shipped', $2, $3, now())
+			ON CONFLICT (order_id) DO UPDATE
+			SET status = 'shipped', carrier = EXCLUDED.carrier,
+			    tracking_number = EXCLUDED.tracking_number, shipped_at = EXCLUDED.shipped_at
+			RETURNING status, carrier, tracking_number, created_at, shipped_at, delivered_at;
+		`, orderID, carrier, tracking))
+	})
+}
+
+// Deliver: shipped -> delivered.
+func (s *Service) Deliver(ctx context.Context, orderID uuid.UUID) (*orderpg.Shipment, error) {
+	return s.transition(ctx, orderID, "shipped", "delivered", func(ctx context.Context, tx pgx.Tx) (*orderpg.Shipment, error) {
+		return scanShipment(tx.QueryRow(ctx, `
+			UPDATE shipments
+			SET status = 'delivered', delivered_at = now()
+			WHERE order_id = $1
+			RETURNING status, carrier, tracking_number, created_at, shipped_at, delivered_at;
+		`, orderID))
+	})
+}
+
+func (s *Service) transition(
+	ctx context.Context, orderID uuid.UUID, from, to string,
+	apply func(ctx context.Context, tx pgx.Tx) (*orderpg.Shipment, error),
+) (*orderpg.Shipment, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		userID uuid.UUID
+		cur    string
+	)
+	if err := tx.QueryRow(ctx, `SELECT user_id, status FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&userID, &cur); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("select order: %w", err)
+	}
+
+	repeat, err := checkTransition(cur, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// идемпотентный повтор admin-команды
+	if repeat {
+		shp, err := scanShipment(tx.QueryRow(ctx, `
+			SELECT status, carrier, tracking_number, created_at, shipped_at, delivered_at
+			FROM shipments WHERE order_id = $1;
+		`, orderID))
+		if err != nil {
+			return nil, fmt.Errorf("select shipment: %w", err)
+		}
+		return shp, nil
+	}
+	if _, err := tx.Exec(ctx, `UPDATE orders SET status = $2, updated_at = now() WHERE id = $1`, orderID, to); err != nil {
+		return nil, fmt.Errorf("update order status: %w", err)
+	}
+	shp, err := apply(ctx, tx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no shipment for order", ErrInvalidTransition)
+		}
+		return nil, fmt.Errorf("update shipment: %w", err)
+	}
+
+	if err := s.insertOutbox(ctx, tx, shipmentEvent{
+		Event:          "order." + to,
+		Version:        1,
+		OrderID:        orderID,
+		UserID:         userID,
+		Status:         to,
+		Carrier:        shp.Carrier,
+		TrackingNumber: shp.TrackingNumber,
+		ShippedAt:      shp.ShippedAt,
+		DeliveredAt:    shp.DeliveredAt,
+		At:             time.Now().UTC(),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	s.cache.Set(ctx, orderID.String(), to)
+	s.log.Info("orders.fulfillment: status updated",
+		slog.String("order_id", orderID.String()),
+		slog.String("from", from),
+		slog.String("to", to),
+	)
+	return shp, nil
+}
+
+// checkTransition — переход только from -> to; cur == to — повтор команды (не ошибка).
+func checkTransition(cur, from, to string) (repeat bool, err error) {
+	switch cur {
+	case to:
+		return true, nil
+	case from:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, cur, to)
+	}
+}
+
+func (s *Service) insertOutbox(ctx context.Context, tx pgx.Tx, ev shipmentEvent) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshal outbox payload: %w", err)
+	}
+	headers, err := json.Marshal([]struct{ K, V string }{
+		{K: "event-type", V: ev.Event},
+		{K: "source", V: "orders-fulfillment"},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal headers: %w", err)
+	}
+
+	const insOutbox = `
+		INSERT INTO orders_outbox (agg_type, agg_id, topic, key, headers, payload)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6::jsonb);
+	`
+	if _, err := tx.Exec(ctx, insOutbox,
+		"order", ev.OrderID, s.topic, ev.OrderID[:], headers, payload,
+	); err != nil {
+		return fmt.Errorf("insert outbox: %w", err)
+	}
+	return nil
+}
+
+func scanShipment(row pgx.Row) (*orderpg.Shipment, error) {
+	var shp orderpg.Shipment
+	if err := row.Scan(&shp.Status, &shp.Carrier, &shp.TrackingNumber, &shp.CreatedAt, &shp.ShippedAt, &shp.DeliveredAt); err != nil {
+		return nil, err
+	}
+	return &shp, nil
+}
@@ -0,0 +1,50 @@
+package fulfillment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCheckTransition(t *testing.T) {
+	cases := []struct {
+		cur, from, to string
+		wantRepeat    bool
+		wantErr       bool
+	}{
+		// Ship
+		{"paid", "paid", "shipped", false, false},
+		{"shipped", "paid", "shipped", true, false},
+		{"new", "paid", "shipped", false, true},
+		{"payment_failed", "paid", "shipped", false, true},
+		{"cancelled", "paid", "shipped", false, true},
+		{"delivered", "paid", "shipped", false, true}, // назад не откатываем
+		// Deliver
+		{"shipped", "shipped", "delivered", false, false},
+		{"delivered", "shipped", "delivered", true, false},
+		{"paid", "shipped", "delivered", false, true}, // без отправки не доставить
+		{"new", "shipped", "delivered", false, true},
+	}
+	for _, tc := range cases {
+		repeat, err := checkTransition(tc.cur, tc.from, tc.to)
+		if repeat != tc.wantRepeat || (err != nil) != tc.wantErr {
+			t.Fatalf("%s (%s -> %s): repeat=%v err=%v, want repeat=%v err=%v",
+				tc.cur, tc.from, tc.to, repeat, err, tc.wantRepeat, tc.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidTransition) {
+			t.Fatalf("%s -> %s: err = %v, want ErrInvalidTransition", tc.cur, tc.to, err)
+		}
+	}
+}
+
+// Аргументы проверяются до транзакции.
+func TestShip_RequiresCarrierAndTracking(t *testing.T) {
+	s := New(nil, nil, nil, "")
+	for _, args := range [][2]string{{"", "TRK1"}, {"DHL", ""}, {"  ", " "}} {
+		if _, err := s.Ship(context.Background(), uuid.New(), args[0], args[1]); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Ship(%q, %q): err = %v, want ErrInvalidArgument", args[0], args[1], err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package grpcsvr
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"google.golang.org/grpc/metadata"
+
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
+	"goshop/services/orders/api/orderspb"
+)
+
+// adminMethods — admin-методы и роли, которые их открывают (access-токен users с ролью).
+var adminMethods = grpcauth.Rules{
+	orderspb.Orders_ListOrders_FullMethodName:   {jwtauth.RoleAdmin, jwtauth.RoleSupport},
+	orderspb.Orders_ShipOrder_FullMethodName:    {jwtauth.RoleAdmin},
+	orderspb.Orders_DeliverOrder_FullMethodName: {jwtauth.RoleAdmin},
+
+	orderspb.Orders_CreatePromotion_FullMethodName:     {jwtauth.RoleAdmin},
+	orderspb.Orders_ListPromotions_FullMethodName:      {jwtauth.RoleAdmin},
+	orderspb.Orders_DeactivatePromotion_FullMethodName: {jwtauth.RoleAdmin},
+}
+
+// adminToken — статический metadata "x-admin-token" для скриптов без пользователя; пустой — не принимается.
+func adminToken(token string) func(ctx context.Context) bool {
+	return func(ctx context.Context) bool {
+		if token == "" {
+			return false
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		got := md.Get("x-admin-token")
+		return len(got) > 0 && subtle.ConstantTimeCompare([]byte(got[0]), []byte(token)) == 1
+	}
+}
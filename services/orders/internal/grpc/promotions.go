@@ -0,0 +1,147 @@
+package grpcsvr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/money"
+	"goshop/services/orders/api/orderspb"
+	"goshop/services/orders/internal/promotions"
+)
+
+func (s *Server) CreatePromotion(ctx context.Context, in *orderspb.CreatePromotionRequest) (*orderspb.Promotion, error) {
+	if s.promo == nil {
+		return nil, status.Error(codes.Unimplemented, "promotions are not enabled")
+	}
+	pb := in.GetPromotion()
+	if pb == nil {
+		return nil, status.Error(codes.InvalidArgument, "promotion is required")
+	}
+
+	p := promotions.Promotion{
+		Code:         pb.GetCode(),
+		PercentOff:   int(pb.GetPercentOff()),
+		Currency:     pb.GetCurrency(),
+		PerUserLimit: int(pb.GetPerUserLimit()),
+	}
+	switch pb.GetKind() {
+	case orderspb.PromotionKind_PROMOTION_KIND_PERCENT:
+		p.Kind = promotions.KindPercent
+	case orderspb.PromotionKind_PROMOTION_KIND_FIXED:
+		p.Kind = promotions.KindFixed
+	}
+	// валюта суммы скидки/минимума и currency должны совпадать
+	for _, m := range []*orderspb.Money{pb.GetAmountOff(), pb.GetMinAmount()} {
+		if m == nil {
+			continue
+		}
+		c, err := money.ParseCurrency(m.GetCurrency())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "unsupported currency")
+		}
+		if p.Currency != "" && p.Currency != c.Code {
+			return nil, status.Error(codes.InvalidArgument, "amount_off, min_amount and currency must use one currency")
+		}
+		p.Currency = c.Code
+	}
+	p.AmountOffMinor = pb.GetAmountOff().GetUnitsMinor()
+	p.MinAmountMinor = pb.GetMinAmount().GetUnitsMinor()
+	if n := pb.GetMaxRedemptions(); n != 0 {
+		v := int(n)
+		p.MaxRedemptions = &v
+	}
+	var err error
+	if v := pb.GetStartsAt(); v != "" {
+		if p.StartsAt, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "starts_at must be RFC3339")
+		}
+	}
+	if v := pb.GetEndsAt(); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "ends_at must be RFC3339")
+		}
+		p.EndsAt = &t
+	}
+
+	out, err := s.promo.Create(ctx, p)
+	if err != nil {
+		return nil, promotionStatus(err)
+	}
+	return toPbPromotion(out), nil
+}
+
+func (s *Server) ListPromotions(ctx context.Context, in *orderspb.ListPromotionsRequest) (*orderspb.ListPromotionsResponse, error) {
+	if s.promo == nil {
+		return nil, status.Error(codes.Unimplemented, "promotions are not enabled")
+	}
+	list, err := s.promo.List(ctx, in.GetActiveOnly())
+	if err != nil {
+		return nil, promotionStatus(err)
+	}
+	resp := &orderspb.ListPromotionsResponse{Promotions: make([]*orderspb.Promotion, 0, len(list))}
+	for i := range list {
+		resp.Promotions = append(resp.Promotions, toPbPromotion(&list[i]))
+	}
+	return resp, nil
+}
+
+func (s *Server) DeactivatePromotion(ctx context.Context, in *orderspb.DeactivatePromotionRequest) (*orderspb.Promotion, error) {
+	if s.promo == nil {
+		return nil, status.Error(codes.Unimplemented, "promotions are not enabled")
+	}
+	if in.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	out, err := s.promo.Deactivate(ctx, in.GetCode())
+	if err != nil {
+		return nil, promotionStatus(err)
+	}
+	return toPbPromotion(out), nil
+}
+
+func promotionStatus(err error) error {
+	switch {
+	case errors.Is(err, promotions.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, promotions.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, promotions.ErrInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "promotions: %v", err)
+	}
+}
+
+func toPbPromotion(p *promotions.Promotion) *orderspb.Promotion {
+	out := &orderspb.Promotion{
+		Code:         p.Code,
+		PercentOff:   int32(p.PercentOff),
+		Currency:     p.Currency,
+		StartsAt:     p.StartsAt.UTC().Format(time.RFC3339),
+		PerUserLimit: int32(p.PerUserLimit),
+		Active:       p.Active,
+		Redemptions:  p.Redemptions,
+	}
+	switch p.Kind {
+	case promotions.KindPercent:
+		out.Kind = orderspb.PromotionKind_PROMOTION_KIND_PERCENT
+	case promotions.KindFixed:
+		out.Kind = orderspb.PromotionKind_PROMOTION_KIND_FIXED
+		out.AmountOff = &orderspb.Money{UnitsMinor: p.AmountOffMinor, Currency: p.Currency}
+	}
+	if p.MinAmountMinor > 0 {
+		out.MinAmount = &orderspb.Money{UnitsMinor: p.MinAmountMinor, Currency: p.Currency}
+	}
+	if p.EndsAt != nil {
+		out.EndsAt = p.EndsAt.UTC().Format(time.RFC3339)
+	}
+	if p.MaxRedemptions != nil {
+		out.MaxRedemptions = int32(*p.MaxRedemptions)
+	}
+	return out
+}
@@ -2,6 +2,7 @@ package grpcsvr
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net"
 	"time"
@@ -11,20 +12,34 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
+	"goshop/pkg/money"
 	"goshop/services/orders/api/orderspb"
 	"goshop/services/orders/internal/adapters/repo/orderpg"
+	"goshop/services/orders/internal/fulfillment"
+	"goshop/services/orders/internal/promotions"
 )
 
 type Options struct {
-	Addr   string
-	Logger *slog.Logger
-	Repo   *orderpg.Repository
+	Addr        string
+	Logger      *slog.Logger
+	Repo        *orderpg.Repository
+	Fulfillment *fulfillment.Service
+	Promotions  *promotions.Service
+	// admin-методы (adminMethods): access-токен с ролью или AdminToken; без обоих — выключены
+	Verifier   jwtauth.TokenVerifier
+	Revoked    grpcauth.RevocationChecker
+	Audience   string
+	AdminToken string
 }
 
 type Server struct {
 	orderspb.UnimplementedOrdersServer
-	log  *slog.Logger
-	repo *orderpg.Repository
+	log   *slog.Logger
+	repo  *orderpg.Repository
+	ful   *fulfillment.Service
+	promo *promotions.Service
 }
 
 func (s *Server) CreateOrder(ctx context.Context, in *orderspb.CreateOrderRequest) (*orderspb.CreateOrderResponse, error) {
@@ -40,15 +55,34 @@ func (s *Server) CreateOrder(ctx context.Context, in *orderspb.CreateOrderReques
 	if curr == "" {
 		curr = "RUB"
 	}
+	cur, err := money.ParseCurrency(curr)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "unsupported currency")
+	}
+	addr := fromPbAddress(in.GetShippingAddress())
+	if addr != nil {
+		addr.Normalize()
+		if err := addr.Validate(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
 
 	ord, err := s.repo.Create(ctx, orderpg.CreateParams{
 		UserID:        uid,
 		AmountCents:   in.AmountCents,
-		Currency:      curr,
+		Currency:      cur.Code,
+		Address:       addr,
+		PromoCode:     in.GetPromoCode(),
 		OutboxTopic:   "orders.events",
 		OutboxHeaders: map[string]string{"event-type": "order.created", "source": "orders-grpc"},
 	})
 	if err != nil {
+		switch {
+		case errors.Is(err, promotions.ErrNotApplicable):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, orderpg.ErrPromoDisabled):
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "create order: %v", err)
 	}
 
@@ -56,8 +90,163 @@ func (s *Server) CreateOrder(ctx context.Context, in *orderspb.CreateOrderReques
 		OrderId:     ord.ID.String(),
 		Status:      toPbStatus(ord.Status),
 		Currency:    ord.Currency,
-		TotalAmount: ord.TotalAmount,
+		TotalAmount: ord.Money().Major(), // deprecated, до v2
 		CreatedAt:   ord.CreatedAt.UTC().Format(time.RFC3339),
+		Total:       toPbMoney(ord.Money()),
+		Discount:    toPbDiscount(ord),
+		PromoCode:   ord.PromoCode,
+	}
+	return resp, nil
+}
+
+func (s *Server) GetOrder(ctx context.Context, in *orderspb.GetOrderRequest) (*orderspb.GetOrderResponse, error) {
+	if in == nil || in.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	id, err := uuid.Parse(in.OrderId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "bad order_id")
+	}
+
+	ord, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, orderpg.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		return nil, status.Errorf(codes.Internal, "get order: %v", err)
+	}
+
+	return &orderspb.GetOrderResponse{
+		OrderId:     ord.ID.String(),
+		UserId:      ord.UserID.String(),
+		Status:      toPbStatus(ord.Status),
+		Currency:    ord.Currency,
+		TotalAmount: ord.Money().Major(), // deprecated, до v2
+		CreatedAt:   ord.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   ord.UpdatedAt.UTC().Format(time.RFC3339),
+		Total:       toPbMoney(ord.Money()),
+
+		ShippingAddress: toPbAddress(ord.ShippingAddress),
+		Shipment:        toPbShipment(ord.Shipment),
+		Discount:        toPbDiscount(ord),
+		PromoCode:       ord.PromoCode,
+	}, nil
+}
+
+func (s *Server) ShipOrder(ctx context.Context, in *orderspb.ShipOrderRequest) (*orderspb.FulfillmentResponse, error) {
+	id, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "bad order_id")
+	}
+	shp, err := s.ful.Ship(ctx, id, in.GetCarrier(), in.GetTrackingNumber())
+	if err != nil {
+		return nil, fulfillmentStatus(err)
+	}
+	return &orderspb.FulfillmentResponse{
+		OrderId:  id.String(),
+		Status:   orderspb.OrderStatus_ORDER_STATUS_SHIPPED,
+		Shipment: toPbShipment(shp),
+	}, nil
+}
+
+func (s *Server) DeliverOrder(ctx context.Context, in *orderspb.DeliverOrderRequest) (*orderspb.FulfillmentResponse, error) {
+	id, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "bad order_id")
+	}
+	shp, err := s.ful.Deliver(ctx, id)
+	if err != nil {
+		return nil, fulfillmentStatus(err)
+	}
+	return &orderspb.FulfillmentResponse{
+		OrderId:  id.String(),
+		Status:   orderspb.OrderStatus_ORDER_STATUS_DELIVERED,
+		Shipment: toPbShipment(shp),
+	}, nil
+}
+
+func fulfillmentStatus(err error) error {
+	switch {
+	case errors.Is(err, fulfillment.ErrNotFound):
+		return status.Error(codes.NotFound, "order not found")
+	case errors.Is(err, fulfillment.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, fulfillment.ErrInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "fulfillment: %v", err)
+	}
+}
+
+func (s *Server) ListOrders(ctx context.Context, in *orderspb.ListOrdersRequest) (*orderspb.ListOrdersResponse, error) {
+	if in == nil {
+		in = &orderspb.ListOrdersRequest{}
+	}
+	p := orderpg.ListParams{
+		Limit:     int(in.GetPageSize()),
+		PageToken: in.GetPageToken(),
+	}
+	if in.GetPageSize() < 0 || in.GetPageSize() > orderpg.MaxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 0..%d", orderpg.MaxPageSize)
+	}
+	if in.GetUserId() != "" {
+		uid, err := uuid.Parse(in.GetUserId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "bad user_id")
+		}
+		p.UserID = &uid
+	}
+	for _, st := range in.GetStatuses() {
+		v := fromPbStatus(st)
+		if v == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "bad status: %s", st)
+		}
+		p.Statuses = append(p.Statuses, v)
+	}
+	if in.GetCurrency() != "" {
+		cur, err := money.ParseCurrency(in.GetCurrency())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "unsupported currency")
+		}
+		p.Currency = cur.Code
+	}
+	var err error
+	if v := in.GetCreatedFrom(); v != "" {
+		if p.CreatedFrom, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "created_from must be RFC3339")
+		}
+	}
+	if v := in.GetCreatedTo(); v != "" {
+		if p.CreatedTo, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "created_to must be RFC3339")
+		}
+	}
+	if in.GetSort() == orderspb.OrderSort_ORDER_SORT_CREATED_AT_ASC {
+		p.Sort = orderpg.SortCreatedAsc
+	}
+
+	page, err := s.repo.List(ctx, p)
+	if err != nil {
+		if errors.Is(err, orderpg.ErrBadCursor) {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		return nil, status.Errorf(codes.Internal, "list orders: %v", err)
+	}
+
+	resp := &orderspb.ListOrdersResponse{
+		Orders:        make([]*orderspb.Order, 0, len(page.Orders)),
+		NextPageToken: page.NextPageToken,
+	}
+	for i := range page.Orders {
+		ord := &page.Orders[i]
+		resp.Orders = append(resp.Orders, &orderspb.Order{
+			OrderId:   ord.ID.String(),
+			UserId:    ord.UserID.String(),
+			Status:    toPbStatus(ord.Status),
+			Total:     toPbMoney(ord.Money()),
+			CreatedAt: ord.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt: ord.UpdatedAt.UTC().Format(time.RFC3339),
+		})
 	}
 	return resp, nil
 }
@@ -75,8 +264,14 @@ func Start(ctx context.Context, opt Options) error {
 		return err
 	}
 
-	s := grpc.NewServer()
-	orderspb.RegisterOrdersServer(s, &Server{log: opt.Logger, repo: opt.Repo})
+	s := grpc.NewServer(grpc.UnaryInterceptor(grpcauth.RequireRole(grpcauth.Options{
+		Logger:   opt.Logger,
+		Verifier: opt.Verifier,
+		Revoked:  opt.Revoked,
+		Audience: opt.Audience,
+		Fallback: adminToken(opt.AdminToken),
+	}, adminMethods)))
+	orderspb.RegisterOrdersServer(s, &Server{log: opt.Logger, repo: opt.Repo, ful: opt.Fulfillment, promo: opt.Promotions})
 
 	errCh := make(chan error, 1)
 	go func() {
@@ -103,7 +298,94 @@ func toPbStatus(s string) orderspb.OrderStatus {
 		return orderspb.OrderStatus_ORDER_STATUS_PAID
 	case "cancelled", "canceled":
 		return orderspb.OrderStatus_ORDER_STATUS_CANCELLED
+	case "shipped":
+		return orderspb.OrderStatus_ORDER_STATUS_SHIPPED
+	case "delivered":
+		return orderspb.OrderStatus_ORDER_STATUS_DELIVERED
+	case "payment_failed":
+		return orderspb.OrderStatus_ORDER_STATUS_PAYMENT_FAILED
 	default:
 		return orderspb.OrderStatus_ORDER_STATUS_UNSPECIFIED
 	}
 }
+
+func fromPbStatus(s orderspb.OrderStatus) string {
+	switch s {
+	case orderspb.OrderStatus_ORDER_STATUS_NEW:
+		return "new"
+	case orderspb.OrderStatus_ORDER_STATUS_PAID:
+		return "paid"
+	case orderspb.OrderStatus_ORDER_STATUS_CANCELLED:
+		return "cancelled"
+	case orderspb.OrderStatus_ORDER_STATUS_SHIPPED:
+		return "shipped"
+	case orderspb.OrderStatus_ORDER_STATUS_DELIVERED:
+		return "delivered"
+	case orderspb.OrderStatus_ORDER_STATUS_PAYMENT_FAILED:
+		return "payment_failed"
+	default:
+		return ""
+	}
+}
+
+func toPbMoney(m money.Money) *orderspb.Money {
+	return &orderspb.Money{UnitsMinor: m.Amount, Currency: m.Currency.Code}
+}
+
+// toPbDiscount — nil для заказа без промо-кода.
+func toPbDiscount(ord *orderpg.Order) *orderspb.Money {
+	if ord.DiscountMinor == 0 {
+		return nil
+	}
+	return &orderspb.Money{UnitsMinor: ord.DiscountMinor, Currency: ord.Currency}
+}
+
+func fromPbAddress(a *orderspb.Address) *orderpg.Address {
+	if a == nil {
+		return nil
+	}
+	return &orderpg.Address{
+		Recipient:  a.GetRecipient(),
+		Phone:      a.GetPhone(),
+		Country:    a.GetCountry(),
+		Region:     a.GetRegion(),
+		City:       a.GetCity(),
+		PostalCode: a.GetPostalCode(),
+		Line1:      a.GetLine1(),
+		Line2:      a.GetLine2(),
+	}
+}
+
+func toPbAddress(a *orderpg.Address) *orderspb.Address {
+	if a == nil {
+		return nil
+	}
+	return &orderspb.Address{
+		Recipient:  a.Recipient,
+		Phone:      a.Phone,
+		Country:    a.Country,
+		Region:     a.Region,
+		City:       a.City,
+		PostalCode: a.PostalCode,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+	}
+}
+
+func toPbShipment(s *orderpg.Shipment) *orderspb.Shipment {
+	if s == nil {
+		return nil
+	}
+	out := &orderspb.Shipment{
+		Status:         s.Status,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+	}
+	if s.ShippedAt != nil {
+		out.ShippedAt = s.ShippedAt.UTC().Format(time.RFC3339)
+	}
+	if s.DeliveredAt != nil {
+		out.DeliveredAt = s.DeliveredAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
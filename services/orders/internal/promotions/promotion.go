@@ -0,0 +1,115 @@
+package promotions
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"goshop/pkg/money"
+)
+
+var (
+	ErrNotFound        = errors.New("promotion not found")
+	ErrAlreadyExists   = errors.New("promotion code already exists")
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrNotApplicable — код нельзя применить к этому заказу (истёк, лимит, мин. сумма, валюта)
+	ErrNotApplicable = errors.New("promo code is not applicable")
+)
+
+const (
+	KindPercent = "percent"
+	KindFixed   = "fixed"
+)
+
+type Promotion struct {
+	ID             uuid.UUID
+	Code           string
+	Kind           string // percent|fixed
+	PercentOff     int    // 1..99 для percent
+	AmountOffMinor int64  // для fixed, в Currency
+	Currency       string // обязательна для fixed и при MinAmountMinor > 0
+	MinAmountMinor int64
+	StartsAt       time.Time
+	EndsAt         *time.Time // nil — бессрочно
+	MaxRedemptions *int       // nil — без общего лимита
+	PerUserLimit   int
+	Active         bool
+	Redemptions    int64 // погашено (без отменённых заказов)
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Validate — инварианты акции (те же, что CHECK в таблице promotions).
+func (p *Promotion) Validate() error {
+	if p.Code == "" {
+		return fmt.Errorf("%w: code is required", ErrInvalidArgument)
+	}
+	if p.Currency != "" {
+		c, err := money.ParseCurrency(p.Currency)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+		}
+		p.Currency = c.Code
+	}
+	switch p.Kind {
+	case KindPercent:
+		if p.PercentOff < 1 || p.PercentOff > 99 {
+			return fmt.Errorf("%w: percent_off must be 1..99", ErrInvalidArgument)
+		}
+	case KindFixed:
+		if p.AmountOffMinor <= 0 || p.Currency == "" {
+			return fmt.Errorf("%w: fixed promotion needs positive amount_off and currency", ErrInvalidArgument)
+		}
+		// скидка не должна обнулять заказ
+		if p.MinAmountMinor <= p.AmountOffMinor {
+			return fmt.Errorf("%w: min_amount must be greater than amount_off", ErrInvalidArgument)
+		}
+	default:
+		return fmt.Errorf("%w: kind must be percent or fixed", ErrInvalidArgument)
+	}
+	if p.MinAmountMinor < 0 || (p.MinAmountMinor > 0 && p.Currency == "") {
+		return fmt.Errorf("%w: min_amount needs currency", ErrInvalidArgument)
+	}
+	if p.EndsAt != nil && !p.EndsAt.After(p.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidArgument)
+	}
+	if p.MaxRedemptions != nil && *p.MaxRedemptions <= 0 {
+		return fmt.Errorf("%w: max_redemptions must be positive", ErrInvalidArgument)
+	}
+	if p.PerUserLimit <= 0 {
+		return fmt.Errorf("%w: per_user_limit must be positive", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// Discount — скидка для суммы заказа на момент now (без учёта лимитов использования).
+// Процент округляется вниз, чтобы не давать больше заявленного.
+func (p *Promotion) Discount(amount money.Money, now time.Time) (int64, error) {
+	switch {
+	case !p.Active:
+		return 0, fmt.Errorf("%w: promotion is disabled", ErrNotApplicable)
+	case now.Before(p.StartsAt):
+		return 0, fmt.Errorf("%w: promotion has not started", ErrNotApplicable)
+	case p.EndsAt != nil && !now.Before(*p.EndsAt):
+		return 0, fmt.Errorf("%w: promotion has expired", ErrNotApplicable)
+	case p.Currency != "" && p.Currency != amount.Currency.Code:
+		return 0, fmt.Errorf("%w: promotion is valid for %s orders only", ErrNotApplicable, p.Currency)
+	case amount.Amount < p.MinAmountMinor:
+		minAmt := money.Money{Amount: p.MinAmountMinor, Currency: amount.Currency}
+		return 0, fmt.Errorf("%w: minimum order amount is %s", ErrNotApplicable, minAmt)
+	}
+
+	var d int64
+	if p.Kind == KindPercent {
+		d = amount.Amount / 100 * int64(p.PercentOff)
+		d += amount.Amount % 100 * int64(p.PercentOff) / 100
+	} else {
+		d = p.AmountOffMinor
+	}
+	if d <= 0 || d >= amount.Amount {
+		return 0, fmt.Errorf("%w: order amount is too small", ErrNotApplicable)
+	}
+	return d, nil
+}
@@ -0,0 +1,62 @@
+package promotions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goshop/pkg/money"
+)
+
+func TestPromotionDiscount(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ended := now.Add(-time.Hour)
+	rub := func(v int64) money.Money { return money.Money{Amount: v, Currency: money.MustCurrency("RUB")} }
+	usd := money.Money{Amount: 10000, Currency: money.MustCurrency("USD")}
+
+	percent := Promotion{Kind: KindPercent, PercentOff: 15, Active: true, StartsAt: now.Add(-time.Hour)}
+	fixed := Promotion{Kind: KindFixed, AmountOffMinor: 50000, Currency: "RUB", MinAmountMinor: 200000, Active: true, StartsAt: now.Add(-time.Hour)}
+
+	cases := []struct {
+		name    string
+		p       Promotion
+		amount  money.Money
+		want    int64
+		wantErr bool
+	}{
+		{"percent", percent, rub(19999), 2999, false},
+		{"percent any currency", percent, usd, 1500, false},
+		{"fixed", fixed, rub(250000), 50000, false},
+		{"fixed below minimum", fixed, rub(199999), 0, true},
+		{"fixed other currency", fixed, usd, 0, true},
+		{"percent too small", percent, rub(5), 0, true},
+		{"disabled", func() Promotion { p := percent; p.Active = false; return p }(), rub(1000), 0, true},
+		{"not started", func() Promotion { p := percent; p.StartsAt = now.Add(time.Minute); return p }(), rub(1000), 0, true},
+		{"expired", func() Promotion { p := percent; p.EndsAt = &ended; return p }(), rub(1000), 0, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.p.Discount(tc.amount, now)
+			if tc.wantErr {
+				if !errors.Is(err, ErrNotApplicable) {
+					t.Fatalf("err = %v, want ErrNotApplicable", err)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Fatalf("Discount = %d, %v; want %d", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPromotionValidate(t *testing.T) {
+	p := Promotion{Code: "SALE", Kind: KindFixed, AmountOffMinor: 500, Currency: "rub", MinAmountMinor: 500, PerUserLimit: 1}
+	if err := p.Validate(); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("fixed with min_amount == amount_off: err = %v", err)
+	}
+	p.MinAmountMinor = 1000
+	if err := p.Validate(); err != nil || p.Currency != "RUB" {
+		t.Fatalf("Validate = %v, currency %q", err, p.Currency)
+	}
+}
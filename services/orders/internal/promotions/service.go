@@ -0,0 +1,206 @@
+package promotions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"goshop/pkg/money"
+	"goshop/services/orders/internal/adapters/repo/orderpg"
+)
+
+// Service — промо-коды: admin-управление и погашение в транзакции создания заказа
+// (реализует orderpg.Coupons). Использования отменённых заказов в лимитах не считаются.
+type Service struct {
+	log *slog.Logger
+	db  *pgxpool.Pool
+}
+
+var _ orderpg.Coupons = (*Service)(nil)
+
+func New(log *slog.Logger, db *pgxpool.Pool) *Service {
+	return &Service{log: log, db: db}
+}
+
+const promoCols = `
+	p.id, p.code, p.kind, COALESCE(p.percent_off, 0), COALESCE(p.amount_off_minor, 0), COALESCE(p.currency, ''),
+	p.min_amount_minor, p.starts_at, p.ends_at, p.max_redemptions, p.per_user_limit, p.active,
+	p.created_at, p.updated_at`
+
+// liveRedemptions — FROM/WHERE живых использований акции promoRef (заказ не отменён).
+func liveRedemptions(promoRef string) string {
+	return `
+	FROM promotion_redemptions r
+	JOIN orders o ON o.id = r.order_id
+	WHERE r.promotion_id = ` + promoRef + ` AND o.status NOT IN ('cancelled', 'canceled')`
+}
+
+func (s *Service) Create(ctx context.Context, p Promotion) (*Promotion, error) {
+	p.Code = orderpg.NormalizePromoCode(p.Code)
+	if p.StartsAt.IsZero() {
+		p.StartsAt = time.Now().UTC()
+	}
+	if p.PerUserLimit == 0 {
+		p.PerUserLimit = 1
+	}
+	p.Active = true
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var percent *int
+	var amountOff *int64
+	if p.Kind == KindPercent {
+		percent = &p.PercentOff
+	} else {
+		amountOff = &p.AmountOffMinor
+	}
+	var currency *string
+	if p.Currency != "" {
+		currency = &p.Currency
+	}
+
+	out, err := scanPromotion(s.db.QueryRow(ctx, `
+		INSERT INTO promotions AS p (code, kind, percent_off, amount_off_minor, currency, min_amount_minor,
+		                             starts_at, ends_at, max_redemptions, per_user_limit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+promoCols+`;
+	`, p.Code, p.Kind, percent, amountOff, currency, p.MinAmountMinor,
+		p.StartsAt, p.EndsAt, p.MaxRedemptions, p.PerUserLimit))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("insert promotion: %w", err)
+	}
+
+	s.log.Info("orders.promotions: created",
+		slog.String("code", out.Code),
+		slog.String("kind", out.Kind),
+	)
+	return out, nil
+}
+
+// List — акции с числом использований; activeOnly — только включённые и не истёкшие.
+func (s *Service) List(ctx context.Context, activeOnly bool) ([]Promotion, error) {
+	q := `SELECT ` + promoCols + `,
+		(SELECT count(*) ` + liveRedemptions("p.id") + `)
+		FROM promotions p`
+	if activeOnly {
+		q += ` WHERE p.active AND (p.ends_at IS NULL OR p.ends_at > now())`
+	}
+	q += ` ORDER BY p.created_at DESC;`
+
+	rows, err := s.db.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("select promotions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Promotion
+	for rows.Next() {
+		p, err := scanPromotionRow(rows, true)
+		if err != nil {
+			return nil, fmt.Errorf("scan promotion: %w", err)
+		}
+		out = append(out, *p)
+	}
+	return out, rows.Err()
+}
+
+// Deactivate — выключить код; уже созданные заказы не меняются.
+func (s *Service) Deactivate(ctx context.Context, code string) (*Promotion, error) {
+	p, err := scanPromotion(s.db.QueryRow(ctx, `
+		UPDATE promotions AS p SET active = false
+		WHERE code = $1
+		RETURNING `+promoCols+`;
+	`, orderpg.NormalizePromoCode(code)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("deactivate promotion: %w", err)
+	}
+	s.log.Info("orders.promotions: deactivated", slog.String("code", p.Code))
+	return p, nil
+}
+
+// QuoteTx — см. orderpg.Coupons. FOR UPDATE сериализует погашения одного кода,
+// поэтому подсчёт использований ниже не гонится с параллельными заказами.
+func (s *Service) QuoteTx(ctx context.Context, tx pgx.Tx, code string, userID uuid.UUID, amount money.Money) (*orderpg.Discount, error) {
+	p, err := scanPromotion(tx.QueryRow(ctx, `
+		SELECT `+promoCols+`
+		FROM promotions p
+		WHERE p.code = $1
+		FOR UPDATE;
+	`, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: unknown code", ErrNotApplicable)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select promotion: %w", err)
+	}
+
+	d, err := p.Discount(amount, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var total, mine int64
+	if err := tx.QueryRow(ctx, `
+		SELECT count(*), count(*) FILTER (WHERE r.user_id = $2)
+	`+liveRedemptions("$1")+`;`, p.ID, userID).Scan(&total, &mine); err != nil {
+		return nil, fmt.Errorf("count redemptions: %w", err)
+	}
+	if p.MaxRedemptions != nil && total >= int64(*p.MaxRedemptions) {
+		return nil, fmt.Errorf("%w: usage limit reached", ErrNotApplicable)
+	}
+	if mine >= int64(p.PerUserLimit) {
+		return nil, fmt.Errorf("%w: already used", ErrNotApplicable)
+	}
+
+	return &orderpg.Discount{
+		PromotionID: p.ID,
+		Code:        p.Code,
+		AmountMinor: d,
+		Currency:    amount.Currency.Code,
+	}, nil
+}
+
+func (s *Service) RedeemTx(ctx context.Context, tx pgx.Tx, orderID, userID uuid.UUID, d *orderpg.Discount) error {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO promotion_redemptions (order_id, promotion_id, user_id, discount_minor, currency)
+		VALUES ($1, $2, $3, $4, $5);
+	`, orderID, d.PromotionID, userID, d.AmountMinor, d.Currency); err != nil {
+		return fmt.Errorf("insert redemption: %w", err)
+	}
+	return nil
+}
+
+func scanPromotion(row pgx.Row) (*Promotion, error) {
+	return scanPromotionRow(row, false)
+}
+
+func scanPromotionRow(row pgx.Row, withCount bool) (*Promotion, error) {
+	var p Promotion
+	dst := []any{
+		&p.ID, &p.Code, &p.Kind, &p.PercentOff, &p.AmountOffMinor, &p.Currency,
+		&p.MinAmountMinor, &p.StartsAt, &p.EndsAt, &p.MaxRedemptions, &p.PerUserLimit, &p.Active,
+		&p.CreatedAt, &p.UpdatedAt,
+	}
+	if withCount {
+		dst = append(dst, &p.Redemptions)
+	}
+	if err := row.Scan(dst...); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,55 @@
+package statuscache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache — статус заказа в Redis (order:<id>:status) + уведомление подписчиков
+// в канал order:<id>:status:updates (gateway WatchOrderStatus).
+type Cache struct {
+	log *slog.Logger
+	rds *redis.Client
+	ttl time.Duration
+}
+
+func New(log *slog.Logger, rds *redis.Client, ttl time.Duration) *Cache {
+	return &Cache{log: log, rds: rds, ttl: ttl}
+}
+
+func Key(orderID string) string { return "order:" + orderID + ":status" }
+
+// Set — best effort: ошибки Redis только логируем, источник правды — Postgres.
+func (c *Cache) Set(ctx context.Context, orderID, status string) {
+	if c == nil || c.rds == nil {
+		return
+	}
+	key := Key(orderID)
+	if err := c.rds.Set(ctx, key, status, c.ttl).Err(); err != nil {
+		c.log.Warn("orders.statuscache.redis: set status failed",
+			slog.String("key", key),
+			slog.String("status", status),
+			slog.Any("err", err),
+		)
+		return
+	}
+	c.log.Debug("orders.statuscache: status cached",
+		slog.String("key", key),
+		slog.String("status", status),
+		slog.Int64("ttl_ms", c.ttl.Milliseconds()),
+	)
+
+	// уведомляем подписчиков (gateway WatchOrderStatus); доставка best effort —
+	// подписчик всегда может перечитать кэш
+	ch := key + ":updates"
+	if err := c.rds.Publish(ctx, ch, status).Err(); err != nil {
+		c.log.Warn("orders.statuscache.redis: publish status failed",
+			slog.String("channel", ch),
+			slog.String("status", status),
+			slog.Any("err", err),
+		)
+	}
+}
@@ -0,0 +1,35 @@
+-- +goose Up
+-- суммы в минорных единицах валюты: NUMERIC(12,2) ломал валюты с 0 и 3 знаками (JPY, KWD)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS amount_minor BIGINT;
+
+-- старые заказы: валюта могла прийти в нижнем регистре или с пробелами
+UPDATE orders SET currency = upper(trim(currency)) WHERE currency <> upper(trim(currency));
+
+-- total_amount хранил amount_cents/100 для любой валюты (payments.amount_cents = total*100),
+-- поэтому ×100, а не по экспоненте ISO: иначе JPY/KWD разойдутся с платежами
+UPDATE orders
+SET amount_minor = round(total_amount * 100)::bigint
+WHERE amount_minor IS NULL;
+
+ALTER TABLE orders ALTER COLUMN amount_minor SET NOT NULL;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_amount;
+ALTER TABLE orders ADD CONSTRAINT orders_currency_iso CHECK (currency ~ '^[A-Z]{3}$');
+
+-- курсы: 1 base = rate quote
+CREATE TABLE IF NOT EXISTS fx_rates (
+    base        TEXT            NOT NULL CHECK (base  ~ '^[A-Z]{3}$'),
+    quote       TEXT            NOT NULL CHECK (quote ~ '^[A-Z]{3}$'),
+    rate        NUMERIC(24,12)  NOT NULL CHECK (rate > 0),
+    updated_at  TIMESTAMPTZ     NOT NULL DEFAULT now(),
+    PRIMARY KEY (base, quote)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS fx_rates;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_currency_iso;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+UPDATE orders SET total_amount = amount_minor::numeric / 100;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS amount_minor;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- keyset-пагинация списка заказов: (created_at, id) с фильтром по пользователю и без
+CREATE INDEX IF NOT EXISTS idx_orders_user_created ON orders (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_created      ON orders (created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_orders_created;
+DROP INDEX IF EXISTS idx_orders_user_created;
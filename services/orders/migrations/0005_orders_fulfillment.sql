@@ -0,0 +1,38 @@
+-- +goose Up
+-- адрес доставки, фиксируется при создании заказа
+CREATE TABLE IF NOT EXISTS order_addresses (
+    order_id     UUID        PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    recipient    TEXT        NOT NULL,
+    phone        TEXT        NOT NULL DEFAULT '',
+    country      CHAR(2)     NOT NULL, -- ISO 3166-1 alpha-2
+    region       TEXT        NOT NULL DEFAULT '',
+    city         TEXT        NOT NULL,
+    postal_code  TEXT        NOT NULL,
+    line1        TEXT        NOT NULL,
+    line2        TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- fulfillment: отправка создаётся по payment.confirmed, дальше shipped -> delivered (admin API)
+CREATE TABLE IF NOT EXISTS shipments (
+    order_id         UUID        PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    status           TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'shipped', 'delivered')),
+    carrier          TEXT        NOT NULL DEFAULT '',
+    tracking_number  TEXT        NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    shipped_at       TIMESTAMPTZ,
+    delivered_at     TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_shipments_open ON shipments(status) WHERE status <> 'delivered';
+
+DROP TRIGGER IF EXISTS trg_shipments_updated_at ON shipments;
+CREATE TRIGGER trg_shipments_updated_at
+    BEFORE UPDATE ON shipments
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_shipments_updated_at ON shipments;
+DROP TABLE IF EXISTS shipments;
+DROP TABLE IF EXISTS order_addresses;
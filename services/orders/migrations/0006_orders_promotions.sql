@@ -0,0 +1,55 @@
+-- +goose Up
+-- промо-коды: процент или фиксированная скидка, окно действия, лимиты, минимальная сумма заказа
+CREATE TABLE IF NOT EXISTS promotions (
+    id                UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    code              TEXT        NOT NULL UNIQUE CHECK (code = upper(code) AND code <> ''),
+    kind              TEXT        NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    percent_off       INT         CHECK (percent_off BETWEEN 1 AND 99),
+    amount_off_minor  BIGINT      CHECK (amount_off_minor > 0),
+    currency          CHAR(3),    -- обязательна для fixed и для min_amount_minor > 0
+    min_amount_minor  BIGINT      NOT NULL DEFAULT 0 CHECK (min_amount_minor >= 0),
+    starts_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ends_at           TIMESTAMPTZ,          -- NULL — бессрочно
+    max_redemptions   INT         CHECK (max_redemptions > 0), -- NULL — без общего лимита
+    per_user_limit    INT         NOT NULL DEFAULT 1 CHECK (per_user_limit > 0),
+    active            BOOLEAN     NOT NULL DEFAULT true,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (
+        (kind = 'percent' AND percent_off IS NOT NULL AND amount_off_minor IS NULL) OR
+        (kind = 'fixed' AND amount_off_minor IS NOT NULL AND percent_off IS NULL
+            AND currency IS NOT NULL AND min_amount_minor > amount_off_minor)
+    ),
+    CHECK (min_amount_minor = 0 OR currency IS NOT NULL),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+DROP TRIGGER IF EXISTS trg_promotions_updated_at ON promotions;
+CREATE TRIGGER trg_promotions_updated_at
+    BEFORE UPDATE ON promotions
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
+
+-- использование кода: пишется в транзакции создания заказа
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    order_id        UUID        PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id    UUID        NOT NULL REFERENCES promotions(id),
+    user_id         UUID        NOT NULL,
+    discount_minor  BIGINT      NOT NULL CHECK (discount_minor > 0),
+    currency        CHAR(3)     NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_promo_user ON promotion_redemptions(promotion_id, user_id);
+
+-- amount_minor остаётся суммой к оплате; исходная = amount_minor + discount_minor
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS discount_minor BIGINT NOT NULL DEFAULT 0 CHECK (discount_minor >= 0),
+    ADD COLUMN IF NOT EXISTS promo_code     TEXT;
+
+-- +goose Down
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS promo_code,
+    DROP COLUMN IF EXISTS discount_minor;
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TRIGGER IF EXISTS trg_promotions_updated_at ON promotions;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- expiry: поиск заказов, зависших в new
+CREATE INDEX IF NOT EXISTS idx_orders_new_created ON orders (created_at) WHERE status = 'new';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_orders_new_created;
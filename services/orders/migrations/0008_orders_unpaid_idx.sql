@@ -0,0 +1,8 @@
+-- +goose Up
+-- expiry: неоплаченные заказы — new и payment_failed (ждут повтор оплаты)
+CREATE INDEX IF NOT EXISTS idx_orders_unpaid_created ON orders (created_at) WHERE status IN ('new', 'payment_failed');
+DROP INDEX IF EXISTS idx_orders_new_created;
+
+-- +goose Down
+CREATE INDEX IF NOT EXISTS idx_orders_new_created ON orders (created_at) WHERE status = 'new';
+DROP INDEX IF EXISTS idx_orders_unpaid_created;
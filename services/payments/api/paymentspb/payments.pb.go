@@ -0,0 +1,593 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
+// source: services/payments/api/paymentspb/payments.proto
+
+package paymentspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PaymentStatus int32
+
+const (
+	PaymentStatus_PAYMENT_STATUS_UNSPECIFIED PaymentStatus = 0
+	PaymentStatus_PAYMENT_STATUS_CONFIRMED   PaymentStatus = 1
+	PaymentStatus_PAYMENT_STATUS_FAILED      PaymentStatus = 2
+)
+
+// Enum value maps for PaymentStatus.
+var (
+	PaymentStatus_name = map[int32]string{
+		0: "PAYMENT_STATUS_UNSPECIFIED",
+		1: "PAYMENT_STATUS_CONFIRMED",
+		2: "PAYMENT_STATUS_FAILED",
+	}
+	PaymentStatus_value = map[string]int32{
+		"PAYMENT_STATUS_UNSPECIFIED": 0,
+		"PAYMENT_STATUS_CONFIRMED":   1,
+		"PAYMENT_STATUS_FAILED":      2,
+	}
+)
+
+func (x PaymentStatus) Enum() *PaymentStatus {
+	p := new(PaymentStatus)
+	*p = x
+	return p
+}
+
+func (x PaymentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_payments_api_paymentspb_payments_proto_enumTypes[0].Descriptor()
+}
+
+func (PaymentStatus) Type() protoreflect.EnumType {
+	return &file_services_payments_api_paymentspb_payments_proto_enumTypes[0]
+}
+
+func (x PaymentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentStatus.Descriptor instead.
+func (PaymentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_services_payments_api_paymentspb_payments_proto_rawDescGZIP(), []int{0}
+}
+
+// Money — точная сумма: units_minor в минорных единицах currency (ISO-4217).
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UnitsMinor    int64                  `protobuf:"varint,1,opt,name=units_minor,json=unitsMinor,proto3" json:"units_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_services_payments_api_paymentspb_payments_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetUnitsMinor() int64 {
+	if x != nil {
+		return x.UnitsMinor
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Payment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status        PaymentStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=payments.v1.PaymentStatus" json:"status,omitempty"`
+	Provider      string                 `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`                        // причина отказа (например, insufficient_funds); пусто для CONFIRMED
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339
+	Attempt       int32                  `protobuf:"varint,9,opt,name=attempt,proto3" json:"attempt,omitempty"`                     // 1 — по order.created, дальше — RetryPayment
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_services_payments_api_paymentspb_payments_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Payment) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Payment) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Payment) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Payment) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Payment) GetStatus() PaymentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PaymentStatus_PAYMENT_STATUS_UNSPECIFIED
+}
+
+func (x *Payment) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Payment) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Payment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Payment) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+type GetPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentRequest) Reset() {
+	*x = GetPaymentRequest{}
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentRequest) ProtoMessage() {}
+
+func (x *GetPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_services_payments_api_paymentspb_payments_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetPaymentRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type ListPaymentsByOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsByOrderRequest) Reset() {
+	*x = ListPaymentsByOrderRequest{}
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsByOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsByOrderRequest) ProtoMessage() {}
+
+func (x *ListPaymentsByOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsByOrderRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentsByOrderRequest) Descriptor() ([]byte, []int) {
+	return file_services_payments_api_paymentspb_payments_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListPaymentsByOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type ListPaymentsByUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // по умолчанию 20, максимум 100
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token предыдущей страницы
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsByUserRequest) Reset() {
+	*x = ListPaymentsByUserRequest{}
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsByUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsByUserRequest) ProtoMessage() {}
+
+func (x *ListPaymentsByUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsByUserRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentsByUserRequest) Descriptor() ([]byte, []int) {
+	return file_services_payments_api_paymentspb_payments_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListPaymentsByUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListPaymentsByUserRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPaymentsByUserRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListPaymentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`                                  // новые первыми
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // пусто — последняя страница
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsResponse) Reset() {
+	*x = ListPaymentsResponse{}
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsResponse) ProtoMessage() {}
+
+func (x *ListPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_services_payments_api_paymentspb_payments_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPaymentsResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *ListPaymentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// RetryPaymentRequest — новая попытка для заказа, последняя попытка которого FAILED.
+// Статус заказа (PAYMENT_FAILED) проверяет вызывающий — payments его не знает.
+type RetryPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryPaymentRequest) Reset() {
+	*x = RetryPaymentRequest{}
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPaymentRequest) ProtoMessage() {}
+
+func (x *RetryPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_payments_api_paymentspb_payments_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPaymentRequest.ProtoReflect.Descriptor instead.
+func (*RetryPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_services_payments_api_paymentspb_payments_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RetryPaymentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+var File_services_payments_api_paymentspb_payments_proto protoreflect.FileDescriptor
+
+const file_services_payments_api_paymentspb_payments_proto_rawDesc = "" +
+	"\n" +
+	"/services/payments/api/paymentspb/payments.proto\x12\vpayments.v1\"D\n" +
+	"\x05Money\x12\x1f\n" +
+	"\vunits_minor\x18\x01 \x01(\x03R\n" +
+	"unitsMinor\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xa9\x02\n" +
+	"\aPayment\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12*\n" +
+	"\x06amount\x18\x04 \x01(\v2\x12.payments.v1.MoneyR\x06amount\x122\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1a.payments.v1.PaymentStatusR\x06status\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x18\n" +
+	"\aattempt\x18\t \x01(\x05R\aattempt\"2\n" +
+	"\x11GetPaymentRequest\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\"7\n" +
+	"\x1aListPaymentsByOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"p\n" +
+	"\x19ListPaymentsByUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"p\n" +
+	"\x14ListPaymentsResponse\x120\n" +
+	"\bpayments\x18\x01 \x03(\v2\x14.payments.v1.PaymentR\bpayments\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"0\n" +
+	"\x13RetryPaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId*h\n" +
+	"\rPaymentStatus\x12\x1e\n" +
+	"\x1aPAYMENT_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18PAYMENT_STATUS_CONFIRMED\x10\x01\x12\x19\n" +
+	"\x15PAYMENT_STATUS_FAILED\x10\x022\xda\x02\n" +
+	"\bPayments\x12B\n" +
+	"\n" +
+	"GetPayment\x12\x1e.payments.v1.GetPaymentRequest\x1a\x14.payments.v1.Payment\x12a\n" +
+	"\x13ListPaymentsByOrder\x12'.payments.v1.ListPaymentsByOrderRequest\x1a!.payments.v1.ListPaymentsResponse\x12_\n" +
+	"\x12ListPaymentsByUser\x12&.payments.v1.ListPaymentsByUserRequest\x1a!.payments.v1.ListPaymentsResponse\x12F\n" +
+	"\fRetryPayment\x12 .payments.v1.RetryPaymentRequest\x1a\x14.payments.v1.PaymentB/Z-./services/payments/api/paymentspb;paymentspbb\x06proto3"
+
+var (
+	file_services_payments_api_paymentspb_payments_proto_rawDescOnce sync.Once
+	file_services_payments_api_paymentspb_payments_proto_rawDescData []byte
+)
+
+func file_services_payments_api_paymentspb_payments_proto_rawDescGZIP() []byte {
+	file_services_payments_api_paymentspb_payments_proto_rawDescOnce.Do(func() {
+		file_services_payments_api_paymentspb_payments_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_services_payments_api_paymentspb_payments_proto_rawDesc), len(file_services_payments_api_paymentspb_payments_proto_rawDesc)))
+	})
+	return file_services_payments_api_paymentspb_payments_proto_rawDescData
+}
+
+var file_services_payments_api_paymentspb_payments_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_services_payments_api_paymentspb_payments_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_services_payments_api_paymentspb_payments_proto_goTypes = []any{
+	(PaymentStatus)(0),                 // 0: payments.v1.PaymentStatus
+	(*Money)(nil),                      // 1: payments.v1.Money
+	(*Payment)(nil),                    // 2: payments.v1.Payment
+	(*GetPaymentRequest)(nil),          // 3: payments.v1.GetPaymentRequest
+	(*ListPaymentsByOrderRequest)(nil), // 4: payments.v1.ListPaymentsByOrderRequest
+	(*ListPaymentsByUserRequest)(nil),  // 5: payments.v1.ListPaymentsByUserRequest
+	(*ListPaymentsResponse)(nil),       // 6: payments.v1.ListPaymentsResponse
+	(*RetryPaymentRequest)(nil),        // 7: payments.v1.RetryPaymentRequest
+}
+var file_services_payments_api_paymentspb_payments_proto_depIdxs = []int32{
+	1, // 0: payments.v1.Payment.amount:type_name -> payments.v1.Money
+	0, // 1: payments.v1.Payment.status:type_name -> payments.v1.PaymentStatus
+	2, // 2: payments.v1.ListPaymentsResponse.payments:type_name -> payments.v1.Payment
+	3, // 3: payments.v1.Payments.GetPayment:input_type -> payments.v1.GetPaymentRequest
+	4, // 4: payments.v1.Payments.ListPaymentsByOrder:input_type -> payments.v1.ListPaymentsByOrderRequest
+	5, // 5: payments.v1.Payments.ListPaymentsByUser:input_type -> payments.v1.ListPaymentsByUserRequest
+	7, // 6: payments.v1.Payments.RetryPayment:input_type -> payments.v1.RetryPaymentRequest
+	2, // 7: payments.v1.Payments.GetPayment:output_type -> payments.v1.Payment
+	6, // 8: payments.v1.Payments.ListPaymentsByOrder:output_type -> payments.v1.ListPaymentsResponse
+	6, // 9: payments.v1.Payments.ListPaymentsByUser:output_type -> payments.v1.ListPaymentsResponse
+	2, // 10: payments.v1.Payments.RetryPayment:output_type -> payments.v1.Payment
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_services_payments_api_paymentspb_payments_proto_init() }
+func file_services_payments_api_paymentspb_payments_proto_init() {
+	if File_services_payments_api_paymentspb_payments_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_services_payments_api_paymentspb_payments_proto_rawDesc), len(file_services_payments_api_paymentspb_payments_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_services_payments_api_paymentspb_payments_proto_goTypes,
+		DependencyIndexes: file_services_payments_api_paymentspb_payments_proto_depIdxs,
+		EnumInfos:         file_services_payments_api_paymentspb_payments_proto_enumTypes,
+		MessageInfos:      file_services_payments_api_paymentspb_payments_proto_msgTypes,
+	}.Build()
+	File_services_payments_api_paymentspb_payments_proto = out.File
+	file_services_payments_api_paymentspb_payments_proto_goTypes = nil
+	file_services_payments_api_paymentspb_payments_proto_depIdxs = nil
+}
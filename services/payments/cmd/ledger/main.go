@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"goshop/pkg/logger"
+	"goshop/pkg/postgres"
+	"goshop/services/payments/config"
+	"goshop/services/payments/internal/ledger"
+)
+
+// ledger — обслуживание журнала payments:
+//
+//	reconcile — сверка журнала с payments (JSON-отчёт в stdout, код 1 при расхождениях)
+//	backfill  — записи 'charge' для подтверждённых платежей без них
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: ledger <reconcile|backfill>\n")
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg := config.New()
+	if err := cfg.Validate(); err != nil {
+		fail("config", err)
+	}
+	log := logger.NewLogger(cfg.Logger)
+
+	pool, err := postgres.NewPool(ctx, cfg.Postgres)
+	if err != nil {
+		fail("postgres", err)
+	}
+	defer pool.Close()
+
+	led := ledger.New(log, pool, ledger.Config{FeeBps: cfg.Ledger.FeeBps})
+
+	switch cmd := flag.Arg(0); cmd {
+	case "reconcile":
+		rep, err := led.Reconcile(ctx)
+		if err != nil {
+			fail("reconcile", err)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(rep)
+		if !rep.OK() {
+			pool.Close()
+			os.Exit(1)
+		}
+	case "backfill":
+		n, err := led.Backfill(ctx)
+		if err != nil {
+			fail("backfill", err)
+		}
+		fmt.Printf("backfilled %d payments\n", n)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func fail(what string, err error) {
+	fmt.Fprintf(os.Stderr, "ledger: %s: %v\n", what, err)
+	os.Exit(1)
+}
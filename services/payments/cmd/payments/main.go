@@ -10,12 +10,20 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/twmb/franz-go/pkg/kgo"
 
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
 	"goshop/pkg/logger"
 	"goshop/pkg/postgres"
+	"goshop/pkg/revocation"
 	"goshop/services/payments/config"
+	"goshop/services/payments/internal/adapters/repo/paymentpg"
+	"goshop/services/payments/internal/billing"
 	"goshop/services/payments/internal/consumer"
+	grpcsvr "goshop/services/payments/internal/grpc"
+	"goshop/services/payments/internal/ledger"
 )
 
 func main() {
@@ -38,6 +46,7 @@ func main() {
 	log.Info("payments.consumer: starting",
 		slog.String("kafka.topic", cfg.Consumer.Topic),
 		slog.String("group", cfg.Consumer.Group),
+		slog.String("grpc.addr", cfg.GRPC.Addr),
 	)
 
 	// Postgres
@@ -80,7 +89,12 @@ func main() {
 	defer cl.Close()
 
 	// Processor & Runner
-	proc := consumer.NewProcessor(log, pool, cfg.Outbox.Topic)
+	led := ledger.New(log, pool, ledger.Config{FeeBps: cfg.Ledger.FeeBps})
+	bill := billing.New(log, pool, led, billing.Config{
+		OutboxTopic: cfg.Outbox.Topic,
+		MaxAttempts: cfg.Retry.MaxAttempts,
+	})
+	proc := consumer.NewProcessor(log, pool, bill)
 	rcfg := consumer.Config{
 		Group:            cfg.Consumer.Group,
 		Topic:            cfg.Consumer.Topic,
@@ -89,6 +103,69 @@ func main() {
 	}
 	r := consumer.New(log, pool, cl, rcfg, proc)
 
+	// JWT: публичные ключи из JWKS users; secret — только для HS256-токенов, выданных до перехода
+	var jwtm jwtauth.TokenVerifier
+	if cfg.JWT.JWKSURL != "" {
+		v := jwtauth.NewVerifier(jwtauth.VerifierConfig{
+			JWKSURL: cfg.JWT.JWKSURL,
+			Issuer:  cfg.JWT.Issuer,
+			Secret:  cfg.JWT.Secret,
+			Refresh: cfg.JWT.JWKSRefresh,
+		})
+		// users может подняться позже — ключи догрузятся на первом запросе
+		if err := v.Refresh(ctx); err != nil {
+			log.Warn("jwt: jwks prefetch failed", slog.String("url", cfg.JWT.JWKSURL), slog.Any("err", err))
+		}
+		jwtm = v
+		log.Info("jwt: jwks verifier initialized",
+			slog.String("issuer", cfg.JWT.Issuer),
+			slog.String("jwks_url", cfg.JWT.JWKSURL),
+			slog.Bool("hs256_fallback", cfg.JWT.Secret != ""),
+		)
+	} else {
+		jwtm = jwtauth.New(jwtauth.Config{
+			Secret:     cfg.JWT.Secret,
+			Issuer:     cfg.JWT.Issuer,
+			AccessTTL:  cfg.JWT.AccessTTL,
+			RefreshTTL: cfg.JWT.RefreshTTL,
+		})
+		log.Info("jwt: manager initialized", slog.String("issuer", cfg.JWT.Issuer))
+	}
+
+	// Redis — только список отозванных сессий; недоступен на старте — проверка fail-open, как в grpcauth
+	var rev grpcauth.RevocationChecker
+	if cfg.Redis.Addr != "" {
+		rds := redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		if err := rds.Ping(ctx).Err(); err != nil {
+			log.Warn("redis: ping failed", slog.String("addr", cfg.Redis.Addr), slog.Any("err", err))
+		}
+		defer func() { _ = rds.Close() }()
+		rev = revocation.New(rds)
+	} else {
+		log.Warn("redis: not configured, revoked sessions are not checked")
+	}
+
+	// gRPC
+	go func() {
+		if err := grpcsvr.Start(ctx, grpcsvr.Options{
+			Addr:       cfg.GRPC.Addr,
+			Logger:     log,
+			Repo:       paymentpg.NewRepo(pool),
+			Billing:    bill,
+			Verifier:   jwtm,
+			Revoked:    rev,
+			Audience:   "api", // как у orders: refresh-токен не подходит
+			AdminToken: cfg.GRPC.AdminToken,
+		}); err != nil && !errors.Is(err, context.Canceled) {
+			log.Error("payments.grpc: stopped with error", slog.Any("err", err))
+			stop()
+		}
+	}()
+
 	// Run (blocking)
 	if err := r.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
 		log.Error("payments.consumer: stopped with error", slog.Any("err", err))
@@ -22,6 +22,29 @@ type Payments struct {
 	Outbox struct {
 		Topic string `mapstructure:"topic"`
 	} `mapstructure:"outbox"`
+	GRPC   GRPC   `mapstructure:"grpc"`
+	Retry  Retry  `mapstructure:"retry"`
+	Ledger Ledger `mapstructure:"ledger"`
+
+	// JWT — проверка access-токенов gRPC API (jwks_url или secret); Redis — список отозванных сессий, пусто — без него
+	JWT   cfg.JWT   `mapstructure:"jwt"`
+	Redis cfg.Redis `mapstructure:"redis"`
+}
+
+// Ledger — журнал двойной записи; fee_bps — комиссия провайдера в базисных пунктах (250 = 2.5%).
+type Ledger struct {
+	FeeBps int `mapstructure:"fee_bps"`
+}
+
+// Retry — повторные попытки оплаты (RetryPayment); max_attempts — всего, включая первую.
+type Retry struct {
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+// GRPC — API платежей (GetPayment, ListPaymentsByOrder/ByUser, RetryPayment).
+type GRPC struct {
+	Addr       string `mapstructure:"addr"`
+	AdminToken string `mapstructure:"admin_token"` // metadata x-admin-token для скриптов без JWT; пусто — только токены
 }
 
 func (p *Payments) Validate() error {
@@ -31,6 +54,18 @@ func (p *Payments) Validate() error {
 	if err := p.Postgres.Validate(); err != nil {
 		return fmt.Errorf("postgres: %w", err)
 	}
+	if p.JWT.Secret == "" && p.JWT.JWKSURL == "" {
+		return errors.New("jwt.jwks_url or jwt.secret is required (for token verification)")
+	}
+	if p.GRPC.Addr == "" {
+		p.GRPC.Addr = ":7073"
+	}
+	if p.Retry.MaxAttempts <= 0 {
+		p.Retry.MaxAttempts = 3
+	}
+	if p.Ledger.FeeBps < 0 || p.Ledger.FeeBps >= 10000 {
+		return errors.New("ledger.fee_bps must be 0..9999")
+	}
 	return nil
 }
 
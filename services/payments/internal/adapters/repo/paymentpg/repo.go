@@ -0,0 +1,168 @@
+package paymentpg
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrNotFound  = errors.New("payment not found")
+	ErrBadCursor = errors.New("invalid page token")
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Repository — чтение платежей (пишет только consumer.Processor).
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepo(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+type Payment struct {
+	ID          uuid.UUID
+	OrderID     uuid.UUID
+	UserID      uuid.UUID
+	AmountCents int64 // минорные единицы Currency
+	Currency    string
+	Status      string // confirmed|failed
+	Provider    string
+	Reason      *string
+	Attempt     int // 1 — по order.created, дальше — RetryPayment
+	CreatedAt   time.Time
+}
+
+type Page struct {
+	Payments      []Payment
+	NextPageToken string // пусто — страниц больше нет
+}
+
+// cursor — keyset по (created_at, id); U — пользователь, чтобы токен не подходил к чужому списку.
+type cursor struct {
+	T time.Time `json:"t"`
+	I uuid.UUID `json:"i"`
+	U uuid.UUID `json:"u"`
+}
+
+const paymentCols = `id, order_id, user_id, amount_cents, currency, status, provider, reason, attempt, created_at`
+
+func (r *Repository) Get(ctx context.Context, id uuid.UUID) (*Payment, error) {
+	p, err := scanPayment(r.db.QueryRow(ctx, `SELECT `+paymentCols+` FROM payments WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select payment: %w", err)
+	}
+	return p, nil
+}
+
+// ListByOrder — все попытки оплаты заказа, последняя первой.
+func (r *Repository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]Payment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+paymentCols+`
+		FROM payments
+		WHERE order_id = $1
+		ORDER BY attempt DESC;
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("select payments: %w", err)
+	}
+	return collect(rows)
+}
+
+// ListByUser — платежи пользователя, новые первыми, keyset-пагинация.
+func (r *Repository) ListByUser(ctx context.Context, userID uuid.UUID, limit int, pageToken string) (*Page, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	q := `SELECT ` + paymentCols + ` FROM payments WHERE user_id = $1`
+	args := []any{userID, limit + 1}
+	if pageToken != "" {
+		c, err := decodeCursor(pageToken)
+		if err != nil || c.U != userID {
+			return nil, ErrBadCursor
+		}
+		q += ` AND (created_at, id) < ($3, $4)`
+		args = append(args, c.T, c.I)
+	}
+	q += ` ORDER BY created_at DESC, id DESC LIMIT $2;`
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select payments: %w", err)
+	}
+	out, err := collect(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Payments: out}
+	if len(out) > limit {
+		page.Payments = out[:limit]
+		last := page.Payments[limit-1]
+		page.NextPageToken = encodeCursor(cursor{T: last.CreatedAt, I: last.ID, U: userID})
+	}
+	return page, nil
+}
+
+func collect(rows pgx.Rows) ([]Payment, error) {
+	defer rows.Close()
+	var out []Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan payment: %w", err)
+		}
+		out = append(out, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select payments: %w", err)
+	}
+	return out, nil
+}
+
+func scanPayment(row pgx.Row) (*Payment, error) {
+	var p Payment
+	if err := row.Scan(&p.ID, &p.OrderID, &p.UserID, &p.AmountCents, &p.Currency, &p.Status, &p.Provider, &p.Reason, &p.Attempt, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, err
+	}
+	if c.T.IsZero() || c.I == uuid.Nil {
+		return c, ErrBadCursor
+	}
+	return c, nil
+}
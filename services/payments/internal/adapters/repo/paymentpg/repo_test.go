@@ -0,0 +1,58 @@
+package paymentpg
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	in := cursor{T: time.Date(2025, 9, 22, 13, 0, 0, 123456789, time.UTC), I: uuid.New(), U: uuid.New()}
+	out, err := decodeCursor(encodeCursor(in))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !out.T.Equal(in.T) || out.I != in.I || out.U != in.U {
+		t.Fatalf("round trip: got %+v, want %+v", out, in)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	b64 := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "!!!"},
+		{"not json", b64("garbage")},
+		{"no time", b64(`{"i":"` + uuid.NewString() + `","u":"` + uuid.NewString() + `"}`)},
+		{"no id", b64(`{"t":"2025-09-22T13:00:00Z","u":"` + uuid.NewString() + `"}`)},
+		{"bad id", b64(`{"t":"2025-09-22T13:00:00Z","i":"nope"}`)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := decodeCursor(tc.token); err == nil {
+				t.Fatalf("decodeCursor(%q) = nil error", tc.token)
+			}
+		})
+	}
+}
+
+// Чужой или битый токен отклоняется до запроса в БД.
+func TestListByUser_BadCursor(t *testing.T) {
+	uid := uuid.New()
+	foreign := encodeCursor(cursor{T: time.Now(), I: uuid.New(), U: uuid.New()})
+	r := &Repository{}
+	for name, token := range map[string]string{
+		"other user": foreign,
+		"garbage":    "garbage",
+	} {
+		if _, err := r.ListByUser(context.Background(), uid, 10, token); !errors.Is(err, ErrBadCursor) {
+			t.Fatalf("%s: err = %v, want ErrBadCursor", name, err)
+		}
+	}
+}
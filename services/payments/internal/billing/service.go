@@ -0,0 +1,356 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"goshop/services/payments/internal/adapters/repo/paymentpg"
+	"goshop/services/payments/internal/ledger"
+)
+
+var (
+	ErrNotFound = errors.New("no payments for order")
+	// ErrNotRetryable — последняя попытка не провалилась (заказ уже оплачен) или отказ окончательный (card_declined)
+	ErrNotRetryable      = errors.New("last payment attempt cannot be retried")
+	ErrAttemptsExhausted = errors.New("payment attempts exhausted")
+	// ErrConcurrentRetry — ту же попытку уже записал параллельный RetryPayment
+	ErrConcurrentRetry = errors.New("payment retry is already in progress")
+	// ErrOrderClosed — orders уже отменил заказ (order.expired), платить по нему нельзя
+	ErrOrderClosed     = errors.New("order is closed")
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrNotRefundable — возвращать нечего: платёж не подтверждён
+	ErrNotRefundable = errors.New("payment is not confirmed")
+)
+
+type Config struct {
+	OutboxTopic string
+	MaxAttempts int // всего попыток на заказ, включая первую
+}
+
+// Service — попытки списания: первая по order.created, следующие — RetryPayment.
+// Каждая попытка — строка payments с номером attempt, проводки журнала (для подтверждённой)
+// и событие в payments_outbox в одной транзакции; так же — возврат (Refund).
+type Service struct {
+	log *slog.Logger
+	db  *pgxpool.Pool
+	led *ledger.Ledger
+	cfg Config
+}
+
+func New(log *slog.Logger, db *pgxpool.Pool, led *ledger.Ledger, cfg Config) *Service {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.OutboxTopic == "" {
+		cfg.OutboxTopic = "payments.events"
+	}
+	return &Service{log: log, db: db, led: led, cfg: cfg}
+}
+
+func (s *Service) MaxAttempts() int { return s.cfg.MaxAttempts }
+
+// Charge — что списываем; Attempt начинается с 1.
+type Charge struct {
+	OrderID  uuid.UUID
+	UserID   uuid.UUID
+	Amount   int64
+	Currency string
+	Attempt  int
+}
+
+// исходящее событие; v2 — attempt/max_attempts/retryable
+type paymentEvent struct {
+	Event       string    `json:"event"` // payment.confirmed | payment.failed | payment.refunded
+	Version     int       `json:"version"`
+	PaymentID   uuid.UUID `json:"payment_id"`
+	OrderID     uuid.UUID `json:"order_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Amount      int64     `json:"amount_cents"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"` // confirmed | failed | refunded
+	ProcessedAt time.Time `json:"processed_at"`
+	Reason      *string   `json:"reason,omitempty"`
+	Attempt     int       `json:"attempt"`
+	MaxAttempts int       `json:"max_attempts"`
+	Retryable   bool      `json:"retryable"` // failed по повторяемой причине и попытки ещё есть — orders ждёт RetryPayment
+}
+
+// Decide — “логика эквайринга” mockpay для демонстрации: суммы, кратные 25, отклоняются всегда,
+// кратные 5 — только на первой попытке (повтор проходит, будто клиент пополнил счёт).
+func Decide(amountCents int64, attempt int) (status string, reason *string) {
+	switch {
+	case amountCents%25 == 0:
+		r := "card_declined"
+		return "failed", &r
+	case amountCents%5 == 0 && attempt <= 1:
+		r := "insufficient_funds"
+		return "failed", &r
+	}
+	return "confirmed", nil
+}
+
+// retryableReasons — отказы, после которых повтор может пройти (клиент пополнит счёт);
+// остальные (card_declined) повторять бессмысленно.
+var retryableReasons = map[string]bool{
+	"insufficient_funds": true,
+}
+
+// retryable — можно ли повторить провалившуюся попытку attempt из maxAttempts с этой причиной.
+func retryable(reason *string, attempt, maxAttempts int) bool {
+	return reason != nil && retryableReasons[*reason] && attempt < maxAttempts
+}
+
+// nextAttempt — номер следующей попытки после последней (status, reason, attempt);
+// closed — заказ отменён в orders.
+func nextAttempt(status string, reason *string, attempt, maxAttempts int, closed bool) (int, error) {
+	if closed {
+		return 0, ErrOrderClosed
+	}
+	if status != "failed" || reason == nil || !retryableReasons[*reason] {
+		return 0, ErrNotRetryable
+	}
+	if attempt >= maxAttempts {
+		return 0, ErrAttemptsExhausted
+	}
+	return attempt + 1, nil
+}
+
+// ChargeTx — попытка c.Attempt в транзакции tx; (nil, nil) — эта попытка уже записана.
+func (s *Service) ChargeTx(ctx context.Context, tx pgx.Tx, c Charge) (*paymentpg.Payment, error) {
+	status, reason := Decide(c.Amount, c.Attempt)
+
+	p := paymentpg.Payment{
+		OrderID:     c.OrderID,
+		UserID:      c.UserID,
+		AmountCents: c.Amount,
+		Currency:    c.Currency,
+		Status:      status,
+		Provider:    "mockpay",
+		Reason:      reason,
+		Attempt:     c.Attempt,
+	}
+
+	// 1) запись в payments
+	err := tx.QueryRow(ctx, `
+		INSERT INTO payments (order_id, user_id, amount_cents, currency, status, provider, reason, attempt)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (order_id, attempt) DO NOTHING
+		RETURNING id, created_at;
+	`, c.OrderID, c.UserID, c.Amount, c.Currency, status, p.Provider, reason, c.Attempt).Scan(&p.ID, &p.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("insert payments: %w", err)
+	}
+
+	// 2) деньги двигаются только при подтверждении
+	if status == "confirmed" {
+		if err := s.led.PostChargeTx(ctx, tx, p.ID, c.Amount, c.Currency); err != nil {
+			return nil, fmt.Errorf("ledger: %w", err)
+		}
+	}
+
+	// 3) публикация результата в payments_outbox (подберёт outboxer)
+	ev := paymentEvent{
+		Event:       "payment." + status,
+		Version:     2,
+		PaymentID:   p.ID,
+		OrderID:     c.OrderID,
+		UserID:      c.UserID,
+		Amount:      c.Amount,
+		Currency:    c.Currency,
+		Status:      status,
+		ProcessedAt: time.Now().UTC(),
+		Reason:      reason,
+		Attempt:     c.Attempt,
+		MaxAttempts: s.cfg.MaxAttempts,
+		Retryable:   status == "failed" && retryable(reason, c.Attempt, s.cfg.MaxAttempts),
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payment event: %w", err)
+	}
+
+	// key — по order_id, чтобы downstream (orders) получал попытки одного заказа по порядку
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO payments_outbox (agg_type, agg_id, topic, key, headers, payload)
+		VALUES ('payment', $1, $2, $3, '[]'::jsonb, $4::jsonb);
+	`, p.ID, s.cfg.OutboxTopic, c.OrderID[:], payload); err != nil {
+		return nil, fmt.Errorf("insert payments_outbox: %w", err)
+	}
+	return &p, nil
+}
+
+// Retry — следующая попытка для заказа, последняя попытка которого провалилась.
+// userID — владелец заказа (чужой — ErrNotFound); uuid.Nil — без проверки (admin).
+// Заказ, отменённый в orders (CloseOrder), не повторяется — ErrOrderClosed, кем бы ни был вызывающий.
+func (s *Service) Retry(ctx context.Context, orderID, userID uuid.UUID) (*paymentpg.Payment, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		c      Charge
+		last   string
+		reason *string
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT order_id, user_id, amount_cents, currency, status, reason, attempt
+		FROM payments
+		WHERE order_id = $1
+		ORDER BY attempt DESC
+		LIMIT 1
+		FOR UPDATE;
+	`, orderID).Scan(&c.OrderID, &c.UserID, &c.Amount, &c.Currency, &last, &reason, &c.Attempt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select last payment: %w", err)
+	}
+	if userID != uuid.Nil && c.UserID != userID {
+		return nil, ErrNotFound
+	}
+	var closed bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM payments_closed_orders WHERE order_id = $1);
+	`, orderID).Scan(&closed); err != nil {
+		return nil, fmt.Errorf("select closed order: %w", err)
+	}
+	if c.Attempt, err = nextAttempt(last, reason, c.Attempt, s.cfg.MaxAttempts, closed); err != nil {
+		return nil, err
+	}
+
+	p, err := s.ChargeTx(ctx, tx, c)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrConcurrentRetry
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	s.log.Info("payments.billing: retried",
+		slog.String("order_id", orderID.String()),
+		slog.String("payment_id", p.ID.String()),
+		slog.Int("attempt", p.Attempt),
+		slog.String("status", p.Status),
+	)
+	return p, nil
+}
+
+// CloseOrder — заказ отменён в orders (order.expired): дальше RetryPayment по нему отклоняется.
+// Попытки заказа блокируются — параллельный Retry дождётся и увидит отмену. false — уже отмечен.
+func (s *Service) CloseOrder(ctx context.Context, orderID uuid.UUID, reason string) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM payments WHERE order_id = $1 FOR UPDATE`, orderID); err != nil {
+		return false, fmt.Errorf("lock payments: %w", err)
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO payments_closed_orders (order_id, reason) VALUES ($1, $2)
+		ON CONFLICT (order_id) DO NOTHING;
+	`, orderID, reason)
+	if err != nil {
+		return false, fmt.Errorf("insert payments_closed_orders: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Refund — полный возврат подтверждённого платежа: запись 'refund' журнала и payment.refunded
+// в payments_outbox в одной транзакции (mockpay возвращает всегда). false — возврат уже был.
+func (s *Service) Refund(ctx context.Context, paymentID uuid.UUID, reason string) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		c      Charge
+		status string
+	)
+	// FOR UPDATE — параллельные возвраты одного платежа идут по очереди
+	err = tx.QueryRow(ctx, `
+		SELECT order_id, user_id, amount_cents, currency, status, attempt
+		FROM payments
+		WHERE id = $1
+		FOR UPDATE;
+	`, paymentID).Scan(&c.OrderID, &c.UserID, &c.Amount, &c.Currency, &status, &c.Attempt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrPaymentNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("select payment: %w", err)
+	}
+	if status != "confirmed" {
+		return false, ErrNotRefundable
+	}
+	done, err := s.led.RefundedTx(ctx, tx, paymentID)
+	if err != nil {
+		return false, fmt.Errorf("ledger: %w", err)
+	}
+	if done {
+		return false, nil
+	}
+
+	if err := s.led.PostRefundTx(ctx, tx, paymentID, c.Amount, c.Currency, "mockpay refund: "+reason); err != nil {
+		return false, fmt.Errorf("ledger: %w", err)
+	}
+
+	ev := paymentEvent{
+		Event:       "payment.refunded",
+		Version:     2,
+		PaymentID:   paymentID,
+		OrderID:     c.OrderID,
+		UserID:      c.UserID,
+		Amount:      c.Amount,
+		Currency:    c.Currency,
+		Status:      "refunded",
+		ProcessedAt: time.Now().UTC(),
+		Reason:      &reason,
+		Attempt:     c.Attempt,
+		MaxAttempts: s.cfg.MaxAttempts,
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return false, fmt.Errorf("marshal payment event: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO payments_outbox (agg_type, agg_id, topic, key, headers, payload)
+		VALUES ('payment', $1, $2, $3, '[]'::jsonb, $4::jsonb);
+	`, paymentID, s.cfg.OutboxTopic, c.OrderID[:], payload); err != nil {
+		return false, fmt.Errorf("insert payments_outbox: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+
+	s.log.Info("payments.billing: refunded",
+		slog.String("order_id", c.OrderID.String()),
+		slog.String("payment_id", paymentID.String()),
+		slog.Int64("amount_cents", c.Amount),
+		slog.String("currency", c.Currency),
+	)
+	return true, nil
+}
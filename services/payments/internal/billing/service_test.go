@@ -0,0 +1,85 @@
+package billing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecide(t *testing.T) {
+	cases := []struct {
+		amount  int64
+		attempt int
+		status  string
+		reason  string
+	}{
+		{19901, 1, "confirmed", ""},
+		{1505, 1, "failed", "insufficient_funds"},
+		{1505, 2, "confirmed", ""},
+		{2500, 1, "failed", "card_declined"},
+		{2500, 3, "failed", "card_declined"},
+	}
+	for _, tc := range cases {
+		st, r := Decide(tc.amount, tc.attempt)
+		got := ""
+		if r != nil {
+			got = *r
+		}
+		if st != tc.status || got != tc.reason {
+			t.Errorf("Decide(%d, %d) = %s/%q, want %s/%q", tc.amount, tc.attempt, st, got, tc.status, tc.reason)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	declined, funds, other := "card_declined", "insufficient_funds", "do_not_honor"
+	cases := []struct {
+		reason  *string
+		attempt int
+		want    bool
+	}{
+		{&funds, 1, true},
+		{&funds, 2, true},
+		{&funds, 3, false}, // последняя из трёх
+		{&declined, 1, false},
+		{&other, 1, false},
+		{nil, 1, false},
+	}
+	for _, tc := range cases {
+		if got := retryable(tc.reason, tc.attempt, 3); got != tc.want {
+			t.Errorf("retryable(%v, %d, 3) = %v, want %v", deref(tc.reason), tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestNextAttempt(t *testing.T) {
+	declined, funds := "card_declined", "insufficient_funds"
+	cases := []struct {
+		name    string
+		status  string
+		reason  *string
+		attempt int
+		want    int
+		closed  bool
+		wantErr error
+	}{
+		{"retry after insufficient funds", "failed", &funds, 1, 2, false, nil},
+		{"second retry", "failed", &funds, 2, 3, false, nil},
+		{"exhausted", "failed", &funds, 3, 0, false, ErrAttemptsExhausted},
+		{"card declined", "failed", &declined, 1, 0, false, ErrNotRetryable},
+		{"already confirmed", "confirmed", nil, 2, 0, false, ErrNotRetryable},
+		{"order cancelled by expiry", "failed", &funds, 1, 0, true, ErrOrderClosed},
+	}
+	for _, tc := range cases {
+		got, err := nextAttempt(tc.status, tc.reason, tc.attempt, 3, tc.closed)
+		if got != tc.want || !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: nextAttempt = %d, %v; want %d, %v", tc.name, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
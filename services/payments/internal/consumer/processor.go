@@ -3,6 +3,7 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -11,16 +12,18 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/twmb/franz-go/pkg/kgo"
+
+	"goshop/services/payments/internal/billing"
 )
 
 type Processor struct {
-	log         *slog.Logger
-	db          *pgxpool.Pool
-	outboxTopic string
+	log     *slog.Logger
+	db      *pgxpool.Pool
+	billing *billing.Service
 }
 
-func NewProcessor(log *slog.Logger, db *pgxpool.Pool, outboxTopic string) *Processor {
-	return &Processor{log: log, db: db, outboxTopic: outboxTopic}
+func NewProcessor(log *slog.Logger, db *pgxpool.Pool, bill *billing.Service) *Processor {
+	return &Processor{log: log, db: db, billing: bill}
 }
 
 // входящее событие из orders (как формируем в orders repo)
@@ -35,18 +38,23 @@ type orderCreated struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// исходящее событие из payments
-type paymentEvent struct {
-	Event       string    `json:"event"` // payment.confirmed | payment.failed
-	Version     int       `json:"version"`
-	PaymentID   uuid.UUID `json:"payment_id"`
-	OrderID     uuid.UUID `json:"order_id"`
-	UserID      uuid.UUID `json:"user_id"`
-	Amount      int64     `json:"amount_cents"`
-	Currency    string    `json:"currency"`
-	Status      string    `json:"status"` // confirmed | failed
-	ProcessedAt time.Time `json:"processed_at"`
-	Reason      *string   `json:"reason,omitempty"`
+// компенсация из orders: деньги списаны, а заказ уже отменён
+type refundRequired struct {
+	Event     string    `json:"event"`
+	Version   int       `json:"version"`
+	OrderID   uuid.UUID `json:"order_id"`
+	PaymentID uuid.UUID `json:"payment_id"`
+	Amount    int64     `json:"amount_cents"`
+	Currency  string    `json:"currency"`
+	Reason    string    `json:"reason"`
+}
+
+// заказ отменён в orders планировщиком автоотмены
+type orderExpired struct {
+	Event   string    `json:"event"`
+	Version int       `json:"version"`
+	OrderID uuid.UUID `json:"order_id"`
+	Reason  string    `json:"reason"`
 }
 
 func (p *Processor) ProcessRecord(ctx context.Context, rec *kgo.Record) error {
@@ -76,80 +84,124 @@ func (p *Processor) ProcessRecord(ctx context.Context, rec *kgo.Record) error {
 			return nil
 		}
 		return p.handleOrderCreated(ctx, oc)
+	case "order.refund_required":
+		var rr refundRequired
+		if err := json.Unmarshal(rec.Value, &rr); err != nil {
+			p.log.Warn("payments.processor: bad order.refund_required payload",
+				slog.Any("err", err),
+				slog.String("topic", rec.Topic),
+				slog.Int64("partition", int64(rec.Partition)),
+				slog.Int64("offset", rec.Offset),
+			)
+			return nil
+		}
+		return p.handleRefundRequired(ctx, rr)
+	case "order.expired":
+		var oe orderExpired
+		if err := json.Unmarshal(rec.Value, &oe); err != nil {
+			p.log.Warn("payments.processor: bad order.expired payload",
+				slog.Any("err", err),
+				slog.String("topic", rec.Topic),
+				slog.Int64("partition", int64(rec.Partition)),
+				slog.Int64("offset", rec.Offset),
+			)
+			return nil
+		}
+		return p.handleOrderExpired(ctx, oe)
 	default:
 		return nil
 	}
 }
 
-// простая “логика эквайринга”: каждое 5-е число фейлим — для демонстрации
-func (p *Processor) decideStatus(amountCents int64) (status string, reason *string) {
-	if amountCents%5 == 0 {
-		r := "insufficient_funds"
-		return "failed", &r
-	}
-	return "confirmed", nil
-}
-
-// одна транзакция: INSERT payments + INSERT payments_outbox
+// первая попытка: payments + payments_outbox в одной транзакции (billing.ChargeTx)
 func (p *Processor) handleOrderCreated(ctx context.Context, oc orderCreated) error {
-	status, reason := p.decideStatus(oc.Amount)
-
 	tx, err := p.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	var paymentID uuid.UUID
-	now := time.Now().UTC()
-
-	// 1) запись в payments
-	err = tx.QueryRow(ctx, `
-		INSERT INTO payments (order_id, user_id, amount_cents, currency, status, provider, reason)
-		VALUES ($1, $2, $3, $4, $5, 'mockpay', $6)
-		RETURNING id;
-	`, oc.OrderID, oc.UserID, oc.Amount, oc.Currency, status, reason).Scan(&paymentID)
+	pay, err := p.billing.ChargeTx(ctx, tx, billing.Charge{
+		OrderID:  oc.OrderID,
+		UserID:   oc.UserID,
+		Amount:   oc.Amount,
+		Currency: oc.Currency,
+		Attempt:  1,
+	})
 	if err != nil {
-		return fmt.Errorf("insert payments: %w", err)
+		return err
 	}
-
-	// 2) публикация результата в payments_outbox (подберёт outboxer)
-	ev := paymentEvent{
-		Event:       "payment." + status,
-		Version:     1,
-		PaymentID:   paymentID,
-		OrderID:     oc.OrderID,
-		UserID:      oc.UserID,
-		Amount:      oc.Amount,
-		Currency:    oc.Currency,
-		Status:      status,
-		ProcessedAt: now,
-		Reason:      reason,
+	if pay == nil {
+		// повторная доставка order.created — попытка уже записана
+		p.log.Info("payments.processor: duplicate order.created, skipped",
+			slog.String("order_id", oc.OrderID.String()),
+		)
+		return nil
 	}
-	payload, err := json.Marshal(ev)
-	if err != nil {
-		return fmt.Errorf("marshal payment event: %w", err)
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
 	}
 
-	// key — по order_id, чтобы downstream (orders) получал по тому же ключу
-	key := oc.OrderID[:]
+	p.log.Info("payments.processor: processed",
+		slog.String("order_id", oc.OrderID.String()),
+		slog.String("payment_id", pay.ID.String()),
+		slog.String("status", pay.Status),
+	)
+	return nil
+}
 
-	_, err = tx.Exec(ctx, `
-		INSERT INTO payments_outbox (agg_type, agg_id, topic, key, headers, payload)
-		VALUES ('payment', $1, $2, $3, '[]'::jsonb, $4::jsonb);
-	`, paymentID, p.outboxTopic, key, payload)
+// отменённый заказ больше не оплачивается: RetryPayment по нему — FailedPrecondition
+func (p *Processor) handleOrderExpired(ctx context.Context, oe orderExpired) error {
+	reason := oe.Reason
+	if reason == "" {
+		reason = "expired"
+	}
+	closed, err := p.billing.CloseOrder(ctx, oe.OrderID, reason)
 	if err != nil {
-		return fmt.Errorf("insert payments_outbox: %w", err)
+		return err
+	}
+	if closed {
+		p.log.Info("payments.processor: order closed",
+			slog.String("order_id", oe.OrderID.String()),
+			slog.String("reason", reason),
+		)
 	}
+	return nil
+}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit: %w", err)
+// возврат: запись 'refund' журнала + payment.refunded в одной транзакции (billing.Refund);
+// повторная доставка — no-op, неподтверждённый платёж возвращать нечего
+func (p *Processor) handleRefundRequired(ctx context.Context, rr refundRequired) error {
+	reason := rr.Reason
+	if reason == "" {
+		reason = "order_cancelled"
 	}
 
-	p.log.Info("payments.processor: processed",
-		slog.String("order_id", oc.OrderID.String()),
-		slog.String("payment_id", paymentID.String()),
-		slog.String("status", status),
+	done, err := p.billing.Refund(ctx, rr.PaymentID, reason)
+	switch {
+	case errors.Is(err, billing.ErrPaymentNotFound), errors.Is(err, billing.ErrNotRefundable):
+		p.log.Warn("payments.processor: refund skipped",
+			slog.String("order_id", rr.OrderID.String()),
+			slog.String("payment_id", rr.PaymentID.String()),
+			slog.Any("err", err),
+		)
+		return nil
+	case err != nil:
+		return err
+	case !done:
+		p.log.Info("payments.processor: duplicate order.refund_required, skipped",
+			slog.String("order_id", rr.OrderID.String()),
+			slog.String("payment_id", rr.PaymentID.String()),
+		)
+		return nil
+	}
+
+	p.log.Info("payments.processor: refund processed",
+		slog.String("order_id", rr.OrderID.String()),
+		slog.String("payment_id", rr.PaymentID.String()),
+		slog.Int64("amount_cents", rr.Amount),
+		slog.String("currency", rr.Currency),
 	)
 	return nil
 }
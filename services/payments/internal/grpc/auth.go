@@ -0,0 +1,61 @@
+package grpcsvr
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
+	"goshop/services/payments/api/paymentspb"
+)
+
+// methods — все методы только с access-токеном (или x-admin-token); владельца проверяют сами методы через caller:
+// чужие платежи читают admin/support, повторяет — только admin.
+var methods = grpcauth.Rules{
+	paymentspb.Payments_GetPayment_FullMethodName:          {},
+	paymentspb.Payments_ListPaymentsByOrder_FullMethodName: {},
+	paymentspb.Payments_ListPaymentsByUser_FullMethodName:  {},
+	paymentspb.Payments_RetryPayment_FullMethodName:        {},
+}
+
+// caller — чей платёж можно трогать: uuid.Nil — любой (роль из roles или x-admin-token).
+func (s *Server) caller(ctx context.Context, roles ...string) (uuid.UUID, error) {
+	claims, ok := grpcauth.ClaimsFromContext(ctx)
+	if !ok {
+		// RequireRole пропускает без claims только по x-admin-token
+		if s.admin != nil && s.admin(ctx) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, status.Error(codes.Unauthenticated, "missing authorization")
+	}
+	if claims.HasRole(roles...) {
+		return uuid.Nil, nil
+	}
+	uid, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.Unauthenticated, "invalid token subject")
+	}
+	return uid, nil
+}
+
+var (
+	readAll  = []string{jwtauth.RoleAdmin, jwtauth.RoleSupport}
+	retryAll = []string{jwtauth.RoleAdmin}
+)
+
+// adminToken — статический metadata "x-admin-token" для скриптов без пользователя; пустой — не принимается.
+func adminToken(token string) func(ctx context.Context) bool {
+	return func(ctx context.Context) bool {
+		if token == "" {
+			return false
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		got := md.Get("x-admin-token")
+		return len(got) > 0 && subtle.ConstantTimeCompare([]byte(got[0]), []byte(token)) == 1
+	}
+}
@@ -0,0 +1,58 @@
+package grpcsvr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
+	"goshop/services/payments/api/paymentspb"
+)
+
+func TestCaller(t *testing.T) {
+	s := &Server{admin: adminToken("secret")}
+	uid := uuid.New()
+
+	got, err := s.caller(grpcauth.NewContext(context.Background(), &jwtauth.Claims{UserID: uid.String()}), readAll...)
+	if err != nil || got != uid {
+		t.Fatalf("user: got %v, %v; want %v", got, err, uid)
+	}
+
+	support := &jwtauth.Claims{UserID: uid.String(), Roles: []string{jwtauth.RoleSupport}}
+	if got, err := s.caller(grpcauth.NewContext(context.Background(), support), readAll...); err != nil || got != uuid.Nil {
+		t.Fatalf("support read: got %v, %v; want any owner", got, err)
+	}
+	if got, err := s.caller(grpcauth.NewContext(context.Background(), support), retryAll...); err != nil || got != uid {
+		t.Fatalf("support retry: got %v, %v; want own payments only", got, err)
+	}
+
+	admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "secret"))
+	if got, err := s.caller(admin, retryAll...); err != nil || got != uuid.Nil {
+		t.Fatalf("admin token: got %v, %v; want any owner", got, err)
+	}
+
+	for name, ctx := range map[string]context.Context{
+		"no claims":   context.Background(),
+		"wrong token": metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "nope")),
+		"bad subject": grpcauth.NewContext(context.Background(), &jwtauth.Claims{UserID: "u1"}),
+	} {
+		if _, err := s.caller(ctx, readAll...); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("%s: err = %v, want Unauthenticated", name, err)
+		}
+	}
+}
+
+// Чужой user_id отклоняется до запроса в БД (repo == nil).
+func TestListPaymentsByUser_OtherUser(t *testing.T) {
+	s := &Server{}
+	ctx := grpcauth.NewContext(context.Background(), &jwtauth.Claims{UserID: uuid.NewString()})
+	_, err := s.ListPaymentsByUser(ctx, &paymentspb.ListPaymentsByUserRequest{UserId: uuid.NewString()})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("err = %v, want PermissionDenied", err)
+	}
+}
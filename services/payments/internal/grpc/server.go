@@ -0,0 +1,215 @@
+package grpcsvr
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"goshop/pkg/grpcauth"
+	"goshop/pkg/jwtauth"
+	"goshop/services/payments/api/paymentspb"
+	"goshop/services/payments/internal/adapters/repo/paymentpg"
+	"goshop/services/payments/internal/billing"
+)
+
+type Options struct {
+	Addr    string
+	Logger  *slog.Logger
+	Repo    *paymentpg.Repository
+	Billing *billing.Service
+	// все методы — по access-токену users (claims.UserID — владелец) или AdminToken; без обоих — выключены
+	Verifier   jwtauth.TokenVerifier
+	Revoked    grpcauth.RevocationChecker
+	Audience   string
+	AdminToken string
+}
+
+// Server — API платежей: чтение истории и повтор неудачной оплаты.
+type Server struct {
+	paymentspb.UnimplementedPaymentsServer
+	log   *slog.Logger
+	repo  *paymentpg.Repository
+	bill  *billing.Service
+	admin func(ctx context.Context) bool
+}
+
+func (s *Server) GetPayment(ctx context.Context, in *paymentspb.GetPaymentRequest) (*paymentspb.Payment, error) {
+	id, err := uuid.Parse(in.GetPaymentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "bad payment_id")
+	}
+	owner, err := s.caller(ctx, readAll...)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, paymentpg.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "payment not found")
+		}
+		return nil, status.Errorf(codes.Internal, "get payment: %v", err)
+	}
+	if owner != uuid.Nil && p.UserID != owner {
+		return nil, status.Error(codes.NotFound, "payment not found")
+	}
+	return toPbPayment(p), nil
+}
+
+func (s *Server) ListPaymentsByOrder(ctx context.Context, in *paymentspb.ListPaymentsByOrderRequest) (*paymentspb.ListPaymentsResponse, error) {
+	id, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "bad order_id")
+	}
+	owner, err := s.caller(ctx, readAll...)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListByOrder(ctx, id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list payments: %v", err)
+	}
+	// чужой заказ — как заказ без попыток
+	if owner != uuid.Nil && len(list) > 0 && list[0].UserID != owner {
+		list = nil
+	}
+	return toPbList(list, ""), nil
+}
+
+func (s *Server) ListPaymentsByUser(ctx context.Context, in *paymentspb.ListPaymentsByUserRequest) (*paymentspb.ListPaymentsResponse, error) {
+	owner, err := s.caller(ctx, readAll...)
+	if err != nil {
+		return nil, err
+	}
+	// пустой user_id — свои платежи
+	uid := owner
+	if in.GetUserId() != "" || owner == uuid.Nil {
+		if uid, err = uuid.Parse(in.GetUserId()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "bad user_id")
+		}
+	}
+	if owner != uuid.Nil && uid != owner {
+		return nil, status.Error(codes.PermissionDenied, "payments of another user")
+	}
+	if in.GetPageSize() < 0 || in.GetPageSize() > paymentpg.MaxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 0..%d", paymentpg.MaxPageSize)
+	}
+	page, err := s.repo.ListByUser(ctx, uid, int(in.GetPageSize()), in.GetPageToken())
+	if err != nil {
+		if errors.Is(err, paymentpg.ErrBadCursor) {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		return nil, status.Errorf(codes.Internal, "list payments: %v", err)
+	}
+	return toPbList(page.Payments, page.NextPageToken), nil
+}
+
+func (s *Server) RetryPayment(ctx context.Context, in *paymentspb.RetryPaymentRequest) (*paymentspb.Payment, error) {
+	id, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "bad order_id")
+	}
+	owner, err := s.caller(ctx, retryAll...)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.bill.Retry(ctx, id, owner)
+	if err != nil {
+		switch {
+		case errors.Is(err, billing.ErrNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, billing.ErrNotRetryable), errors.Is(err, billing.ErrAttemptsExhausted),
+			errors.Is(err, billing.ErrOrderClosed):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, billing.ErrConcurrentRetry):
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "retry payment: %v", err)
+	}
+	return toPbPayment(p), nil
+}
+
+func Start(ctx context.Context, opt Options) error {
+	if opt.Logger == nil {
+		opt.Logger = slog.Default()
+	}
+	lis, err := net.Listen("tcp", opt.Addr)
+	if err != nil {
+		opt.Logger.Error("payments.grpc: listen failed",
+			slog.String("addr", opt.Addr),
+			slog.Any("err", err),
+		)
+		return err
+	}
+
+	admin := adminToken(opt.AdminToken)
+	s := grpc.NewServer(grpc.UnaryInterceptor(grpcauth.RequireRole(grpcauth.Options{
+		Logger:   opt.Logger,
+		Verifier: opt.Verifier,
+		Revoked:  opt.Revoked,
+		Audience: opt.Audience,
+		Fallback: admin,
+	}, methods)))
+	paymentspb.RegisterPaymentsServer(s, &Server{log: opt.Logger, repo: opt.Repo, bill: opt.Billing, admin: admin})
+
+	errCh := make(chan error, 1)
+	go func() {
+		opt.Logger.Info("payments.grpc: listening", slog.String("addr", opt.Addr))
+		errCh <- s.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		opt.Logger.Info("payments.grpc: shutting down", slog.String("reason", "context done"))
+		s.GracefulStop()
+		return nil
+	case err := <-errCh:
+		opt.Logger.Error("payments.grpc: serve stopped with error", slog.Any("err", err))
+		return err
+	}
+}
+
+func toPbList(list []paymentpg.Payment, next string) *paymentspb.ListPaymentsResponse {
+	resp := &paymentspb.ListPaymentsResponse{
+		Payments:      make([]*paymentspb.Payment, 0, len(list)),
+		NextPageToken: next,
+	}
+	for i := range list {
+		resp.Payments = append(resp.Payments, toPbPayment(&list[i]))
+	}
+	return resp
+}
+
+func toPbPayment(p *paymentpg.Payment) *paymentspb.Payment {
+	out := &paymentspb.Payment{
+		PaymentId: p.ID.String(),
+		OrderId:   p.OrderID.String(),
+		UserId:    p.UserID.String(),
+		Amount:    &paymentspb.Money{UnitsMinor: p.AmountCents, Currency: p.Currency},
+		Status:    toPbStatus(p.Status),
+		Provider:  p.Provider,
+		CreatedAt: p.CreatedAt.UTC().Format(time.RFC3339),
+		Attempt:   int32(p.Attempt),
+	}
+	if p.Reason != nil {
+		out.Reason = *p.Reason
+	}
+	return out
+}
+
+func toPbStatus(s string) paymentspb.PaymentStatus {
+	switch s {
+	case "confirmed":
+		return paymentspb.PaymentStatus_PAYMENT_STATUS_CONFIRMED
+	case "failed":
+		return paymentspb.PaymentStatus_PAYMENT_STATUS_FAILED
+	default:
+		return paymentspb.PaymentStatus_PAYMENT_STATUS_UNSPECIFIED
+	}
+}
@@ -0,0 +1,64 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Счета — по одному на вид и валюту (ledger_accounts).
+const (
+	AccountCustomer = "customer" // деньги покупателей, пришедшие через провайдера
+	AccountMerchant = "merchant" // выручка магазина за вычетом комиссии
+	AccountFees     = "fees"     // комиссия провайдера
+	AccountRefunds  = "refunds"  // возвраты покупателям (контрсчёт выручки)
+)
+
+var Accounts = []string{AccountCustomer, AccountMerchant, AccountFees, AccountRefunds}
+
+var ErrUnbalanced = errors.New("ledger entry is unbalanced")
+
+// Posting — проводка: Amount > 0 — дебет, < 0 — кредит.
+type Posting struct {
+	Account string
+	Amount  int64
+}
+
+// ChargePostings — проводки подтверждённого списания amount: покупатель отдаёт всю сумму,
+// комиссия (feeBps базисных пунктов, округление вниз) уходит в fees, остальное — merchant.
+func ChargePostings(amount int64, feeBps int) []Posting {
+	fee := amount / 10000 * int64(feeBps)
+	fee += amount % 10000 * int64(feeBps) / 10000
+
+	out := []Posting{
+		{Account: AccountCustomer, Amount: -amount},
+		{Account: AccountMerchant, Amount: amount - fee},
+	}
+	if fee > 0 {
+		out = append(out, Posting{Account: AccountFees, Amount: fee})
+	}
+	return out
+}
+
+// RefundPostings — проводки полного возврата списания amount: покупателю возвращается вся сумма
+// за счёт refunds; комиссия провайдера не возвращается и остаётся в fees.
+func RefundPostings(amount int64) []Posting {
+	return []Posting{
+		{Account: AccountCustomer, Amount: amount},
+		{Account: AccountRefunds, Amount: -amount},
+	}
+}
+
+// Check — запись сходится в ноль и не содержит пустых проводок (то же проверяет БД на COMMIT).
+func Check(postings []Posting) error {
+	var sum int64
+	for _, p := range postings {
+		if p.Amount == 0 {
+			return fmt.Errorf("%w: zero posting to %s", ErrUnbalanced, p.Account)
+		}
+		sum += p.Amount
+	}
+	if len(postings) < 2 || sum != 0 {
+		return fmt.Errorf("%w: %d postings, sum %d", ErrUnbalanced, len(postings), sum)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChargePostings(t *testing.T) {
+	cases := []struct {
+		amount   int64
+		feeBps   int
+		merchant int64
+		fee      int64
+	}{
+		{amount: 19901, feeBps: 250, merchant: 19404, fee: 497}, // 497.525 -> вниз
+		{amount: 10000, feeBps: 0, merchant: 10000, fee: 0},
+		{amount: 1, feeBps: 250, merchant: 1, fee: 0},
+	}
+	for _, tc := range cases {
+		ps := ChargePostings(tc.amount, tc.feeBps)
+		if err := Check(ps); err != nil {
+			t.Fatalf("ChargePostings(%d, %d): %v", tc.amount, tc.feeBps, err)
+		}
+		got := map[string]int64{}
+		for _, p := range ps {
+			got[p.Account] += p.Amount
+		}
+		if got[AccountCustomer] != -tc.amount || got[AccountMerchant] != tc.merchant || got[AccountFees] != tc.fee {
+			t.Errorf("ChargePostings(%d, %d) = %v", tc.amount, tc.feeBps, got)
+		}
+	}
+}
+
+func TestCheckUnbalanced(t *testing.T) {
+	for _, ps := range [][]Posting{
+		{{Account: AccountCustomer, Amount: -100}, {Account: AccountMerchant, Amount: 99}},
+		{{Account: AccountCustomer, Amount: -100}},
+		{{Account: AccountCustomer, Amount: 0}, {Account: AccountMerchant, Amount: 0}},
+	} {
+		if err := Check(ps); !errors.Is(err, ErrUnbalanced) {
+			t.Errorf("Check(%v) = %v, want ErrUnbalanced", ps, err)
+		}
+	}
+}
+
+// TestRefundPostings — списание и его возврат: каждая запись сходится, покупатель в нуле,
+// выручка за вычетом возвратов — минус комиссия.
+func TestRefundPostings(t *testing.T) {
+	const amount, feeBps = 19901, 250
+
+	charge, refund := ChargePostings(amount, feeBps), RefundPostings(amount)
+	for _, ps := range [][]Posting{charge, refund} {
+		if err := Check(ps); err != nil {
+			t.Fatalf("Check(%v): %v", ps, err)
+		}
+	}
+
+	bal := map[string]int64{}
+	for _, p := range append(charge, refund...) {
+		bal[p.Account] += p.Amount
+	}
+	if bal[AccountCustomer] != 0 {
+		t.Errorf("customer balance = %d, want 0", bal[AccountCustomer])
+	}
+	if bal[AccountRefunds] != -amount {
+		t.Errorf("refunds balance = %d, want %d", bal[AccountRefunds], -amount)
+	}
+	if got := bal[AccountMerchant] + bal[AccountRefunds]; got != -bal[AccountFees] {
+		t.Errorf("merchant net of refunds = %d, want %d", got, -bal[AccountFees])
+	}
+}
@@ -0,0 +1,304 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Config struct {
+	FeeBps int // комиссия провайдера в базисных пунктах (250 = 2.5%)
+}
+
+// Ledger пишет проводки в транзакции изменения платежа и сверяет журнал с payments.
+type Ledger struct {
+	log *slog.Logger
+	db  *pgxpool.Pool
+	cfg Config
+}
+
+func New(log *slog.Logger, db *pgxpool.Pool, cfg Config) *Ledger {
+	return &Ledger{log: log, db: db, cfg: cfg}
+}
+
+// PostChargeTx — запись 'charge' для подтверждённого платежа в той же транзакции, что и строка payments.
+func (l *Ledger) PostChargeTx(ctx context.Context, tx pgx.Tx, paymentID uuid.UUID, amount int64, currency string) error {
+	return postTx(ctx, tx, paymentID, "charge", currency, "mockpay charge", ChargePostings(amount, l.cfg.FeeBps))
+}
+
+// PostRefundTx — запись 'refund' (полный возврат списания amount) в транзакции возврата.
+// Второй возврат того же платежа упадёт на unique.
+func (l *Ledger) PostRefundTx(ctx context.Context, tx pgx.Tx, paymentID uuid.UUID, amount int64, currency, memo string) error {
+	return postTx(ctx, tx, paymentID, "refund", currency, memo, RefundPostings(amount))
+}
+
+// RefundedTx — есть ли уже запись 'refund' для платежа.
+func (l *Ledger) RefundedTx(ctx context.Context, tx pgx.Tx, paymentID uuid.UUID) (bool, error) {
+	var ok bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM ledger_entries WHERE payment_id = $1 AND kind = 'refund');
+	`, paymentID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("select refund entry: %w", err)
+	}
+	return ok, nil
+}
+
+// postTx — запись kind с проводками postings.
+func postTx(ctx context.Context, tx pgx.Tx, paymentID uuid.UUID, kind, currency, memo string, postings []Posting) error {
+	if err := Check(postings); err != nil {
+		return err
+	}
+	accounts, err := accountsTx(ctx, tx, currency)
+	if err != nil {
+		return err
+	}
+
+	var entryID int64
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO ledger_entries (payment_id, kind, currency, memo)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;
+	`, paymentID, kind, currency, memo).Scan(&entryID); err != nil {
+		return fmt.Errorf("insert ledger entry: %w", err)
+	}
+
+	for _, p := range postings {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO ledger_postings (entry_id, account_id, amount_minor) VALUES ($1, $2, $3);
+		`, entryID, accounts[p.Account], p.Amount); err != nil {
+			return fmt.Errorf("insert ledger posting: %w", err)
+		}
+	}
+	return nil
+}
+
+// accountsTx — id счетов валюты по виду; недостающие создаются.
+func accountsTx(ctx context.Context, tx pgx.Tx, currency string) (map[string]int64, error) {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO ledger_accounts (kind, currency)
+		SELECT k, $2 FROM unnest($1::text[]) AS k
+		ON CONFLICT (kind, currency) DO NOTHING;
+	`, Accounts, currency); err != nil {
+		return nil, fmt.Errorf("ensure ledger accounts: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT kind, id FROM ledger_accounts WHERE currency = $1`, currency)
+	if err != nil {
+		return nil, fmt.Errorf("select ledger accounts: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]int64, len(Accounts))
+	for rows.Next() {
+		var kind string
+		var id int64
+		if err := rows.Scan(&kind, &id); err != nil {
+			return nil, fmt.Errorf("scan ledger account: %w", err)
+		}
+		out[kind] = id
+	}
+	return out, rows.Err()
+}
+
+// Backfill — записи 'charge' для подтверждённых платежей без них (платежи до появления журнала).
+func (l *Ledger) Backfill(ctx context.Context) (int, error) {
+	rows, err := l.db.Query(ctx, `
+		SELECT p.id, p.amount_cents, p.currency
+		FROM payments p
+		WHERE p.status = 'confirmed'
+		  AND NOT EXISTS (SELECT 1 FROM ledger_entries e WHERE e.payment_id = p.id AND e.kind = 'charge')
+		ORDER BY p.created_at;
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("select unposted payments: %w", err)
+	}
+	type unposted struct {
+		id       uuid.UUID
+		amount   int64
+		currency string
+	}
+	var list []unposted
+	for rows.Next() {
+		var u unposted
+		if err := rows.Scan(&u.id, &u.amount, &u.currency); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan unposted payment: %w", err)
+		}
+		list = append(list, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("select unposted payments: %w", err)
+	}
+
+	n := 0
+	for _, u := range list {
+		if err := pgx.BeginTxFunc(ctx, l.db, pgx.TxOptions{}, func(tx pgx.Tx) error {
+			return l.PostChargeTx(ctx, tx, u.id, u.amount, u.currency)
+		}); err != nil {
+			return n, fmt.Errorf("backfill payment %s: %w", u.id, err)
+		}
+		n++
+		l.log.Info("payments.ledger: backfilled", slog.String("payment_id", u.id.String()))
+	}
+	return n, nil
+}
+
+// Report — расхождения журнала с payments; пустые списки и сошедшиеся Totals — всё в порядке.
+type Report struct {
+	Unbalanced []UnbalancedEntry `json:"unbalanced"`
+	Unposted   []uuid.UUID       `json:"unposted"` // подтверждённые платежи без записи 'charge'
+	Mismatched []MismatchedEntry `json:"mismatched"`
+	Totals     []CurrencyTotal   `json:"totals"`
+}
+
+type UnbalancedEntry struct {
+	EntryID int64 `json:"entry_id"`
+	Sum     int64 `json:"sum_minor"`
+}
+
+type MismatchedEntry struct {
+	EntryID   int64     `json:"entry_id"`
+	PaymentID uuid.UUID `json:"payment_id"`
+	Kind      string    `json:"kind"` // charge | refund
+	Reason    string    `json:"reason"`
+}
+
+// CurrencyTotal — сумма подтверждённых платежей против списаний по счёту customer;
+// Refunded — возвращённое покупателям (справочно: возврат не меняет статус платежа).
+type CurrencyTotal struct {
+	Currency  string `json:"currency"`
+	Confirmed int64  `json:"confirmed_minor"`
+	Charged   int64  `json:"charged_minor"`
+	Refunded  int64  `json:"refunded_minor"`
+}
+
+func (r *Report) OK() bool {
+	for _, t := range r.Totals {
+		if t.Confirmed != t.Charged {
+			return false
+		}
+	}
+	return len(r.Unbalanced) == 0 && len(r.Unposted) == 0 && len(r.Mismatched) == 0
+}
+
+// Reconcile сверяет журнал с payments: баланс записей, покрытие подтверждённых платежей,
+// суммы записей против строк payments и итоги по валютам.
+func (l *Ledger) Reconcile(ctx context.Context) (*Report, error) {
+	rep := &Report{}
+
+	if err := collect(ctx, l.db, `
+		SELECT entry_id, sum(amount_minor)::bigint
+		FROM ledger_postings
+		GROUP BY entry_id
+		HAVING sum(amount_minor) <> 0
+		ORDER BY entry_id;
+	`, func(rows pgx.Rows) error {
+		var u UnbalancedEntry
+		if err := rows.Scan(&u.EntryID, &u.Sum); err != nil {
+			return err
+		}
+		rep.Unbalanced = append(rep.Unbalanced, u)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("unbalanced entries: %w", err)
+	}
+
+	if err := collect(ctx, l.db, `
+		SELECT p.id
+		FROM payments p
+		WHERE p.status = 'confirmed'
+		  AND NOT EXISTS (SELECT 1 FROM ledger_entries e WHERE e.payment_id = p.id AND e.kind = 'charge')
+		ORDER BY p.created_at;
+	`, func(rows pgx.Rows) error {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		rep.Unposted = append(rep.Unposted, id)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("unposted payments: %w", err)
+	}
+
+	// списание (возврат) по счёту customer должно равняться сумме платежа, а платёж — быть подтверждённым;
+	// возврат — только после списания
+	if err := collect(ctx, l.db, `
+		SELECT e.id, e.payment_id, e.kind,
+		       CASE
+		           WHEN p.status <> 'confirmed' THEN 'payment is ' || p.status
+		           WHEN e.currency <> p.currency THEN 'currency ' || e.currency || ' <> ' || p.currency
+		           WHEN c.moved <> p.amount_cents THEN 'moved ' || c.moved || ' <> amount ' || p.amount_cents
+		           ELSE 'refund without charge'
+		       END
+		FROM ledger_entries e
+		JOIN payments p ON p.id = e.payment_id
+		CROSS JOIN LATERAL (
+		    SELECT COALESCE(sum(lp.amount_minor), 0)::bigint * CASE e.kind WHEN 'charge' THEN -1 ELSE 1 END AS moved
+		    FROM ledger_postings lp
+		    JOIN ledger_accounts a ON a.id = lp.account_id
+		    WHERE lp.entry_id = e.id AND a.kind = 'customer'
+		) c
+		WHERE p.status <> 'confirmed' OR e.currency <> p.currency OR c.moved <> p.amount_cents
+		   OR (e.kind = 'refund'
+		       AND NOT EXISTS (SELECT 1 FROM ledger_entries ch WHERE ch.payment_id = e.payment_id AND ch.kind = 'charge'))
+		ORDER BY e.id;
+	`, func(rows pgx.Rows) error {
+		var m MismatchedEntry
+		if err := rows.Scan(&m.EntryID, &m.PaymentID, &m.Kind, &m.Reason); err != nil {
+			return err
+		}
+		rep.Mismatched = append(rep.Mismatched, m)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("mismatched entries: %w", err)
+	}
+
+	if err := collect(ctx, l.db, `
+		WITH confirmed AS (
+		    SELECT currency, sum(amount_cents)::bigint AS amount FROM payments WHERE status = 'confirmed' GROUP BY currency
+		), moved AS (
+		    SELECT a.currency::text AS currency,
+		           -sum(lp.amount_minor) FILTER (WHERE e.kind = 'charge') AS charged,
+		           sum(lp.amount_minor) FILTER (WHERE e.kind = 'refund') AS refunded
+		    FROM ledger_postings lp
+		    JOIN ledger_entries e ON e.id = lp.entry_id
+		    JOIN ledger_accounts a ON a.id = lp.account_id AND a.kind = 'customer'
+		    GROUP BY a.currency
+		)
+		SELECT COALESCE(c.currency, m.currency), COALESCE(c.amount, 0),
+		       COALESCE(m.charged, 0)::bigint, COALESCE(m.refunded, 0)::bigint
+		FROM confirmed c
+		FULL JOIN moved m ON m.currency = c.currency
+		ORDER BY 1;
+	`, func(rows pgx.Rows) error {
+		var t CurrencyTotal
+		if err := rows.Scan(&t.Currency, &t.Confirmed, &t.Charged, &t.Refunded); err != nil {
+			return err
+		}
+		rep.Totals = append(rep.Totals, t)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("currency totals: %w", err)
+	}
+
+	return rep, nil
+}
+
+func collect(ctx context.Context, db *pgxpool.Pool, q string, scan func(pgx.Rows) error) error {
+	rows, err := db.Query(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,8 @@
+-- +goose Up
+-- read API: история платежей пользователя/заказа, новые первыми (keyset по created_at, id)
+CREATE INDEX IF NOT EXISTS idx_payments_user_created  ON payments (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_payments_order_created ON payments (order_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_payments_order_created;
+DROP INDEX IF EXISTS idx_payments_user_created;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- повторные попытки оплаты: каждая — отдельная строка с номером попытки
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS attempt INT NOT NULL DEFAULT 1 CHECK (attempt > 0);
+
+-- до повторов строк на заказ могло быть несколько (старые дубли order.created) — нумеруем по времени,
+-- иначе все они получили бы attempt = 1 и уникальный индекс не построился бы
+UPDATE payments p
+SET attempt = n.rn
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY order_id ORDER BY created_at, id) AS rn
+    FROM payments
+) n
+WHERE p.id = n.id AND p.attempt <> n.rn;
+
+-- одна строка на попытку: повторная доставка order.created и гонка двух RetryPayment не создадут дубль
+CREATE UNIQUE INDEX IF NOT EXISTS ux_payments_order_attempt ON payments (order_id, attempt);
+
+-- +goose Down
+DROP INDEX IF EXISTS ux_payments_order_attempt;
+ALTER TABLE payments DROP COLUMN IF EXISTS attempt;
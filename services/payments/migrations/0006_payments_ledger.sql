@@ -0,0 +1,97 @@
+-- +goose Up
+-- двойная запись: счета по виду и валюте, проводки неизменяемы и в каждой записи сходятся в ноль
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id          BIGSERIAL   PRIMARY KEY,
+    kind        TEXT        NOT NULL CHECK (kind IN ('customer', 'merchant', 'fees', 'refunds')),
+    currency    CHAR(3)     NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (kind, currency)
+);
+
+-- запись журнала: одна бизнес-операция (списание, возврат)
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id          BIGSERIAL   PRIMARY KEY,
+    payment_id  UUID        NOT NULL REFERENCES payments(id),
+    kind        TEXT        NOT NULL CHECK (kind IN ('charge', 'refund')),
+    currency    CHAR(3)     NOT NULL,
+    memo        TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- одно списание на платёж: повторная запись упадёт на unique
+CREATE UNIQUE INDEX IF NOT EXISTS ux_ledger_entries_charge ON ledger_entries (payment_id) WHERE kind = 'charge';
+
+-- проводка: amount_minor > 0 — дебет, < 0 — кредит
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id           BIGSERIAL PRIMARY KEY,
+    entry_id     BIGINT    NOT NULL REFERENCES ledger_entries(id),
+    account_id   BIGINT    NOT NULL REFERENCES ledger_accounts(id),
+    amount_minor BIGINT    NOT NULL CHECK (amount_minor <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_entry   ON ledger_postings (entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_account ON ledger_postings (account_id);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ledger_immutable()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $func$
+BEGIN
+  RAISE EXCEPTION 'ledger is append-only: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$func$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- баланс проверяется на COMMIT: проводки записи вставляются по одной
+CREATE OR REPLACE FUNCTION ledger_entry_balanced()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $func$
+DECLARE
+  total BIGINT;
+BEGIN
+  SELECT COALESCE(sum(amount_minor), 0) INTO total FROM ledger_postings WHERE entry_id = NEW.entry_id;
+  IF total <> 0 THEN
+    RAISE EXCEPTION 'ledger entry % is unbalanced: %', NEW.entry_id, total;
+  END IF;
+  RETURN NULL;
+END;
+$func$;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trg_ledger_entries_immutable ON ledger_entries;
+CREATE TRIGGER trg_ledger_entries_immutable
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW
+    EXECUTE PROCEDURE ledger_immutable();
+
+DROP TRIGGER IF EXISTS trg_ledger_postings_immutable ON ledger_postings;
+CREATE TRIGGER trg_ledger_postings_immutable
+    BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW
+    EXECUTE PROCEDURE ledger_immutable();
+
+DROP TRIGGER IF EXISTS trg_ledger_postings_balanced ON ledger_postings;
+CREATE CONSTRAINT TRIGGER trg_ledger_postings_balanced
+    AFTER INSERT ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE PROCEDURE ledger_entry_balanced();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_ledger_postings_balanced ON ledger_postings;
+DROP TRIGGER IF EXISTS trg_ledger_postings_immutable ON ledger_postings;
+DROP TRIGGER IF EXISTS trg_ledger_entries_immutable ON ledger_entries;
+DROP TABLE IF EXISTS ledger_postings;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_accounts;
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS ledger_entry_balanced();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS ledger_immutable();
+-- +goose StatementEnd
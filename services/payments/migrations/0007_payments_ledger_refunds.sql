@@ -0,0 +1,6 @@
+-- +goose Up
+-- один возврат на платёж: возврат всегда на полную сумму списания, повторная запись упадёт на unique
+CREATE UNIQUE INDEX IF NOT EXISTS ux_ledger_entries_refund ON ledger_entries (payment_id) WHERE kind = 'refund';
+
+-- +goose Down
+DROP INDEX IF EXISTS ux_ledger_entries_refund;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- заказы, закрытые в orders без участия payments (order.expired): повтор оплаты по ним запрещён
+CREATE TABLE IF NOT EXISTS payments_closed_orders (
+    order_id   UUID        PRIMARY KEY,
+    reason     TEXT        NOT NULL,
+    closed_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS payments_closed_orders;
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"goshop/pkg/logger"
+	"goshop/pkg/postgres"
+	"goshop/services/reconcile/config"
+	"goshop/services/reconcile/internal/recon"
+)
+
+// reconcile — сверка orders ↔ payments за окно времени. Отчёт (Markdown или JSON) — в stdout
+// или файл; код 1, если есть неисправленные расхождения или застрявший outbox.
+//
+//	reconcile                                   # заказы за последние window (24h)
+//	reconcile -from 2026-10-01T00:00:00Z -to 2026-10-02T00:00:00Z -format json
+//	reconcile -fix                              # переотправить потерянные события
+func main() {
+	var (
+		window = flag.Duration("window", 0, "check orders created within the last window (default: config window)")
+		fromS  = flag.String("from", "", "window start, RFC3339 (overrides -window)")
+		toS    = flag.String("to", "", "window end, RFC3339 (default: now)")
+		format = flag.String("format", "md", "report format: md|json")
+		out    = flag.String("o", "", "write report to file instead of stdout")
+		fix    = flag.Bool("fix", false, "emit corrective events for fixable discrepancies")
+	)
+	flag.Parse()
+	if *format != "md" && *format != "json" {
+		fail("flags", fmt.Errorf("unknown format %q", *format))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg := config.New()
+	if err := cfg.Validate(); err != nil {
+		fail("config", err)
+	}
+	log := logger.NewLogger(cfg.Logger)
+
+	to := time.Now()
+	if *toS != "" {
+		t, err := time.Parse(time.RFC3339, *toS)
+		if err != nil {
+			fail("flags", fmt.Errorf("-to: %w", err))
+		}
+		to = t
+	}
+	from := to.Add(-cfg.Window)
+	if *window > 0 {
+		from = to.Add(-*window)
+	}
+	if *fromS != "" {
+		t, err := time.Parse(time.RFC3339, *fromS)
+		if err != nil {
+			fail("flags", fmt.Errorf("-from: %w", err))
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		fail("flags", fmt.Errorf("empty window %s — %s", from.Format(time.RFC3339), to.Format(time.RFC3339)))
+	}
+
+	ordersDB, err := postgres.NewPool(ctx, cfg.Orders.Postgres)
+	if err != nil {
+		fail("orders postgres", err)
+	}
+	defer ordersDB.Close()
+	paymentsDB, err := postgres.NewPool(ctx, cfg.Payments.Postgres)
+	if err != nil {
+		fail("payments postgres", err)
+	}
+	defer paymentsDB.Close()
+
+	rec := recon.New(log, ordersDB, paymentsDB, recon.Config{
+		Grace:          cfg.Grace,
+		OrdersTopic:    cfg.Orders.OutboxTopic,
+		PaymentsTopic:  cfg.Payments.OutboxTopic,
+		MaxAttempts:    cfg.MaxAttempts,
+		EmitCorrective: *fix,
+	})
+	rep, err := rec.Run(ctx, from, to)
+	if err != nil {
+		fail("run", err)
+	}
+
+	var body []byte
+	switch *format {
+	case "json":
+		body, err = json.MarshalIndent(rep, "", "  ")
+		body = append(body, '\n')
+	default:
+		body = []byte(rep.Markdown())
+	}
+	if err != nil {
+		fail("encode", err)
+	}
+	if *out != "" {
+		err = os.WriteFile(*out, body, 0o644)
+	} else {
+		_, err = os.Stdout.Write(body)
+	}
+	if err != nil {
+		fail("write", err)
+	}
+
+	log.Info("reconcile: done",
+		slog.Int("orders", rep.Orders),
+		slog.Int("payments", rep.Payments),
+		slog.Int("discrepancies", len(rep.Discrepancies)),
+		slog.Bool("ok", rep.OK()),
+	)
+	if !rep.OK() {
+		ordersDB.Close()
+		paymentsDB.Close()
+		os.Exit(1)
+	}
+}
+
+func fail(what string, err error) {
+	fmt.Fprintf(os.Stderr, "reconcile: %s: %v\n", what, err)
+	os.Exit(1)
+}
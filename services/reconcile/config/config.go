@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cfg "goshop/pkg/config"
+)
+
+type Reconcile struct {
+	AppName string     `mapstructure:"app_name"`
+	Logger  cfg.Logger `mapstructure:"logger"`
+
+	// базы сервисов могут быть разными — сверка идёт на стороне команды
+	Orders   Side `mapstructure:"orders"`
+	Payments Side `mapstructure:"payments"`
+
+	Window time.Duration `mapstructure:"window"` // по умолчанию сверяем заказы за последние 24h
+	Grace  time.Duration `mapstructure:"grace"`  // свежее — ещё в пути (outbox → Kafka → consumer), не расхождение
+
+	// MaxAttempts — retry.max_attempts payments; нужен для retryable в повторно отправленном payment.failed
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+type Side struct {
+	Postgres    cfg.Postgres `mapstructure:"postgres"`
+	OutboxTopic string       `mapstructure:"outbox_topic"`
+}
+
+func (c *Reconcile) Validate() error {
+	if c.AppName == "" {
+		return errors.New("app_name is required")
+	}
+	if err := c.Orders.Postgres.Validate(); err != nil {
+		return fmt.Errorf("orders.postgres: %w", err)
+	}
+	if err := c.Payments.Postgres.Validate(); err != nil {
+		return fmt.Errorf("payments.postgres: %w", err)
+	}
+	if c.Orders.OutboxTopic == "" {
+		c.Orders.OutboxTopic = "orders.events"
+	}
+	if c.Payments.OutboxTopic == "" {
+		c.Payments.OutboxTopic = "payments.events"
+	}
+	if c.Window <= 0 {
+		c.Window = 24 * time.Hour
+	}
+	if c.Grace <= 0 {
+		c.Grace = 5 * time.Minute
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+	return nil
+}
+
+// New — грузим конфиг по схеме: файлы -> ENV (с префиксом RECONCILE_)
+func New() *Reconcile {
+	c := cfg.MustLoad[Reconcile](cfg.Options{
+		Paths:         []string{"./config", "./services/reconcile/config", "./configs", "/etc/goshop"},
+		Names:         []string{"defaults", "reconcile", "config"},
+		Type:          "yaml",
+		EnvPrefix:     "RECONCILE",
+		OptionalFiles: true, // false - требовать хотя бы один файл
+	})
+	c.Logger.AppName = c.AppName
+	return c
+}
@@ -0,0 +1,157 @@
+package recon
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Виды расхождений. Fixable — те, что лечатся повторной отправкой события через outbox.
+const (
+	// заказ оплачен/отгружен, а подтверждённого платежа нет
+	KindPaidWithoutPayment = "paid_without_payment"
+	// платёж подтверждён, заказ остался new/payment_failed — потерялся payment.confirmed (fixable)
+	KindPaymentNotApplied = "payment_not_applied"
+	// последняя попытка провалилась, заказ остался new — потерялся payment.failed (fixable)
+	KindFailureNotApplied = "failure_not_applied"
+	// заказ new без единой попытки — потерялся order.created (fixable)
+	KindPaymentMissing = "payment_missing"
+	// деньги списаны за отменённый заказ, а возврата (payments, по order.refund_required) нет
+	KindChargedCancelled = "charged_cancelled_order"
+	// сумма/валюта подтверждённого платежа не совпадает с заказом
+	KindAmountMismatch = "amount_mismatch"
+	// платёж по заказу, которого нет в orders
+	KindPaymentWithoutOrder = "payment_without_order"
+)
+
+type Order struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Status      string
+	AmountMinor int64
+	Currency    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Payment struct {
+	ID          uuid.UUID
+	OrderID     uuid.UUID
+	UserID      uuid.UUID
+	AmountMinor int64
+	Currency    string
+	Status      string // confirmed|failed
+	Refunded    bool   // в журнале payments есть запись 'refund'
+	Reason      *string
+	Attempt     int
+	CreatedAt   time.Time
+}
+
+type Discrepancy struct {
+	Kind          string     `json:"kind"`
+	OrderID       uuid.UUID  `json:"order_id"`
+	PaymentID     *uuid.UUID `json:"payment_id,omitempty"`
+	OrderStatus   string     `json:"order_status,omitempty"`
+	PaymentStatus string     `json:"payment_status,omitempty"`
+	Detail        string     `json:"detail"`
+	Fixable       bool       `json:"fixable"`
+	Fixed         bool       `json:"fixed"`
+	FixError      string     `json:"fix_error,omitempty"` // исправляющее событие не записалось
+
+	order   *Order
+	payment *Payment
+}
+
+// Compare сверяет заказы с их платежами. Расхождения моложе grace (по времени последнего
+// изменения) не считаются: событие может быть ещё в пути.
+func Compare(orders []Order, payments []Payment, now time.Time, grace time.Duration) []Discrepancy {
+	byOrder := make(map[uuid.UUID][]*Payment, len(orders))
+	for i := range payments {
+		p := &payments[i]
+		byOrder[p.OrderID] = append(byOrder[p.OrderID], p)
+	}
+	settled := func(t time.Time) bool { return now.Sub(t) >= grace }
+
+	var out []Discrepancy
+	seen := make(map[uuid.UUID]bool, len(orders))
+	for i := range orders {
+		o := &orders[i]
+		seen[o.ID] = true
+		ps := byOrder[o.ID]
+		sort.Slice(ps, func(a, b int) bool { return ps[a].Attempt < ps[b].Attempt })
+
+		var confirmed, last *Payment
+		for _, p := range ps {
+			if p.Status == "confirmed" && confirmed == nil {
+				confirmed = p
+			}
+			last = p
+		}
+
+		add := func(kind string, p *Payment, fixable bool, format string, args ...any) {
+			d := Discrepancy{
+				Kind:        kind,
+				OrderID:     o.ID,
+				OrderStatus: o.Status,
+				Detail:      fmt.Sprintf(format, args...),
+				Fixable:     fixable,
+				order:       o,
+				payment:     p,
+			}
+			if p != nil {
+				id := p.ID
+				d.PaymentID = &id
+				d.PaymentStatus = p.Status
+			}
+			out = append(out, d)
+		}
+
+		switch o.Status {
+		case "paid", "shipped", "delivered":
+			if confirmed == nil && settled(o.UpdatedAt) {
+				add(KindPaidWithoutPayment, last, false, "order is %s, no confirmed payment among %d attempts", o.Status, len(ps))
+			}
+		case "new", "payment_failed":
+			switch {
+			case confirmed != nil && settled(confirmed.CreatedAt):
+				add(KindPaymentNotApplied, confirmed, true, "payment confirmed at %s, order still %s",
+					confirmed.CreatedAt.UTC().Format(time.RFC3339), o.Status)
+			case last == nil && o.Status == "new" && settled(o.CreatedAt):
+				add(KindPaymentMissing, nil, true, "no payment attempts for order created at %s",
+					o.CreatedAt.UTC().Format(time.RFC3339))
+			case last != nil && last.Status == "failed" && o.Status == "new" && settled(last.CreatedAt):
+				add(KindFailureNotApplied, last, true, "attempt %d failed at %s, order still new",
+					last.Attempt, last.CreatedAt.UTC().Format(time.RFC3339))
+			}
+		case "cancelled", "canceled":
+			if confirmed != nil && !confirmed.Refunded {
+				add(KindChargedCancelled, confirmed, false, "order cancelled, payment confirmed at %s: refund required",
+					confirmed.CreatedAt.UTC().Format(time.RFC3339))
+			}
+		}
+
+		if confirmed != nil && (confirmed.AmountMinor != o.AmountMinor || confirmed.Currency != o.Currency) {
+			add(KindAmountMismatch, confirmed, false, "order %d %s, payment %d %s",
+				o.AmountMinor, o.Currency, confirmed.AmountMinor, confirmed.Currency)
+		}
+	}
+
+	for i := range payments {
+		p := &payments[i]
+		if seen[p.OrderID] {
+			continue
+		}
+		id := p.ID
+		out = append(out, Discrepancy{
+			Kind:          KindPaymentWithoutOrder,
+			OrderID:       p.OrderID,
+			PaymentID:     &id,
+			PaymentStatus: p.Status,
+			Detail:        fmt.Sprintf("attempt %d: %d %s, order not found", p.Attempt, p.AmountMinor, p.Currency),
+			payment:       p,
+		})
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCompare(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-time.Hour)
+	fresh := now.Add(-time.Minute)
+
+	order := func(status string, at time.Time) Order {
+		return Order{ID: uuid.New(), UserID: uuid.New(), Status: status, AmountMinor: 1000, Currency: "RUB", CreatedAt: at, UpdatedAt: at}
+	}
+	pay := func(o Order, status string, attempt int, at time.Time) Payment {
+		return Payment{ID: uuid.New(), OrderID: o.ID, UserID: o.UserID, AmountMinor: o.AmountMinor, Currency: o.Currency, Status: status, Attempt: attempt, CreatedAt: at}
+	}
+
+	okPaid := order("paid", old)
+	paidNoPay := order("paid", old)
+	notApplied := order("new", old)
+	failNotApplied := order("new", old)
+	missing := order("new", old)
+	missingFresh := order("new", fresh)
+	cancelled := order("cancelled", old)
+	refunded := order("cancelled", old)
+	mismatch := order("paid", old)
+	retrying := order("payment_failed", old)
+
+	orders := []Order{okPaid, paidNoPay, notApplied, failNotApplied, missing, missingFresh, cancelled, refunded, mismatch, retrying}
+	mm := pay(mismatch, "confirmed", 1, old)
+	mm.AmountMinor = 900
+	orphan := pay(order("paid", old), "confirmed", 1, old)
+	rf := pay(refunded, "confirmed", 1, old)
+	rf.Refunded = true
+	payments := []Payment{
+		pay(okPaid, "confirmed", 1, old),
+		pay(paidNoPay, "failed", 1, old),
+		pay(notApplied, "failed", 1, old),
+		pay(notApplied, "confirmed", 2, old),
+		pay(failNotApplied, "failed", 1, old),
+		pay(cancelled, "confirmed", 1, old),
+		rf,
+		mm,
+		pay(retrying, "failed", 1, old),
+		orphan,
+	}
+
+	want := map[uuid.UUID]string{
+		paidNoPay.ID:      KindPaidWithoutPayment,
+		notApplied.ID:     KindPaymentNotApplied,
+		failNotApplied.ID: KindFailureNotApplied,
+		missing.ID:        KindPaymentMissing,
+		cancelled.ID:      KindChargedCancelled,
+		mismatch.ID:       KindAmountMismatch,
+		orphan.OrderID:    KindPaymentWithoutOrder,
+	}
+
+	got := Compare(orders, payments, now, 5*time.Minute)
+	if len(got) != len(want) {
+		t.Fatalf("got %d discrepancies, want %d: %+v", len(got), len(want), got)
+	}
+	for _, d := range got {
+		if want[d.OrderID] != d.Kind {
+			t.Errorf("order %s: kind %s, want %q", d.OrderID, d.Kind, want[d.OrderID])
+		}
+		fixable := d.Kind == KindPaymentNotApplied || d.Kind == KindFailureNotApplied || d.Kind == KindPaymentMissing
+		if d.Fixable != fixable {
+			t.Errorf("%s: fixable %v", d.Kind, d.Fixable)
+		}
+	}
+
+	rep := Report{Discrepancies: got}
+	if rep.OK() {
+		t.Error("report with discrepancies is OK")
+	}
+}
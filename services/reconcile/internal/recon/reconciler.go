@@ -0,0 +1,253 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Config struct {
+	Grace          time.Duration
+	OrdersTopic    string
+	PaymentsTopic  string
+	MaxAttempts    int  // retry.max_attempts payments — для retryable в payment.failed
+	EmitCorrective bool // отправлять исправляющие события для fixable-расхождений
+}
+
+// Reconciler сверяет базы orders и payments (они могут быть разными, поэтому сравнение — в Go)
+// и при EmitCorrective повторно отправляет потерянные события через outbox нужного сервиса.
+type Reconciler struct {
+	log      *slog.Logger
+	orders   *pgxpool.Pool
+	payments *pgxpool.Pool
+	cfg      Config
+}
+
+func New(log *slog.Logger, orders, payments *pgxpool.Pool, cfg Config) *Reconciler {
+	return &Reconciler{log: log, orders: orders, payments: payments, cfg: cfg}
+}
+
+// Run — сверка заказов, созданных в [from, to), и платежей того же окна.
+func (r *Reconciler) Run(ctx context.Context, from, to time.Time) (*Report, error) {
+	now := time.Now()
+
+	orders, err := r.loadOrders(ctx, `created_at >= $1 AND created_at < $2`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(orders))
+	for _, o := range orders {
+		ids = append(ids, o.ID)
+	}
+
+	// платежи заказов окна + платежи окна по заказам вне его
+	payments, err := r.loadPayments(ctx, ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		known[id] = true
+	}
+	var extra []uuid.UUID
+	for _, p := range payments {
+		if !known[p.OrderID] {
+			known[p.OrderID] = true
+			extra = append(extra, p.OrderID)
+		}
+	}
+	if len(extra) > 0 {
+		more, err := r.loadOrders(ctx, `id = ANY($1)`, extra)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, more...)
+	}
+
+	rep := &Report{
+		From:          from,
+		To:            to,
+		GeneratedAt:   now.UTC(),
+		Orders:        len(orders),
+		Payments:      len(payments),
+		Discrepancies: Compare(orders, payments, now, r.cfg.Grace),
+	}
+
+	for _, t := range []struct {
+		db    *pgxpool.Pool
+		table string
+	}{{r.orders, "orders_outbox"}, {r.payments, "payments_outbox"}} {
+		b, err := outboxBacklog(ctx, t.db, t.table, now.Add(-r.cfg.Grace))
+		if err != nil {
+			return nil, err
+		}
+		rep.Outbox = append(rep.Outbox, b)
+	}
+
+	if r.cfg.EmitCorrective {
+		r.fixAll(ctx, rep.Discrepancies, r.fix)
+	}
+	return rep, nil
+}
+
+// fixAll — исправляющие события для fixable-расхождений. Ошибка одного не прерывает остальные:
+// она остаётся в отчёте (FixError), расхождение — неисправленным.
+func (r *Reconciler) fixAll(ctx context.Context, ds []Discrepancy, fix func(context.Context, *Discrepancy) error) {
+	for i := range ds {
+		d := &ds[i]
+		if !d.Fixable {
+			continue
+		}
+		if err := fix(ctx, d); err != nil {
+			d.FixError = err.Error()
+			r.log.Error("reconcile: corrective event failed",
+				slog.String("kind", d.Kind),
+				slog.String("order_id", d.OrderID.String()),
+				slog.Any("err", err),
+			)
+			continue
+		}
+		d.Fixed = true
+		r.log.Info("reconcile: corrective event emitted",
+			slog.String("kind", d.Kind),
+			slog.String("order_id", d.OrderID.String()),
+		)
+	}
+}
+
+func (r *Reconciler) loadOrders(ctx context.Context, where string, args ...any) ([]Order, error) {
+	rows, err := r.orders.Query(ctx, `
+		SELECT id, user_id, status, amount_minor, currency, created_at, updated_at
+		FROM orders
+		WHERE `+where+`
+		ORDER BY created_at;
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select orders: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Order
+	for rows.Next() {
+		var o Order
+		if err := rows.Scan(&o.ID, &o.UserID, &o.Status, &o.AmountMinor, &o.Currency, &o.CreatedAt, &o.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan order: %w", err)
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+func (r *Reconciler) loadPayments(ctx context.Context, orderIDs []uuid.UUID, from, to time.Time) ([]Payment, error) {
+	rows, err := r.payments.Query(ctx, `
+		SELECT p.id, p.order_id, p.user_id, p.amount_cents, p.currency, p.status,
+		       EXISTS (SELECT 1 FROM ledger_entries e WHERE e.payment_id = p.id AND e.kind = 'refund'),
+		       p.reason, p.attempt, p.created_at
+		FROM payments p
+		WHERE p.order_id = ANY($1) OR (p.created_at >= $2 AND p.created_at < $3)
+		ORDER BY p.order_id, p.attempt;
+	`, orderIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("select payments: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Payment
+	for rows.Next() {
+		var p Payment
+		if err := rows.Scan(&p.ID, &p.OrderID, &p.UserID, &p.AmountMinor, &p.Currency, &p.Status, &p.Refunded, &p.Reason, &p.Attempt, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan payment: %w", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func outboxBacklog(ctx context.Context, db *pgxpool.Pool, table string, before time.Time) (OutboxBacklog, error) {
+	b := OutboxBacklog{Table: table}
+	if err := db.QueryRow(ctx, `
+		SELECT count(*), min(created_at)
+		FROM `+pgx.Identifier{table}.Sanitize()+`
+		WHERE published_at IS NULL AND created_at < $1;
+	`, before).Scan(&b.Pending, &b.Oldest); err != nil {
+		return b, fmt.Errorf("outbox backlog %s: %w", table, err)
+	}
+	return b, nil
+}
+
+// fix — событие, которое должно было дойти, в outbox сервиса-источника. Получатели идемпотентны:
+// orders не меняет уже применённый статус, payments не создаёт вторую попытку с тем же номером.
+func (r *Reconciler) fix(ctx context.Context, d *Discrepancy) error {
+	switch d.Kind {
+	case KindPaymentMissing:
+		o := d.order
+		return insertOutbox(ctx, r.orders, "orders_outbox", "order", o.ID, r.cfg.OrdersTopic, "order.created", map[string]any{
+			"event":        "order.created",
+			"version":      1,
+			"order_id":     o.ID,
+			"user_id":      o.UserID,
+			"amount_cents": o.AmountMinor,
+			"currency":     o.Currency,
+			"status":       o.Status,
+			"created_at":   o.CreatedAt.UTC(),
+		})
+	case KindPaymentNotApplied, KindFailureNotApplied:
+		p := d.payment
+		return insertOutbox(ctx, r.payments, "payments_outbox", "payment", p.ID, r.cfg.PaymentsTopic, "payment."+p.Status, map[string]any{
+			"event":        "payment." + p.Status,
+			"version":      2,
+			"payment_id":   p.ID,
+			"order_id":     p.OrderID,
+			"user_id":      p.UserID,
+			"amount_cents": p.AmountMinor,
+			"currency":     p.Currency,
+			"status":       p.Status,
+			"processed_at": p.CreatedAt.UTC(),
+			"reason":       p.Reason,
+			"attempt":      p.Attempt,
+			"max_attempts": r.cfg.MaxAttempts,
+			"retryable":    retryable(p, r.cfg.MaxAttempts),
+		})
+	default:
+		return fmt.Errorf("%s is not fixable", d.Kind)
+	}
+}
+
+// retryableReasons — как в payments billing: повторять имеет смысл только эти отказы (card_declined — нет).
+var retryableReasons = map[string]bool{
+	"insufficient_funds": true,
+}
+
+// retryable — поле retryable переотправленного payment.failed, как его выставил бы payments.
+func retryable(p *Payment, maxAttempts int) bool {
+	return p.Status == "failed" && p.Reason != nil && retryableReasons[*p.Reason] && p.Attempt < maxAttempts
+}
+
+// insertOutbox — key = order_id: события заказа идут в одну партицию по порядку.
+func insertOutbox(ctx context.Context, db *pgxpool.Pool, table, aggType string, aggID uuid.UUID, topic, event string, payload map[string]any) error {
+	orderID, _ := payload["order_id"].(uuid.UUID)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	headers, err := json.Marshal([]struct{ K, V string }{
+		{K: "event-type", V: event},
+		{K: "source", V: "reconcile"},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal headers: %w", err)
+	}
+	if _, err := db.Exec(ctx, `
+		INSERT INTO `+pgx.Identifier{table}.Sanitize()+` (agg_type, agg_id, topic, key, headers, payload)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6::jsonb);
+	`, aggType, aggID, topic, orderID[:], headers, body); err != nil {
+		return fmt.Errorf("insert %s: %w", table, err)
+	}
+	return nil
+}
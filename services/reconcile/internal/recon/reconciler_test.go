@@ -0,0 +1,72 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Ошибка одного исправления остаётся в отчёте, остальные расхождения всё равно исправляются.
+func TestFixAll_ContinuesAfterError(t *testing.T) {
+	r := New(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, Config{})
+	broken := uuid.New()
+	ds := []Discrepancy{
+		{Kind: KindPaymentMissing, OrderID: uuid.New(), Fixable: true},
+		{Kind: KindPaymentMissing, OrderID: broken, Fixable: true},
+		{Kind: KindAmountMismatch, OrderID: uuid.New()},
+		{Kind: KindFailureNotApplied, OrderID: uuid.New(), Fixable: true},
+	}
+
+	var called int
+	r.fixAll(context.Background(), ds, func(_ context.Context, d *Discrepancy) error {
+		called++
+		if d.OrderID == broken {
+			return errors.New("insert orders_outbox: connection reset")
+		}
+		return nil
+	})
+
+	if called != 3 {
+		t.Fatalf("fix called %d times, want 3 (fixable only)", called)
+	}
+	if !ds[0].Fixed || !ds[3].Fixed {
+		t.Fatalf("fixable discrepancies after the error are not fixed: %+v", ds)
+	}
+	if ds[1].Fixed || !strings.Contains(ds[1].FixError, "connection reset") {
+		t.Fatalf("failed fix: fixed=%v error=%q", ds[1].Fixed, ds[1].FixError)
+	}
+	if ds[2].Fixed || ds[2].FixError != "" {
+		t.Fatalf("not fixable discrepancy touched: %+v", ds[2])
+	}
+
+	rep := &Report{Discrepancies: ds}
+	if rep.OK() {
+		t.Fatal("report with a failed fix is OK")
+	}
+	if md := rep.Markdown(); !strings.Contains(md, "ошибка: insert orders_outbox") {
+		t.Fatalf("markdown does not show the fix error:\n%s", md)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	declined, funds := "card_declined", "insufficient_funds"
+	cases := []struct {
+		p    Payment
+		want bool
+	}{
+		{Payment{Status: "failed", Reason: &funds, Attempt: 1}, true},
+		{Payment{Status: "failed", Reason: &funds, Attempt: 3}, false},
+		{Payment{Status: "failed", Reason: &declined, Attempt: 1}, false},
+		{Payment{Status: "confirmed", Attempt: 1}, false},
+	}
+	for _, tc := range cases {
+		if got := retryable(&tc.p, 3); got != tc.want {
+			t.Fatalf("%+v: retryable = %v, want %v", tc.p, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package recon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Report struct {
+	From          time.Time       `json:"from"`
+	To            time.Time       `json:"to"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+	Orders        int             `json:"orders_checked"`
+	Payments      int             `json:"payments_checked"`
+	Discrepancies []Discrepancy   `json:"discrepancies"`
+	Outbox        []OutboxBacklog `json:"outbox"`
+}
+
+// OutboxBacklog — неопубликованные события старше grace: застряли между outbox и Kafka.
+type OutboxBacklog struct {
+	Table   string     `json:"table"`
+	Pending int64      `json:"pending"`
+	Oldest  *time.Time `json:"oldest,omitempty"`
+}
+
+func (r *Report) OK() bool {
+	for _, b := range r.Outbox {
+		if b.Pending > 0 {
+			return false
+		}
+	}
+	for _, d := range r.Discrepancies {
+		if !d.Fixed {
+			return false
+		}
+	}
+	return true
+}
+
+// Counts — число расхождений по виду.
+func (r *Report) Counts() map[string]int {
+	out := make(map[string]int)
+	for _, d := range r.Discrepancies {
+		out[d.Kind]++
+	}
+	return out
+}
+
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Сверка orders ↔ payments\n\n")
+	fmt.Fprintf(&b, "Окно: %s — %s (сформирован %s)\n\n",
+		r.From.UTC().Format(time.RFC3339), r.To.UTC().Format(time.RFC3339), r.GeneratedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Проверено заказов: %d, платежей: %d, расхождений: %d\n\n", r.Orders, r.Payments, len(r.Discrepancies))
+
+	if counts := r.Counts(); len(counts) > 0 {
+		kinds := make([]string, 0, len(counts))
+		for k := range counts {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		b.WriteString("## Сводка\n\n| Вид | Кол-во |\n|---|---:|\n")
+		for _, k := range kinds {
+			fmt.Fprintf(&b, "| `%s` | %d |\n", k, counts[k])
+		}
+		b.WriteString("\n## Расхождения\n\n| Вид | Заказ | Статус заказа | Платёж | Статус платежа | Детали | Исправлено |\n|---|---|---|---|---|---|---|\n")
+		for _, d := range r.Discrepancies {
+			pay := "—"
+			if d.PaymentID != nil {
+				pay = "`" + d.PaymentID.String() + "`"
+			}
+			fixed := "—"
+			switch {
+			case d.Fixed:
+				fixed = "событие отправлено"
+			case d.FixError != "":
+				fixed = "ошибка: " + d.FixError
+			case d.Fixable:
+				fixed = "нет (`-fix`)"
+			}
+			fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s | %s | %s | %s |\n",
+				d.Kind, d.OrderID, orDash(d.OrderStatus), pay, orDash(d.PaymentStatus), d.Detail, fixed)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Outbox) > 0 {
+		b.WriteString("## Outbox\n\n| Таблица | Не опубликовано | Самое старое |\n|---|---:|---|\n")
+		for _, o := range r.Outbox {
+			oldest := "—"
+			if o.Oldest != nil {
+				oldest = o.Oldest.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(&b, "| `%s` | %d | %s |\n", o.Table, o.Pending, oldest)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Discrepancies) == 0 && r.OK() {
+		b.WriteString("Расхождений нет.\n")
+	}
+	return b.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
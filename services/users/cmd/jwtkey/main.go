@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// jwtkey — ключ подписи для jwt.keys users: приватный PKCS#8 в -out, публичный (PKIX) в -out.pub.
+//
+//	go run ./services/users/cmd/jwtkey -alg EdDSA -out jwt-2026-10.pem
+func main() {
+	alg := flag.String("alg", "EdDSA", "key algorithm: EdDSA|RS256")
+	bits := flag.Int("bits", 3072, "RSA key size")
+	out := flag.String("out", "", "private key file (public key goes to <out>.pub)")
+	flag.Parse()
+	if *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var (
+		priv any
+		pub  any
+	)
+	switch *alg {
+	case "EdDSA":
+		p, k, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			fail(err)
+		}
+		priv, pub = k, p
+	case "RS256":
+		k, err := rsa.GenerateKey(rand.Reader, *bits)
+		if err != nil {
+			fail(err)
+		}
+		priv, pub = k, &k.PublicKey
+	default:
+		fail(fmt.Errorf("unknown alg %q", *alg))
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		fail(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile(*out, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0o600); err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile(*out+".pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o644); err != nil {
+		fail(err)
+	}
+	fmt.Printf("%s key written to %s (public: %s.pub)\n", *alg, *out, *out)
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "jwtkey: %v\n", err)
+	os.Exit(1)
+}
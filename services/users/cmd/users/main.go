@@ -12,12 +12,21 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+
 	"goshop/pkg/httpx"
 	"goshop/pkg/jwtauth"
 	"goshop/pkg/logger"
 	"goshop/pkg/postgres"
+	"goshop/pkg/ratelimit"
+	"goshop/pkg/revocation"
 	"goshop/services/users/config"
+	"goshop/services/users/internal/adapters/challengeredis"
 	httpmod "goshop/services/users/internal/adapters/http"
+	"goshop/services/users/internal/adapters/loginguard"
+	"goshop/services/users/internal/adapters/mailer"
+	"goshop/services/users/internal/adapters/oidc"
+	"goshop/services/users/internal/adapters/repo/sessionpg"
 	"goshop/services/users/internal/adapters/repo/userpg"
 	"goshop/services/users/internal/app"
 )
@@ -86,20 +95,145 @@ func main() {
 	repo := userpg.NewRepo(pool)
 	svc := app.NewService(repo, 12)
 
-	// JWT
+	// JWT: асимметричные ключи (если заданы) — публикуются в /.well-known/jwks.json
+	specs := make([]jwtauth.KeySpec, 0, len(cfg.JWT.Keys))
+	for _, k := range cfg.JWT.Keys {
+		specs = append(specs, jwtauth.KeySpec{Kid: k.Kid, PrivateKeyFile: k.PrivateKeyFile, PublicKeyFile: k.PublicKeyFile})
+	}
+	jwtKeys, err := jwtauth.LoadKeys(specs)
+	if err != nil {
+		log.Error("jwt: load keys failed", slog.Any("err", err))
+		return
+	}
 	jwtm := jwtauth.New(jwtauth.Config{
 		Secret:          cfg.JWT.Secret,
+		Keys:            jwtKeys,
+		ActiveKid:       cfg.JWT.ActiveKid,
 		Issuer:          cfg.JWT.Issuer,
 		AccessTTL:       cfg.JWT.AccessTTL,
 		RefreshTTL:      cfg.JWT.RefreshTTL,
 		AccessAudience:  cfg.JWT.AccessAudience,
 		RefreshAudience: cfg.JWT.RefreshAudience,
 	})
-	log.Info("jwt: verifier initialized", "issuer", cfg.JWT.Issuer)
+	log.Info("jwt: verifier initialized",
+		slog.String("issuer", cfg.JWT.Issuer),
+		slog.String("active_kid", jwtm.ActiveKid()),
+		slog.Int("jwks_keys", len(jwtKeys)),
+	)
 
 	// HTTP module + server
-	usersHTTP := httpmod.NewModule(log, pool, svc, jwtm)
-	srv := httpx.NewServer(cfg.HTTP, log, httpx.WithMiddleware(metrics.GinMiddleware(httpm)), httpx.WithModules(usersHTTP))
+	httpOpts := []httpx.Option{httpx.WithMiddleware(metrics.GinMiddleware(httpm))}
+
+	// Redis: список отозванных сессий (access-токены после logout) и rate limiting
+	rds := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	if err := rds.Ping(ctx).Err(); err != nil {
+		log.Error("redis: connect failed", slog.String("addr", cfg.Redis.Addr), slog.Any("err", err))
+		return
+	}
+	defer func() { _ = rds.Close() }()
+	revoked := revocation.New(rds)
+
+	// Rate limiting (Redis token bucket)
+	if cfg.RateLimit.Enabled {
+		lim := ratelimit.New(rds, ratelimit.RulesFromConfig(cfg.RateLimit),
+			ratelimit.WithPrefix("rl:users:"),
+			ratelimit.WithObserver(metrics.NewRateLimitMetrics(m.Registry(), "goshop", "users")),
+		)
+		httpOpts = append(httpOpts, httpx.WithMiddleware(httpx.RateLimit(log, lim, jwtm)))
+		log.Info("http: rate limiting enabled", slog.Int("rules", len(cfg.RateLimit.Rules)))
+	}
+
+	// Почта: подтверждение email и сброс пароля
+	var mail app.Mailer
+	switch cfg.Mail.Driver {
+	case "smtp":
+		mail, err = mailer.NewSMTP(mailer.SMTPConfig{
+			Host:     cfg.Mail.SMTP.Host,
+			Port:     cfg.Mail.SMTP.Port,
+			Username: cfg.Mail.SMTP.Username,
+			Password: cfg.Mail.SMTP.Password,
+			From:     cfg.Mail.From,
+		})
+	default:
+		mail, err = mailer.NewFile(log, cfg.Mail.From, cfg.Mail.Dir)
+	}
+	if err != nil {
+		log.Error("mail: init failed", slog.String("driver", cfg.Mail.Driver), slog.Any("err", err))
+		return
+	}
+	log.Info("mail: initialized", slog.String("driver", cfg.Mail.Driver))
+	accounts := app.NewAccounts(log, repo, mail, app.AccountsConfig{
+		VerifyURL:            cfg.Accounts.VerifyURL,
+		ResetURL:             cfg.Accounts.ResetURL,
+		VerifyTTL:            cfg.Accounts.VerifyTTL,
+		ResetTTL:             cfg.Accounts.ResetTTL,
+		RequireVerifiedEmail: cfg.Accounts.RequireVerifiedEmail,
+	}, 12)
+
+	// 2FA: секреты TOTP зашифрованы в БД, mfa_token второго шага логина — в Redis
+	mfaKey, _ := cfg.MFA.Key() // проверен в Validate
+	mfa, err := app.NewMFA(repo, challengeredis.New(rds), app.MFAConfig{
+		Issuer:        cfg.MFA.Issuer,
+		EncryptionKey: mfaKey,
+		ChallengeTTL:  cfg.MFA.ChallengeTTL,
+		MaxAttempts:   cfg.MFA.MaxAttempts,
+		Skew:          cfg.MFA.Skew,
+	})
+	if err != nil {
+		log.Error("mfa: init failed", slog.Any("err", err))
+		return
+	}
+
+	// Подбор пароля: паузы и временные блокировки по аккаунту и IP
+	var guard *loginguard.Guard
+	if cfg.LoginGuard.Enabled {
+		guard = loginguard.New(rds, loginguard.Config{
+			Window:           cfg.LoginGuard.Window,
+			FreeAttempts:     cfg.LoginGuard.FreeAttempts,
+			BaseDelay:        cfg.LoginGuard.BaseDelay,
+			MaxDelay:         cfg.LoginGuard.MaxDelay,
+			AccountLockAfter: cfg.LoginGuard.AccountLockAfter,
+			AccountLockout:   cfg.LoginGuard.AccountLockout,
+			IPLockAfter:      cfg.LoginGuard.IPLockAfter,
+			IPLockout:        cfg.LoginGuard.IPLockout,
+			AttemptTimeout:   cfg.LoginGuard.AttemptTimeout,
+		}, loginguard.WithObserver(metrics.NewLoginMetrics(m.Registry(), "goshop", "users")))
+		log.Info("users.login: brute-force guard enabled")
+	}
+
+	// RBAC: роли из user_roles — в claim "roles" access-токена
+	roles := app.NewRoles(repo)
+
+	// Вход через OIDC-провайдеров: discovery — при первом входе, недоступный провайдер не мешает старту
+	var social *app.Social
+	if len(cfg.OIDC.Providers) > 0 {
+		providers := make([]app.IdentityProvider, 0, len(cfg.OIDC.Providers))
+		for name, p := range cfg.OIDC.Providers {
+			providers = append(providers, oidc.New(oidc.Config{
+				Name:         name,
+				Issuer:       p.Issuer,
+				ClientID:     p.ClientID,
+				ClientSecret: p.ClientSecret,
+				RedirectURL:  p.RedirectURL,
+				Scopes:       p.Scopes,
+			}))
+			log.Info("users.oidc: provider configured", slog.String("provider", name), slog.String("issuer", p.Issuer))
+		}
+		social = app.NewSocial(repo, challengeredis.NewOIDCStates(rds), providers, app.SocialConfig{
+			StateTTL: cfg.OIDC.StateTTL,
+		})
+	}
+
+	// Профиль, смена пароля, удаление аккаунта (user.deleted — через users_outbox)
+	profiles := app.NewProfiles(repo, 12)
+
+	sessions := sessionpg.New(pool)
+	usersHTTP := httpmod.NewModule(log, pool, svc, jwtm, sessions, revoked, accounts, mfa, guard, roles, social, profiles)
+	srv := httpx.NewServer(cfg.HTTP, log, append(httpOpts, httpx.WithModules(usersHTTP))...)
 
 	// HTTP Listen
 	go func() {
@@ -109,6 +243,19 @@ func main() {
 		}
 	}()
 
+	// Sessions cleanup: истёкшие/отозванные старше sessions.retention
+	go func() {
+		err := sessions.RunCleanup(ctx, log, sessionpg.CleanupConfig{
+			Retention: cfg.Sessions.Retention,
+			Interval:  cfg.Sessions.CleanupInterval,
+			Batch:     cfg.Sessions.CleanupBatch,
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Error("users.sessions: cleanup stopped with error", slog.Any("err", err))
+			stop()
+		}
+	}()
+
 	// Wait for signal
 	<-ctx.Done()
 	log.Info("users: shutdown: signal received")
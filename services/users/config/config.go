@@ -1,19 +1,109 @@
 package config
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"time"
+
 	cfg "goshop/pkg/config"
 )
 
 type Users struct {
-	AppName   string        `mapstructure:"app_name"`
-	HTTP      cfg.HTTP      `mapstructure:"http"`
-	Postgres  cfg.Postgres  `mapstructure:"postgres"`
-	Redis     cfg.Redis     `mapstructure:"redis"`
-	Logger    cfg.Logger    `mapstructure:"logger"`
-	Telemetry cfg.Telemetry `mapstructure:"telemetry"`
-	JWT       cfg.JWT       `mapstructure:"jwt"`
+	AppName    string        `mapstructure:"app_name"`
+	HTTP       cfg.HTTP      `mapstructure:"http"`
+	Postgres   cfg.Postgres  `mapstructure:"postgres"`
+	Redis      cfg.Redis     `mapstructure:"redis"`
+	Logger     cfg.Logger    `mapstructure:"logger"`
+	Telemetry  cfg.Telemetry `mapstructure:"telemetry"`
+	JWT        cfg.JWT       `mapstructure:"jwt"`
+	RateLimit  cfg.RateLimit `mapstructure:"rate_limit"`
+	Sessions   Sessions      `mapstructure:"sessions"`
+	Mail       Mail          `mapstructure:"mail"`
+	Accounts   Accounts      `mapstructure:"accounts"`
+	MFA        MFA           `mapstructure:"mfa"`
+	LoginGuard LoginGuard    `mapstructure:"login_guard"`
+	OIDC       OIDC          `mapstructure:"oidc"`
+}
+
+// Sessions — фоновая очистка истёкших и отозванных сессий.
+type Sessions struct {
+	Retention       time.Duration `mapstructure:"retention"`
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+	CleanupBatch    int           `mapstructure:"cleanup_batch"`
+}
+
+// Mail — исходящая почта: smtp в проде, log — письма в лог (и .eml в dir) для локальной разработки.
+type Mail struct {
+	Driver string `mapstructure:"driver"`
+	From   string `mapstructure:"from"`
+	Dir    string `mapstructure:"dir"`
+	SMTP   SMTP   `mapstructure:"smtp"`
+}
+
+type SMTP struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// Accounts — подтверждение email и сброс пароля. URL — страницы фронта, токен добавляется ?token=.
+type Accounts struct {
+	VerifyURL            string        `mapstructure:"verify_url"`
+	ResetURL             string        `mapstructure:"reset_url"`
+	VerifyTTL            time.Duration `mapstructure:"verify_ttl"`
+	ResetTTL             time.Duration `mapstructure:"reset_ttl"`
+	RequireVerifiedEmail bool          `mapstructure:"require_verified_email"`
+}
+
+// MFA — TOTP 2FA. EncryptionKey — base64 32 байт (AES-256-GCM для секретов TOTP в БД).
+type MFA struct {
+	Issuer        string        `mapstructure:"issuer"`
+	EncryptionKey string        `mapstructure:"encryption_key"`
+	ChallengeTTL  time.Duration `mapstructure:"challenge_ttl"`
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	Skew          int           `mapstructure:"skew"`
+}
+
+// Key — декодированный encryption_key.
+func (m MFA) Key() ([]byte, error) {
+	k, err := base64.StdEncoding.DecodeString(m.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption_key: %w", err)
+	}
+	if len(k) != 32 {
+		return nil, fmt.Errorf("encryption_key: want 32 bytes, got %d", len(k))
+	}
+	return k, nil
+}
+
+// LoginGuard — защита от подбора пароля (счётчики неудач в Redis); нули — значения по умолчанию.
+type LoginGuard struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	Window           time.Duration `mapstructure:"window"`
+	FreeAttempts     int           `mapstructure:"free_attempts"`
+	BaseDelay        time.Duration `mapstructure:"base_delay"`
+	MaxDelay         time.Duration `mapstructure:"max_delay"`
+	AccountLockAfter int           `mapstructure:"account_lock_after"`
+	AccountLockout   time.Duration `mapstructure:"account_lockout"`
+	IPLockAfter      int           `mapstructure:"ip_lock_after"`
+	IPLockout        time.Duration `mapstructure:"ip_lockout"`
+	AttemptTimeout   time.Duration `mapstructure:"attempt_timeout"`
+}
+
+// OIDC — вход через внешних провайдеров; ключ providers — имя в URL (/v1/users/oidc/<name>).
+type OIDC struct {
+	StateTTL  time.Duration           `mapstructure:"state_ttl"`
+	Providers map[string]OIDCProvider `mapstructure:"providers"`
+}
+
+type OIDCProvider struct {
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
 }
 
 func (u *Users) Validate() error {
@@ -32,8 +122,71 @@ func (u *Users) Validate() error {
 	if err := u.Telemetry.Validate(); err != nil {
 		return fmt.Errorf("telemetry: %w", err)
 	}
-	if u.JWT.Secret == "" {
-		return errors.New("jwt.secret is required (no defaults for secrets)")
+	if u.JWT.Secret == "" && len(u.JWT.Keys) == 0 {
+		return errors.New("jwt.secret or jwt.keys is required (no defaults for secrets)")
+	}
+	if err := u.RateLimit.Validate(); err != nil {
+		return fmt.Errorf("rate_limit: %w", err)
+	}
+	if u.Sessions.Retention <= 0 {
+		u.Sessions.Retention = 7 * 24 * time.Hour
+	}
+	if u.Sessions.CleanupInterval <= 0 {
+		u.Sessions.CleanupInterval = time.Hour
+	}
+	if u.Sessions.CleanupBatch <= 0 {
+		u.Sessions.CleanupBatch = 1000
+	}
+	if u.Mail.Driver == "" {
+		u.Mail.Driver = "log"
+	}
+	if u.Mail.From == "" {
+		u.Mail.From = "goshop <no-reply@goshop.local>"
+	}
+	switch u.Mail.Driver {
+	case "log":
+	case "smtp":
+		if u.Mail.SMTP.Host == "" {
+			return errors.New("mail.smtp.host is required for mail.driver=smtp")
+		}
+	default:
+		return fmt.Errorf("mail.driver: unknown %q (log|smtp)", u.Mail.Driver)
+	}
+	if u.Accounts.VerifyTTL <= 0 {
+		u.Accounts.VerifyTTL = 48 * time.Hour
+	}
+	if u.Accounts.ResetTTL <= 0 {
+		u.Accounts.ResetTTL = time.Hour
+	}
+	if u.MFA.EncryptionKey == "" {
+		return errors.New("mfa.encryption_key is required (no defaults for secrets)")
+	}
+	if _, err := u.MFA.Key(); err != nil {
+		return fmt.Errorf("mfa: %w", err)
+	}
+	if u.MFA.Issuer == "" {
+		u.MFA.Issuer = "goshop"
+	}
+	if u.MFA.ChallengeTTL <= 0 {
+		u.MFA.ChallengeTTL = 5 * time.Minute
+	}
+	if u.MFA.MaxAttempts <= 0 {
+		u.MFA.MaxAttempts = 5
+	}
+	if u.MFA.Skew <= 0 {
+		u.MFA.Skew = 1
+	}
+	if u.OIDC.StateTTL <= 0 {
+		u.OIDC.StateTTL = 10 * time.Minute
+	}
+	for name, p := range u.OIDC.Providers {
+		if p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return fmt.Errorf("oidc.providers.%s: issuer, client_id and redirect_url are required", name)
+		}
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "email", "profile"}
+			u.OIDC.Providers[name] = p
+		}
 	}
 	return nil
 }
@@ -41,6 +194,15 @@ func (u *Users) Validate() error {
 func (u Users) Redact() any {
 	u.JWT.Secret = "***"
 	u.Postgres.Password = "***"
+	u.Redis.Password = "***"
+	u.Mail.SMTP.Password = "***"
+	u.MFA.EncryptionKey = "***"
+	providers := make(map[string]OIDCProvider, len(u.OIDC.Providers))
+	for name, p := range u.OIDC.Providers {
+		p.ClientSecret = "***"
+		providers[name] = p
+	}
+	u.OIDC.Providers = providers
 	return u
 }
 
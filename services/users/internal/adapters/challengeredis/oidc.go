@@ -0,0 +1,64 @@
+package challengeredis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"goshop/services/users/internal/app"
+)
+
+const DefaultOIDCPrefix = "users:oidc:state:"
+
+// OIDCStates — state входа через провайдера: ключ — sha256 state, значение — JSON
+// {provider, nonce, verifier}, живёт StateTTL и читается один раз (GETDEL).
+type OIDCStates struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+var _ app.OIDCStateStore = (*OIDCStates)(nil)
+
+func NewOIDCStates(rdb *redis.Client) *OIDCStates {
+	return &OIDCStates{rdb: rdb, prefix: DefaultOIDCPrefix}
+}
+
+func (s *OIDCStates) Save(ctx context.Context, state string, st app.OIDCState, ttl time.Duration) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if err := s.rdb.Set(ctx, s.key(state), b, ttl).Err(); err != nil {
+		return fmt.Errorf("oidc state: save: %w", err)
+	}
+	return nil
+}
+
+func (s *OIDCStates) Take(ctx context.Context, state string) (app.OIDCState, error) {
+	if state == "" {
+		return app.OIDCState{}, app.ErrInvalidState
+	}
+	b, err := s.rdb.GetDel(ctx, s.key(state)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return app.OIDCState{}, app.ErrInvalidState
+	}
+	if err != nil {
+		return app.OIDCState{}, fmt.Errorf("oidc state: take: %w", err)
+	}
+	var st app.OIDCState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return app.OIDCState{}, app.ErrInvalidState
+	}
+	return st, nil
+}
+
+func (s *OIDCStates) key(state string) string {
+	h := sha256.Sum256([]byte(state))
+	return s.prefix + hex.EncodeToString(h[:])
+}
@@ -0,0 +1,108 @@
+package challengeredis
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"goshop/services/users/internal/app"
+)
+
+const DefaultPrefix = "users:mfa:challenge:"
+
+// Store — mfa_token второго шага логина в Redis. Ключ — sha256 токена, сам токен есть только
+// у клиента; hash {uid, email, attempts} живёт ChallengeTTL.
+type Store struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+var _ app.ChallengeStore = (*Store)(nil)
+
+func New(rdb *redis.Client) *Store {
+	return &Store{rdb: rdb, prefix: DefaultPrefix}
+}
+
+func (s *Store) Create(ctx context.Context, ch app.Challenge, ttl time.Duration) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	tok := base64.RawURLEncoding.EncodeToString(b)
+	key := s.key(tok)
+
+	_, err := s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, key, "uid", ch.UserID.String(), "email", ch.Email, "attempts", 0)
+		p.PExpire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("mfa challenge: create: %w", err)
+	}
+	return tok, nil
+}
+
+func (s *Store) Peek(ctx context.Context, token string) (app.Challenge, error) {
+	res, err := s.rdb.HMGet(ctx, s.key(token), "uid", "email").Result()
+	if err != nil {
+		return app.Challenge{}, fmt.Errorf("mfa challenge: peek: %w", err)
+	}
+	uidStr, _ := res[0].(string)
+	email, _ := res[1].(string)
+	uid, err := uuid.Parse(uidStr)
+	if err != nil {
+		return app.Challenge{}, app.ErrInvalidChallenge
+	}
+	return app.Challenge{UserID: uid, Email: email}, nil
+}
+
+func (s *Store) Attempt(ctx context.Context, token string, maxAttempts int) (app.Challenge, error) {
+	res, err := attempt.Run(ctx, s.rdb, []string{s.key(token)}, maxAttempts).StringSlice()
+	if errors.Is(err, redis.Nil) {
+		return app.Challenge{}, app.ErrInvalidChallenge
+	}
+	if err != nil {
+		return app.Challenge{}, fmt.Errorf("mfa challenge: attempt: %w", err)
+	}
+	uid, err := uuid.Parse(res[0])
+	if err != nil {
+		return app.Challenge{}, app.ErrInvalidChallenge
+	}
+	return app.Challenge{UserID: uid, Email: res[1]}, nil
+}
+
+func (s *Store) Consume(ctx context.Context, token string) (bool, error) {
+	n, err := s.rdb.Del(ctx, s.key(token)).Result()
+	if err != nil {
+		return false, fmt.Errorf("mfa challenge: consume: %w", err)
+	}
+	return n == 1, nil
+}
+
+func (s *Store) key(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return s.prefix + hex.EncodeToString(h[:])
+}
+
+// attempt: KEYS[1] — challenge; ARGV[1] — максимум попыток. Последняя разрешённая попытка
+// ещё проходит, следующая удаляет challenge — подбирать код дальше нельзя.
+// Возвращает {uid, email} или nil.
+var attempt = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return false
+end
+local n = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+if n > tonumber(ARGV[1]) then
+  redis.call('DEL', KEYS[1])
+  return false
+end
+return redis.call('HMGET', KEYS[1], 'uid', 'email')
+`)
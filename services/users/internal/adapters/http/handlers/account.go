@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"log/slog"
+
+	"goshop/services/users/internal/app"
+)
+
+type verifyReq struct {
+	Token string `json:"token"`
+}
+
+// emailReq — запросы письма по адресу (повтор подтверждения, сброс пароля).
+type emailReq struct {
+	Email string `json:"email"`
+}
+
+type resetReq struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (h *UsersHandlers) VerifyEmail(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	var in verifyReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		return
+	}
+
+	userID, err := h.accounts.VerifyEmail(c.Request.Context(), in.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidToken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired token"})
+			return
+		case errors.Is(err, app.ErrEmailTaken):
+			// ссылка смены email, а адрес за это время зарегистрировали
+			c.JSON(http.StatusConflict, gin.H{"error": "email already taken"})
+			return
+		}
+		l.Error("users.verify: failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	l.Info("users.verify: email verified", slog.String("user_id", userID.String()))
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification всегда отвечает 202, как и ForgotPassword.
+func (h *UsersHandlers) ResendVerification(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	var in emailReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		return
+	}
+
+	if err := h.accounts.ResendVerification(c.Request.Context(), in.Email); err != nil {
+		l.Error("users.verify_resend: failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ForgotPassword всегда отвечает 202: по ответу нельзя узнать, зарегистрирован ли адрес.
+func (h *UsersHandlers) ForgotPassword(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	var in emailReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		return
+	}
+
+	if err := h.accounts.ForgotPassword(c.Request.Context(), in.Email); err != nil {
+		l.Error("users.password_forgot: failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword — новый пароль по токену из письма; все сессии пользователя отзываются.
+func (h *UsersHandlers) ResetPassword(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	var in resetReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		return
+	}
+
+	userID, err := h.accounts.ResetPassword(c.Request.Context(), in.Token, in.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrWeakPassword):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "weak password"})
+		case errors.Is(err, app.ErrInvalidToken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired token"})
+		default:
+			l.Error("users.password_reset: failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+
+	// пароль уже сменён; не удалось отозвать сессии — 500: войти с новым паролем и сделать logout_all
+	_, families, err := h.sessions.RevokeAll(c.Request.Context(), userID)
+	if err != nil {
+		l.Error("users.password_reset: revoke sessions failed",
+			slog.String("user_id", userID.String()),
+			slog.Any("err", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	sids := make([]string, 0, len(families))
+	for _, f := range families {
+		sids = append(sids, f.String())
+	}
+	h.revokeAccess(c, sids...)
+
+	l.Info("users.password_reset: password changed",
+		slog.String("user_id", userID.String()),
+		slog.Int("revoked_sessions", len(families)),
+	)
+	c.Status(http.StatusNoContent)
+}
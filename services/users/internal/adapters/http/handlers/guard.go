@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"goshop/services/users/internal/adapters/loginguard"
+)
+
+// guardCheck — перед проверкой пароля: при активной паузе или блокировке отвечает 429
+// с Retry-After и возвращает false. Недоступный Redis логин не блокирует.
+func (h *UsersHandlers) guardCheck(c *gin.Context, l *slog.Logger, email, ip string) bool {
+	if h.guard == nil {
+		return true
+	}
+	d, err := h.guard.Check(c.Request.Context(), email, ip)
+	if err != nil {
+		l.Warn("users.login: guard check failed", slog.Any("err", err))
+		return true
+	}
+	if d.Allowed {
+		return true
+	}
+	l.Warn("users.login: blocked",
+		slog.String("scope", d.Scope),
+		slog.Bool("locked", d.Locked),
+		slog.Int64("retry_after_ms", d.RetryAfter.Milliseconds()),
+	)
+	tooManyAttempts(c, d)
+	return false
+}
+
+// guardFail — неудачная попытка (пароль или второй фактор); новая пауза вступает со следующей.
+func (h *UsersHandlers) guardFail(c *gin.Context, l *slog.Logger, email, ip string) {
+	if h.guard == nil {
+		return
+	}
+	d, err := h.guard.Fail(c.Request.Context(), email, ip)
+	if err != nil {
+		l.Warn("users.login: guard fail failed", slog.Any("err", err))
+		return
+	}
+	if d.Locked {
+		l.Warn("users.login: locked out",
+			slog.String("scope", d.Scope),
+			slog.Int64("lockout_ms", d.RetryAfter.Milliseconds()),
+		)
+	}
+}
+
+// guardRelease — пароль верен, но вход ещё не завершён: резерв снимается, счётчики остаются.
+func (h *UsersHandlers) guardRelease(c *gin.Context, l *slog.Logger, email, ip string) {
+	if h.guard == nil {
+		return
+	}
+	if err := h.guard.Release(c.Request.Context(), email, ip); err != nil {
+		l.Warn("users.login: guard release failed", slog.Any("err", err))
+	}
+}
+
+// guardSuccess — вход завершён (выданы токены): счётчик аккаунта сбрасывается.
+func (h *UsersHandlers) guardSuccess(c *gin.Context, l *slog.Logger, email, ip string) {
+	if h.guard == nil {
+		return
+	}
+	if err := h.guard.Success(c.Request.Context(), email, ip); err != nil {
+		l.Warn("users.login: guard reset failed", slog.Any("err", err))
+	}
+}
+
+func tooManyAttempts(c *gin.Context, d loginguard.Decision) {
+	c.Header("Retry-After", strconv.Itoa(d.RetryAfterSeconds()))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many failed attempts"})
+}
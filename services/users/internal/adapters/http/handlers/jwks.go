@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS — GET /.well-known/jwks.json. Кэш короткий: после добавления ключа при ротации
+// проверяющие должны увидеть его до того, как им начнут подписывать.
+func (h *UsersHandlers) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtm.JWKS())
+}
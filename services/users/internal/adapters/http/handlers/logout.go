@@ -8,6 +8,7 @@ import (
 	"log/slog"
 
 	"goshop/pkg/httpx"
+	"goshop/pkg/jwtauth"
 )
 
 type logoutReq struct {
@@ -43,6 +44,7 @@ func (h *UsersHandlers) Logout(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
 	}
+	h.revokeAccess(c, sessionID(claims))
 
 	c.Status(http.StatusNoContent)
 }
@@ -64,12 +66,47 @@ func (h *UsersHandlers) LogoutAll(c *gin.Context) {
 		return
 	}
 
-	n, err := h.sessions.RevokeAll(c.Request.Context(), userID)
+	n, families, err := h.sessions.RevokeAll(c.Request.Context(), userID)
 	if err != nil {
 		l.Error("users.logout_all: revoke all failed", slog.Any("err", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
 	}
+	sids := make([]string, 0, len(families)+1)
+	for _, f := range families {
+		sids = append(sids, f.String())
+	}
+	// текущий токен — даже если его сессия уже была отозвана
+	sids = append(sids, claims.SessionID)
+	h.revokeAccess(c, sids...)
 
 	c.JSON(http.StatusOK, gin.H{"revoked": n})
 }
+
+// sessionID — sid семейства из refresh; у токенов, выданных до появления sid, — сам jti.
+func sessionID(claims *jwtauth.Claims) string {
+	if claims.SessionID != "" {
+		return claims.SessionID
+	}
+	return claims.ID
+}
+
+// revokeAccess — внести sid в список отозванных: access-токены сессий перестают приниматься
+// сразу, а не через AccessTTL. Ошибка не отменяет отзыв сессий в БД — только логируется.
+func (h *UsersHandlers) revokeAccess(c *gin.Context, sids ...string) {
+	if h.revoked == nil {
+		return
+	}
+	out := sids[:0]
+	for _, s := range sids {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	if err := h.revoked.Revoke(c.Request.Context(), h.jwtm.AccessTTL(), out...); err != nil {
+		ReqLog(c, h.log).Error("users: access token revocation failed",
+			slog.Int("sessions", len(out)),
+			slog.Any("err", err),
+		)
+	}
+}
@@ -1,12 +1,37 @@
 package handlers
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
 	"goshop/pkg/httpx"
+	"goshop/services/users/internal/app"
+	domain "goshop/services/users/internal/domain/user"
 )
 
+type meResp struct {
+	UID            string          `json:"uid"`
+	Email          string          `json:"email"`
+	EmailVerified  bool            `json:"email_verified"`
+	Name           string          `json:"name,omitempty"`
+	Phone          string          `json:"phone,omitempty"`
+	DefaultAddress *domain.Address `json:"default_address,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	// из access-токена
+	Issuer    string           `json:"issuer"`
+	Subject   string           `json:"subject"`
+	IssuedAt  *jwt.NumericDate `json:"issued_at"`
+	ExpiresAt *jwt.NumericDate `json:"expires_at"`
+}
+
+// Me — профиль из БД; без профилей (h.profiles == nil) — только claims токена.
 func (h *UsersHandlers) Me(c *gin.Context) {
 	noCache(c)
 
@@ -19,12 +44,48 @@ func (h *UsersHandlers) Me(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"uid":        claims.UserID,
-		"email":      claims.Email,
-		"issuer":     claims.Issuer,
-		"subject":    claims.Subject,
-		"issued_at":  claims.IssuedAt,
-		"expires_at": claims.ExpiresAt,
-	})
+	if h.profiles == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"uid":        claims.UserID,
+			"email":      claims.Email,
+			"issuer":     claims.Issuer,
+			"subject":    claims.Subject,
+			"issued_at":  claims.IssuedAt,
+			"expires_at": claims.ExpiresAt,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	u, err := h.profiles.Get(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, app.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		l.Error("users.me: get failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	resp := toMeResp(u)
+	resp.Issuer, resp.Subject, resp.IssuedAt, resp.ExpiresAt = claims.Issuer, claims.Subject, claims.IssuedAt, claims.ExpiresAt
+	c.JSON(http.StatusOK, resp)
+}
+
+func toMeResp(u domain.User) meResp {
+	return meResp{
+		UID:            u.ID.String(),
+		Email:          u.Email,
+		EmailVerified:  u.EmailVerifiedAt != nil,
+		Name:           u.Profile.Name,
+		Phone:          u.Profile.Phone,
+		DefaultAddress: u.Profile.DefaultAddress,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
+	}
 }
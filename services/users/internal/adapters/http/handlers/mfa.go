@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"log/slog"
+
+	"goshop/pkg/httpx"
+	"goshop/services/users/internal/app"
+)
+
+type mfaEnrollResp struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type mfaCodeReq struct {
+	Code string `json:"code"`
+}
+
+type mfaConfirmResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type mfaLoginReq struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"` // TOTP или код восстановления
+}
+
+type mfaDisableReq struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// LoginMFA — второй шаг логина: mfa_token из /login и код; при успехе — обычная пара токенов.
+func (h *UsersHandlers) LoginMFA(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	var in mfaLoginReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.MFAToken == "" || in.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mfa_token and code are required"})
+		return
+	}
+
+	// счётчики подбора — до кода: новый mfa_token (повторный /login) их не обходит
+	owner, err := h.mfa.PeekChallenge(c.Request.Context(), in.MFAToken)
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidChallenge) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+			return
+		}
+		l.Error("users.login_mfa: challenge lookup failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	ip := c.ClientIP()
+	if !h.guardCheck(c, l, owner.Email, ip) {
+		return
+	}
+
+	ch, err := h.mfa.CompleteChallenge(c.Request.Context(), in.MFAToken, in.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidChallenge):
+			h.guardRelease(c, l, owner.Email, ip)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+		case errors.Is(err, app.ErrInvalidCode), errors.Is(err, app.ErrMFANotEnabled):
+			// подбор кода — та же неудача логина: аккаунт уйдёт в паузу/блокировку
+			h.guardFail(c, l, owner.Email, ip)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid code"})
+		default:
+			h.guardRelease(c, l, owner.Email, ip)
+			l.Error("users.login_mfa: failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+
+	if h.startSession(c, l, "users.login_mfa", ch.UserID, ch.Email) {
+		h.guardSuccess(c, l, owner.Email, ip)
+	} else {
+		h.guardRelease(c, l, owner.Email, ip)
+	}
+}
+
+// EnrollMFA — новый секрет TOTP; 2FA включится после ConfirmMFA первым кодом из приложения.
+func (h *UsersHandlers) EnrollMFA(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	userID, email, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	secret, uri, err := h.mfa.Enroll(c.Request.Context(), userID, email)
+	if err != nil {
+		if errors.Is(err, app.ErrMFAEnabled) {
+			c.JSON(http.StatusConflict, gin.H{"error": "mfa already enabled"})
+			return
+		}
+		l.Error("users.mfa_enroll: failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mfaEnrollResp{Secret: secret, OTPAuthURI: uri})
+}
+
+func (h *UsersHandlers) ConfirmMFA(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	userID, _, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	var in mfaCodeReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	codes, err := h.mfa.Confirm(c.Request.Context(), userID, in.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidCode):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid code"})
+		case errors.Is(err, app.ErrMFANoPending):
+			c.JSON(http.StatusConflict, gin.H{"error": "mfa enrollment not started"})
+		case errors.Is(err, app.ErrMFAEnabled):
+			c.JSON(http.StatusConflict, gin.H{"error": "mfa already enabled"})
+		default:
+			l.Error("users.mfa_confirm: failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+	l.Info("users.mfa: enabled", slog.String("user_id", userID.String()))
+
+	c.JSON(http.StatusOK, mfaConfirmResp{RecoveryCodes: codes})
+}
+
+// DisableMFA — повторная аутентификация: пароль и действующий код (TOTP или восстановления),
+// одного access-токена мало.
+func (h *UsersHandlers) DisableMFA(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	userID, email, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	var in mfaDisableReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.Password == "" || in.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password and code are required"})
+		return
+	}
+
+	if _, ok := h.reauth(c, l, userID, email, in.Password); !ok {
+		return
+	}
+
+	if err := h.mfa.Disable(c.Request.Context(), userID, in.Code); err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidCode):
+			h.guardFail(c, l, email, c.ClientIP())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid code"})
+		case errors.Is(err, app.ErrMFANotEnabled):
+			c.JSON(http.StatusConflict, gin.H{"error": "mfa not enabled"})
+		default:
+			l.Error("users.mfa_disable: failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+	h.guardSuccess(c, l, email, c.ClientIP())
+	l.Info("users.mfa: disabled", slog.String("user_id", userID.String()))
+
+	c.Status(http.StatusNoContent)
+}
+
+// currentUser — uid/email из access-токена; при ошибке ответ уже отправлен.
+func (h *UsersHandlers) currentUser(c *gin.Context) (uuid.UUID, string, bool) {
+	claims, ok := httpx.GetJWTClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return uuid.Nil, "", false
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return uuid.Nil, "", false
+	}
+	return userID, claims.Email, true
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+
+	"goshop/services/users/internal/adapters/challengeredis"
+	"goshop/services/users/internal/adapters/loginguard"
+	"goshop/services/users/internal/app"
+	domain "goshop/services/users/internal/domain/user"
+)
+
+// confirmedMFARepo — 2FA включена, ни один код не подходит.
+type confirmedMFARepo struct{}
+
+func (confirmedMFARepo) GetMFA(context.Context, uuid.UUID) (app.MFAState, error) {
+	now := time.Now()
+	return app.MFAState{ConfirmedAt: &now}, nil
+}
+func (confirmedMFARepo) SaveMFASecret(context.Context, uuid.UUID, []byte) error { return nil }
+func (confirmedMFARepo) ConfirmMFA(context.Context, uuid.UUID, int64, [][]byte) error {
+	return nil
+}
+func (confirmedMFARepo) UseMFAStep(context.Context, uuid.UUID, int64) (bool, error) {
+	return false, nil
+}
+func (confirmedMFARepo) UseRecoveryCode(context.Context, uuid.UUID, []byte) (bool, error) {
+	return false, nil
+}
+func (confirmedMFARepo) DeleteMFA(context.Context, uuid.UUID) error { return nil }
+
+// Подбор второго фактора упирается в счётчики логина: новый mfa_token (повторный /login с
+// верным паролем) их не сбрасывает.
+func TestUsersHandlers_LoginMFA_Guarded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	store := challengeredis.New(rdb)
+	mfa, err := app.NewMFA(confirmedMFARepo{}, store, app.MFAConfig{EncryptionKey: make([]byte, 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), 4)
+	if err != nil {
+		t.Fatalf("generate hash: %v", err)
+	}
+	ch := app.Challenge{UserID: uuid.New(), Email: "user@example.com"}
+	repo := &stubUserRepo{
+		t: t,
+		getByEmailFn: func(ctx context.Context, email string) (domain.User, error) {
+			return domain.User{ID: ch.UserID, Email: ch.Email, PasswordHash: hash}, nil
+		},
+	}
+	h := newUsersHandlersWithRepo(t, repo)
+	h.mfa = mfa
+	h.guard = loginguard.New(rdb, loginguard.Config{FreeAttempts: 1, BaseDelay: time.Minute})
+
+	loginMFA := func() int {
+		tok, err := store.Create(context.Background(), ch, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body := []byte(`{"mfa_token":"` + tok + `","code":"bad-recovery-code"}`)
+		return performRequest(t, h.LoginMFA, http.MethodPost, "/v1/users/login/mfa", bytes.NewReader(body)).Code
+	}
+
+	if code := loginMFA(); code != http.StatusUnauthorized {
+		t.Fatalf("wrong code: status %d, want 401", code)
+	}
+	// верный пароль выдаёт новый mfa_token, но неудачу не обнуляет
+	body := []byte(`{"email":"user@example.com","password":"correct-password"}`)
+	if w := performRequest(t, h.Login, http.MethodPost, "/v1/users/login", bytes.NewReader(body)); w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte("mfa_token")) {
+		t.Fatalf("login: status %d body %s, want mfa challenge", w.Code, w.Body.String())
+	}
+	if code := loginMFA(); code != http.StatusUnauthorized {
+		t.Fatalf("second wrong code: status %d, want 401", code)
+	}
+	if code := loginMFA(); code != http.StatusTooManyRequests {
+		t.Fatalf("after failures: status %d, want 429", code)
+	}
+}
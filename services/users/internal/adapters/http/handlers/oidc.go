@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"goshop/services/users/internal/app"
+)
+
+// OIDCStart — GET /v1/users/oidc/:provider: редирект на страницу входа провайдера.
+func (h *UsersHandlers) OIDCStart(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	provider := c.Param("provider")
+	authURL, err := h.social.Start(c.Request.Context(), provider)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown provider"})
+		case errors.Is(err, app.ErrProviderUnavailable):
+			l.Warn("users.oidc: provider unavailable", slog.String("provider", provider), slog.Any("err", err))
+			c.JSON(http.StatusBadGateway, gin.H{"error": "identity provider unavailable"})
+		default:
+			l.Error("users.oidc: start failed", slog.String("provider", provider), slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback — GET /v1/users/oidc/:provider/callback?code=&state=: ответ как у login
+// (пара токенов или mfa_token). Первый вход создаёт аккаунт или привязывает identity к
+// аккаунту с тем же email — только если провайдер этот email подтвердил.
+func (h *UsersHandlers) OIDCCallback(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	provider := c.Param("provider")
+	if e := c.Query("error"); e != "" {
+		l.Info("users.oidc: denied by provider", slog.String("provider", provider), slog.String("error", e))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "login cancelled or denied by provider"})
+		return
+	}
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code and state are required"})
+		return
+	}
+
+	res, err := h.social.Complete(c.Request.Context(), provider, state, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown provider"})
+		case errors.Is(err, app.ErrInvalidState):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired state"})
+		case errors.Is(err, app.ErrInvalidIdentity):
+			l.Warn("users.oidc: identity rejected", slog.String("provider", provider), slog.Any("err", err))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "identity rejected"})
+		case errors.Is(err, app.ErrIdentityConflict), errors.Is(err, app.ErrEmailTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": "email already registered: log in with password to link this provider"})
+		case errors.Is(err, app.ErrProviderUnavailable):
+			l.Warn("users.oidc: provider unavailable", slog.String("provider", provider), slog.Any("err", err))
+			c.JSON(http.StatusBadGateway, gin.H{"error": "identity provider unavailable"})
+		default:
+			l.Error("users.oidc: callback failed", slog.String("provider", provider), slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+
+	u := res.User
+	switch {
+	case res.Created:
+		l.Info("users.oidc: account created", slog.String("provider", provider), slog.String("user_id", u.ID.String()))
+		// провайдер email не подтвердил — обычное письмо со ссылкой
+		if u.EmailVerifiedAt == nil && h.accounts != nil {
+			if err := h.accounts.SendVerification(c.Request.Context(), u); err != nil {
+				l.Error("users.oidc: send verification failed", slog.Any("err", err))
+			}
+		}
+	case res.Takeover:
+		// пароль и сессии были у того, кто зарегистрировал адрес, не подтвердив его
+		l.Warn("users.oidc: unverified account taken over by email owner",
+			slog.String("provider", provider),
+			slog.String("user_id", u.ID.String()),
+		)
+		h.logoutEverywhere(c, l, u.ID)
+	case res.Linked:
+		l.Info("users.oidc: identity linked", slog.String("provider", provider), slog.String("user_id", u.ID.String()))
+	}
+
+	h.finishLogin(c, l, "users.oidc", u)
+}
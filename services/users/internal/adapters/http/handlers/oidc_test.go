@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"goshop/services/users/internal/adapters/oidc"
+	"goshop/services/users/internal/adapters/oidc/oidctest"
+	"goshop/services/users/internal/app"
+)
+
+type stubStates map[string]app.OIDCState
+
+func (s stubStates) Save(_ context.Context, state string, st app.OIDCState, _ time.Duration) error {
+	s[state] = st
+	return nil
+}
+
+func (s stubStates) Take(_ context.Context, state string) (app.OIDCState, error) {
+	st, ok := s[state]
+	if !ok {
+		return app.OIDCState{}, app.ErrInvalidState
+	}
+	delete(s, state)
+	return st, nil
+}
+
+func TestUsersHandlers_OIDC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock, err := oidctest.New("goshop", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+	p := oidc.New(oidc.Config{Name: "mock", Issuer: mock.Issuer(), ClientID: "goshop", ClientSecret: "s3cret", RedirectURL: "http://localhost/cb"})
+	h := &UsersHandlers{
+		log:    newTestLogger(),
+		social: app.NewSocial(nil, stubStates{}, []app.IdentityProvider{p}, app.SocialConfig{}),
+	}
+
+	r := gin.New()
+	r.GET("/v1/users/oidc/:provider", h.OIDCStart)
+	r.GET("/v1/users/oidc/:provider/callback", h.OIDCCallback)
+	serve := func(path string) (int, http.Header) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code, w.Header()
+	}
+
+	code, hdr := serve("/v1/users/oidc/mock")
+	if code != http.StatusFound || !strings.HasPrefix(hdr.Get("Location"), mock.Issuer()+"/authorize?") {
+		t.Fatalf("start: status=%d location=%q", code, hdr.Get("Location"))
+	}
+	if code, _ := serve("/v1/users/oidc/nope"); code != http.StatusNotFound {
+		t.Fatalf("unknown provider: status=%d", code)
+	}
+
+	cases := []struct {
+		name, path string
+		want       int
+	}{
+		{"denied by provider", "/v1/users/oidc/mock/callback?error=access_denied&state=x", http.StatusUnauthorized},
+		{"no code", "/v1/users/oidc/mock/callback?state=x", http.StatusBadRequest},
+		{"forged state", "/v1/users/oidc/mock/callback?code=c&state=forged", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		if code, _ := serve(tc.path); code != tc.want {
+			t.Fatalf("%s: status=%d, want %d", tc.name, code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"goshop/pkg/httpx"
+	"goshop/services/users/internal/app"
+	domain "goshop/services/users/internal/domain/user"
+)
+
+// profilePatch — отсутствующее поле не меняется; "" (или null у адреса) — стереть.
+type profilePatch struct {
+	Name           *string         `json:"name"`
+	Phone          *string         `json:"phone"`
+	DefaultAddress json.RawMessage `json:"default_address"`
+}
+
+type changeEmailReq struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type changePasswordReq struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+type deleteMeReq struct {
+	Password string `json:"password"`
+	Code     string `json:"code"` // при включённой 2FA
+}
+
+// UpdateMe — PATCH /v1/users/me: имя, телефон, адрес по умолчанию. Email меняется
+// отдельно (POST /me/email), с подтверждением нового адреса.
+func (h *UsersHandlers) UpdateMe(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	userID, _, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	var in profilePatch
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		return
+	}
+	upd := app.ProfileUpdate{Name: in.Name, Phone: in.Phone}
+	if len(in.DefaultAddress) > 0 {
+		upd.SetAddress = true
+		if !bytes.Equal(in.DefaultAddress, []byte("null")) {
+			var a domain.Address
+			if err := json.Unmarshal(in.DefaultAddress, &a); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid default_address"})
+				return
+			}
+			upd.DefaultAddress = &a
+		}
+	}
+
+	u, err := h.profiles.Update(c.Request.Context(), userID, upd)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidName):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid name"})
+		case errors.Is(err, domain.ErrInvalidPhone):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid phone"})
+		case errors.Is(err, domain.ErrInvalidAddress):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid default_address"})
+		case errors.Is(err, app.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			l.Error("users.profile: update failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+
+	resp := toMeResp(u)
+	if claims, ok := httpx.GetJWTClaims(c); ok {
+		resp.Issuer, resp.Subject, resp.IssuedAt, resp.ExpiresAt = claims.Issuer, claims.Subject, claims.IssuedAt, claims.ExpiresAt
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ChangeEmail — POST /v1/users/me/email: письмо со ссылкой на новый адрес; email меняется,
+// когда ссылку откроют (POST /v1/users/verify). До этого входить — по старому адресу.
+func (h *UsersHandlers) ChangeEmail(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	userID, email, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	var in changeEmailReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.Email == "" || in.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email and password are required"})
+		return
+	}
+	u, ok := h.reauth(c, l, userID, email, in.Password)
+	if !ok {
+		return
+	}
+	h.guardSuccess(c, l, email, c.ClientIP())
+
+	if err := h.accounts.RequestEmailChange(c.Request.Context(), u, in.Email); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidEmail):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid email"})
+		case errors.Is(err, app.ErrSameEmail):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "email unchanged"})
+		case errors.Is(err, app.ErrEmailTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": "email already taken"})
+		default:
+			l.Error("users.email_change: failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+	l.Info("users.email_change: requested", slog.String("user_id", userID.String()))
+
+	c.Status(http.StatusAccepted)
+}
+
+// ChangePassword — POST /v1/users/me/password: по старому паролю; остальные сессии
+// закрываются, текущая остаётся.
+func (h *UsersHandlers) ChangePassword(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	userID, email, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	var in changePasswordReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.OldPassword == "" || in.NewPassword == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "old_password and new_password are required"})
+		return
+	}
+
+	// старый пароль подбирают так же, как при логине: те же счётчики
+	ip := c.ClientIP()
+	if !h.guardCheck(c, l, email, ip) {
+		return
+	}
+	if err := h.profiles.ChangePassword(c.Request.Context(), userID, in.OldPassword, in.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidCredentials), errors.Is(err, app.ErrNotFound):
+			h.guardFail(c, l, email, ip)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		case errors.Is(err, app.ErrNoPassword):
+			c.JSON(http.StatusConflict, gin.H{"error": "password not set: use password reset"})
+		case errors.Is(err, app.ErrWeakPassword):
+			// старый пароль уже проверен
+			h.guardSuccess(c, l, email, ip)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "weak password"})
+		default:
+			l.Error("users.password_change: failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+	h.guardSuccess(c, l, email, ip)
+
+	// пароль уже сменён; не удалось закрыть другие сессии — 500, как и в ResetPassword
+	var keep uuid.UUID // без sid (токен выдан до его появления) — закрываются все
+	if claims, ok := httpx.GetJWTClaims(c); ok {
+		keep, _ = uuid.Parse(claims.SessionID)
+	}
+	families, err := h.sessions.RevokeOthers(c.Request.Context(), userID, keep)
+	if err != nil {
+		l.Error("users.password_change: revoke sessions failed",
+			slog.String("user_id", userID.String()),
+			slog.Any("err", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	sids := make([]string, 0, len(families))
+	for _, f := range families {
+		sids = append(sids, f.String())
+	}
+	h.revokeAccess(c, sids...)
+
+	l.Info("users.password_change: password changed",
+		slog.String("user_id", userID.String()),
+		slog.Int("revoked_sessions", len(families)),
+	)
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteMe — DELETE /v1/users/me: пароль (и код 2FA, если включена); персональные данные
+// стираются, все сессии закрываются, downstream-сервисы получают user.deleted.
+func (h *UsersHandlers) DeleteMe(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	userID, email, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	var in deleteMeReq
+	if err := c.ShouldBindJSON(&in); err != nil || in.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password is required"})
+		return
+	}
+	if _, ok := h.reauth(c, l, userID, email, in.Password); !ok {
+		return
+	}
+	if h.mfa != nil {
+		on, err := h.mfa.Enabled(c.Request.Context(), userID)
+		if err != nil {
+			l.Error("users.delete: mfa lookup failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if on {
+			if err := h.mfa.Verify(c.Request.Context(), userID, in.Code); err != nil {
+				if errors.Is(err, app.ErrInvalidCode) {
+					h.guardFail(c, l, email, c.ClientIP())
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid code"})
+					return
+				}
+				l.Error("users.delete: mfa verify failed", slog.Any("err", err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+		}
+	}
+	h.guardSuccess(c, l, email, c.ClientIP())
+
+	if err := h.profiles.Delete(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, app.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		l.Error("users.delete: failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	l.Info("users.delete: account deleted", slog.String("user_id", userID.String()))
+
+	h.logoutEverywhere(c, l, userID)
+	if claims, ok := httpx.GetJWTClaims(c); ok {
+		h.revokeAccess(c, claims.SessionID)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// reauth — пароль перед опасным действием; при ошибке ответ уже отправлен. Пользователь —
+// по id (email в токене мог устареть после смены); без профилей — по email из токена.
+// Попытка идёт через счётчики логина (по email из токена): неверный пароль — guardFail, а
+// guardSuccess вызывает сам обработчик, когда проверены все факторы — иначе верный пароль
+// сбрасывал бы счётчик перед каждой попыткой подобрать код 2FA.
+func (h *UsersHandlers) reauth(c *gin.Context, l *slog.Logger, userID uuid.UUID, email, password string) (domain.User, bool) {
+	if !h.guardCheck(c, l, email, c.ClientIP()) {
+		return domain.User{}, false
+	}
+	var (
+		u   domain.User
+		err error
+	)
+	if h.profiles != nil {
+		u, err = h.profiles.VerifyPassword(c.Request.Context(), userID, password)
+	} else {
+		u, err = h.svc.Authenticate(c.Request.Context(), email, password)
+		if err == nil && u.ID != userID {
+			err = app.ErrInvalidCredentials
+		}
+	}
+	switch {
+	case err == nil:
+		return u, true
+	case errors.Is(err, app.ErrInvalidCredentials), errors.Is(err, app.ErrNotFound):
+		h.guardFail(c, l, email, c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+	case errors.Is(err, app.ErrNoPassword):
+		c.JSON(http.StatusConflict, gin.H{"error": "password not set: use password reset"})
+	default:
+		l.Error("users: password check failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+	return domain.User{}, false
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+
+	"goshop/services/users/internal/adapters/loginguard"
+	domain "goshop/services/users/internal/domain/user"
+)
+
+// Пароль перед опасным действием подбирается так же, как при логине: неудачи идут в счётчики
+// логина, после паузы даже верный пароль получает 429.
+func TestUsersHandlers_Reauth_Guarded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), 4)
+	if err != nil {
+		t.Fatalf("generate hash: %v", err)
+	}
+	userID := uuid.New()
+	repo := &stubUserRepo{
+		t: t,
+		getByEmailFn: func(ctx context.Context, email string) (domain.User, error) {
+			return domain.User{ID: userID, Email: domain.NormalizeEmail(email), PasswordHash: hash}, nil
+		},
+	}
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	h := newUsersHandlersWithRepo(t, repo)
+	h.guard = loginguard.New(rdb, loginguard.Config{FreeAttempts: 1, BaseDelay: time.Minute})
+
+	reauth := func(password string) (int, bool) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/v1/users/me", nil)
+		_, ok := h.reauth(c, newTestLogger(), userID, "user@example.com", password)
+		if ok {
+			h.guardSuccess(c, newTestLogger(), "user@example.com", c.ClientIP())
+		}
+		return w.Code, ok
+	}
+
+	if _, ok := reauth("correct-password"); !ok {
+		t.Fatal("correct password rejected")
+	}
+	for i := 1; i <= 2; i++ {
+		if code, ok := reauth("wrong-password"); ok || code != http.StatusUnauthorized {
+			t.Fatalf("wrong password #%d: ok=%v status=%d, want 401", i, ok, code)
+		}
+	}
+	code, ok := reauth("correct-password")
+	if ok || code != http.StatusTooManyRequests {
+		t.Fatalf("during pause: ok=%v status=%d, want 429", ok, code)
+	}
+}
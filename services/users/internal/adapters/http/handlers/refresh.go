@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"crypto/sha256"
+	"errors"
 	"net"
 	"net/http"
 
@@ -10,6 +11,7 @@ import (
 	"log/slog"
 
 	"goshop/services/users/internal/adapters/repo/sessionpg"
+	"goshop/services/users/internal/app"
 )
 
 type refreshReq struct {
@@ -53,8 +55,37 @@ func (h *UsersHandlers) Refresh(c *gin.Context) {
 	}
 
 	oldHash := sha256.Sum256([]byte(in.RefreshToken))
+	sid := sessionID(claims)
 
-	access, newRefresh, newJTI, err := h.jwtm.GeneratePair(userID.String(), claims.Email)
+	// email — текущий из БД (мог смениться), удалённый аккаунт сессию не продлит
+	email := claims.Email
+	if h.profiles != nil {
+		u, err := h.profiles.Get(c.Request.Context(), userID)
+		if err != nil {
+			if errors.Is(err, app.ErrNotFound) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired session"})
+				return
+			}
+			l.Error("users.refresh: user lookup failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		email = u.Email
+	}
+
+	// роли — из БД, а не из старого токена: назначение/снятие роли действует с ротации
+	roles, err := h.rolesOf(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, app.ErrNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired session"})
+			return
+		}
+		l.Error("users.refresh: roles lookup failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	access, newRefresh, newJTI, err := h.jwtm.GeneratePair(userID.String(), email, sid, roles...)
 	if err != nil {
 		l.Error("users.refresh: GeneratePair failed", slog.Any("err", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
@@ -83,11 +114,15 @@ func (h *UsersHandlers) Refresh(c *gin.Context) {
 		ip,
 	); err != nil {
 		switch err {
-		case sessionpg.ErrNotFound, sessionpg.ErrRevoked, sessionpg.ErrRotated, sessionpg.ErrExpired:
+		case sessionpg.ErrNotFound, sessionpg.ErrRevoked, sessionpg.ErrExpired:
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired session"})
 			return
 		case sessionpg.ErrRefreshReuse:
-			l.Warn("users.refresh: reuse detected", slog.String("session_id", oldID.String()))
+			l.Warn("users.refresh: reuse detected, session family revoked",
+				slog.String("session_id", oldID.String()),
+				slog.String("user_id", userID.String()),
+			)
+			h.revokeAccess(c, sid)
 			c.JSON(http.StatusConflict, gin.H{"error": "refresh token reused"})
 			return
 		default:
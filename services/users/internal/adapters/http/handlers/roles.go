@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"goshop/services/users/internal/app"
+)
+
+type roleResp struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type userRolesResp struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
+
+// ListRoles — GET /v1/users/admin/roles: справочник ролей.
+func (h *UsersHandlers) ListRoles(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	roles, err := h.roles.List(c.Request.Context())
+	if err != nil {
+		l.Error("users.roles: list failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	out := make([]roleResp, 0, len(roles))
+	for _, r := range roles {
+		out = append(out, roleResp{Name: r.Name, Description: r.Description})
+	}
+	c.JSON(http.StatusOK, gin.H{"roles": out})
+}
+
+// GetUserRoles — GET /v1/users/admin/users/:id/roles.
+func (h *UsersHandlers) GetUserRoles(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	roles, err := h.roles.Of(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, app.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		l.Error("users.roles: get failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, userRolesResp{UserID: userID.String(), Roles: roles})
+}
+
+// GrantRole — PUT /v1/users/admin/users/:id/roles/:role: идемпотентно; роль появится
+// в access-токене пользователя при следующем логине или refresh.
+func (h *UsersHandlers) GrantRole(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	actor, _, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	role := c.Param("role")
+
+	granted, err := h.roles.Grant(c.Request.Context(), actor, userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, app.ErrUnknownRole):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown role"})
+		default:
+			l.Error("users.roles: grant failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+	if granted {
+		l.Info("users.roles: granted",
+			slog.String("user_id", userID.String()),
+			slog.String("role", role),
+			slog.String("actor_id", actor.String()),
+		)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeRole — DELETE /v1/users/admin/users/:id/roles/:role: идемпотентно. Сессии пользователя
+// закрываются сразу — иначе роль жила бы в access-токенах до exp.
+func (h *UsersHandlers) RevokeRole(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	actor, _, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	role := c.Param("role")
+
+	revoked, err := h.roles.Revoke(c.Request.Context(), actor, userID, role)
+	if err != nil {
+		if errors.Is(err, app.ErrSelfDemote) {
+			c.JSON(http.StatusConflict, gin.H{"error": "cannot revoke own admin role"})
+			return
+		}
+		l.Error("users.roles: revoke failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	if revoked {
+		l.Info("users.roles: revoked",
+			slog.String("user_id", userID.String()),
+			slog.String("role", role),
+			slog.String("actor_id", actor.String()),
+		)
+		h.logoutEverywhere(c, l, userID)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// logoutEverywhere — закрыть все сессии пользователя и отозвать их access-токены: отзыв идёт
+// по sid сессии, так что просто перевыпустить токен без роли нельзя — нужен новый логин.
+// Так же закрываются сессии аккаунта, перешедшего к владельцу email при входе через провайдера.
+func (h *UsersHandlers) logoutEverywhere(c *gin.Context, l *slog.Logger, userID uuid.UUID) {
+	_, families, err := h.sessions.RevokeAll(c.Request.Context(), userID)
+	if err != nil {
+		l.Error("users.sessions: revoke all failed", slog.String("user_id", userID.String()), slog.Any("err", err))
+		return
+	}
+	sids := make([]string, 0, len(families))
+	for _, f := range families {
+		sids = append(sids, f.String())
+	}
+	h.revokeAccess(c, sids...)
+}
+
+// rolesOf — роли для нового access-токена; без RBAC — пусто.
+func (h *UsersHandlers) rolesOf(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	if h.roles == nil {
+		return nil, nil
+	}
+	return h.roles.Of(ctx, userID)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"log/slog"
+
+	"goshop/pkg/httpx"
+	"goshop/services/users/internal/adapters/repo/sessionpg"
+)
+
+type sessionResp struct {
+	ID         string    `json:"id"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	IP         *string   `json:"ip,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type sessionsResp struct {
+	Sessions []sessionResp `json:"sessions"`
+}
+
+// ListSessions — GET /v1/users/sessions: активные устройства; current — то, чьим токеном спросили.
+func (h *UsersHandlers) ListSessions(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	claims, ok := httpx.GetJWTClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	ss, err := h.sessions.ListActive(c.Request.Context(), userID)
+	if err != nil {
+		l.Error("users.sessions: list failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	out := sessionsResp{Sessions: make([]sessionResp, 0, len(ss))}
+	for _, s := range ss {
+		out.Sessions = append(out.Sessions, sessionResp{
+			ID:         s.ID.String(),
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.ID.String() == claims.SessionID,
+		})
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteSession — DELETE /v1/users/sessions/:id: выход на одном устройстве; его access-токены
+// отзываются сразу. Чужая или уже закрытая сессия — 404.
+func (h *UsersHandlers) DeleteSession(c *gin.Context) {
+	noCache(c)
+
+	l := ReqLog(c, h.log)
+
+	claims, ok := httpx.GetJWTClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	if err := h.sessions.RevokeFamily(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, sessionpg.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+		l.Error("users.sessions: revoke failed", slog.String("session_id", id.String()), slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	h.revokeAccess(c, id.String())
+
+	c.Status(http.StatusNoContent)
+}
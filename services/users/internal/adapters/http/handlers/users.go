@@ -8,9 +8,12 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"log/slog"
 
 	"goshop/pkg/jwtauth"
+	"goshop/pkg/revocation"
+	"goshop/services/users/internal/adapters/loginguard"
 	"goshop/services/users/internal/adapters/repo/sessionpg"
 	"goshop/services/users/internal/adapters/repo/userpg"
 	"goshop/services/users/internal/app"
@@ -22,10 +25,17 @@ type UsersHandlers struct {
 	svc      *app.Service
 	jwtm     *jwtauth.Manager
 	sessions *sessionpg.Repo
+	revoked  *revocation.List  // nil — access-токены живут до exp
+	accounts *app.Accounts     // nil — без писем (подтверждение email и сброс пароля)
+	mfa      *app.MFA          // nil — логин без второго фактора
+	guard    *loginguard.Guard // nil — без защиты от подбора пароля
+	roles    *app.Roles        // nil — токены без ролей, admin API выключен
+	social   *app.Social       // nil — вход только по паролю
+	profiles *app.Profiles     // nil — /me из claims, без изменения профиля и удаления
 }
 
-func NewUsersHandlers(log *slog.Logger, svc *app.Service, jwtm *jwtauth.Manager, sess *sessionpg.Repo) *UsersHandlers {
-	return &UsersHandlers{log: log, svc: svc, jwtm: jwtm, sessions: sess}
+func NewUsersHandlers(log *slog.Logger, svc *app.Service, jwtm *jwtauth.Manager, sess *sessionpg.Repo, revoked *revocation.List, accounts *app.Accounts, mfa *app.MFA, guard *loginguard.Guard, roles *app.Roles, social *app.Social, profiles *app.Profiles) *UsersHandlers {
+	return &UsersHandlers{log: log, svc: svc, jwtm: jwtm, sessions: sess, revoked: revoked, accounts: accounts, mfa: mfa, guard: guard, roles: roles, social: social, profiles: profiles}
 }
 
 type registerReq struct {
@@ -49,6 +59,13 @@ type loginResp struct {
 	ExpiresIn    int64  `json:"expires_in"`
 }
 
+// mfaChallengeResp — ответ login при включённой 2FA: токены выдаст /login/mfa.
+type mfaChallengeResp struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
 func (h *UsersHandlers) Register(c *gin.Context) {
 	noCache(c)
 
@@ -83,6 +100,13 @@ func (h *UsersHandlers) Register(c *gin.Context) {
 		}
 	}
 
+	// письмо со ссылкой подтверждения; сбой не отменяет регистрацию — есть /verify/resend
+	if h.accounts != nil {
+		if err := h.accounts.SendVerification(c.Request.Context(), u); err != nil {
+			l.Error("users.register: send verification failed", slog.Any("err", err))
+		}
+	}
+
 	c.JSON(http.StatusCreated, registerResp{
 		ID:        u.ID.String(),
 		Email:     u.Email,
@@ -105,24 +129,82 @@ func (h *UsersHandlers) Login(c *gin.Context) {
 		return
 	}
 
+	ip := c.ClientIP()
+	if !h.guardCheck(c, l, in.Email, ip) {
+		return
+	}
+
 	u, err := h.svc.Authenticate(c.Request.Context(), in.Email, in.Password)
 	if err != nil {
+		h.guardFail(c, l, in.Email, ip)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
+	// счётчик сбрасывается только с выдачей токенов: при 2FA верный пароль не должен обнулять
+	// неудачи перед подбором кода
+	if h.finishLogin(c, l, "users.login", u) {
+		h.guardSuccess(c, l, in.Email, ip)
+	} else {
+		h.guardRelease(c, l, in.Email, ip)
+	}
+}
+
+// finishLogin — личность подтверждена (паролем или провайдером): проверка email, второй фактор
+// или сразу новая сессия. true — выданы токены.
+func (h *UsersHandlers) finishLogin(c *gin.Context, l *slog.Logger, op string, u domain.User) bool {
+	if h.accounts != nil && h.accounts.RequireVerifiedEmail() && u.EmailVerifiedAt == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "email not verified"})
+		return false
+	}
 
-	access, refresh, jti, err := h.jwtm.GeneratePair(u.ID.String(), u.Email)
+	// 2FA: вместо пары токенов — mfa_token для второго шага (POST /v1/users/login/mfa)
+	if h.mfa != nil {
+		on, err := h.mfa.Enabled(c.Request.Context(), u.ID)
+		if err != nil {
+			l.Error(op+": mfa lookup failed", slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return false
+		}
+		if on {
+			tok, err := h.mfa.Challenge(c.Request.Context(), u)
+			if err != nil {
+				l.Error(op+": mfa challenge failed", slog.Any("err", err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return false
+			}
+			c.JSON(http.StatusOK, mfaChallengeResp{
+				MFARequired: true,
+				MFAToken:    tok,
+				ExpiresIn:   int64(h.mfa.ChallengeTTL().Seconds()),
+			})
+			return false
+		}
+	}
+
+	return h.startSession(c, l, op, u.ID, u.Email)
+}
+
+// startSession — новая сессия (семейство) и пара токенов: после пароля или после второго фактора.
+// false — ответ с ошибкой уже отправлен.
+func (h *UsersHandlers) startSession(c *gin.Context, l *slog.Logger, op string, userID uuid.UUID, email string) bool {
+	roles, err := h.rolesOf(c.Request.Context(), userID)
 	if err != nil {
-		l.Error("users.login: jwt.GeneratePair failed", slog.Any("err", err))
+		l.Error(op+": roles lookup failed", slog.Any("err", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-		return
+		return false
+	}
+	access, refresh, jti, err := h.jwtm.GeneratePair(userID.String(), email, "", roles...)
+	if err != nil {
+		l.Error(op+": jwt.GeneratePair failed", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return false
 	}
 
 	claims, err := h.jwtm.ParseAndVerify(refresh)
 	if err != nil || claims.ExpiresAt == nil {
-		l.Error("users.login: parse refresh failed", slog.Any("err", err))
+		l.Error(op+": parse refresh failed", slog.Any("err", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-		return
+		return false
 	}
 	expiresAt := claims.ExpiresAt.Time
 
@@ -133,15 +215,15 @@ func (h *UsersHandlers) Login(c *gin.Context) {
 	if _, err := h.sessions.CreateSession(
 		c.Request.Context(),
 		jti,
-		u.ID,
+		userID,
 		hash[:],
 		expiresAt,
 		ua,
 		ip,
 	); err != nil {
-		l.Error("users.login: sessions.CreateSession failed", slog.Any("err", err))
+		l.Error(op+": sessions.CreateSession failed", slog.Any("err", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-		return
+		return false
 	}
 
 	c.JSON(http.StatusOK, loginResp{
@@ -150,4 +232,5 @@ func (h *UsersHandlers) Login(c *gin.Context) {
 		TokenType:    "Bearer",
 		ExpiresIn:    h.jwtm.ExpiresIn(),
 	})
+	return true
 }
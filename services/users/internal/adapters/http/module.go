@@ -9,27 +9,43 @@ import (
 
 	"goshop/pkg/httpx"
 	"goshop/pkg/jwtauth"
+	"goshop/pkg/revocation"
 
 	"goshop/services/users/internal/adapters/http/handlers"
+	"goshop/services/users/internal/adapters/loginguard"
 	"goshop/services/users/internal/adapters/repo/sessionpg"
 	"goshop/services/users/internal/app"
 )
 
 type Module struct {
-	log   *slog.Logger
-	db    *pgxpool.Pool
-	svc   *app.Service
-	jwtm  *jwtauth.Manager
-	srepo *sessionpg.Repo
+	log    *slog.Logger
+	db     *pgxpool.Pool
+	svc    *app.Service
+	jwtm   *jwtauth.Manager
+	srepo  *sessionpg.Repo
+	rev    *revocation.List
+	acc    *app.Accounts
+	mfa    *app.MFA
+	guard  *loginguard.Guard
+	roles  *app.Roles
+	social *app.Social
+	prof   *app.Profiles
 }
 
-func NewModule(log *slog.Logger, db *pgxpool.Pool, svc *app.Service, jwtm *jwtauth.Manager) *Module {
+func NewModule(log *slog.Logger, db *pgxpool.Pool, svc *app.Service, jwtm *jwtauth.Manager, srepo *sessionpg.Repo, rev *revocation.List, acc *app.Accounts, mfa *app.MFA, guard *loginguard.Guard, roles *app.Roles, social *app.Social, prof *app.Profiles) *Module {
 	return &Module{
-		log:   log,
-		db:    db,
-		svc:   svc,
-		jwtm:  jwtm,
-		srepo: sessionpg.New(db),
+		log:    log,
+		db:     db,
+		svc:    svc,
+		jwtm:   jwtm,
+		srepo:  srepo,
+		rev:    rev,
+		acc:    acc,
+		mfa:    mfa,
+		guard:  guard,
+		roles:  roles,
+		social: social,
+		prof:   prof,
 	}
 }
 
@@ -48,21 +64,60 @@ func (m *Module) Mount(r *gin.Engine) error {
 	v1.GET("/db/ping", hh.DBPing)
 
 	// Users endpoints
-	uh := handlers.NewUsersHandlers(m.log, m.svc, m.jwtm, m.srepo)
+	uh := handlers.NewUsersHandlers(m.log, m.svc, m.jwtm, m.srepo, m.rev, m.acc, m.mfa, m.guard, m.roles, m.social, m.prof)
+
+	// публичные ключи подписи для проверяющих сервисов
+	r.GET("/.well-known/jwks.json", uh.JWKS)
 
 	u := v1.Group("/users")
 	{
 		// public
 		u.POST("/register", uh.Register)
 		u.POST("/login", uh.Login)
+		u.POST("/login/mfa", uh.LoginMFA) // второй шаг при включённой 2FA
 		u.POST("/refresh", uh.Refresh)
 		u.POST("/logout", uh.Logout) // по refresh token, без auth
 
+		// по ссылкам из писем
+		u.POST("/verify", uh.VerifyEmail)
+		u.POST("/verify/resend", uh.ResendVerification)
+		u.POST("/password/forgot", uh.ForgotPassword)
+		u.POST("/password/reset", uh.ResetPassword)
+
+		// вход через внешних OIDC-провайдеров
+		if m.social != nil {
+			u.GET("/oidc/:provider", uh.OIDCStart)
+			u.GET("/oidc/:provider/callback", uh.OIDCCallback)
+		}
+
 		// protected (Access JWT)
 		auth := u.Group("")
-		auth.Use(httpx.AuthJWT(m.log, m.jwtm)) // единый мидлвар аутентификации JWT
+		auth.Use(httpx.AuthJWTRevocable(m.log, m.jwtm, m.rev, "")) // JWT + список отозванных сессий
 		auth.GET("/me", uh.Me)
+		if m.prof != nil {
+			auth.PATCH("/me", uh.UpdateMe)
+			auth.DELETE("/me", uh.DeleteMe) // анонимизация, событие user.deleted
+			auth.POST("/me/password", uh.ChangePassword)
+			if m.acc != nil {
+				auth.POST("/me/email", uh.ChangeEmail) // с подтверждением нового адреса
+			}
+		}
 		auth.POST("/logout_all", uh.LogoutAll)
+		auth.GET("/sessions", uh.ListSessions)
+		auth.DELETE("/sessions/:id", uh.DeleteSession)
+		auth.POST("/mfa/enroll", uh.EnrollMFA)
+		auth.POST("/mfa/confirm", uh.ConfirmMFA)
+		auth.POST("/mfa/disable", uh.DisableMFA)
+
+		// admin: назначение ролей (RBAC)
+		if m.roles != nil {
+			adm := auth.Group("/admin")
+			adm.Use(httpx.RequireRole(m.log, jwtauth.RoleAdmin))
+			adm.GET("/roles", uh.ListRoles)
+			adm.GET("/users/:id/roles", uh.GetUserRoles)
+			adm.PUT("/users/:id/roles/:role", uh.GrantRole)
+			adm.DELETE("/users/:id/roles/:role", uh.RevokeRole)
+		}
 	}
 
 	m.log.Info("http: routes registered",
@@ -0,0 +1,307 @@
+package loginguard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	domain "goshop/services/users/internal/domain/user"
+)
+
+const DefaultPrefix = "users:login:"
+
+// Config — пороги подбора пароля. Аккаунт: первые FreeAttempts неудач без задержки, дальше
+// пауза BaseDelay, удваивающаяся до MaxDelay; после AccountLockAfter — блокировка на
+// AccountLockout. IP: блокировка после IPLockAfter неудач по любым аккаунтам (перебор
+// утёкших пар email/пароль с одного адреса). Счётчики живут Window с последней неудачи.
+// AttemptTimeout — сколько держится резерв попытки, если за Check не последовали ни Fail, ни Success
+// (обрыв запроса, ошибка БД).
+type Config struct {
+	Window           time.Duration
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	AccountLockAfter int
+	AccountLockout   time.Duration
+	IPLockAfter      int
+	IPLockout        time.Duration
+	AttemptTimeout   time.Duration
+}
+
+const (
+	ScopeAccount = "account"
+	ScopeIP      = "ip"
+)
+
+// Decision — можно ли проверять пароль сейчас; иначе — через RetryAfter.
+type Decision struct {
+	Allowed    bool
+	RetryAfter time.Duration
+	Scope      string // что заблокировано: account | ip
+	Locked     bool   // блокировка, а не прогрессивная пауза
+}
+
+// RetryAfterSeconds — значение для заголовка Retry-After (минимум 1).
+func (d Decision) RetryAfterSeconds() int {
+	s := int((d.RetryAfter + time.Second - 1) / time.Second)
+	if s < 1 {
+		s = 1
+	}
+	return s
+}
+
+// Observer — исходы логина и блокировки (см. metrics.LoginMetrics).
+type Observer interface {
+	ObserveLogin(result string)
+	ObserveLockout(scope string)
+}
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultBlocked = "blocked"
+)
+
+// Guard — счётчики неудачных логинов в Redis. Проверка идёт до bcrypt: заблокированный
+// аккаунт или IP не тратит CPU. Ошибки Redis не блокируют логин (fail-open) — их логирует вызывающий.
+type Guard struct {
+	rdb    *redis.Client
+	cfg    Config
+	prefix string
+	obs    Observer
+}
+
+type Option func(*Guard)
+
+func WithPrefix(p string) Option     { return func(g *Guard) { g.prefix = p } }
+func WithObserver(o Observer) Option { return func(g *Guard) { g.obs = o } }
+
+func New(rdb *redis.Client, cfg Config, opts ...Option) *Guard {
+	if cfg.Window <= 0 {
+		cfg.Window = 15 * time.Minute
+	}
+	if cfg.FreeAttempts <= 0 {
+		cfg.FreeAttempts = 3
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = time.Second
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = time.Minute
+	}
+	if cfg.AccountLockAfter <= 0 {
+		cfg.AccountLockAfter = 10
+	}
+	if cfg.AccountLockout <= 0 {
+		cfg.AccountLockout = 15 * time.Minute
+	}
+	if cfg.IPLockAfter <= 0 {
+		cfg.IPLockAfter = 50
+	}
+	if cfg.IPLockout <= 0 {
+		cfg.IPLockout = 15 * time.Minute
+	}
+	if cfg.AttemptTimeout <= 0 {
+		cfg.AttemptTimeout = 30 * time.Second
+	}
+	g := &Guard{rdb: rdb, cfg: cfg, prefix: DefaultPrefix}
+	for _, o := range opts {
+		o(g)
+	}
+	return g
+}
+
+// Check — перед проверкой пароля. Разрешённая попытка резервируется в том же скрипте: пока она
+// не закрыта Fail или Success, параллельные попытки считаются уже неудачными, и подбор пачкой
+// запросов упирается в паузу и блокировку так же, как последовательный.
+func (g *Guard) Check(ctx context.Context, email, ip string) (Decision, error) {
+	c := g.cfg
+	res, err := check.Run(ctx, g.rdb, g.keys(email, ip),
+		c.FreeAttempts, c.BaseDelay.Milliseconds(), c.AccountLockAfter, c.IPLockAfter, c.AttemptTimeout.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return Decision{Allowed: true}, fmt.Errorf("loginguard: check: %w", err)
+	}
+	d := decide(res)
+	if !d.Allowed {
+		g.observeLogin(ResultBlocked)
+	}
+	return d, nil
+}
+
+// Fail — неверный пароль (или второй фактор): резерв Check снимается, счётчики растут, возвращается
+// пауза до следующей попытки.
+func (g *Guard) Fail(ctx context.Context, email, ip string) (Decision, error) {
+	g.observeLogin(ResultFailure)
+	c := g.cfg
+	res, err := fail.Run(ctx, g.rdb, g.keys(email, ip),
+		c.Window.Milliseconds(), c.FreeAttempts, c.BaseDelay.Milliseconds(), c.MaxDelay.Milliseconds(),
+		c.AccountLockAfter, c.AccountLockout.Milliseconds(), c.IPLockAfter, c.IPLockout.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return Decision{Allowed: true}, fmt.Errorf("loginguard: fail: %w", err)
+	}
+	// res[4], res[5] — блокировка началась именно этой неудачей
+	if res[4] == 1 {
+		g.observeLockout(ScopeAccount)
+	}
+	if res[5] == 1 {
+		g.observeLockout(ScopeIP)
+	}
+	return decide(res[:4]), nil
+}
+
+// Success — резерв Check снимается, счётчик аккаунта сбрасывается; счётчик IP — нет: при переборе
+// утёкших пар часть подходит, и удачный вход не должен обнулять следы перебора.
+func (g *Guard) Success(ctx context.Context, email, ip string) error {
+	g.observeLogin(ResultSuccess)
+	if err := success.Run(ctx, g.rdb, g.keys(email, ip)).Err(); err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("loginguard: reset: %w", err)
+	}
+	return nil
+}
+
+// Release — резерв Check снимается без исхода: пароль верен, но вход не завершён (выдан mfa_token,
+// email не подтверждён, ошибка сервера). Счётчики не меняются — неудачи до второго фактора остаются.
+func (g *Guard) Release(ctx context.Context, email, ip string) error {
+	if err := releaseOnly.Run(ctx, g.rdb, g.keys(email, ip)).Err(); err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("loginguard: release: %w", err)
+	}
+	return nil
+}
+
+// keys — счётчики аккаунта и IP и их резервы (попытки в полёте). Email хэшируется: в Redis
+// не попадают адреса, в том числе чужие, которые перебирают.
+func (g *Guard) keys(email, ip string) []string {
+	h := sha256.Sum256([]byte(domain.NormalizeEmail(email)))
+	acc := g.prefix + ScopeAccount + ":" + hex.EncodeToString(h[:])
+	ipk := g.prefix + ScopeIP + ":" + ip
+	return []string{acc, ipk, acc + ":inflight", ipk + ":inflight"}
+}
+
+// decide — res: {account_wait_ms, account_locked, ip_wait_ms, ip_locked}; побеждает большее ожидание.
+func decide(res []int64) Decision {
+	d := Decision{Allowed: true}
+	if res[0] > 0 {
+		d = Decision{RetryAfter: time.Duration(res[0]) * time.Millisecond, Scope: ScopeAccount, Locked: res[1] == 1}
+	}
+	if res[2] > 0 && time.Duration(res[2])*time.Millisecond > d.RetryAfter {
+		d = Decision{RetryAfter: time.Duration(res[2]) * time.Millisecond, Scope: ScopeIP, Locked: res[3] == 1}
+	}
+	return d
+}
+
+func (g *Guard) observeLogin(result string) {
+	if g.obs != nil {
+		g.obs.ObserveLogin(result)
+	}
+}
+
+func (g *Guard) observeLockout(scope string) {
+	if g.obs != nil {
+		g.obs.ObserveLockout(scope)
+	}
+}
+
+// check: KEYS — аккаунт, IP, их резервы; ARGV — free, base (мс), account_lock_after, ip_lock_after,
+// attempt_timeout (мс). Возвращает {account_wait_ms, account_locked, ip_wait_ms, ip_locked}.
+// Активная пауза или блокировка — отказ. Иначе отказ, если неудачи вместе с попытками в полёте уже
+// дошли до порога (free для аккаунта, ip_lock_after для IP): исход попыток в полёте ещё неизвестен,
+// повторить — через base. Разрешённая попытка увеличивает оба резерва.
+// Время — из Redis (TIME), чтобы реплики не зависели от своих часов.
+var check = redis.NewScript(`
+local t   = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local free = tonumber(ARGV[1])
+local base = tonumber(ARGV[2])
+local limits = {math.min(free, tonumber(ARGV[3])), tonumber(ARGV[4])}
+local out = {}
+local allowed = true
+for i = 1, 2 do
+  local v = redis.call('HMGET', KEYS[i], 'until', 'locked', 'fails')
+  local u = tonumber(v[1]) or 0
+  local inflight = tonumber(redis.call('GET', KEYS[i + 2])) or 0
+  if u > now then
+    out[#out + 1] = u - now
+    out[#out + 1] = tonumber(v[2]) or 0
+    allowed = false
+  elseif inflight > 0 and (tonumber(v[3]) or 0) + inflight >= limits[i] then
+    out[#out + 1] = base
+    out[#out + 1] = 0
+    allowed = false
+  else
+    out[#out + 1] = 0
+    out[#out + 1] = 0
+  end
+end
+if allowed then
+  for i = 3, 4 do
+    redis.call('INCR', KEYS[i])
+    redis.call('PEXPIRE', KEYS[i], ARGV[5])
+  end
+end
+return out
+`)
+
+// release — снимает резерв Check (не ниже нуля).
+const release = `
+local function release(key)
+  if (tonumber(redis.call('GET', key)) or 0) > 0 then
+    redis.call('DECR', key)
+  end
+end
+`
+
+// success: KEYS — как у check. Счётчик аккаунта удаляется, резервы снимаются.
+var success = redis.NewScript(release + `
+release(KEYS[3])
+release(KEYS[4])
+redis.call('DEL', KEYS[1])
+return 0
+`)
+
+// releaseOnly: KEYS — как у check. Только резервы.
+var releaseOnly = redis.NewScript(release + `
+release(KEYS[3])
+release(KEYS[4])
+return 0
+`)
+
+// fail: KEYS — как у check; ARGV — window, free, base, max, account_lock_after, account_lockout,
+// ip_lock_after, ip_lockout (мс). Возвращает check-ответ плюс {account_locked_now, ip_locked_now}.
+var fail = redis.NewScript(release + `
+release(KEYS[3])
+release(KEYS[4])
+
+local t   = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local window = tonumber(ARGV[1])
+local free   = tonumber(ARGV[2])
+local base   = tonumber(ARGV[3])
+local maxd   = tonumber(ARGV[4])
+
+local function bump(key, lockAfter, lockout, progressive)
+  local n = redis.call('HINCRBY', key, 'fails', 1)
+  local wait, locked, lockedNow = 0, 0, 0
+  if n >= lockAfter then
+    wait, locked = lockout, 1
+    if n == lockAfter then lockedNow = 1 end
+  elseif progressive and n > free then
+    wait = math.floor(math.min(base * 2 ^ (n - free - 1), maxd))
+  end
+  if wait > 0 then
+    redis.call('HSET', key, 'until', now + wait, 'locked', locked)
+  end
+  redis.call('PEXPIRE', key, math.max(window, wait))
+  return wait, locked, lockedNow
+end
+
+local aw, al, an = bump(KEYS[1], tonumber(ARGV[5]), tonumber(ARGV[6]), true)
+local iw, il, inow = bump(KEYS[2], tonumber(ARGV[7]), tonumber(ARGV[8]), false)
+return {aw, al, iw, il, an, inow}
+`)
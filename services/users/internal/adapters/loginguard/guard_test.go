@@ -0,0 +1,246 @@
+package loginguard
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestDecide(t *testing.T) {
+	cases := []struct {
+		name string
+		res  []int64
+		want Decision
+	}{
+		{"free", []int64{0, 0, 0, 0}, Decision{Allowed: true}},
+		{"account delay", []int64{2000, 0, 0, 0}, Decision{RetryAfter: 2 * time.Second, Scope: ScopeAccount}},
+		{"ip lock wins", []int64{2000, 0, 900000, 1}, Decision{RetryAfter: 15 * time.Minute, Scope: ScopeIP, Locked: true}},
+		{"account lock wins", []int64{900000, 1, 1000, 1}, Decision{RetryAfter: 15 * time.Minute, Scope: ScopeAccount, Locked: true}},
+	}
+	for _, c := range cases {
+		if got := decide(c.res); got != c.want {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+	if s := (Decision{RetryAfter: 1500 * time.Millisecond}).RetryAfterSeconds(); s != 2 {
+		t.Errorf("RetryAfterSeconds = %d, want 2", s)
+	}
+}
+
+type countObserver struct {
+	mu       sync.Mutex
+	lockouts map[string]int
+}
+
+func (o *countObserver) ObserveLogin(string) {}
+
+func (o *countObserver) ObserveLockout(scope string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.lockouts[scope]++
+}
+
+// testRedis — miniredis с управляемыми часами: TIME в скриптах берётся из SetTime, TTL ключей — из FastForward.
+type testRedis struct {
+	*miniredis.Miniredis
+	now time.Time
+}
+
+func (r *testRedis) advance(d time.Duration) {
+	r.now = r.now.Add(d)
+	r.SetTime(r.now)
+	r.FastForward(d)
+}
+
+func newTestGuard(t *testing.T, cfg Config) (*Guard, *testRedis, *countObserver) {
+	t.Helper()
+	mr := &testRedis{Miniredis: miniredis.RunT(t), now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mr.SetTime(mr.now)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	obs := &countObserver{lockouts: map[string]int{}}
+	return New(rdb, cfg, WithObserver(obs)), mr, obs
+}
+
+func TestGuard_ProgressiveDelay(t *testing.T) {
+	ctx := context.Background()
+	g, mr, _ := newTestGuard(t, Config{FreeAttempts: 2, BaseDelay: time.Second, MaxDelay: 4 * time.Second})
+	acc := g.keys("a@example.com", "10.0.0.1")[0]
+
+	for i, want := range []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		d, err := g.Check(ctx, "a@example.com", "10.0.0.1")
+		if err != nil || !d.Allowed {
+			t.Fatalf("attempt %d: Check = %+v, %v; want allowed", i+1, d, err)
+		}
+		d, err = g.Fail(ctx, "a@example.com", "10.0.0.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.RetryAfter != want || d.Locked {
+			t.Fatalf("attempt %d: Fail = %+v, want pause %v", i+1, d, want)
+		}
+		if ttl := mr.TTL(acc); ttl != 15*time.Minute {
+			t.Fatalf("attempt %d: account key ttl = %v, want window", i+1, ttl)
+		}
+		if want == 0 {
+			continue
+		}
+		d, _ = g.Check(ctx, "A@Example.com ", "10.0.0.2")
+		if d.Allowed || d.Scope != ScopeAccount || d.RetryAfter != want {
+			t.Fatalf("attempt %d: Check during pause = %+v", i+1, d)
+		}
+		mr.advance(want)
+	}
+}
+
+func TestGuard_AccountLockout(t *testing.T) {
+	ctx := context.Background()
+	g, mr, obs := newTestGuard(t, Config{FreeAttempts: 10, AccountLockAfter: 3, AccountLockout: time.Hour})
+	acc := g.keys("a@example.com", "")[0]
+
+	var d Decision
+	for i := 0; i < 3; i++ {
+		d, _ = g.Fail(ctx, "a@example.com", "10.0.0.1")
+	}
+	if !d.Locked || d.Scope != ScopeAccount || d.RetryAfter != time.Hour {
+		t.Fatalf("third failure: %+v, want account lockout 1h", d)
+	}
+	// блокировка дольше window — ключ живёт до её конца
+	if ttl := mr.TTL(acc); ttl != time.Hour {
+		t.Fatalf("account key ttl = %v, want 1h", ttl)
+	}
+	if d, _ = g.Check(ctx, "a@example.com", "10.0.0.9"); d.Allowed || !d.Locked {
+		t.Fatalf("Check while locked = %+v", d)
+	}
+	_, _ = g.Fail(ctx, "a@example.com", "10.0.0.1")
+	if obs.lockouts[ScopeAccount] != 1 {
+		t.Fatalf("account lockouts observed = %d, want 1", obs.lockouts[ScopeAccount])
+	}
+
+	mr.advance(time.Hour)
+	if d, _ = g.Check(ctx, "a@example.com", "10.0.0.1"); !d.Allowed {
+		t.Fatalf("Check after lockout = %+v", d)
+	}
+}
+
+func TestGuard_IPLockout(t *testing.T) {
+	ctx := context.Background()
+	g, mr, obs := newTestGuard(t, Config{IPLockAfter: 3, IPLockout: time.Hour})
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if d, _ := g.Check(ctx, email, "10.0.0.1"); !d.Allowed {
+			t.Fatalf("%s: Check = %+v", email, d)
+		}
+		_, _ = g.Fail(ctx, email, "10.0.0.1")
+	}
+	if obs.lockouts[ScopeIP] != 1 {
+		t.Fatalf("ip lockouts observed = %d, want 1", obs.lockouts[ScopeIP])
+	}
+	if ttl := mr.TTL(g.keys("", "10.0.0.1")[1]); ttl != time.Hour {
+		t.Fatalf("ip key ttl = %v, want 1h", ttl)
+	}
+
+	// удачный вход не снимает блокировку IP
+	if err := g.Success(ctx, "d@example.com", "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	d, _ := g.Check(ctx, "d@example.com", "10.0.0.1")
+	if d.Allowed || d.Scope != ScopeIP || !d.Locked {
+		t.Fatalf("Check from locked ip = %+v", d)
+	}
+	if d, _ = g.Check(ctx, "d@example.com", "10.0.0.2"); !d.Allowed {
+		t.Fatalf("Check from other ip = %+v", d)
+	}
+}
+
+func TestGuard_SuccessResetsAccount(t *testing.T) {
+	ctx := context.Background()
+	g, mr, _ := newTestGuard(t, Config{FreeAttempts: 1})
+	keys := g.keys("a@example.com", "10.0.0.1")
+
+	_, _ = g.Fail(ctx, "a@example.com", "10.0.0.1")
+	if d, _ := g.Check(ctx, "a@example.com", "10.0.0.1"); !d.Allowed {
+		t.Fatalf("Check = %+v", d)
+	}
+	if err := g.Success(ctx, "a@example.com", "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists(keys[0]) {
+		t.Fatal("account counter not reset")
+	}
+	if v := mr.HGet(keys[1], "fails"); v != "1" {
+		t.Fatalf("ip fails = %q, want 1", v)
+	}
+	for _, k := range keys[2:] {
+		if v, _ := mr.Get(k); v != "0" {
+			t.Fatalf("%s = %q, want 0 (reservation released)", k, v)
+		}
+	}
+}
+
+// Параллельные попытки резервируются в Check: пройти могут только те, что укладываются в
+// бесплатные, остальные ждут исхода уже идущих.
+func TestGuard_ConcurrentCheck(t *testing.T) {
+	ctx := context.Background()
+	g, _, _ := newTestGuard(t, Config{FreeAttempts: 3, BaseDelay: time.Second})
+
+	run := func(n int) (allowed int) {
+		var (
+			wg sync.WaitGroup
+			mu sync.Mutex
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d, err := g.Check(ctx, "a@example.com", "10.0.0.1")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !d.Allowed && (d.Scope != ScopeAccount || d.RetryAfter != time.Second || d.Locked) {
+					t.Errorf("blocked Check = %+v, want account pause of base", d)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if d.Allowed {
+					allowed++
+				}
+			}()
+		}
+		wg.Wait()
+		return allowed
+	}
+
+	if got := run(20); got != 3 {
+		t.Fatalf("first burst: %d allowed, want 3", got)
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = g.Fail(ctx, "a@example.com", "10.0.0.1")
+	}
+	// бесплатные исчерпаны: следующая неудача даст паузу, поэтому в полёте — не больше одной
+	if got := run(20); got != 1 {
+		t.Fatalf("second burst: %d allowed, want 1", got)
+	}
+}
+
+func TestGuard_ReservationExpires(t *testing.T) {
+	ctx := context.Background()
+	g, mr, _ := newTestGuard(t, Config{FreeAttempts: 1, AttemptTimeout: 10 * time.Second})
+
+	if d, _ := g.Check(ctx, "a@example.com", "10.0.0.1"); !d.Allowed {
+		t.Fatalf("Check = %+v", d)
+	}
+	if d, _ := g.Check(ctx, "a@example.com", "10.0.0.1"); d.Allowed {
+		t.Fatal("second Check allowed while first is in flight")
+	}
+	// запрос оборвался — ни Fail, ни Success: резерв истекает сам
+	mr.advance(10 * time.Second)
+	if d, _ := g.Check(ctx, "a@example.com", "10.0.0.1"); !d.Allowed {
+		t.Fatalf("Check after reservation expiry = %+v", d)
+	}
+}
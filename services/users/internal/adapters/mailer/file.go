@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"goshop/services/users/internal/app"
+)
+
+// File — почта для локальной разработки: письмо пишется в лог целиком (ссылки видны сразу),
+// а при заданном dir ещё и сохраняется как .eml — его можно открыть почтовым клиентом.
+type File struct {
+	log  *slog.Logger
+	from string
+	dir  string
+}
+
+var _ app.Mailer = (*File)(nil)
+
+func NewFile(log *slog.Logger, from, dir string) (*File, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("mail dir: %w", err)
+		}
+	}
+	return &File{log: log, from: from, dir: dir}, nil
+}
+
+func (f *File) Send(_ context.Context, m app.Message) error {
+	now := time.Now()
+	attrs := []any{
+		slog.String("to", m.To),
+		slog.String("subject", m.Subject),
+		slog.String("body", m.Body),
+	}
+	if f.dir != "" {
+		msg, err := build(f.from, m, now)
+		if err != nil {
+			return err
+		}
+		name := filepath.Join(f.dir, fmt.Sprintf("%d-%s.eml", now.UnixNano(), sanitize(m.To)))
+		if err := os.WriteFile(name, msg, 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		attrs = append(attrs, slog.String("file", name))
+	}
+	f.log.Info("users.mail: message", attrs...)
+	return nil
+}
+
+// sanitize — адрес в имени файла без разделителей пути.
+func sanitize(addr string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', 0:
+			return '_'
+		}
+		return r
+	}, addr)
+}
@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goshop/services/users/internal/app"
+)
+
+func TestFileWritesEML(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewFile(slog.New(slog.NewTextHandler(io.Discard, nil)), "goshop <no-reply@goshop.local>", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ссылка длиннее 76 символов: quoted-printable переносит строку, после декодирования она целая
+	link := "http://localhost:3000/reset?token=" + strings.Repeat("x", 80)
+	if err := f.Send(context.Background(), app.Message{To: "a@example.com", Subject: "Сброс пароля", Body: "ссылка:\n" + link + "\n"}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("eml files: %v", files)
+	}
+	raw, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = raw.Close() }()
+	msg, err := mail.ReadMessage(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subj, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subj != "Сброс пароля" {
+		t.Errorf("subject = %q (%v)", subj, err)
+	}
+	if to := msg.Header.Get("To"); to != "a@example.com" {
+		t.Errorf("to = %q", to)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), link) {
+		t.Errorf("body does not contain link:\n%s", body)
+	}
+}
@@ -0,0 +1,291 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"goshop/pkg/jwtauth"
+	"goshop/services/users/internal/app"
+)
+
+// Config — клиент, зарегистрированный у провайдера. Issuer — откуда берётся discovery
+// (<issuer>/.well-known/openid-configuration); RedirectURL — наш callback.
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string // по умолчанию openid email profile
+	Timeout      time.Duration
+	HTTPClient   *http.Client
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider — OIDC authorization code flow с PKCE. Discovery и ключи загружаются при первом
+// обращении и кэшируются: недоступный провайдер не мешает старту users.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu     sync.Mutex
+	meta   *metadata
+	keys   map[string]crypto.PublicKey
+	keysAt time.Time
+}
+
+var _ app.IdentityProvider = (*Provider)(nil)
+
+// keysMinRefresh — незнакомый kid перечитывает JWKS не чаще этого (ротация у провайдера)
+const keysMinRefresh = time.Minute
+
+func New(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	p := &Provider{cfg: cfg, client: cfg.HTTPClient}
+	if p.client == nil {
+		p.client = &http.Client{Timeout: cfg.Timeout}
+	}
+	return p
+}
+
+func (p *Provider) Name() string { return p.cfg.Name }
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("oidc %s: authorization_endpoint: %w", p.cfg.Name, err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+type tokenResp struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange — code и PKCE verifier на токены; отказ провайдера и невалидный ID token — ErrInvalidIdentity.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (app.Identity, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return app.Identity{}, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+		"client_id":     {p.cfg.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return app.Identity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return app.Identity{}, fmt.Errorf("%w: %s: token: %v", app.ErrProviderUnavailable, p.cfg.Name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var tr tokenResp
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tr); err != nil {
+		return app.Identity{}, fmt.Errorf("%w: %s: token: status %d: %v", app.ErrProviderUnavailable, p.cfg.Name, resp.StatusCode, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized:
+		return app.Identity{}, fmt.Errorf("%w: %s: %s %s", app.ErrInvalidIdentity, p.cfg.Name, tr.Error, tr.ErrorDescription)
+	case resp.StatusCode != http.StatusOK:
+		return app.Identity{}, fmt.Errorf("%w: %s: token: status %d", app.ErrProviderUnavailable, p.cfg.Name, resp.StatusCode)
+	case tr.IDToken == "":
+		return app.Identity{}, fmt.Errorf("%w: %s: no id_token", app.ErrInvalidIdentity, p.cfg.Name)
+	}
+	return p.verify(ctx, meta, tr.IDToken, nonce)
+}
+
+type idClaims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"` // bool; у некоторых провайдеров — строка "true"
+	AZP           string `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// verify — подпись по JWKS провайдера, iss, aud (и azp при нескольких aud), exp, nonce.
+func (p *Provider) verify(ctx context.Context, meta *metadata, raw, nonce string) (app.Identity, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	var cl idClaims
+	_, err := parser.ParseWithClaims(raw, &cl, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, meta, kid, t.Method)
+	})
+	if err != nil {
+		return app.Identity{}, fmt.Errorf("%w: %s: id_token: %v", app.ErrInvalidIdentity, p.cfg.Name, err)
+	}
+	if cl.Nonce == "" || cl.Nonce != nonce {
+		return app.Identity{}, fmt.Errorf("%w: %s: nonce mismatch", app.ErrInvalidIdentity, p.cfg.Name)
+	}
+	if len(cl.Audience) > 1 && cl.AZP != p.cfg.ClientID {
+		return app.Identity{}, fmt.Errorf("%w: %s: azp mismatch", app.ErrInvalidIdentity, p.cfg.Name)
+	}
+	if cl.Subject == "" {
+		return app.Identity{}, fmt.Errorf("%w: %s: no sub", app.ErrInvalidIdentity, p.cfg.Name)
+	}
+	verified := false
+	switch v := cl.EmailVerified.(type) {
+	case bool:
+		verified = v
+	case string:
+		verified = v == "true"
+	}
+	return app.Identity{Provider: p.cfg.Name, Subject: cl.Subject, Email: cl.Email, EmailVerified: verified}, nil
+}
+
+// key — ключ по kid; алгоритм токена должен соответствовать типу ключа.
+func (p *Provider) key(ctx context.Context, meta *metadata, kid string, method jwt.SigningMethod) (any, error) {
+	p.mu.Lock()
+	k, ok := p.keys[kid]
+	stale := time.Since(p.keysAt) >= keysMinRefresh
+	p.mu.Unlock()
+
+	if !ok && (p.keys == nil || stale) {
+		if err := p.fetchKeys(ctx, meta); err != nil {
+			return nil, err
+		}
+		p.mu.Lock()
+		k, ok = p.keys[kid]
+		p.mu.Unlock()
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	switch k.(type) {
+	case *rsa.PublicKey:
+		if method.Alg() == jwt.SigningMethodRS256.Alg() {
+			return k, nil
+		}
+	case ed25519.PublicKey:
+		if method.Alg() == jwt.SigningMethodEdDSA.Alg() {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("kid %q: alg %s does not match key", kid, method.Alg())
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, meta *metadata) error {
+	var set jwtauth.JWKS
+	if err := p.getJSON(ctx, meta.JWKSURI, &set); err != nil {
+		return fmt.Errorf("oidc %s: jwks: %w", p.cfg.Name, err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		k, err := j.Key()
+		if err != nil {
+			continue // незнакомый тип ключа не должен ломать остальные
+		}
+		keys[k.ID] = k.Public
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysAt = time.Now()
+	p.mu.Unlock()
+	return nil
+}
+
+// discover — метаданные провайдера; ошибка не кэшируется, следующий вход попробует снова.
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	meta := p.meta
+	p.mu.Unlock()
+	if meta != nil {
+		return meta, nil
+	}
+
+	var m metadata
+	wellKnown := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, &m); err != nil {
+		return nil, fmt.Errorf("%w: %s: discovery: %v", app.ErrProviderUnavailable, p.cfg.Name, err)
+	}
+	// OIDC Discovery §4.3: issuer в документе должен совпадать с тем, у кого спрашивали
+	if m.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("oidc %s: discovery: issuer %q, want %q", p.cfg.Name, m.Issuer, p.cfg.Issuer)
+	}
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JWKSURI == "" {
+		return nil, errors.New("oidc " + p.cfg.Name + ": discovery: incomplete metadata")
+	}
+
+	p.mu.Lock()
+	p.meta = &m
+	p.mu.Unlock()
+	return &m, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
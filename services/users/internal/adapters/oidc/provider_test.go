@@ -0,0 +1,87 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"goshop/services/users/internal/adapters/oidc/oidctest"
+	"goshop/services/users/internal/app"
+)
+
+func newProvider(t *testing.T) (*oidctest.Provider, *Provider) {
+	t.Helper()
+	mock, err := oidctest.New("goshop", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mock.Close)
+	return mock, New(Config{
+		Name:         "mock",
+		Issuer:       mock.Issuer(),
+		ClientID:     "goshop",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://localhost/v1/users/oidc/mock/callback",
+	})
+}
+
+func authorize(t *testing.T, mock *oidctest.Provider, p *Provider, state, nonce, verifier string) string {
+	t.Helper()
+	authURL, err := p.AuthCodeURL(context.Background(), state, nonce, app.PKCEChallenge(verifier))
+	if err != nil {
+		t.Fatalf("auth url: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if got := u.Query().Get("code_challenge_method"); got != "S256" {
+		t.Fatalf("code_challenge_method=%q", got)
+	}
+	code, gotState, err := mock.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if gotState != state {
+		t.Fatalf("state=%q, want %q", gotState, state)
+	}
+	return code
+}
+
+func TestExchange(t *testing.T) {
+	mock, p := newProvider(t)
+	mock.SetUser(oidctest.User{Subject: "42", Email: "Ann@Example.com", EmailVerified: true})
+
+	code := authorize(t, mock, p, "st", "n-1", "verifier-1")
+	id, err := p.Exchange(context.Background(), code, "verifier-1", "n-1")
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	want := app.Identity{Provider: "mock", Subject: "42", Email: "Ann@Example.com", EmailVerified: true}
+	if id != want {
+		t.Fatalf("identity=%+v, want %+v", id, want)
+	}
+
+	// code одноразовый
+	if _, err := p.Exchange(context.Background(), code, "verifier-1", "n-1"); !errors.Is(err, app.ErrInvalidIdentity) {
+		t.Fatalf("reused code: err=%v", err)
+	}
+}
+
+func TestExchange_Rejected(t *testing.T) {
+	mock, p := newProvider(t)
+
+	code := authorize(t, mock, p, "st", "n-1", "verifier-1")
+	if _, err := p.Exchange(context.Background(), code, "other-verifier", "n-1"); !errors.Is(err, app.ErrInvalidIdentity) {
+		t.Fatalf("wrong verifier: err=%v", err)
+	}
+
+	code = authorize(t, mock, p, "st", "n-1", "verifier-1")
+	if _, err := p.Exchange(context.Background(), code, "verifier-1", "n-2"); !errors.Is(err, app.ErrInvalidIdentity) {
+		t.Fatalf("wrong nonce: err=%v", err)
+	}
+
+	bad := New(Config{Name: "mock", Issuer: mock.Issuer(), ClientID: "goshop", ClientSecret: "wrong", RedirectURL: "http://localhost/cb"})
+	code = authorize(t, mock, bad, "st", "n-1", "verifier-1")
+	if _, err := bad.Exchange(context.Background(), code, "verifier-1", "n-1"); !errors.Is(err, app.ErrInvalidIdentity) {
+		t.Fatalf("wrong client secret: err=%v", err)
+	}
+}
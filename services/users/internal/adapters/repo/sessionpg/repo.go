@@ -3,7 +3,9 @@ package sessionpg
 import (
 	"context"
 	"crypto/subtle"
+	"encoding/json"
 	"errors"
+	"log/slog"
 	"net"
 	"time"
 
@@ -21,13 +23,17 @@ func New(db *pgxpool.Pool) *Repo {
 }
 
 var (
-	ErrNotFound     = errors.New("session not found")
-	ErrRevoked      = errors.New("session revoked")
-	ErrRotated      = errors.New("session already rotated")
-	ErrExpired      = errors.New("session expired")
+	ErrNotFound = errors.New("session not found")
+	ErrRevoked  = errors.New("session revoked")
+	ErrExpired  = errors.New("session expired")
+	// ErrRefreshReuse — предъявлен уже ротированный (или чужой) refresh: считаем кражей,
+	// всё семейство сессий отозвано
 	ErrRefreshReuse = errors.New("refresh reuse detected")
 )
 
+// OutboxTopic — топик событий users (аудит безопасности)
+const OutboxTopic = "users.events"
+
 func (r *Repo) CreateSession(
 	ctx context.Context,
 	sessionID uuid.UUID,
@@ -38,9 +44,10 @@ func (r *Repo) CreateSession(
 	ip net.IP,
 ) (uuid.UUID, error) {
 
+	// новая сессия логина открывает своё семейство
 	const q = `
-        INSERT INTO sessions (id, user_id, refresh_hash, user_agent, ip, expires_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO sessions (id, family_id, user_id, refresh_hash, user_agent, ip, expires_at)
+        VALUES ($1, $1, $2, $3, $4, $5, $6)
         RETURNING id;
     `
 	var id uuid.UUID
@@ -68,19 +75,20 @@ func (r *Repo) RotateSession(
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	const sel = `
-		SELECT user_id, refresh_hash, revoked_at, rotated_at, expires_at
+		SELECT user_id, family_id, refresh_hash, revoked_at, rotated_at, expires_at
 		FROM sessions
 		WHERE id = $1
 		FOR UPDATE;
 	`
 	var (
 		userID    uuid.UUID
+		familyID  uuid.UUID
 		dbHash    []byte
 		revokedAt *time.Time
 		rotatedAt *time.Time
 		expiresAt time.Time
 	)
-	if err := tx.QueryRow(ctx, sel, oldID).Scan(&userID, &dbHash, &revokedAt, &rotatedAt, &expiresAt); err != nil {
+	if err := tx.QueryRow(ctx, sel, oldID).Scan(&userID, &familyID, &dbHash, &revokedAt, &rotatedAt, &expiresAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return uuid.Nil, ErrNotFound
 		}
@@ -89,19 +97,20 @@ func (r *Repo) RotateSession(
 	if revokedAt != nil {
 		return uuid.Nil, ErrRevoked
 	}
-	if rotatedAt != nil {
-		return uuid.Nil, ErrRotated
+	// повторное предъявление ротированного токена: у кого-то есть его копия — неизвестно, у вора
+	// или у владельца, поэтому отзываем всех потомков (и текущую живую сессию семейства)
+	if rotatedAt != nil || subtle.ConstantTimeCompare(dbHash, oldHash) != 1 {
+		if err := revokeFamilyTx(ctx, tx, userID, familyID, oldID, userAgent, ip); err != nil {
+			return uuid.Nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return uuid.Nil, err
+		}
+		return uuid.Nil, ErrRefreshReuse
 	}
 	if time.Now().After(expiresAt) {
 		return uuid.Nil, ErrExpired
 	}
-	if subtle.ConstantTimeCompare(dbHash, oldHash) != 1 {
-		const revoke = `UPDATE sessions SET revoked_at = now() WHERE id = $1;`
-		if _, uerr := tx.Exec(ctx, revoke, oldID); uerr != nil {
-			return uuid.Nil, uerr
-		}
-		return uuid.Nil, ErrRefreshReuse
-	}
 
 	const markRotated = `UPDATE sessions SET rotated_at = now() WHERE id = $1;`
 	if _, err := tx.Exec(ctx, markRotated, oldID); err != nil {
@@ -109,12 +118,12 @@ func (r *Repo) RotateSession(
 	}
 
 	const ins = `
-		INSERT INTO sessions (id, user_id, refresh_hash, user_agent, ip, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO sessions (id, family_id, parent_id, user_id, refresh_hash, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id;
 	`
 	var ret uuid.UUID
-	if err := tx.QueryRow(ctx, ins, newID, userID, newHash, nullIfEmpty(userAgent), ip, newExpiresAt).Scan(&ret); err != nil {
+	if err := tx.QueryRow(ctx, ins, newID, familyID, oldID, userID, newHash, nullIfEmpty(userAgent), ip, newExpiresAt).Scan(&ret); err != nil {
 		return uuid.Nil, err
 	}
 
@@ -124,6 +133,55 @@ func (r *Repo) RotateSession(
 	return ret, nil
 }
 
+// revokeFamilyTx — отзыв семейства и событие аудита в users_outbox в той же транзакции.
+func revokeFamilyTx(ctx context.Context, tx pgx.Tx, userID, familyID, replayedID uuid.UUID, userAgent string, ip net.IP) error {
+	const revoke = `
+		UPDATE sessions
+		SET revoked_at = now()
+		WHERE family_id = $1 AND revoked_at IS NULL;
+	`
+	ct, err := tx.Exec(ctx, revoke, familyID)
+	if err != nil {
+		return err
+	}
+
+	type reuseDetected struct {
+		Event      string    `json:"event"`
+		Version    int       `json:"version"`
+		UserID     uuid.UUID `json:"user_id"`
+		FamilyID   uuid.UUID `json:"family_id"`
+		SessionID  uuid.UUID `json:"session_id"` // чей refresh предъявлен повторно
+		Revoked    int64     `json:"revoked_sessions"`
+		UserAgent  string    `json:"user_agent,omitempty"`
+		IP         string    `json:"ip,omitempty"`
+		DetectedAt time.Time `json:"detected_at"`
+	}
+	ev := reuseDetected{
+		Event:      "user.session_reuse_detected",
+		Version:    1,
+		UserID:     userID,
+		FamilyID:   familyID,
+		SessionID:  replayedID,
+		Revoked:    ct.RowsAffected(),
+		UserAgent:  userAgent,
+		DetectedAt: time.Now().UTC(),
+	}
+	if ip != nil {
+		ev.IP = ip.String()
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	// key — user_id: уведомления пользователя идут по порядку
+	const insOutbox = `
+		INSERT INTO users_outbox (agg_type, agg_id, topic, key, headers, payload)
+		VALUES ('user', $1, $2, $3, '[{"K":"event-type","V":"user.session_reuse_detected"}]'::jsonb, $4::jsonb);
+	`
+	_, err = tx.Exec(ctx, insOutbox, userID, OutboxTopic, userID[:], payload)
+	return err
+}
+
 func (r *Repo) RevokeSession(ctx context.Context, id uuid.UUID) error {
 	const q = `UPDATE sessions SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL;`
 	ct, err := r.db.Exec(ctx, q, id)
@@ -134,17 +192,185 @@ func (r *Repo) RevokeSession(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (r *Repo) RevokeAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+// RevokeAll — отзыв всех сессий пользователя; families — их семейства (sid в access-токенах).
+func (r *Repo) RevokeAll(ctx context.Context, userID uuid.UUID) (n int64, families []uuid.UUID, err error) {
+	const q = `
+		UPDATE sessions
+		SET revoked_at = now()
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING family_id;
+	`
+	rows, err := r.db.Query(ctx, q, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	seen := map[uuid.UUID]bool{}
+	for rows.Next() {
+		var f uuid.UUID
+		if err := rows.Scan(&f); err != nil {
+			return 0, nil, err
+		}
+		n++
+		if !seen[f] {
+			seen[f] = true
+			families = append(families, f)
+		}
+	}
+	return n, families, rows.Err()
+}
+
+// RevokeOthers — как RevokeAll, но семейство keep (текущее устройство) остаётся живым.
+func (r *Repo) RevokeOthers(ctx context.Context, userID, keep uuid.UUID) (families []uuid.UUID, err error) {
 	const q = `
 		UPDATE sessions
 		SET revoked_at = now()
-		WHERE user_id = $1 AND revoked_at IS NULL;
+		WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL
+		RETURNING family_id;
 	`
-	ct, err := r.db.Exec(ctx, q, userID)
+	rows, err := r.db.Query(ctx, q, userID, keep)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := map[uuid.UUID]bool{}
+	for rows.Next() {
+		var f uuid.UUID
+		if err := rows.Scan(&f); err != nil {
+			return nil, err
+		}
+		if !seen[f] {
+			seen[f] = true
+			families = append(families, f)
+		}
+	}
+	return families, rows.Err()
+}
+
+// Session — активное устройство: последняя живая сессия семейства. ID — family_id (он же sid
+// в токенах): не меняется при ротации refresh.
+type Session struct {
+	ID         uuid.UUID
+	UserAgent  *string
+	IP         *string
+	CreatedAt  time.Time // логин (первая сессия семейства)
+	LastUsedAt time.Time // последняя ротация refresh
+	ExpiresAt  time.Time
+}
+
+func (r *Repo) ListActive(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	const q = `
+		SELECT s.family_id, s.user_agent, host(s.ip), f.started_at, s.created_at, s.expires_at
+		FROM sessions s
+		CROSS JOIN LATERAL (
+			SELECT min(created_at) AS started_at FROM sessions WHERE family_id = s.family_id
+		) f
+		WHERE s.user_id = $1
+		  AND s.revoked_at IS NULL AND s.rotated_at IS NULL AND s.expires_at > now()
+		ORDER BY s.created_at DESC;
+	`
+	rows, err := r.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Session{}
+	for rows.Next() {
+		var s Session
+		if err := rows.Scan(&s.ID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// RevokeFamily — выход с одного устройства по family_id; ErrNotFound — нет живой сессии
+// с таким id у этого пользователя.
+func (r *Repo) RevokeFamily(ctx context.Context, userID, familyID uuid.UUID) error {
+	const q = `
+		UPDATE sessions
+		SET revoked_at = now()
+		WHERE user_id = $1 AND family_id = $2 AND revoked_at IS NULL;
+	`
+	ct, err := r.db.Exec(ctx, q, userID, familyID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type CleanupConfig struct {
+	Retention time.Duration // сколько хранить истёкшие/отозванные сессии (для аудита и reuse detection)
+	Interval  time.Duration
+	Batch     int
+}
+
+// Cleanup удаляет сессии, истёкшие или отозванные раньше now-retention, пачками по batch.
+// Ротированные, но не отозванные строки живут до своего expires_at: по ним ловится повтор refresh.
+func (r *Repo) Cleanup(ctx context.Context, retention time.Duration, batch int) (int64, error) {
+	const q = `
+		DELETE FROM sessions
+		WHERE id IN (
+			SELECT id FROM sessions
+			WHERE expires_at < $1 OR revoked_at < $1
+			LIMIT $2
+		);
+	`
+	var total int64
+	for {
+		ct, err := r.db.Exec(ctx, q, time.Now().Add(-retention), batch)
+		if err != nil {
+			return total, err
+		}
+		total += ct.RowsAffected()
+		if ct.RowsAffected() < int64(batch) {
+			return total, nil
+		}
+	}
+}
+
+// RunCleanup — Cleanup каждые cfg.Interval до отмены ctx; ошибка прохода логируется.
+func (r *Repo) RunCleanup(ctx context.Context, log *slog.Logger, cfg CleanupConfig) error {
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.Batch <= 0 {
+		cfg.Batch = 1000
+	}
+	log.Info("users.sessions: cleanup started",
+		slog.Duration("retention", cfg.Retention),
+		slog.Duration("interval", cfg.Interval),
+		slog.Int("batch", cfg.Batch),
+	)
+	t := time.NewTicker(cfg.Interval)
+	defer t.Stop()
+
+	for {
+		if n, err := r.Cleanup(ctx, cfg.Retention, cfg.Batch); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Error("users.sessions: cleanup failed", slog.Any("err", err))
+		} else if n > 0 {
+			log.Info("users.sessions: cleaned up", slog.Int64("deleted", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
 	}
-	return ct.RowsAffected(), nil
 }
 
 func nullIfEmpty(s string) any {
@@ -0,0 +1,97 @@
+package userpg
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"goshop/services/users/internal/app"
+	domain "goshop/services/users/internal/domain/user"
+)
+
+var _ app.IdentityRepository = (*Repository)(nil)
+
+func (r *Repository) UserByIdentity(ctx context.Context, id app.Identity) (domain.User, error) {
+	q := `
+		WITH i AS (
+			UPDATE user_identities SET email = $3, last_login_at = now()
+			WHERE provider = $1 AND subject = $2
+			RETURNING user_id
+		)
+		SELECT ` + userColumnsOf("u") + `
+		FROM users u JOIN i ON i.user_id = u.id
+		WHERE u.deleted_at IS NULL;
+	`
+	return scanUser(r.db.QueryRow(ctx, q, id.Provider, id.Subject, nullIfEmpty(id.Email)))
+}
+
+// CreateWithIdentity — аккаунт без пароля; email подтверждён, если его подтвердил провайдер.
+func (r *Repository) CreateWithIdentity(ctx context.Context, id app.Identity) (domain.User, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const ins = `
+		INSERT INTO users (email, password_hash, email_verified_at)
+		VALUES ($1, NULL, CASE WHEN $2 THEN now() END)
+		RETURNING ` + userColumns
+	u, err := scanUser(tx.QueryRow(ctx, ins, domain.NormalizeEmail(id.Email), id.EmailVerified))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.User{}, ErrEmailTaken
+		}
+		return domain.User{}, err
+	}
+	if err := insertIdentity(ctx, tx, u.ID, id); err != nil {
+		return domain.User{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+func (r *Repository) LinkIdentity(ctx context.Context, userID uuid.UUID, id app.Identity, takeover bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := insertIdentity(ctx, tx, userID, id); err != nil {
+		return err
+	}
+	if takeover {
+		// пароль задал тот, кто зарегистрировал адрес, не подтвердив его, — он больше не действует
+		if _, err := tx.Exec(ctx, `
+			UPDATE users SET password_hash = NULL, email_verified_at = now(), updated_at = now()
+			WHERE id = $1;
+		`, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// insertIdentity — ErrIdentityConflict, если у аккаунта уже есть другая identity этого провайдера
+// (или эту identity только что привязал параллельный запрос).
+func insertIdentity(ctx context.Context, tx pgx.Tx, userID uuid.UUID, id app.Identity) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO user_identities (provider, subject, user_id, email)
+		VALUES ($1, $2, $3, $4);
+	`, id.Provider, id.Subject, userID, nullIfEmpty(id.Email))
+	if isUniqueViolation(err) {
+		return app.ErrIdentityConflict
+	}
+	return err
+}
+
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,109 @@
+package userpg
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"goshop/services/users/internal/app"
+)
+
+var _ app.MFARepository = (*Repository)(nil)
+
+func (r *Repository) GetMFA(ctx context.Context, userID uuid.UUID) (app.MFAState, error) {
+	var st app.MFAState
+	err := r.db.QueryRow(ctx, `
+		SELECT secret, confirmed_at, last_step FROM user_mfa WHERE user_id = $1;
+	`, userID).Scan(&st.Secret, &st.ConfirmedAt, &st.LastStep)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return app.MFAState{}, ErrNotFound
+	}
+	return st, err
+}
+
+func (r *Repository) SaveMFASecret(ctx context.Context, userID uuid.UUID, secret []byte) error {
+	const q = `
+		INSERT INTO user_mfa (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_step = 0, created_at = now()
+		WHERE user_mfa.confirmed_at IS NULL;
+	`
+	ct, err := r.db.Exec(ctx, q, userID, secret)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return app.ErrMFAEnabled
+	}
+	return nil
+}
+
+func (r *Repository) ConfirmMFA(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes [][]byte) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx, `
+		UPDATE user_mfa SET confirmed_at = now(), last_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL;
+	`, userID, step)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return app.ErrMFAEnabled
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1;`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO user_recovery_codes (user_id, code_hash)
+		SELECT $1, unnest($2::bytea[]);
+	`, userID, recoveryHashes); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) UseMFAStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	ct, err := r.db.Exec(ctx, `
+		UPDATE user_mfa SET last_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_step < $2;
+	`, userID, step)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() == 1, nil
+}
+
+func (r *Repository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash []byte) (bool, error) {
+	ct, err := r.db.Exec(ctx, `
+		UPDATE user_recovery_codes SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+	`, userID, hash)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() == 1, nil
+}
+
+func (r *Repository) DeleteMFA(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1;`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_mfa WHERE user_id = $1;`, userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,30 @@
+package userpg
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"goshop/services/users/internal/adapters/repo/sessionpg"
+)
+
+// outboxTx — событие в users_outbox в транзакции изменения; key — user_id, события
+// одного пользователя идут по порядку.
+func outboxTx(ctx context.Context, tx pgx.Tx, userID uuid.UUID, eventType string, ev any) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	headers, err := json.Marshal([]struct{ K, V string }{{K: "event-type", V: eventType}})
+	if err != nil {
+		return err
+	}
+	const q = `
+		INSERT INTO users_outbox (agg_type, agg_id, topic, key, headers, payload)
+		VALUES ('user', $1, $2, $3, $4::jsonb, $5::jsonb);
+	`
+	_, err = tx.Exec(ctx, q, userID, sessionpg.OutboxTopic, userID[:], headers, payload)
+	return err
+}
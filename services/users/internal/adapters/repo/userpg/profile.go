@@ -0,0 +1,125 @@
+package userpg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"goshop/services/users/internal/app"
+	domain "goshop/services/users/internal/domain/user"
+)
+
+var _ app.ProfileRepository = (*Repository)(nil)
+
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	const q = `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND deleted_at IS NULL`
+	return scanUser(r.db.QueryRow(ctx, q, id))
+}
+
+// UpdateProfile — только переданные поля, одним UPDATE: параллельные PATCH разных полей
+// не затирают друг друга.
+func (r *Repository) UpdateProfile(ctx context.Context, id uuid.UUID, upd app.ProfileUpdate) (domain.User, error) {
+	var address []byte
+	if upd.SetAddress && upd.DefaultAddress != nil {
+		var err error
+		if address, err = json.Marshal(upd.DefaultAddress); err != nil {
+			return domain.User{}, err
+		}
+	}
+	const q = `
+		UPDATE users SET
+			name            = CASE WHEN $2 THEN $3 ELSE name END,
+			phone           = CASE WHEN $4 THEN $5 ELSE phone END,
+			default_address = CASE WHEN $6 THEN $7::jsonb ELSE default_address END,
+			updated_at      = now()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + userColumns
+	return scanUser(r.db.QueryRow(ctx, q, id,
+		upd.Name != nil, nullIfEmptyPtr(upd.Name),
+		upd.Phone != nil, nullIfEmptyPtr(upd.Phone),
+		upd.SetAddress, address,
+	))
+}
+
+func (r *Repository) SetPassword(ctx context.Context, id uuid.UUID, passwordHash []byte) error {
+	ct, err := r.db.Exec(ctx, `
+		UPDATE users SET password_hash = $2, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NULL;
+	`, id, passwordHash)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteUser — строка users остаётся (на user_id ссылаются заказы и события), но без
+// персональных данных: email заменяется несуществующим адресом, пароль и профиль стираются.
+// Привязки провайдеров, токены, 2FA и роли удаляются, у сессий стираются IP и User-Agent.
+func (r *Repository) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var deletedAt time.Time
+	err = tx.QueryRow(ctx, `
+		UPDATE users SET
+			email             = 'deleted-' || id || '@deleted.invalid',
+			password_hash     = NULL,
+			email_verified_at = NULL,
+			name              = NULL,
+			phone             = NULL,
+			default_address   = NULL,
+			deleted_at        = now(),
+			updated_at        = now()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING deleted_at;
+	`, id).Scan(&deletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, q := range []string{
+		`DELETE FROM user_identities WHERE user_id = $1;`,
+		`DELETE FROM user_tokens WHERE user_id = $1;`,
+		`DELETE FROM user_recovery_codes WHERE user_id = $1;`,
+		`DELETE FROM user_mfa WHERE user_id = $1;`,
+		`DELETE FROM user_roles WHERE user_id = $1;`,
+		`UPDATE sessions SET user_agent = NULL, ip = NULL WHERE user_id = $1;`,
+	} {
+		if _, err := tx.Exec(ctx, q, id); err != nil {
+			return err
+		}
+	}
+
+	// без персональных данных: потребителям нужен только user_id, чтобы стереть свои
+	type userDeleted struct {
+		Event     string    `json:"event"`
+		Version   int       `json:"version"`
+		UserID    uuid.UUID `json:"user_id"`
+		DeletedAt time.Time `json:"deleted_at"`
+	}
+	ev := userDeleted{Event: "user.deleted", Version: 1, UserID: id, DeletedAt: deletedAt.UTC()}
+	if err := outboxTx(ctx, tx, id, ev.Event, ev); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func nullIfEmptyPtr(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return nullIfEmpty(*s)
+}
@@ -2,19 +2,24 @@ package userpg
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"goshop/services/users/internal/app"
 	domain "goshop/services/users/internal/domain/user"
 
-	"github.com/jackc/pgconn"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
-	ErrNotFound   = errors.New("users: not found")
-	ErrEmailTaken = errors.New("users: email already taken")
+	ErrNotFound   = app.ErrNotFound
+	ErrEmailTaken = app.ErrEmailTaken
 )
 
 type Repository struct {
@@ -27,6 +32,53 @@ func NewRepo(db *pgxpool.Pool) *Repository {
 
 var _ app.UserRepository = (*Repository)(nil)
 
+// userColumns — колонки для scanUser; запросы с JOIN подставляют их с алиасом таблицы.
+const userColumns = `id, email, password_hash, created_at, updated_at, email_verified_at, name, phone, default_address`
+
+func userColumnsOf(alias string) string {
+	return alias + "." + strings.ReplaceAll(userColumns, ", ", ", "+alias+".")
+}
+
+// isUniqueViolation — нарушение уникального индекса (23505), например занятый email. Тип ошибки —
+// из github.com/jackc/pgx/v5/pgconn: pgx v5 возвращает именно его, и *PgError из отдельного модуля
+// github.com/jackc/pgconn (v1) с ним не совпадает — errors.As молча не срабатывал бы, и вместо
+// ErrEmailTaken (409) регистрация на занятый email отдавала бы 500.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func scanUser(row pgx.Row) (domain.User, error) {
+	var (
+		u           domain.User
+		name, phone *string
+		address     []byte
+	)
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt, &u.EmailVerifiedAt, &name, &phone, &address)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, ErrNotFound
+		}
+		return domain.User{}, err
+	}
+	if name != nil {
+		u.Profile.Name = *name
+	}
+	if phone != nil {
+		u.Profile.Phone = *phone
+	}
+	if address != nil {
+		var a domain.Address
+		if err := json.Unmarshal(address, &a); err != nil {
+			return domain.User{}, fmt.Errorf("user %s: default_address: %w", u.ID, err)
+		}
+		u.Profile.DefaultAddress = &a
+	}
+	u.CreatedAt = u.CreatedAt.UTC()
+	u.UpdatedAt = u.UpdatedAt.UTC()
+	return u, nil
+}
+
 func (r *Repository) Create(ctx context.Context, email string, passwordHash []byte) (domain.User, error) {
 	email = domain.NormalizeEmail(email)
 	if err := domain.ValidateEmail(email); err != nil {
@@ -36,44 +88,126 @@ func (r *Repository) Create(ctx context.Context, email string, passwordHash []by
 	const q = `
 		INSERT INTO users (email, password_hash)
 		VALUES ($1, $2)
-		RETURNING id, email, password_hash, created_at, updated_at
-	`
-	var u domain.User
-	err := r.db.QueryRow(ctx, q, email, passwordHash).
-		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+		RETURNING ` + userColumns
+	u, err := scanUser(r.db.QueryRow(ctx, q, email, passwordHash))
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.User{}, ErrNotFound
-		}
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if isUniqueViolation(err) {
 			return domain.User{}, ErrEmailTaken
 		}
 		return domain.User{}, err
 	}
-	u.CreatedAt = u.CreatedAt.UTC()
-	u.UpdatedAt = u.UpdatedAt.UTC()
 	return u, nil
 }
 
 func (r *Repository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
 	email = domain.NormalizeEmail(email)
 
-	const q = `
-		SELECT id, email, password_hash, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-	var u domain.User
-	err := r.db.QueryRow(ctx, q, email).
-		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+	const q = `SELECT ` + userColumns + ` FROM users WHERE email = $1 AND deleted_at IS NULL`
+	return scanUser(r.db.QueryRow(ctx, q, email))
+}
+
+var _ app.TokenRepository = (*Repository)(nil)
+
+func (r *Repository) CreateToken(ctx context.Context, userID uuid.UUID, kind app.TokenKind, email string, hash []byte, expiresAt time.Time) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.User{}, ErrNotFound
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// действует только последняя отправленная ссылка
+	if _, err := tx.Exec(ctx, `
+		UPDATE user_tokens SET used_at = now()
+		WHERE user_id = $1 AND kind = $2 AND used_at IS NULL;
+	`, userID, string(kind)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO user_tokens (user_id, kind, email, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5);
+	`, userID, string(kind), nullIfEmpty(email), hash, expiresAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) VerifyEmail(ctx context.Context, hash []byte) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// и подтверждение адреса, и смена email — одна ссылка на той же странице фронта
+	var (
+		userID   uuid.UUID
+		kind     string
+		newEmail *string
+	)
+	err = tx.QueryRow(ctx, `
+		UPDATE user_tokens SET used_at = now()
+		WHERE token_hash = $1 AND kind IN ($2, $3) AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id, kind, email;
+	`, hash, string(app.TokenVerifyEmail), string(app.TokenChangeEmail)).Scan(&userID, &kind, &newEmail)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, app.ErrInvalidToken
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if app.TokenKind(kind) == app.TokenChangeEmail && newEmail != nil {
+		_, err = tx.Exec(ctx, `
+			UPDATE users SET email = $2, email_verified_at = now(), updated_at = now()
+			WHERE id = $1 AND deleted_at IS NULL;
+		`, userID, *newEmail)
+		if isUniqueViolation(err) {
+			return uuid.Nil, ErrEmailTaken
 		}
-		return domain.User{}, err
+	} else {
+		_, err = tx.Exec(ctx, `
+			UPDATE users SET email_verified_at = coalesce(email_verified_at, now()), updated_at = now()
+			WHERE id = $1;
+		`, userID)
 	}
-	u.CreatedAt = u.CreatedAt.UTC()
-	u.UpdatedAt = u.UpdatedAt.UTC()
-	return u, nil
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userID, tx.Commit(ctx)
+}
+
+// ResetPassword — ссылка пришла на этот адрес, так что email заодно считается подтверждённым.
+func (r *Repository) ResetPassword(ctx context.Context, hash, passwordHash []byte) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	userID, err := consumeToken(ctx, tx, app.TokenResetPassword, hash)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE users
+		SET password_hash = $2, email_verified_at = coalesce(email_verified_at, now()), updated_at = now()
+		WHERE id = $1;
+	`, userID, passwordHash); err != nil {
+		return uuid.Nil, err
+	}
+	return userID, tx.Commit(ctx)
+}
+
+// consumeToken — погасить действующий токен; повторное предъявление даёт ErrInvalidToken.
+func consumeToken(ctx context.Context, tx pgx.Tx, kind app.TokenKind, hash []byte) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := tx.QueryRow(ctx, `
+		UPDATE user_tokens SET used_at = now()
+		WHERE token_hash = $1 AND kind = $2 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id;
+	`, hash, string(kind)).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, app.ErrInvalidToken
+	}
+	return userID, err
 }
@@ -0,0 +1,29 @@
+package userpg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Регистрация на занятый email: ошибка pgx v5 с кодом 23505 распознаётся и через обёртку.
+func TestIsUniqueViolation(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unique", &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}, true},
+		{"wrapped", fmt.Errorf("scan: %w", &pgconn.PgError{Code: "23505"}), true},
+		{"foreign key", &pgconn.PgError{Code: "23503"}, false},
+		{"other", errors.New("23505"), false},
+		{"nil", nil, false},
+	}
+	for _, c := range cases {
+		if got := isUniqueViolation(c.err); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
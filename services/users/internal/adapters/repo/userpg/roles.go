@@ -0,0 +1,123 @@
+package userpg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"goshop/services/users/internal/app"
+)
+
+var _ app.RoleRepository = (*Repository)(nil)
+
+func (r *Repository) ListRoles(ctx context.Context) ([]app.Role, error) {
+	rows, err := r.db.Query(ctx, `SELECT name, description FROM roles ORDER BY name;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []app.Role{}
+	for rows.Next() {
+		var role app.Role
+		if err := rows.Scan(&role.Name, &role.Description); err != nil {
+			return nil, err
+		}
+		out = append(out, role)
+	}
+	return out, rows.Err()
+}
+
+func (r *Repository) UserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	const q = `
+		SELECT coalesce(array_agg(ur.role ORDER BY ur.role) FILTER (WHERE ur.role IS NOT NULL), '{}')
+		FROM users u
+		LEFT JOIN user_roles ur ON ur.user_id = u.id
+		WHERE u.id = $1
+		GROUP BY u.id;
+	`
+	var roles []string
+	err := r.db.QueryRow(ctx, q, userID).Scan(&roles)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return roles, err
+}
+
+// GrantRole — назначение и событие аудита user.role_granted в одной транзакции.
+func (r *Repository) GrantRole(ctx context.Context, userID uuid.UUID, role string, grantedBy uuid.UUID) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var by any
+	if grantedBy != uuid.Nil {
+		by = grantedBy
+	}
+	ct, err := tx.Exec(ctx, `
+		INSERT INTO user_roles (user_id, role, granted_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, role) DO NOTHING;
+	`, userID, role, by)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			switch pgErr.ConstraintName {
+			case "user_roles_role_fkey":
+				return false, app.ErrUnknownRole
+			case "user_roles_user_id_fkey":
+				return false, ErrNotFound
+			}
+		}
+		return false, err
+	}
+	if ct.RowsAffected() == 0 {
+		return false, nil
+	}
+	if err := roleEventTx(ctx, tx, "user.role_granted", userID, role, grantedBy); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+func (r *Repository) RevokeRole(ctx context.Context, userID uuid.UUID, role string, revokedBy uuid.UUID) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx, `DELETE FROM user_roles WHERE user_id = $1 AND role = $2;`, userID, role)
+	if err != nil {
+		return false, err
+	}
+	if ct.RowsAffected() == 0 {
+		return false, nil
+	}
+	if err := roleEventTx(ctx, tx, "user.role_revoked", userID, role, revokedBy); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+func roleEventTx(ctx context.Context, tx pgx.Tx, event string, userID uuid.UUID, role string, actor uuid.UUID) error {
+	type roleChanged struct {
+		Event     string     `json:"event"`
+		Version   int        `json:"version"`
+		UserID    uuid.UUID  `json:"user_id"`
+		Role      string     `json:"role"`
+		ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+		ChangedAt time.Time  `json:"changed_at"`
+	}
+	ev := roleChanged{Event: event, Version: 1, UserID: userID, Role: role, ChangedAt: time.Now().UTC()}
+	if actor != uuid.Nil {
+		ev.ActorID = &actor
+	}
+	return outboxTx(ctx, tx, userID, event, ev)
+}
@@ -0,0 +1,264 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	domain "goshop/services/users/internal/domain/user"
+)
+
+// TokenKind — назначение одноразового токена из письма.
+type TokenKind string
+
+const (
+	TokenVerifyEmail   TokenKind = "verify_email"
+	TokenResetPassword TokenKind = "reset_password"
+	// TokenChangeEmail — подтверждение нового адреса: email меняется, только когда ссылку откроют
+	TokenChangeEmail TokenKind = "change_email"
+)
+
+var (
+	// ErrInvalidToken — токена нет, он истёк или уже использован (причину наружу не раскрываем).
+	ErrInvalidToken = errors.New("users: invalid or expired token")
+	ErrSameEmail    = errors.New("users: email unchanged")
+)
+
+// TokenRepository — хранилище одноразовых токенов; в БД лежит только sha256 токена.
+type TokenRepository interface {
+	GetByEmail(ctx context.Context, email string) (domain.User, error)
+	// CreateToken — новый токен; прежние неиспользованные того же вида у пользователя гасятся.
+	// email — новый адрес для TokenChangeEmail, для остальных пусто.
+	CreateToken(ctx context.Context, userID uuid.UUID, kind TokenKind, email string, hash []byte, expiresAt time.Time) error
+	// VerifyEmail — погасить токен подтверждения и отметить email подтверждённым; токен смены
+	// email заодно меняет адрес (ErrEmailTaken — его успели занять).
+	VerifyEmail(ctx context.Context, hash []byte) (uuid.UUID, error)
+	// ResetPassword — погасить токен сброса и сменить пароль в одной транзакции.
+	ResetPassword(ctx context.Context, hash, passwordHash []byte) (uuid.UUID, error)
+}
+
+// Message — исходящее письмо (text/plain).
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer — отправка писем: SMTP в проде, файл/лог локально.
+type Mailer interface {
+	Send(ctx context.Context, m Message) error
+}
+
+type AccountsConfig struct {
+	// VerifyURL/ResetURL — страницы фронта; токен добавляется параметром token
+	VerifyURL string
+	ResetURL  string
+	VerifyTTL time.Duration
+	ResetTTL  time.Duration
+	// RequireVerifiedEmail — не пускать в login, пока email не подтверждён
+	RequireVerifiedEmail bool
+	SendTimeout          time.Duration
+}
+
+// Accounts — подтверждение и смена email, сброс пароля по ссылкам из писем.
+type Accounts struct {
+	log        *slog.Logger
+	repo       TokenRepository
+	mailer     Mailer
+	cfg        AccountsConfig
+	bcryptCost int
+}
+
+func NewAccounts(log *slog.Logger, repo TokenRepository, mailer Mailer, cfg AccountsConfig, bcryptCost int) *Accounts {
+	if cfg.VerifyTTL <= 0 {
+		cfg.VerifyTTL = 48 * time.Hour
+	}
+	if cfg.ResetTTL <= 0 {
+		cfg.ResetTTL = time.Hour
+	}
+	if cfg.SendTimeout <= 0 {
+		cfg.SendTimeout = 10 * time.Second
+	}
+	if bcryptCost <= 0 {
+		bcryptCost = 12
+	}
+	return &Accounts{log: log, repo: repo, mailer: mailer, cfg: cfg, bcryptCost: bcryptCost}
+}
+
+func (a *Accounts) RequireVerifiedEmail() bool { return a.cfg.RequireVerifiedEmail }
+
+// SendVerification — выпустить токен подтверждения и отправить письмо со ссылкой.
+func (a *Accounts) SendVerification(ctx context.Context, u domain.User) error {
+	tok, err := a.issue(ctx, u.ID, TokenVerifyEmail, "", a.cfg.VerifyTTL)
+	if err != nil {
+		return err
+	}
+	a.send(Message{
+		To:      u.Email,
+		Subject: "Подтвердите email",
+		Body: fmt.Sprintf("Чтобы подтвердить адрес, откройте ссылку:\n\n%s\n\nСсылка действует %s.\n",
+			withToken(a.cfg.VerifyURL, tok), a.cfg.VerifyTTL),
+	})
+	return nil
+}
+
+// ResendVerification — повторное письмо подтверждения; как и ForgotPassword, молчит про неизвестный
+// или уже подтверждённый адрес.
+func (a *Accounts) ResendVerification(ctx context.Context, email string) error {
+	u, ok, err := a.lookup(ctx, email)
+	if err != nil || !ok || u.EmailVerifiedAt != nil {
+		return err
+	}
+	return a.SendVerification(ctx, u)
+}
+
+// ForgotPassword — письмо со ссылкой сброса. Неизвестный email — не ошибка: ответ не должен
+// выдавать, зарегистрирован ли адрес.
+func (a *Accounts) ForgotPassword(ctx context.Context, email string) error {
+	u, ok, err := a.lookup(ctx, email)
+	if err != nil || !ok {
+		return err
+	}
+	tok, err := a.issue(ctx, u.ID, TokenResetPassword, "", a.cfg.ResetTTL)
+	if err != nil {
+		return err
+	}
+	a.send(Message{
+		To:      u.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Чтобы задать новый пароль, откройте ссылку:\n\n%s\n\nСсылка действует %s. "+
+			"Если вы не запрашивали сброс, просто проигнорируйте письмо.\n",
+			withToken(a.cfg.ResetURL, tok), a.cfg.ResetTTL),
+	})
+	return nil
+}
+
+// RequestEmailChange — письмо со ссылкой на новый адрес (та же страница, что и подтверждение
+// email); прежний адрес получает уведомление. Пароль проверяет вызывающий.
+func (a *Accounts) RequestEmailChange(ctx context.Context, u domain.User, newEmail string) error {
+	if err := domain.ValidateEmail(newEmail); err != nil {
+		return err
+	}
+	newEmail = domain.NormalizeEmail(newEmail)
+	if newEmail == u.Email {
+		return ErrSameEmail
+	}
+	if _, err := a.repo.GetByEmail(ctx, newEmail); err == nil {
+		return ErrEmailTaken
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	tok, err := a.issue(ctx, u.ID, TokenChangeEmail, newEmail, a.cfg.VerifyTTL)
+	if err != nil {
+		return err
+	}
+	a.send(Message{
+		To:      newEmail,
+		Subject: "Подтвердите новый email",
+		Body: fmt.Sprintf("Чтобы сменить адрес аккаунта на этот, откройте ссылку:\n\n%s\n\nСсылка действует %s.\n",
+			withToken(a.cfg.VerifyURL, tok), a.cfg.VerifyTTL),
+	})
+	a.send(Message{
+		To:      u.Email,
+		Subject: "Запрошена смена email",
+		Body: fmt.Sprintf("Для вашего аккаунта запрошена смена адреса на %s. Адрес сменится после подтверждения "+
+			"по ссылке, отправленной на новый email. Если это были не вы, смените пароль.\n", newEmail),
+	})
+	return nil
+}
+
+func (a *Accounts) VerifyEmail(ctx context.Context, token string) (uuid.UUID, error) {
+	if token == "" {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return a.repo.VerifyEmail(ctx, hashToken(token))
+}
+
+// ResetPassword — новый пароль по токену; вызывающий отзывает сессии вернувшегося пользователя.
+func (a *Accounts) ResetPassword(ctx context.Context, token, password string) (uuid.UUID, error) {
+	if token == "" {
+		return uuid.Nil, ErrInvalidToken
+	}
+	if len(password) < 8 {
+		return uuid.Nil, ErrWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), a.bcryptCost)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return a.repo.ResetPassword(ctx, hashToken(token), hash)
+}
+
+func (a *Accounts) lookup(ctx context.Context, email string) (domain.User, bool, error) {
+	if err := domain.ValidateEmail(email); err != nil {
+		return domain.User{}, false, nil
+	}
+	u, err := a.repo.GetByEmail(ctx, domain.NormalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return domain.User{}, false, nil
+		}
+		return domain.User{}, false, err
+	}
+	return u, true, nil
+}
+
+func (a *Accounts) issue(ctx context.Context, userID uuid.UUID, kind TokenKind, email string, ttl time.Duration) (string, error) {
+	tok, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	if err := a.repo.CreateToken(ctx, userID, kind, email, hashToken(tok), time.Now().Add(ttl)); err != nil {
+		return "", fmt.Errorf("create %s token: %w", kind, err)
+	}
+	return tok, nil
+}
+
+// send — письмо уходит в фоне: время ответа не зависит от SMTP (и не выдаёт, есть ли адрес),
+// а недоступный почтовый сервер не ломает регистрацию. Ошибка только логируется.
+func (a *Accounts) send(m Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.SendTimeout)
+		defer cancel()
+		if err := a.mailer.Send(ctx, m); err != nil {
+			a.log.Error("users.mail: send failed",
+				slog.String("subject", m.Subject),
+				slog.Any("err", err),
+			)
+		}
+	}()
+}
+
+// randomToken — 256 бит, base64url: токены в ссылках, state/nonce/PKCE verifier.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(tok string) []byte {
+	h := sha256.Sum256([]byte(tok))
+	return h[:]
+}
+
+func withToken(base, tok string) string {
+	u, err := url.Parse(base)
+	if err != nil || base == "" {
+		return tok
+	}
+	q := u.Query()
+	q.Set("token", tok)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,310 @@
+package app
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"goshop/pkg/totp"
+	domain "goshop/services/users/internal/domain/user"
+)
+
+var (
+	ErrMFAEnabled    = errors.New("users: mfa already enabled")
+	ErrMFANotEnabled = errors.New("users: mfa not enabled")
+	ErrMFANoPending  = errors.New("users: no pending mfa enrollment")
+	ErrInvalidCode   = errors.New("users: invalid mfa code")
+	// ErrInvalidChallenge — mfa_token неизвестен, истёк, уже использован или исчерпал попытки
+	ErrInvalidChallenge = errors.New("users: invalid mfa challenge")
+)
+
+// MFAState — строка user_mfa; Secret зашифрован.
+type MFAState struct {
+	Secret      []byte
+	ConfirmedAt *time.Time
+	LastStep    int64
+}
+
+type MFARepository interface {
+	// GetMFA — ErrNotFound, если enrollment не начинался.
+	GetMFA(ctx context.Context, userID uuid.UUID) (MFAState, error)
+	// SaveMFASecret — начать (или перезапустить) enrollment; подтверждённую 2FA не трогает.
+	SaveMFASecret(ctx context.Context, userID uuid.UUID, secret []byte) error
+	// ConfirmMFA — включить 2FA и заменить коды восстановления.
+	ConfirmMFA(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes [][]byte) error
+	// UseMFAStep — принять код интервала step, если он новее последнего принятого.
+	UseMFAStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash []byte) (bool, error)
+	DeleteMFA(ctx context.Context, userID uuid.UUID) error
+}
+
+// Challenge — второй шаг логина: пароль уже проверен, ждём код.
+type Challenge struct {
+	UserID uuid.UUID
+	Email  string
+}
+
+// ChallengeStore — короткоживущие mfa_token (Redis). Peek читает токен без попытки, Attempt
+// считает попытки ввода кода, Consume гасит токен после успешной проверки (true — погасили мы,
+// а не параллельный запрос).
+type ChallengeStore interface {
+	Create(ctx context.Context, ch Challenge, ttl time.Duration) (string, error)
+	Peek(ctx context.Context, token string) (Challenge, error)
+	Attempt(ctx context.Context, token string, maxAttempts int) (Challenge, error)
+	Consume(ctx context.Context, token string) (bool, error)
+}
+
+type MFAConfig struct {
+	Issuer string // подпись аккаунта в приложении-аутентификаторе
+	// EncryptionKey — 32 байта AES-256-GCM для секретов TOTP в БД
+	EncryptionKey []byte
+	ChallengeTTL  time.Duration
+	MaxAttempts   int
+	Skew          int // допустимый сдвиг часов, в 30s-интервалах
+	RecoveryCodes int
+}
+
+// MFA — TOTP 2FA: enrollment, подтверждение, второй шаг логина и отключение.
+type MFA struct {
+	repo       MFARepository
+	challenges ChallengeStore
+	aead       cipher.AEAD
+	cfg        MFAConfig
+	now        func() time.Time
+}
+
+func NewMFA(repo MFARepository, challenges ChallengeStore, cfg MFAConfig) (*MFA, error) {
+	if cfg.Issuer == "" {
+		cfg.Issuer = "goshop"
+	}
+	if cfg.ChallengeTTL <= 0 {
+		cfg.ChallengeTTL = 5 * time.Minute
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Skew < 0 {
+		cfg.Skew = 0
+	}
+	if cfg.RecoveryCodes <= 0 {
+		cfg.RecoveryCodes = 10
+	}
+	if len(cfg.EncryptionKey) != 32 {
+		return nil, fmt.Errorf("mfa: encryption key must be 32 bytes, got %d", len(cfg.EncryptionKey))
+	}
+	block, err := aes.NewCipher(cfg.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &MFA{repo: repo, challenges: challenges, aead: aead, cfg: cfg, now: time.Now}, nil
+}
+
+func (m *MFA) ChallengeTTL() time.Duration { return m.cfg.ChallengeTTL }
+
+// Enabled — включена ли 2FA (подтверждённый enrollment).
+func (m *MFA) Enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	st, err := m.repo.GetMFA(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return st.ConfirmedAt != nil, nil
+}
+
+// Enroll — новый секрет; возвращает его (для ручного ввода) и otpauth URI (для QR-кода).
+func (m *MFA) Enroll(ctx context.Context, userID uuid.UUID, email string) (secret, uri string, err error) {
+	if on, err := m.Enabled(ctx, userID); err != nil {
+		return "", "", err
+	} else if on {
+		return "", "", ErrMFAEnabled
+	}
+	raw, err := totp.NewSecret()
+	if err != nil {
+		return "", "", err
+	}
+	enc, err := m.seal(userID, raw)
+	if err != nil {
+		return "", "", err
+	}
+	if err := m.repo.SaveMFASecret(ctx, userID, enc); err != nil {
+		return "", "", err
+	}
+	return totp.EncodeSecret(raw), totp.URI(m.cfg.Issuer, email, raw), nil
+}
+
+// Confirm — первый код из приложения включает 2FA; возвращает коды восстановления
+// (показываются один раз, в БД — только sha256).
+func (m *MFA) Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	st, err := m.repo.GetMFA(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrMFANoPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	if st.ConfirmedAt != nil {
+		return nil, ErrMFAEnabled
+	}
+	secret, err := m.open(userID, st.Secret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(secret, code, m.now(), m.cfg.Skew)
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+
+	codes := make([]string, m.cfg.RecoveryCodes)
+	hashes := make([][]byte, m.cfg.RecoveryCodes)
+	for i := range codes {
+		if codes[i], err = newRecoveryCode(); err != nil {
+			return nil, err
+		}
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	if err := m.repo.ConfirmMFA(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Challenge — выдать mfa_token вместо пары токенов после проверки пароля.
+func (m *MFA) Challenge(ctx context.Context, u domain.User) (string, error) {
+	return m.challenges.Create(ctx, Challenge{UserID: u.ID, Email: u.Email}, m.cfg.ChallengeTTL)
+}
+
+// PeekChallenge — чей mfa_token, без траты попытки: счётчики подбора проверяются до кода.
+func (m *MFA) PeekChallenge(ctx context.Context, token string) (Challenge, error) {
+	return m.challenges.Peek(ctx, token)
+}
+
+// CompleteChallenge — второй шаг логина: код TOTP или код восстановления. Каждая попытка
+// расходует одну из MaxAttempts; после успеха токен гасится. При неверном коде challenge
+// тоже возвращается — чтобы засчитать неудачу аккаунту.
+func (m *MFA) CompleteChallenge(ctx context.Context, token, code string) (Challenge, error) {
+	ch, err := m.challenges.Attempt(ctx, token, m.cfg.MaxAttempts)
+	if err != nil {
+		return Challenge{}, err
+	}
+	if err := m.Verify(ctx, ch.UserID, code); err != nil {
+		return ch, err
+	}
+	ok, err := m.challenges.Consume(ctx, token)
+	if err != nil {
+		return Challenge{}, err
+	}
+	if !ok {
+		return Challenge{}, ErrInvalidChallenge
+	}
+	return ch, nil
+}
+
+// Verify — код включённой 2FA: шестизначный TOTP или одноразовый код восстановления.
+func (m *MFA) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	st, err := m.repo.GetMFA(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrMFANotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if st.ConfirmedAt == nil {
+		return ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		secret, err := m.open(userID, st.Secret)
+		if err != nil {
+			return err
+		}
+		step, ok := totp.Validate(secret, code, m.now(), m.cfg.Skew)
+		if !ok || step <= st.LastStep {
+			return ErrInvalidCode
+		}
+		// условный UPDATE: параллельный запрос с тем же кодом не пройдёт
+		if used, err := m.repo.UseMFAStep(ctx, userID, step); err != nil {
+			return err
+		} else if !used {
+			return ErrInvalidCode
+		}
+		return nil
+	}
+
+	used, err := m.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// Disable — выключить 2FA; вызывающий уже заново проверил пароль, здесь — действующий код.
+func (m *MFA) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	if err := m.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	return m.repo.DeleteMFA(ctx, userID)
+}
+
+// seal — user_id в additional data: секрет нельзя переставить в строку другого пользователя.
+func (m *MFA) seal(userID uuid.UUID, secret []byte) ([]byte, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return m.aead.Seal(nonce, nonce, secret, userID[:]), nil
+}
+
+func (m *MFA) open(userID uuid.UUID, enc []byte) ([]byte, error) {
+	ns := m.aead.NonceSize()
+	if len(enc) < ns {
+		return nil, errors.New("mfa: secret too short")
+	}
+	secret, err := m.aead.Open(nil, enc[:ns], enc[ns:], userID[:])
+	if err != nil {
+		return nil, fmt.Errorf("mfa: decrypt secret: %w", err)
+	}
+	return secret, nil
+}
+
+var recoveryEnc = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCode — 50 бит, вида "abcde-fghij".
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	s := strings.ToLower(recoveryEnc.EncodeToString(b))[:10]
+	return s[:5] + "-" + s[5:], nil
+}
+
+// hashRecoveryCode — регистр, пробелы и дефисы при вводе не важны.
+func hashRecoveryCode(code string) []byte {
+	norm := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+	h := sha256.Sum256([]byte(norm))
+	return h[:]
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/base32"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"goshop/pkg/totp"
+)
+
+type memMFARepo struct {
+	st       *MFAState
+	recovery map[string]bool // hash -> used
+}
+
+func (r *memMFARepo) GetMFA(_ context.Context, _ uuid.UUID) (MFAState, error) {
+	if r.st == nil {
+		return MFAState{}, ErrNotFound
+	}
+	return *r.st, nil
+}
+
+func (r *memMFARepo) SaveMFASecret(_ context.Context, _ uuid.UUID, secret []byte) error {
+	if r.st != nil && r.st.ConfirmedAt != nil {
+		return ErrMFAEnabled
+	}
+	r.st = &MFAState{Secret: secret}
+	return nil
+}
+
+func (r *memMFARepo) ConfirmMFA(_ context.Context, _ uuid.UUID, step int64, hashes [][]byte) error {
+	now := time.Now()
+	r.st.ConfirmedAt, r.st.LastStep = &now, step
+	r.recovery = map[string]bool{}
+	for _, h := range hashes {
+		r.recovery[string(h)] = false
+	}
+	return nil
+}
+
+func (r *memMFARepo) UseMFAStep(_ context.Context, _ uuid.UUID, step int64) (bool, error) {
+	if step <= r.st.LastStep {
+		return false, nil
+	}
+	r.st.LastStep = step
+	return true, nil
+}
+
+func (r *memMFARepo) UseRecoveryCode(_ context.Context, _ uuid.UUID, hash []byte) (bool, error) {
+	used, ok := r.recovery[string(hash)]
+	if !ok || used {
+		return false, nil
+	}
+	r.recovery[string(hash)] = true
+	return true, nil
+}
+
+func (r *memMFARepo) DeleteMFA(_ context.Context, _ uuid.UUID) error {
+	r.st, r.recovery = nil, nil
+	return nil
+}
+
+func TestMFA_EnrollConfirmVerify(t *testing.T) {
+	ctx := context.Background()
+	repo := &memMFARepo{}
+	m, err := NewMFA(repo, nil, MFAConfig{EncryptionKey: bytes.Repeat([]byte{7}, 32), Skew: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+	uid := uuid.New()
+
+	_, uri, err := m.Enroll(ctx, uid, "a@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(uri)
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(u.Query().Get("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(repo.st.Secret, secret) {
+		t.Fatal("secret stored in plain text")
+	}
+
+	if _, err := m.Confirm(ctx, uid, "abcdef"); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("confirm with wrong code: %v", err)
+	}
+	codes, err := m.Confirm(ctx, uid, totp.Code(secret, totp.Step(now)))
+	if err != nil || len(codes) != 10 {
+		t.Fatalf("confirm: %v, %d codes", err, len(codes))
+	}
+	if _, _, err := m.Enroll(ctx, uid, "a@example.com"); !errors.Is(err, ErrMFAEnabled) {
+		t.Fatalf("re-enroll: %v", err)
+	}
+
+	// код, которым подтверждали, второй раз не принимается; следующий интервал — да
+	if err := m.Verify(ctx, uid, totp.Code(secret, totp.Step(now))); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("replayed code: %v", err)
+	}
+	now = now.Add(totp.Period)
+	if err := m.Verify(ctx, uid, totp.Code(secret, totp.Step(now))); err != nil {
+		t.Fatalf("next code: %v", err)
+	}
+
+	// код восстановления — одноразовый, регистр и дефис не важны
+	if err := m.Verify(ctx, uid, " "+codes[0][:5]+codes[0][6:]+" "); err != nil {
+		t.Fatalf("recovery code: %v", err)
+	}
+	if err := m.Verify(ctx, uid, codes[0]); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("reused recovery code: %v", err)
+	}
+
+	if err := m.Disable(ctx, uid, codes[1]); err != nil {
+		t.Fatal(err)
+	}
+	if on, _ := m.Enabled(ctx, uid); on {
+		t.Fatal("mfa still enabled")
+	}
+}
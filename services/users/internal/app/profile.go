@@ -0,0 +1,122 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	domain "goshop/services/users/internal/domain/user"
+)
+
+// ErrNoPassword — у аккаунта нет пароля (создан входом через провайдера): действия,
+// требующие пароля, доступны после его установки через сброс.
+var ErrNoPassword = errors.New("users: password not set")
+
+// ProfileUpdate — частичное обновление профиля: nil — поле не меняется, "" — стереть.
+type ProfileUpdate struct {
+	Name  *string
+	Phone *string
+	// SetAddress — менять адрес; DefaultAddress nil при этом — стереть
+	SetAddress     bool
+	DefaultAddress *domain.Address
+}
+
+func (u ProfileUpdate) Empty() bool {
+	return u.Name == nil && u.Phone == nil && !u.SetAddress
+}
+
+type ProfileRepository interface {
+	// GetByID — ErrNotFound, если пользователя нет или аккаунт удалён.
+	GetByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, upd ProfileUpdate) (domain.User, error)
+	SetPassword(ctx context.Context, id uuid.UUID, passwordHash []byte) error
+	// DeleteUser — анонимизация: персональные данные стираются, привязки и токены удаляются,
+	// событие user.deleted пишется в outbox той же транзакцией. ErrNotFound — уже удалён.
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+}
+
+// Profiles — профиль и жизненный цикл аккаунта: данные, смена пароля, удаление.
+type Profiles struct {
+	repo       ProfileRepository
+	bcryptCost int
+}
+
+func NewProfiles(repo ProfileRepository, bcryptCost int) *Profiles {
+	if bcryptCost <= 0 {
+		bcryptCost = 12
+	}
+	return &Profiles{repo: repo, bcryptCost: bcryptCost}
+}
+
+func (p *Profiles) Get(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	return p.repo.GetByID(ctx, userID)
+}
+
+// Update — нормализует и проверяет изменённые поля (domain.ErrInvalid*), остальные не трогает.
+func (p *Profiles) Update(ctx context.Context, userID uuid.UUID, upd ProfileUpdate) (domain.User, error) {
+	if upd.Name != nil {
+		name := domain.NormalizeName(*upd.Name)
+		if err := domain.ValidateName(name); err != nil {
+			return domain.User{}, err
+		}
+		upd.Name = &name
+	}
+	if upd.Phone != nil {
+		phone := domain.NormalizePhone(*upd.Phone)
+		if err := domain.ValidatePhone(phone); err != nil {
+			return domain.User{}, err
+		}
+		upd.Phone = &phone
+	}
+	if upd.SetAddress && upd.DefaultAddress != nil {
+		a := *upd.DefaultAddress
+		a.Normalize()
+		if err := a.Validate(); err != nil {
+			return domain.User{}, err
+		}
+		upd.DefaultAddress = &a
+	}
+	if upd.Empty() {
+		return p.repo.GetByID(ctx, userID)
+	}
+	return p.repo.UpdateProfile(ctx, userID, upd)
+}
+
+// VerifyPassword — повторная аутентификация перед опасным действием (смена email, удаление).
+// Пользователь — по id, а не по email из токена: email мог смениться.
+func (p *Profiles) VerifyPassword(ctx context.Context, userID uuid.UUID, password string) (domain.User, error) {
+	u, err := p.repo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if len(u.PasswordHash) == 0 {
+		return domain.User{}, ErrNoPassword
+	}
+	if bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password)) != nil {
+		return domain.User{}, ErrInvalidCredentials
+	}
+	return u, nil
+}
+
+// ChangePassword — по старому паролю; прочие сессии отзывает вызывающий.
+func (p *Profiles) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
+	if _, err := p.VerifyPassword(ctx, userID, oldPassword); err != nil {
+		return err
+	}
+	if len(newPassword) < 8 {
+		return ErrWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), p.bcryptCost)
+	if err != nil {
+		return err
+	}
+	return p.repo.SetPassword(ctx, userID, hash)
+}
+
+// Delete — удалить аккаунт (анонимизация); пароль и второй фактор проверяет вызывающий,
+// он же закрывает сессии.
+func (p *Profiles) Delete(ctx context.Context, userID uuid.UUID) error {
+	return p.repo.DeleteUser(ctx, userID)
+}
@@ -0,0 +1,111 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	app "goshop/services/users/internal/app"
+	domain "goshop/services/users/internal/domain/user"
+)
+
+type memProfiles struct {
+	u       domain.User
+	deleted bool
+	updates []app.ProfileUpdate
+}
+
+func (r *memProfiles) GetByID(_ context.Context, id uuid.UUID) (domain.User, error) {
+	if r.deleted || id != r.u.ID {
+		return domain.User{}, app.ErrNotFound
+	}
+	return r.u, nil
+}
+
+func (r *memProfiles) UpdateProfile(_ context.Context, id uuid.UUID, upd app.ProfileUpdate) (domain.User, error) {
+	r.updates = append(r.updates, upd)
+	if upd.Name != nil {
+		r.u.Profile.Name = *upd.Name
+	}
+	if upd.Phone != nil {
+		r.u.Profile.Phone = *upd.Phone
+	}
+	if upd.SetAddress {
+		r.u.Profile.DefaultAddress = upd.DefaultAddress
+	}
+	return r.u, nil
+}
+
+func (r *memProfiles) SetPassword(_ context.Context, _ uuid.UUID, hash []byte) error {
+	r.u.PasswordHash = hash
+	return nil
+}
+
+func (r *memProfiles) DeleteUser(_ context.Context, _ uuid.UUID) error {
+	if r.deleted {
+		return app.ErrNotFound
+	}
+	r.deleted = true
+	return nil
+}
+
+func strp(s string) *string { return &s }
+
+func TestProfiles_Update(t *testing.T) {
+	repo := &memProfiles{u: domain.User{ID: uuid.New(), Email: "ann@example.com"}}
+	p := app.NewProfiles(repo, bcrypt.MinCost)
+	ctx := context.Background()
+
+	u, err := p.Update(ctx, repo.u.ID, app.ProfileUpdate{Name: strp("  Ann   Smith "), Phone: strp("+49 30 123456")})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if u.Profile.Name != "Ann Smith" || u.Profile.Phone != "+4930123456" {
+		t.Fatalf("profile: %+v", u.Profile)
+	}
+
+	if _, err := p.Update(ctx, repo.u.ID, app.ProfileUpdate{Phone: strp("12345")}); !errors.Is(err, domain.ErrInvalidPhone) {
+		t.Fatalf("invalid phone: err=%v", err)
+	}
+	bad := &domain.Address{Recipient: "Ann", Country: "Germany", City: "Berlin", PostalCode: "10115", Line1: "x"}
+	if _, err := p.Update(ctx, repo.u.ID, app.ProfileUpdate{SetAddress: true, DefaultAddress: bad}); !errors.Is(err, domain.ErrInvalidAddress) {
+		t.Fatalf("invalid address: err=%v", err)
+	}
+	if len(repo.updates) != 1 {
+		t.Fatalf("invalid updates reached repository: %d", len(repo.updates))
+	}
+
+	// пустой PATCH — просто текущий профиль
+	if u, err := p.Update(ctx, repo.u.ID, app.ProfileUpdate{}); err != nil || u.Profile.Name != "Ann Smith" || len(repo.updates) != 1 {
+		t.Fatalf("empty update: %+v, %v", u.Profile, err)
+	}
+}
+
+func TestProfiles_ChangePassword(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("OldPass123"), bcrypt.MinCost)
+	repo := &memProfiles{u: domain.User{ID: uuid.New(), Email: "ann@example.com", PasswordHash: hash}}
+	p := app.NewProfiles(repo, bcrypt.MinCost)
+	ctx := context.Background()
+
+	if err := p.ChangePassword(ctx, repo.u.ID, "wrong", "NewPass123"); !errors.Is(err, app.ErrInvalidCredentials) {
+		t.Fatalf("wrong old password: err=%v", err)
+	}
+	if err := p.ChangePassword(ctx, repo.u.ID, "OldPass123", "short"); !errors.Is(err, app.ErrWeakPassword) {
+		t.Fatalf("weak password: err=%v", err)
+	}
+	if err := p.ChangePassword(ctx, repo.u.ID, "OldPass123", "NewPass123"); err != nil {
+		t.Fatalf("change: %v", err)
+	}
+	if _, err := p.VerifyPassword(ctx, repo.u.ID, "NewPass123"); err != nil {
+		t.Fatalf("new password rejected: %v", err)
+	}
+
+	// аккаунт из входа через провайдера: пароля нет
+	repo.u.PasswordHash = nil
+	if err := p.ChangePassword(ctx, repo.u.ID, "anything", "NewPass123"); !errors.Is(err, app.ErrNoPassword) {
+		t.Fatalf("no password: err=%v", err)
+	}
+}
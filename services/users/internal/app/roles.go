@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"goshop/pkg/jwtauth"
+)
+
+var (
+	ErrUnknownRole = errors.New("users: unknown role")
+	// ErrSelfDemote — admin не снимает admin сам с себя: иначе можно остаться без администраторов
+	ErrSelfDemote = errors.New("users: cannot revoke own admin role")
+)
+
+// Role — строка справочника roles.
+type Role struct {
+	Name        string
+	Description string
+}
+
+type RoleRepository interface {
+	ListRoles(ctx context.Context) ([]Role, error)
+	// UserRoles — роли пользователя по имени; ErrNotFound — пользователя нет.
+	UserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+	// GrantRole/RevokeRole пишут событие аудита (user.role_granted / user.role_revoked).
+	// GrantRole — false, если роль уже была; ErrNotFound / ErrUnknownRole.
+	GrantRole(ctx context.Context, userID uuid.UUID, role string, grantedBy uuid.UUID) (bool, error)
+	// RevokeRole — false, если роли не было.
+	RevokeRole(ctx context.Context, userID uuid.UUID, role string, revokedBy uuid.UUID) (bool, error)
+}
+
+// Roles — RBAC: роли из БД попадают в access-токен при логине и refresh.
+type Roles struct {
+	repo RoleRepository
+}
+
+func NewRoles(repo RoleRepository) *Roles {
+	return &Roles{repo: repo}
+}
+
+func (r *Roles) List(ctx context.Context) ([]Role, error) {
+	return r.repo.ListRoles(ctx)
+}
+
+func (r *Roles) Of(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	return r.repo.UserRoles(ctx, userID)
+}
+
+func (r *Roles) Grant(ctx context.Context, actor, userID uuid.UUID, role string) (bool, error) {
+	return r.repo.GrantRole(ctx, userID, role, actor)
+}
+
+func (r *Roles) Revoke(ctx context.Context, actor, userID uuid.UUID, role string) (bool, error) {
+	if actor == userID && role == jwtauth.RoleAdmin {
+		return false, ErrSelfDemote
+	}
+	return r.repo.RevokeRole(ctx, userID, role, actor)
+}
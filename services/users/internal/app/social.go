@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "goshop/services/users/internal/domain/user"
+)
+
+var (
+	ErrUnknownProvider = errors.New("users: unknown identity provider")
+	// ErrInvalidState — state неизвестен, истёк, уже использован или выдан для другого провайдера
+	ErrInvalidState = errors.New("users: invalid or expired oidc state")
+	// ErrInvalidIdentity — провайдер не подтвердил вход: неверный code/PKCE, ID token не прошёл проверку
+	ErrInvalidIdentity = errors.New("users: identity rejected")
+	// ErrIdentityConflict — аккаунт с этим email уже есть, а провайдер email не подтвердил:
+	// привязать нельзя, иначе чужой аккаунт у провайдера открывал бы наш
+	ErrIdentityConflict = errors.New("users: email registered, identity not linked")
+	// ErrProviderUnavailable — провайдер не ответил (discovery, token endpoint)
+	ErrProviderUnavailable = errors.New("users: identity provider unavailable")
+)
+
+// Identity — пользователь у внешнего провайдера по проверенному ID token.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// IdentityProvider — OIDC authorization code flow с PKCE (S256).
+type IdentityProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange — code на токены; ID token проверен (подпись, iss, aud, exp, nonce).
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (Identity, error)
+}
+
+// OIDCState — что нужно callback'у: state — ключ, verifier PKCE и nonce клиенту не отдаются.
+type OIDCState struct {
+	Provider string
+	Nonce    string
+	Verifier string
+}
+
+type OIDCStateStore interface {
+	Save(ctx context.Context, state string, st OIDCState, ttl time.Duration) error
+	// Take — одноразово: повторный callback с тем же state получит ErrInvalidState.
+	Take(ctx context.Context, state string) (OIDCState, error)
+}
+
+type IdentityRepository interface {
+	GetByEmail(ctx context.Context, email string) (domain.User, error)
+	// UserByIdentity — владелец привязанной identity (email и last_login_at обновляются); ErrNotFound.
+	UserByIdentity(ctx context.Context, id Identity) (domain.User, error)
+	// CreateWithIdentity — новый аккаунт без пароля; ErrEmailTaken — email занят.
+	CreateWithIdentity(ctx context.Context, id Identity) (domain.User, error)
+	// LinkIdentity — привязать к существующему аккаунту. takeover: email аккаунта не был
+	// подтверждён — пароль стирается, email помечается подтверждённым.
+	LinkIdentity(ctx context.Context, userID uuid.UUID, id Identity, takeover bool) error
+}
+
+type SocialConfig struct {
+	StateTTL time.Duration
+}
+
+// SocialLogin — итог входа через провайдера.
+type SocialLogin struct {
+	User    domain.User
+	Created bool // аккаунт создан этим входом
+	Linked  bool // identity привязана к существующему аккаунту
+	// Takeover — аккаунт с неподтверждённым email перешёл к владельцу адреса: пароль стёрт,
+	// прежние сессии нужно закрыть (их мог открыть тот, кто зарегистрировал чужой адрес)
+	Takeover bool
+}
+
+// Social — вход через внешних OIDC-провайдеров («Войти через Google»).
+type Social struct {
+	repo      IdentityRepository
+	states    OIDCStateStore
+	providers map[string]IdentityProvider
+	cfg       SocialConfig
+}
+
+func NewSocial(repo IdentityRepository, states OIDCStateStore, providers []IdentityProvider, cfg SocialConfig) *Social {
+	if cfg.StateTTL <= 0 {
+		cfg.StateTTL = 10 * time.Minute
+	}
+	s := &Social{repo: repo, states: states, providers: make(map[string]IdentityProvider, len(providers)), cfg: cfg}
+	for _, p := range providers {
+		s.providers[p.Name()] = p
+	}
+	return s
+}
+
+// Start — URL авторизации у провайдера; state, nonce и PKCE verifier живут StateTTL.
+func (s *Social) Start(ctx context.Context, provider string) (string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+	state, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.states.Save(ctx, state, OIDCState{Provider: provider, Nonce: nonce, Verifier: verifier}, s.cfg.StateTTL); err != nil {
+		return "", err
+	}
+	return p.AuthCodeURL(ctx, state, nonce, PKCEChallenge(verifier))
+}
+
+// Complete — callback провайдера: code на identity, затем вход, привязка или новый аккаунт.
+func (s *Social) Complete(ctx context.Context, provider, state, code string) (SocialLogin, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return SocialLogin{}, ErrUnknownProvider
+	}
+	st, err := s.states.Take(ctx, state)
+	if err != nil {
+		return SocialLogin{}, err
+	}
+	if st.Provider != provider {
+		return SocialLogin{}, ErrInvalidState
+	}
+	id, err := p.Exchange(ctx, code, st.Verifier, st.Nonce)
+	if err != nil {
+		return SocialLogin{}, err
+	}
+	id.Provider = provider
+	id.Email = domain.NormalizeEmail(id.Email)
+
+	u, err := s.repo.UserByIdentity(ctx, id)
+	if err == nil {
+		return SocialLogin{User: u}, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return SocialLogin{}, err
+	}
+
+	// первый вход с этой identity
+	if domain.ValidateEmail(id.Email) != nil {
+		return SocialLogin{}, fmt.Errorf("%w: no usable email", ErrInvalidIdentity)
+	}
+	existing, err := s.repo.GetByEmail(ctx, id.Email)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		u, err := s.repo.CreateWithIdentity(ctx, id)
+		if err != nil {
+			return SocialLogin{}, err
+		}
+		return SocialLogin{User: u, Created: true}, nil
+	case err != nil:
+		return SocialLogin{}, err
+	case !id.EmailVerified:
+		return SocialLogin{}, ErrIdentityConflict
+	}
+
+	takeover := existing.EmailVerifiedAt == nil
+	if err := s.repo.LinkIdentity(ctx, existing.ID, id, takeover); err != nil {
+		return SocialLogin{}, err
+	}
+	if takeover {
+		now := time.Now().UTC()
+		existing.EmailVerifiedAt = &now
+		existing.PasswordHash = nil
+	}
+	return SocialLogin{User: existing, Linked: true, Takeover: takeover}, nil
+}
+
+// PKCEChallenge — code_challenge для метода S256 (RFC 7636).
+func PKCEChallenge(verifier string) string {
+	h := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
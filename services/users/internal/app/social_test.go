@@ -0,0 +1,212 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"goshop/services/users/internal/adapters/oidc"
+	"goshop/services/users/internal/adapters/oidc/oidctest"
+	app "goshop/services/users/internal/app"
+	domain "goshop/services/users/internal/domain/user"
+)
+
+type memIdentities struct {
+	mu    sync.Mutex
+	users map[string]domain.User  // email -> user
+	links map[string]uuid.UUID    // provider|subject -> user id
+	taken map[uuid.UUID]time.Time // takeover: когда стёрт пароль
+}
+
+func newMemIdentities() *memIdentities {
+	return &memIdentities{users: map[string]domain.User{}, links: map[string]uuid.UUID{}, taken: map[uuid.UUID]time.Time{}}
+}
+
+func (r *memIdentities) GetByEmail(_ context.Context, email string) (domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[email]
+	if !ok {
+		return domain.User{}, app.ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *memIdentities) UserByIdentity(_ context.Context, id app.Identity) (domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	uid, ok := r.links[id.Provider+"|"+id.Subject]
+	if !ok {
+		return domain.User{}, app.ErrNotFound
+	}
+	for _, u := range r.users {
+		if u.ID == uid {
+			return u, nil
+		}
+	}
+	return domain.User{}, app.ErrNotFound
+}
+
+func (r *memIdentities) CreateWithIdentity(_ context.Context, id app.Identity) (domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id.Email]; ok {
+		return domain.User{}, app.ErrEmailTaken
+	}
+	u := domain.User{ID: uuid.New(), Email: id.Email}
+	if id.EmailVerified {
+		now := time.Now()
+		u.EmailVerifiedAt = &now
+	}
+	r.users[id.Email] = u
+	r.links[id.Provider+"|"+id.Subject] = u.ID
+	return u, nil
+}
+
+func (r *memIdentities) LinkIdentity(_ context.Context, userID uuid.UUID, id app.Identity, takeover bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.links[id.Provider+"|"+id.Subject] = userID
+	if takeover {
+		r.taken[userID] = time.Now()
+	}
+	return nil
+}
+
+type memStates struct {
+	mu sync.Mutex
+	m  map[string]app.OIDCState
+}
+
+func (s *memStates) Save(_ context.Context, state string, st app.OIDCState, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[state] = st
+	return nil
+}
+
+func (s *memStates) Take(_ context.Context, state string) (app.OIDCState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.m[state]
+	if !ok {
+		return app.OIDCState{}, app.ErrInvalidState
+	}
+	delete(s.m, state)
+	return st, nil
+}
+
+func newSocial(t *testing.T) (*app.Social, *oidctest.Provider, *memIdentities) {
+	t.Helper()
+	mock, err := oidctest.New("goshop", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mock.Close)
+	repo := newMemIdentities()
+	p := oidc.New(oidc.Config{
+		Name: "mock", Issuer: mock.Issuer(), ClientID: "goshop", ClientSecret: "s3cret",
+		RedirectURL: "http://localhost/v1/users/oidc/mock/callback",
+	})
+	return app.NewSocial(repo, &memStates{m: map[string]app.OIDCState{}}, []app.IdentityProvider{p}, app.SocialConfig{}), mock, repo
+}
+
+// login — полный круг: Start, «браузер» у провайдера, Complete.
+func login(t *testing.T, s *app.Social, mock *oidctest.Provider) (app.SocialLogin, error) {
+	t.Helper()
+	authURL, err := s.Start(context.Background(), "mock")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	code, state, err := mock.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	return s.Complete(context.Background(), "mock", state, code)
+}
+
+func TestSocial_CreateThenLogin(t *testing.T) {
+	s, mock, _ := newSocial(t)
+	mock.SetUser(oidctest.User{Subject: "1", Email: "New@Example.com", EmailVerified: true})
+
+	first, err := login(t, s, mock)
+	if err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+	if !first.Created || first.User.Email != "new@example.com" || first.User.EmailVerifiedAt == nil {
+		t.Fatalf("first login: %+v", first)
+	}
+
+	again, err := login(t, s, mock)
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if again.Created || again.Linked || again.User.ID != first.User.ID {
+		t.Fatalf("second login: %+v", again)
+	}
+}
+
+func TestSocial_LinkExisting(t *testing.T) {
+	s, mock, repo := newSocial(t)
+	now := time.Now()
+	verified := domain.User{ID: uuid.New(), Email: "ann@example.com", PasswordHash: []byte("h"), EmailVerifiedAt: &now}
+	unverified := domain.User{ID: uuid.New(), Email: "bob@example.com", PasswordHash: []byte("h")}
+	repo.users[verified.Email], repo.users[unverified.Email] = verified, unverified
+
+	// провайдер не подтвердил email — к существующему аккаунту не привязываем
+	mock.SetUser(oidctest.User{Subject: "a", Email: "ann@example.com"})
+	if _, err := login(t, s, mock); !errors.Is(err, app.ErrIdentityConflict) {
+		t.Fatalf("unverified provider email: err=%v", err)
+	}
+
+	mock.SetUser(oidctest.User{Subject: "a", Email: "ann@example.com", EmailVerified: true})
+	res, err := login(t, s, mock)
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if !res.Linked || res.Takeover || res.User.ID != verified.ID || len(res.User.PasswordHash) == 0 {
+		t.Fatalf("link: %+v", res)
+	}
+
+	// email аккаунта не подтверждён — владелец адреса забирает аккаунт, пароль стирается
+	mock.SetUser(oidctest.User{Subject: "b", Email: "bob@example.com", EmailVerified: true})
+	res, err = login(t, s, mock)
+	if err != nil {
+		t.Fatalf("takeover: %v", err)
+	}
+	if !res.Takeover || res.User.PasswordHash != nil || res.User.EmailVerifiedAt == nil {
+		t.Fatalf("takeover: %+v", res)
+	}
+	if _, ok := repo.taken[unverified.ID]; !ok {
+		t.Fatal("takeover not passed to repository")
+	}
+}
+
+func TestSocial_InvalidState(t *testing.T) {
+	s, mock, _ := newSocial(t)
+
+	authURL, err := s.Start(context.Background(), "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state, err := mock.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Complete(context.Background(), "mock", "forged", code); !errors.Is(err, app.ErrInvalidState) {
+		t.Fatalf("forged state: err=%v", err)
+	}
+	if _, err := s.Complete(context.Background(), "mock", state, code); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if _, err := s.Complete(context.Background(), "mock", state, code); !errors.Is(err, app.ErrInvalidState) {
+		t.Fatalf("replayed state: err=%v", err)
+	}
+	if _, err := s.Start(context.Background(), "nope"); !errors.Is(err, app.ErrUnknownProvider) {
+		t.Fatalf("unknown provider: err=%v", err)
+	}
+}
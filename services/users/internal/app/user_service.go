@@ -3,6 +3,9 @@ package app
 import (
 	"context"
 	"errors"
+	"sync"
+
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
 	domain "goshop/services/users/internal/domain/user"
@@ -11,6 +14,9 @@ import (
 var (
 	ErrWeakPassword       = errors.New("users: weak password")
 	ErrInvalidCredentials = errors.New("users: invalid credentials")
+	// ErrNotFound — пользователя нет (репозитории возвращают именно её)
+	ErrNotFound   = errors.New("users: not found")
+	ErrEmailTaken = errors.New("users: email already taken")
 )
 
 type UserRepository interface {
@@ -21,6 +27,9 @@ type UserRepository interface {
 type Service struct {
 	repo       UserRepository
 	bcryptCost int
+
+	dummyOnce sync.Once
+	dummyHash []byte
 }
 
 func NewService(repo UserRepository, bcryptCost int) *Service {
@@ -54,6 +63,14 @@ func (s *Service) Authenticate(ctx context.Context, email, password string) (dom
 	}
 	u, err := s.repo.GetByEmail(ctx, domain.NormalizeEmail(email))
 	if err != nil {
+		// та же работа bcrypt, что и для существующего аккаунта: по времени ответа
+		// нельзя понять, зарегистрирован ли email
+		_ = bcrypt.CompareHashAndPassword(s.dummy(), []byte(password))
+		return domain.User{}, ErrInvalidCredentials
+	}
+	if len(u.PasswordHash) == 0 {
+		// аккаунт из входа через OIDC-провайдера: пароля нет, пока его не зададут сбросом
+		_ = bcrypt.CompareHashAndPassword(s.dummy(), []byte(password))
 		return domain.User{}, ErrInvalidCredentials
 	}
 	if bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password)) != nil {
@@ -61,3 +78,11 @@ func (s *Service) Authenticate(ctx context.Context, email, password string) (dom
 	}
 	return u, nil
 }
+
+// dummy — хэш случайного пароля той же стоимости, что и настоящие.
+func (s *Service) dummy() []byte {
+	s.dummyOnce.Do(func() {
+		s.dummyHash, _ = bcrypt.GenerateFromPassword([]byte(uuid.NewString()), s.bcryptCost)
+	})
+	return s.dummyHash
+}
@@ -15,6 +15,9 @@ type User struct {
 	PasswordHash []byte
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	// EmailVerifiedAt — nil, пока адрес не подтверждён по ссылке из письма
+	EmailVerifiedAt *time.Time
+	Profile         Profile
 }
 
 var (
@@ -0,0 +1,103 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidName    = errors.New("invalid name")
+	ErrInvalidPhone   = errors.New("invalid phone")
+	ErrInvalidAddress = errors.New("invalid address")
+)
+
+const (
+	maxNameLen  = 100
+	maxFieldLen = 200
+)
+
+// Profile — необязательные данные пользователя; пустая строка — не задано.
+type Profile struct {
+	Name           string
+	Phone          string // E.164: +79991234567
+	DefaultAddress *Address
+}
+
+// Address — адрес доставки по умолчанию; поля как у shipping_address заказа.
+type Address struct {
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone,omitempty"`
+	Country    string `json:"country"` // ISO 3166-1 alpha-2
+	Region     string `json:"region,omitempty"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+}
+
+func NormalizeName(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func ValidateName(s string) error {
+	if utf8.RuneCountInString(s) > maxNameLen {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// NormalizePhone — без пробелов, скобок и дефисов: "+7 (999) 123-45-67" → "+79991234567".
+func NormalizePhone(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '(', ')', '-', '.':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+}
+
+// ValidatePhone — E.164: "+", затем 8–15 цифр, первая не 0. Пустой — телефон не задан.
+func ValidatePhone(s string) error {
+	if s == "" {
+		return nil
+	}
+	digits := strings.TrimPrefix(s, "+")
+	if len(digits) == len(s) || len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return ErrInvalidPhone
+	}
+	if strings.Trim(digits, "0123456789") != "" {
+		return ErrInvalidPhone
+	}
+	return nil
+}
+
+// Normalize — trim всех полей, страна в верхнем регистре.
+func (a *Address) Normalize() {
+	a.Recipient = strings.TrimSpace(a.Recipient)
+	a.Phone = NormalizePhone(a.Phone)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	a.Region = strings.TrimSpace(a.Region)
+	a.City = strings.TrimSpace(a.City)
+	a.PostalCode = strings.TrimSpace(a.PostalCode)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+}
+
+func (a *Address) Validate() error {
+	switch {
+	case a.Recipient == "", a.City == "", a.PostalCode == "", a.Line1 == "":
+		return ErrInvalidAddress
+	case len(a.Country) != 2 || strings.Trim(a.Country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "":
+		return ErrInvalidAddress
+	case ValidatePhone(a.Phone) != nil:
+		return ErrInvalidAddress
+	}
+	for _, f := range []string{a.Recipient, a.Region, a.City, a.PostalCode, a.Line1, a.Line2} {
+		if utf8.RuneCountInString(f) > maxFieldLen {
+			return ErrInvalidAddress
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package user_test
+
+import (
+	"errors"
+	"testing"
+
+	"goshop/services/users/internal/domain/user"
+)
+
+func TestPhone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"+7 (999) 123-45-67", "+79991234567", false},
+		{"", "", false},
+		{"89991234567", "89991234567", true}, // без "+"
+		{"+0123456789", "+0123456789", true},
+		{"+1234567", "+1234567", true},
+		{"+7999abc4567", "+7999abc4567", true},
+	}
+	for _, tt := range tests {
+		got := user.NormalizePhone(tt.in)
+		if got != tt.want {
+			t.Fatalf("NormalizePhone(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if err := user.ValidatePhone(got); (err != nil) != tt.wantErr {
+			t.Fatalf("ValidatePhone(%q) err = %v, wantErr %v", got, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAddress(t *testing.T) {
+	t.Parallel()
+
+	a := user.Address{Recipient: " Ann ", Country: "de", City: "Berlin", PostalCode: "10115", Line1: "Unter den Linden 1", Phone: "+49 30 123456"}
+	a.Normalize()
+	if a.Recipient != "Ann" || a.Country != "DE" || a.Phone != "+4930123456" {
+		t.Fatalf("normalized: %+v", a)
+	}
+	if err := a.Validate(); err != nil {
+		t.Fatalf("valid address: %v", err)
+	}
+
+	a.Country = "DEU"
+	if err := a.Validate(); !errors.Is(err, user.ErrInvalidAddress) {
+		t.Fatalf("alpha-3 country: err = %v", err)
+	}
+	a.Country, a.Line1 = "DE", ""
+	if err := a.Validate(); !errors.Is(err, user.ErrInvalidAddress) {
+		t.Fatalf("no line1: err = %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+-- +goose Up
+
+-- семейство сессий: логин открывает новое (family_id = id), каждая ротация refresh
+-- добавляет потомка с тем же family_id и parent_id = предыдущая сессия
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS family_id UUID;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS parent_id UUID;
+UPDATE sessions SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE sessions ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions (family_id);
+
+-- users_outbox: события users (аудит безопасности), публикует outboxer
+CREATE TABLE IF NOT EXISTS users_outbox (
+    id            BIGSERIAL      PRIMARY KEY,
+    agg_type      TEXT           NOT NULL,
+    agg_id        UUID           NOT NULL,
+    topic         TEXT           NOT NULL,
+    key           BYTEA,
+    headers       JSONB          NOT NULL DEFAULT '[]'::jsonb,
+    payload       JSONB          NOT NULL,
+    created_at    TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    available_at  TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    published_at  TIMESTAMPTZ,
+    error         TEXT,
+    retries       INT            NOT NULL DEFAULT 0
+    );
+CREATE INDEX IF NOT EXISTS users_outbox_pub_null  ON users_outbox(published_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS users_outbox_available ON users_outbox(available_at)  WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS users_outbox_agg       ON users_outbox(agg_type, agg_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS users_outbox;
+DROP INDEX IF EXISTS idx_sessions_family_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS family_id;
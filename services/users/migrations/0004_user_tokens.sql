@@ -0,0 +1,25 @@
+-- +goose Up
+
+-- подтверждение email: NULL — адрес ещё не подтверждён
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- аккаунты, созданные до подтверждения email, считаем подтверждёнными с момента регистрации:
+-- иначе require_verified_email закроет им вход, а вход через OIDC с тем же адресом
+-- примет их за непроверенные и сбросит пароль
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- одноразовые токены из писем (подтверждение email, сброс пароля); хранится только sha256
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id          UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind        TEXT         NOT NULL CHECK (kind IN ('verify_email', 'reset_password')),
+    token_hash  BYTEA        NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    expires_at  TIMESTAMPTZ  NOT NULL,
+    used_at     TIMESTAMPTZ
+    );
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_kind ON user_tokens (user_id, kind) WHERE used_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS user_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- TOTP 2FA: секрет зашифрован (AES-GCM, ключ mfa.encryption_key); confirmed_at NULL — enrollment
+-- начат, но код из приложения ещё не подтверждён. last_step — последний принятый 30s-интервал:
+-- один код нельзя предъявить дважды.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id       UUID         PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret        BYTEA        NOT NULL,
+    confirmed_at  TIMESTAMPTZ,
+    last_step     BIGINT       NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
+    );
+
+-- одноразовые коды восстановления (sha256), выдаются при подтверждении 2FA
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id         UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash  BYTEA        NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    UNIQUE (user_id, code_hash)
+    );
+
+-- +goose Down
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
@@ -0,0 +1,27 @@
+-- +goose Up
+
+-- RBAC: справочник ролей и назначения. Роли попадают в claim "roles" access-токена;
+-- что открывает роль, решает проверяющий сервис (httpx.RequireRole, grpcauth.RequireRole).
+CREATE TABLE IF NOT EXISTS roles (
+    name        TEXT         PRIMARY KEY,
+    description TEXT         NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+    );
+
+INSERT INTO roles (name, description) VALUES
+    ('admin',   'Полный доступ, включая назначение ролей'),
+    ('support', 'Чтение чужих заказов')
+ON CONFLICT (name) DO NOTHING;
+
+-- granted_by — кто назначил (NULL — вручную в БД, например первый admin)
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id     UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role        TEXT         NOT NULL REFERENCES roles(name),
+    granted_by  UUID         REFERENCES users(id) ON DELETE SET NULL,
+    granted_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, role)
+    );
+
+-- +goose Down
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,22 @@
+-- +goose Up
+
+-- Вход через внешних OIDC-провайдеров: (provider, subject) — стабильный id пользователя у провайдера.
+-- email — каким он был при последнем входе (у провайдера может поменяться).
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider      TEXT         NOT NULL,
+    subject       TEXT         NOT NULL,
+    user_id       UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email         TEXT,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, subject),
+    UNIQUE (user_id, provider)
+    );
+
+-- аккаунт, созданный входом через провайдера, пароля не имеет (задаётся через сброс пароля)
+ALTER TABLE users ALTER COLUMN password_hash DROP NOT NULL;
+
+-- +goose Down
+DELETE FROM users WHERE password_hash IS NULL;
+ALTER TABLE users ALTER COLUMN password_hash SET NOT NULL;
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- профиль: имя, телефон, адрес доставки по умолчанию (JSON — как shipping_address в orders)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS name            TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone           TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS default_address JSONB;
+
+-- удалённый аккаунт: строка остаётся (user_id в заказах и событиях), персональные данные стёрты
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- смена email: новый адрес ждёт подтверждения в токене из письма
+ALTER TABLE user_tokens ADD COLUMN IF NOT EXISTS email CITEXT;
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_kind_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_kind_check
+    CHECK (kind IN ('verify_email', 'reset_password', 'change_email'));
+
+-- +goose Down
+DELETE FROM user_tokens WHERE kind = 'change_email';
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_kind_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_kind_check
+    CHECK (kind IN ('verify_email', 'reset_password'));
+ALTER TABLE user_tokens DROP COLUMN IF EXISTS email;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS default_address;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
+ALTER TABLE users DROP COLUMN IF EXISTS name;
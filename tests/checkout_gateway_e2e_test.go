@@ -4,13 +4,17 @@ package integration
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 	"testing"
 	"time"
 
-	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"goshop/services/gateway/api/checkoutpb"
 )
@@ -29,14 +33,15 @@ func gatewayAddr(t *testing.T) string {
 // TestCheckout_CreateOrderAndPay_viaGateway:
 //  1. вызывает Checkout.CreateOrder через gateway;
 //  2. проверяет, что order_id не пустой и статус адекватный (NEW или PAID);
-//  3. опрашивает Checkout.GetOrderStatus, пока статус не станет PAID.
+//  3. проверяет, что без токена и чужим токеном статус не отдаётся;
+//  4. опрашивает Checkout.GetOrderStatus, пока статус не станет PAID.
 //
 // Для прохождения теста должны быть подняты:
 // - postgres, redis, kafka
 // - orders (+ orders-migrate, orders-consumer)
 // - payments (+ payments-migrate, payments-consumer)
 // - outboxer
-// - gateway
+// - gateway, users (токены владельца и постороннего)
 func TestCheckout_CreateOrderAndPay_viaGateway(t *testing.T) {
 	addr := gatewayAddr(t)
 
@@ -59,10 +64,15 @@ func TestCheckout_CreateOrderAndPay_viaGateway(t *testing.T) {
 	client := checkoutpb.NewCheckoutClient(conn)
 
 	// 1) CreateOrder
-	userID := uuid.NewString()
+	userID, access := registerAndLogin(t, "checkout_owner")
+	_, otherAccess := registerAndLogin(t, "checkout_other")
+	asOwner := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+access)
+	if _, err := client.CreateOrder(ctx, &checkoutpb.CreateOrderRequest{UserId: userID, AmountCents: 19901, Currency: "RUB"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("CreateOrder without token: %v, want Unauthenticated", err)
+	}
 	amountCents := int64(19901) // у меня логика что если сумма кратна 5 - failed
 
-	createCtx, cancelCreate := context.WithTimeout(ctx, 5*time.Second)
+	createCtx, cancelCreate := context.WithTimeout(asOwner, 5*time.Second)
 	defer cancelCreate()
 
 	createResp, err := client.CreateOrder(createCtx, &checkoutpb.CreateOrderRequest{
@@ -88,12 +98,21 @@ func TestCheckout_CreateOrderAndPay_viaGateway(t *testing.T) {
 
 	orderID := createResp.OrderId
 
-	// 2) Опрашиваем GetOrderStatus, пока не станет PAID
+	// 2) Заказ и статус — только владельцу
+	if _, err := client.GetOrder(ctx, &checkoutpb.GetOrderRequest{OrderId: orderID}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("GetOrder without token: %v, want Unauthenticated", err)
+	}
+	asOther := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+otherAccess)
+	if _, err := client.GetOrderStatus(asOther, &checkoutpb.GetOrderStatusRequest{OrderId: orderID}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("GetOrderStatus by another user: %v, want PermissionDenied", err)
+	}
+
+	// 3) Опрашиваем GetOrderStatus, пока не станет PAID
 	var finalStatus checkoutpb.OrderStatus
 	deadline := time.Now().Add(20 * time.Second)
 
 	for time.Now().Before(deadline) {
-		stCtx, cancelStatus := context.WithTimeout(ctx, 3*time.Second)
+		stCtx, cancelStatus := context.WithTimeout(asOwner, 3*time.Second)
 		statusResp, err := client.GetOrderStatus(stCtx, &checkoutpb.GetOrderStatusRequest{
 			OrderId: orderID,
 		})
@@ -119,3 +138,69 @@ func TestCheckout_CreateOrderAndPay_viaGateway(t *testing.T) {
 			orderID, finalStatus.String())
 	}
 }
+
+// TestCheckout_WatchOrderStatus_viaGateway:
+//  1. создаёт заказ через Checkout.CreateOrder;
+//  2. подписывается на Checkout.WatchOrderStatus;
+//  3. ждёт, пока стрим пришлёт PAID, и выходит сам: PAID не терминальный — стрим закрывается (io.EOF)
+//     только на DELIVERED/CANCELLED, а отгрузка идёт через admin API.
+//
+// Требования к окружению такие же, как у TestCheckout_CreateOrderAndPay_viaGateway.
+func TestCheckout_WatchOrderStatus_viaGateway(t *testing.T) {
+	addr := gatewayAddr(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(
+		ctx,
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		t.Fatalf("dial gateway at %q failed: %v", addr, err)
+	}
+	defer conn.Close()
+
+	client := checkoutpb.NewCheckoutClient(conn)
+
+	userID, access := registerAndLogin(t, "watch_owner")
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+access)
+
+	createResp, err := client.CreateOrder(ctx, &checkoutpb.CreateOrderRequest{
+		UserId:      userID,
+		AmountCents: 19901, // не кратно 5 — платёж пройдёт
+		Currency:    "RUB",
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	orderID := createResp.OrderId
+
+	stream, err := client.WatchOrderStatus(ctx, &checkoutpb.WatchOrderStatusRequest{OrderId: orderID})
+	if err != nil {
+		t.Fatalf("WatchOrderStatus failed: %v", err)
+	}
+
+	var last checkoutpb.OrderStatus
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("WatchOrderStatus recv: %v", err)
+		}
+		t.Logf("WatchOrderStatus: order=%s status=%s heartbeat=%v", orderID, msg.Status.String(), msg.Heartbeat)
+		last = msg.Status
+		// дальше стрим ждёт shipped/delivered (admin API) — после оплаты выходим сами
+		if last == checkoutpb.OrderStatus_ORDER_STATUS_PAID {
+			break
+		}
+	}
+
+	if last != checkoutpb.OrderStatus_ORDER_STATUS_PAID {
+		t.Fatalf("order %s: stream ended with status=%s, want PAID", orderID, last.String())
+	}
+}
@@ -0,0 +1,138 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"goshop/services/gateway/api/checkoutpb"
+)
+
+// Повтор оплаты через gateway: 1505 — insufficient_funds на первой попытке (повторяемый отказ,
+// повтор проходит), 2500 — card_declined (окончательный, заказ сразу отменяется).
+// Окружение — как у TestCheckout_CreateOrderAndPay_viaGateway, плюс users.
+func TestCheckout_RetryPayment_viaGateway(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, gatewayAddr(t),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		t.Fatalf("dial gateway: %v", err)
+	}
+	defer conn.Close()
+	client := checkoutpb.NewCheckoutClient(conn)
+
+	ownerID, ownerAccess := registerAndLogin(t, "retry_owner")
+	_, otherAccess := registerAndLogin(t, "retry_other")
+	asOwner := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+ownerAccess)
+	asOther := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+otherAccess)
+
+	// 1) повторяемый отказ: PAYMENT_FAILED
+	created, err := client.CreateOrder(asOwner, &checkoutpb.CreateOrderRequest{UserId: ownerID, AmountCents: 1505, Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	orderID := created.GetOrderId()
+	waitOrderStatus(t, asOwner, client, orderID, checkoutpb.OrderStatus_ORDER_STATUS_PAYMENT_FAILED)
+
+	// 2) без токена и чужим токеном повтор не проходит
+	if _, err := client.RetryPayment(ctx, &checkoutpb.RetryPaymentRequest{OrderId: orderID}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("retry without token: %v, want Unauthenticated", err)
+	}
+	if _, err := client.RetryPayment(asOther, &checkoutpb.RetryPaymentRequest{OrderId: orderID}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("retry by another user: %v, want PermissionDenied", err)
+	}
+
+	// 3) параллельные повторы: проходит ровно один, остальные — Aborted (та же попытка)
+	// или FailedPrecondition (последняя попытка уже подтверждена)
+	const n = 5
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		oks  int
+		errs []error
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, err := client.RetryPayment(asOwner, &checkoutpb.RetryPaymentRequest{OrderId: orderID})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			oks++
+			if p.GetAttempt() != 2 || p.GetStatus() != "confirmed" {
+				errs = append(errs, fmt.Errorf("retry: attempt=%d status=%s, want 2/confirmed", p.GetAttempt(), p.GetStatus()))
+			}
+		}()
+	}
+	wg.Wait()
+	if oks != 1 {
+		t.Fatalf("concurrent retries: %d succeeded, want 1 (errs: %v)", oks, errs)
+	}
+	for _, err := range errs {
+		if c := status.Code(err); c != codes.Aborted && c != codes.FailedPrecondition {
+			t.Fatalf("concurrent retry: %v, want Aborted or FailedPrecondition", err)
+		}
+	}
+	waitOrderStatus(t, asOwner, client, orderID, checkoutpb.OrderStatus_ORDER_STATUS_PAID)
+
+	// 4) окончательный отказ: заказ отменён сразу, повторять нечего
+	declined, err := client.CreateOrder(asOwner, &checkoutpb.CreateOrderRequest{UserId: ownerID, AmountCents: 2500, Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	waitOrderStatus(t, asOwner, client, declined.GetOrderId(), checkoutpb.OrderStatus_ORDER_STATUS_CANCELLED)
+	if _, err := client.RetryPayment(asOwner, &checkoutpb.RetryPaymentRequest{OrderId: declined.GetOrderId()}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("retry of declined order: %v, want FailedPrecondition", err)
+	}
+}
+
+func registerAndLogin(t *testing.T, prefix string) (id, access string) {
+	t.Helper()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	email := fmt.Sprintf("%s_%d@example.com", prefix, time.Now().UnixNano())
+	password := "StrongPass123!"
+
+	var reg registerResponse
+	doPostJSON(t, client, "/v1/users/register", registerRequest{Email: email, Password: password}, http.StatusCreated, &reg)
+	var login loginResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &login)
+	return reg.ID, login.AccessToken
+}
+
+func waitOrderStatus(t *testing.T, ctx context.Context, client checkoutpb.CheckoutClient, orderID string, want checkoutpb.OrderStatus) {
+	t.Helper()
+
+	var last checkoutpb.OrderStatus
+	deadline := time.Now().Add(20 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := client.GetOrderStatus(ctx, &checkoutpb.GetOrderStatusRequest{OrderId: orderID})
+		if err != nil {
+			t.Fatalf("GetOrderStatus: %v", err)
+		}
+		if last = resp.GetStatus(); last == want {
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Fatalf("order %s: status=%s, want %s", orderID, last, want)
+}
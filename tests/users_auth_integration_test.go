@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -81,6 +82,18 @@ func TestUsers_AuthFlow(t *testing.T) {
 		t.Fatalf("register: empty email or id in response: %#v", regResp)
 	}
 
+	// 1a) Повторная регистрация (email без учёта регистра) -> 409, а не 500
+	dup := doPostJSONError(t, client, "/v1/users/register",
+		registerRequest{
+			Email:    strings.ToUpper(email),
+			Password: password,
+		},
+		http.StatusConflict,
+	)
+	if dup["error"] != "email already taken" {
+		t.Fatalf("register duplicate: error = %v, want %q", dup["error"], "email already taken")
+	}
+
 	// 2) Логин
 	var loginResp1 loginResponse
 	doPostJSON(t, client, "/v1/users/login",
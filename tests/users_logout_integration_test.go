@@ -161,6 +161,10 @@ func TestUsers_LogoutAll_RevokesAllSessions(t *testing.T) {
 		t.Fatalf("refresh2 after logout_all: expected error=%q, got %v",
 			"invalid or expired session", errBody2["error"])
 	}
+
+	// 6) Выданные access-токены обеих сессий отозваны сразу, не дожидаясь exp
+	doGetJSONAuth(t, client, "/v1/users/me", loginResp1.AccessToken, http.StatusUnauthorized, nil)
+	doGetJSONAuth(t, client, "/v1/users/me", loginResp2.AccessToken, http.StatusUnauthorized, nil)
 }
 
 // doPostJSONAuth — POST с JSON-телом и Authorization: Bearer <token>
@@ -0,0 +1,96 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/base32"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"goshop/pkg/totp"
+)
+
+type mfaEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type mfaConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type mfaChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Enrollment → подтверждение кодом → логин в два шага → отключение с паролем.
+// Код TOTP, которым подтверждали, повторно не принимается, поэтому дальше — коды восстановления.
+func TestUsers_MFA_EnrollLoginDisable(t *testing.T) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	email := fmt.Sprintf("mfa_%d@example.com", time.Now().UnixNano())
+	password := "StrongPass123!"
+
+	var regResp registerResponse
+	doPostJSON(t, client, "/v1/users/register", registerRequest{Email: email, Password: password}, http.StatusCreated, &regResp)
+	var login loginResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &login)
+
+	// 1) Enrollment
+	var enroll mfaEnrollResponse
+	doPostJSONAuth(t, client, "/v1/users/mfa/enroll", login.AccessToken, struct{}{}, http.StatusOK, &enroll)
+	u, err := url.Parse(enroll.OTPAuthURI)
+	if err != nil || u.Scheme != "otpauth" || u.Query().Get("secret") != enroll.Secret {
+		t.Fatalf("otpauth uri: %q (%v)", enroll.OTPAuthURI, err)
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(enroll.Secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+
+	// 2) Подтверждение
+	doPostJSONAuth(t, client, "/v1/users/mfa/confirm", login.AccessToken, map[string]string{"code": "abcdef"}, http.StatusBadRequest, nil)
+	var confirm mfaConfirmResponse
+	doPostJSONAuth(t, client, "/v1/users/mfa/confirm", login.AccessToken,
+		map[string]string{"code": totp.Code(secret, totp.Step(time.Now()))}, http.StatusOK, &confirm)
+	if len(confirm.RecoveryCodes) == 0 {
+		t.Fatal("no recovery codes")
+	}
+
+	// 3) Логин: вместо токенов — challenge
+	var ch mfaChallengeResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &ch)
+	if !ch.MFARequired || ch.MFAToken == "" {
+		t.Fatalf("login with mfa: %#v", ch)
+	}
+	doPostJSONError(t, client, "/v1/users/login/mfa", map[string]string{"mfa_token": ch.MFAToken, "code": "zzzzz-zzzzz"}, http.StatusUnauthorized)
+
+	var mfaLogin loginResponse
+	doPostJSON(t, client, "/v1/users/login/mfa",
+		map[string]string{"mfa_token": ch.MFAToken, "code": confirm.RecoveryCodes[0]}, http.StatusOK, &mfaLogin)
+	if mfaLogin.AccessToken == "" || mfaLogin.RefreshToken == "" {
+		t.Fatalf("login/mfa: empty tokens: %#v", mfaLogin)
+	}
+	// challenge одноразовый
+	doPostJSONError(t, client, "/v1/users/login/mfa",
+		map[string]string{"mfa_token": ch.MFAToken, "code": confirm.RecoveryCodes[1]}, http.StatusUnauthorized)
+
+	// 4) Отключение требует пароль
+	doPostJSONAuth(t, client, "/v1/users/mfa/disable", mfaLogin.AccessToken,
+		map[string]string{"password": "wrong-password", "code": confirm.RecoveryCodes[1]}, http.StatusUnauthorized, nil)
+	doPostJSONAuth(t, client, "/v1/users/mfa/disable", mfaLogin.AccessToken,
+		map[string]string{"password": password, "code": confirm.RecoveryCodes[1]}, http.StatusNoContent, nil)
+
+	var plain loginResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &plain)
+	if plain.AccessToken == "" {
+		t.Fatal("login after disable: no access token")
+	}
+}
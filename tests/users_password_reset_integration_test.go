@@ -0,0 +1,48 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Токен приходит только письмом, поэтому здесь — то, что видно снаружи: forgot не выдаёт,
+// зарегистрирован ли адрес, а чужие токены не принимаются.
+func TestUsers_PasswordReset_NoEnumerationAndInvalidToken(t *testing.T) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	email := fmt.Sprintf("reset_%d@example.com", time.Now().UnixNano())
+	password := "StrongPass123!"
+
+	var regResp registerResponse
+	doPostJSON(t, client, "/v1/users/register",
+		registerRequest{Email: email, Password: password},
+		http.StatusCreated,
+		&regResp,
+	)
+
+	// 1) Один и тот же ответ для существующего и несуществующего адреса
+	doPostJSON(t, client, "/v1/users/password/forgot", map[string]string{"email": email}, http.StatusAccepted, nil)
+	doPostJSON(t, client, "/v1/users/password/forgot", map[string]string{"email": "nobody_" + email}, http.StatusAccepted, nil)
+	doPostJSON(t, client, "/v1/users/verify/resend", map[string]string{"email": "nobody_" + email}, http.StatusAccepted, nil)
+
+	// 2) Выдуманные токены
+	resp := doPostJSONError(t, client, "/v1/users/password/reset",
+		map[string]string{"token": "bogus", "password": "AnotherPass123!"}, http.StatusBadRequest)
+	if resp["error"] != "invalid or expired token" {
+		t.Fatalf("reset: error = %v", resp["error"])
+	}
+	resp = doPostJSONError(t, client, "/v1/users/verify", map[string]string{"token": "bogus"}, http.StatusBadRequest)
+	if resp["error"] != "invalid or expired token" {
+		t.Fatalf("verify: error = %v", resp["error"])
+	}
+
+	// 3) Пароль не изменился
+	var login loginResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &login)
+}
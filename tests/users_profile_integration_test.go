@@ -0,0 +1,122 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type addressJSON struct {
+	Recipient  string `json:"recipient"`
+	Country    string `json:"country"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	Line1      string `json:"line1"`
+}
+
+type profileResponse struct {
+	UID            string       `json:"uid"`
+	Email          string       `json:"email"`
+	Name           string       `json:"name"`
+	Phone          string       `json:"phone"`
+	DefaultAddress *addressJSON `json:"default_address"`
+}
+
+// PATCH /me -> смена пароля (другая сессия закрыта, текущая жива) -> удаление аккаунта.
+func TestUsers_Profile_UpdatePasswordDelete(t *testing.T) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	email := fmt.Sprintf("profile_%d@example.com", time.Now().UnixNano())
+	password := "StrongPass123!"
+	newPassword := "EvenStrongerPass456!"
+
+	var reg registerResponse
+	doPostJSON(t, client, "/v1/users/register", registerRequest{Email: email, Password: password}, http.StatusCreated, &reg)
+	var first, second loginResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &first)
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &second)
+
+	// 1) Профиль
+	var prof profileResponse
+	doJSONAuth(t, client, http.MethodPatch, "/v1/users/me", first.AccessToken, map[string]any{
+		"name":  "  Ann   Smith ",
+		"phone": "+7 (999) 123-45-67",
+		"default_address": addressJSON{
+			Recipient: "Ann Smith", Country: "ru", City: "Moscow", PostalCode: "101000", Line1: "Tverskaya 1",
+		},
+	}, http.StatusOK, &prof)
+	if prof.Name != "Ann Smith" || prof.Phone != "+79991234567" || prof.DefaultAddress == nil || prof.DefaultAddress.Country != "RU" {
+		t.Fatalf("patched profile: %+v", prof)
+	}
+	doJSONAuth(t, client, http.MethodPatch, "/v1/users/me", first.AccessToken, map[string]any{"phone": "12345"}, http.StatusBadRequest, nil)
+
+	prof = profileResponse{}
+	doJSONAuth(t, client, http.MethodPatch, "/v1/users/me", first.AccessToken, map[string]any{"default_address": nil}, http.StatusOK, &prof)
+	if prof.DefaultAddress != nil || prof.Name != "Ann Smith" {
+		t.Fatalf("address not cleared or name lost: %+v", prof)
+	}
+
+	// 2) Смена пароля: вторая сессия закрыта, первая работает
+	doPostJSONAuth(t, client, "/v1/users/me/password", first.AccessToken,
+		map[string]string{"old_password": "wrong", "new_password": newPassword}, http.StatusUnauthorized, nil)
+	doPostJSONAuth(t, client, "/v1/users/me/password", first.AccessToken,
+		map[string]string{"old_password": password, "new_password": newPassword}, http.StatusNoContent, nil)
+
+	doGetJSONAuth(t, client, "/v1/users/me", second.AccessToken, http.StatusUnauthorized, nil)
+	doPostJSONError(t, client, "/v1/users/refresh", refreshRequest{RefreshToken: second.RefreshToken}, http.StatusUnauthorized)
+	doGetJSONAuth(t, client, "/v1/users/me", first.AccessToken, http.StatusOK, nil)
+	doPostJSONError(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusUnauthorized)
+
+	// 3) Удаление: только с паролем; после — ни входа, ни refresh, email снова свободен
+	doJSONAuth(t, client, http.MethodDelete, "/v1/users/me", first.AccessToken, map[string]string{"password": password}, http.StatusUnauthorized, nil)
+	doJSONAuth(t, client, http.MethodDelete, "/v1/users/me", first.AccessToken, map[string]string{"password": newPassword}, http.StatusNoContent, nil)
+
+	doGetJSONAuth(t, client, "/v1/users/me", first.AccessToken, http.StatusUnauthorized, nil)
+	doPostJSONError(t, client, "/v1/users/refresh", refreshRequest{RefreshToken: first.RefreshToken}, http.StatusUnauthorized)
+	doPostJSONError(t, client, "/v1/users/login", loginRequest{Email: email, Password: newPassword}, http.StatusUnauthorized)
+	doPostJSON(t, client, "/v1/users/register", registerRequest{Email: email, Password: password}, http.StatusCreated, nil)
+}
+
+// doJSONAuth — запрос с JSON-телом и Authorization: Bearer <token> для методов кроме POST.
+func doJSONAuth(t *testing.T, client *http.Client, method, path, accessToken string, body any, wantStatus int, out any) {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request body for %s: %v", path, err)
+	}
+
+	req, err := http.NewRequest(method, baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("new request %s: %v", path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s: status=%d, want=%d, body=%s",
+			method, path, resp.StatusCode, wantStatus, string(respBody))
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		t.Fatalf("decode response from %s: %v (body=%s)", path, err, string(respBody))
+	}
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Повторный refresh уже ротированным токеном — кража: отзывается всё семейство,
+// и актуальный (ротированный) refresh перестаёт работать.
+func TestUsers_RefreshReuse_RevokesFamily(t *testing.T) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	email := fmt.Sprintf("reuse_%d@example.com", time.Now().UnixNano())
+	password := "StrongPass123!"
+
+	// 1) Регистрация + логин
+	var regResp registerResponse
+	doPostJSON(t, client, "/v1/users/register",
+		registerRequest{Email: email, Password: password},
+		http.StatusCreated,
+		&regResp,
+	)
+	var loginResp loginResponse
+	doPostJSON(t, client, "/v1/users/login",
+		loginRequest{Email: email, Password: password},
+		http.StatusOK,
+		&loginResp,
+	)
+
+	// 2) Легитимная ротация: R1 -> R2
+	var ref1 refreshResponse
+	doPostJSON(t, client, "/v1/users/refresh",
+		refreshRequest{RefreshToken: loginResp.RefreshToken},
+		http.StatusOK,
+		&ref1,
+	)
+
+	// 3) Повтор R1 -> 409, семейство отозвано
+	errBody := doPostJSONError(t, client, "/v1/users/refresh",
+		refreshRequest{RefreshToken: loginResp.RefreshToken},
+		http.StatusConflict,
+	)
+	if errBody["error"] != "refresh token reused" {
+		t.Fatalf("replay: expected error=%q, got %v", "refresh token reused", errBody["error"])
+	}
+
+	// 4) R2 тоже больше не работает
+	errBody = doPostJSONError(t, client, "/v1/users/refresh",
+		refreshRequest{RefreshToken: ref1.RefreshToken},
+		http.StatusUnauthorized,
+	)
+	if errBody["error"] != "invalid or expired session" {
+		t.Fatalf("refresh after reuse: expected error=%q, got %v",
+			"invalid or expired session", errBody["error"])
+	}
+}
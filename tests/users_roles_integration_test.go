@@ -0,0 +1,116 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type userRolesResponse struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
+
+// Обычный пользователь в admin API не попадает. Назначение ролей проверяется, если задан
+// admin (GOSHOP_ADMIN_EMAIL / GOSHOP_ADMIN_PASSWORD) — первую роль admin выдают в БД, см. README.
+func TestUsers_Roles_GrantAndRevoke(t *testing.T) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	email := fmt.Sprintf("rbac_%d@example.com", time.Now().UnixNano())
+	password := "StrongPass123!"
+
+	var reg registerResponse
+	doPostJSON(t, client, "/v1/users/register", registerRequest{Email: email, Password: password}, http.StatusCreated, &reg)
+	var login loginResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &login)
+
+	doGetJSONAuth(t, client, "/v1/users/admin/roles", login.AccessToken, http.StatusForbidden, nil)
+	doPutAuth(t, client, "/v1/users/admin/users/"+reg.ID+"/roles/admin", login.AccessToken, http.StatusForbidden)
+
+	adminEmail, adminPassword := os.Getenv("GOSHOP_ADMIN_EMAIL"), os.Getenv("GOSHOP_ADMIN_PASSWORD")
+	if adminEmail == "" || adminPassword == "" {
+		t.Skip("GOSHOP_ADMIN_EMAIL/GOSHOP_ADMIN_PASSWORD not set: skipping role management")
+	}
+	var admin loginResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: adminEmail, Password: adminPassword}, http.StatusOK, &admin)
+
+	// 1) Назначение: роль появляется в access после refresh
+	doPutAuth(t, client, "/v1/users/admin/users/"+reg.ID+"/roles/support", admin.AccessToken, http.StatusNoContent)
+	doPutAuth(t, client, "/v1/users/admin/users/"+reg.ID+"/roles/support", admin.AccessToken, http.StatusNoContent)
+	doPutAuth(t, client, "/v1/users/admin/users/"+reg.ID+"/roles/no-such-role", admin.AccessToken, http.StatusBadRequest)
+
+	var roles userRolesResponse
+	doGetJSONAuth(t, client, "/v1/users/admin/users/"+reg.ID+"/roles", admin.AccessToken, http.StatusOK, &roles)
+	if len(roles.Roles) != 1 || roles.Roles[0] != "support" {
+		t.Fatalf("roles: %#v", roles)
+	}
+
+	var refreshed refreshResponse
+	doPostJSON(t, client, "/v1/users/refresh", refreshRequest{RefreshToken: login.RefreshToken}, http.StatusOK, &refreshed)
+	if got := tokenRoles(t, refreshed.AccessToken); len(got) != 1 || got[0] != "support" {
+		t.Fatalf("access roles after refresh: %v", got)
+	}
+
+	// 2) Снятие закрывает сессии пользователя
+	doDeleteAuth(t, client, "/v1/users/admin/users/"+reg.ID+"/roles/support", admin.AccessToken, http.StatusNoContent)
+	doGetJSONAuth(t, client, "/v1/users/me", refreshed.AccessToken, http.StatusUnauthorized, nil)
+	doPostJSONError(t, client, "/v1/users/refresh", refreshRequest{RefreshToken: refreshed.RefreshToken}, http.StatusUnauthorized)
+
+	// 3) Себе admin не снять
+	var me meResponse
+	doGetJSONAuth(t, client, "/v1/users/me", admin.AccessToken, http.StatusOK, &me)
+	doDeleteAuth(t, client, "/v1/users/admin/users/"+me.UID+"/roles/admin", admin.AccessToken, http.StatusConflict)
+}
+
+// tokenRoles — claim roles без проверки подписи (её проверяет сервер).
+func tokenRoles(t *testing.T, token string) []string {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("malformed jwt: %q", token)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode jwt payload: %v", err)
+	}
+	var claims struct {
+		Roles []string `json:"roles"`
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		t.Fatalf("unmarshal jwt payload: %v", err)
+	}
+	return claims.Roles
+}
+
+// doPutAuth — PUT без тела с Authorization: Bearer <token>, проверяет только статус.
+func doPutAuth(t *testing.T, client *http.Client, path, accessToken string, wantStatus int) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPut, baseURL+path, nil)
+	if err != nil {
+		t.Fatalf("new request %s: %v", path, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("PUT %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("PUT %s: status=%d, want=%d, body=%s", path, resp.StatusCode, wantStatus, string(body))
+	}
+}
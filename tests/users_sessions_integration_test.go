@@ -0,0 +1,93 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type sessionsResponse struct {
+	Sessions []struct {
+		ID      string `json:"id"`
+		Current bool   `json:"current"`
+	} `json:"sessions"`
+}
+
+// Два устройства: список с отметкой текущего, выход со второго по DELETE —
+// его refresh и access перестают работать.
+func TestUsers_Sessions_ListAndRevoke(t *testing.T) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	email := fmt.Sprintf("sessions_%d@example.com", time.Now().UnixNano())
+	password := "StrongPass123!"
+
+	var regResp registerResponse
+	doPostJSON(t, client, "/v1/users/register",
+		registerRequest{Email: email, Password: password},
+		http.StatusCreated,
+		&regResp,
+	)
+	var login1, login2 loginResponse
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &login1)
+	doPostJSON(t, client, "/v1/users/login", loginRequest{Email: email, Password: password}, http.StatusOK, &login2)
+
+	// 1) Список с первого устройства
+	var list sessionsResponse
+	doGetJSONAuth(t, client, "/v1/users/sessions", login1.AccessToken, http.StatusOK, &list)
+	if len(list.Sessions) != 2 {
+		t.Fatalf("sessions: got %d, want 2: %#v", len(list.Sessions), list)
+	}
+	var other string
+	current := 0
+	for _, s := range list.Sessions {
+		if s.Current {
+			current++
+		} else {
+			other = s.ID
+		}
+	}
+	if current != 1 || other == "" {
+		t.Fatalf("sessions: want exactly one current: %#v", list)
+	}
+
+	// 2) Выход со второго устройства
+	doDeleteAuth(t, client, "/v1/users/sessions/"+other, login1.AccessToken, http.StatusNoContent)
+	doDeleteAuth(t, client, "/v1/users/sessions/"+other, login1.AccessToken, http.StatusNotFound)
+
+	doGetJSONAuth(t, client, "/v1/users/sessions", login1.AccessToken, http.StatusOK, &list)
+	if len(list.Sessions) != 1 || !list.Sessions[0].Current {
+		t.Fatalf("sessions after delete: %#v", list)
+	}
+
+	// 3) Токены второго устройства больше не работают
+	doPostJSONError(t, client, "/v1/users/refresh", refreshRequest{RefreshToken: login2.RefreshToken}, http.StatusUnauthorized)
+	doGetJSONAuth(t, client, "/v1/users/me", login2.AccessToken, http.StatusUnauthorized, nil)
+}
+
+// doDeleteAuth — DELETE с Authorization: Bearer <token>, проверяет только статус.
+func doDeleteAuth(t *testing.T, client *http.Client, path, accessToken string, wantStatus int) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodDelete, baseURL+path, nil)
+	if err != nil {
+		t.Fatalf("new request %s: %v", path, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("DELETE %s: status=%d, want=%d, body=%s", path, resp.StatusCode, wantStatus, string(body))
+	}
+}